@@ -1,7 +1,8 @@
-import { createContext, useState } from 'react'
+import { createContext, useEffect, useState } from 'react'
 import './App.css'
 import { BrowserRouter, Route, Routes } from 'react-router-dom'
 import { ToastDetails, UserDetails } from './util/types'
+import api from './util/api'
 
 import Header from './pages/Header'
 import SideBar from './components/SideBar'
@@ -50,6 +51,16 @@ function App() {
   const [isSidebarExpanded, setIsSidebarExpanded] = useState<boolean>(true)
   const [isMobileOpen, setIsMobileOpen] = useState<boolean>(false)
 
+  useEffect(() => {
+    // The api refreshes expired tokens, the following calls use the new ones.
+    api.onSessionRefresh = (token, refreshToken) =>
+      setUserData((userData) => ({
+        ...userData,
+        token: token,
+        refresh_token: refreshToken,
+      }))
+  }, [])
+
   return (
     <>
       <ThemeProvider>
@@ -3,6 +3,7 @@ import { useTranslation } from 'react-i18next'
 import { MainContext } from '../App'
 import api from '../util/api'
 import Button from '../stylecomponents/Button'
+import Checkbox from './Checkbox'
 
 interface OrganizationFormProps {
   headText: string
@@ -13,6 +14,8 @@ interface OrganizationFormProps {
   setEmail: (email: string) => void
   activeUntil: string
   setActiveUntil: (date: string) => void
+  requireTwofactor: boolean
+  setRequireTwofactor: (required: boolean) => void
   handleSubmit: () => void
 }
 
@@ -25,6 +28,8 @@ export default function OrganizationForm({
   setEmail,
   activeUntil,
   setActiveUntil,
+  requireTwofactor,
+  setRequireTwofactor,
   handleSubmit,
 }: OrganizationFormProps) {
   const { t } = useTranslation()
@@ -109,6 +114,17 @@ export default function OrganizationForm({
           </div>
         </div>
 
+        <div>
+          <Checkbox
+            label={t('RequireTwofactor')}
+            checked={requireTwofactor}
+            onChange={setRequireTwofactor}
+          />
+          <p className="text-xs text-text-muted">
+            {t('RequireTwofactorDescription')}
+          </p>
+        </div>
+
         {/* Status Display */}
         {organizationId && activeUntil && (
           <div className="p-4 bg-surface-secondary rounded-lg border border-border-default">
@@ -11,6 +11,7 @@ export default function CreateOrganization() {
 
   const [organizationName, setOrganizationName] = useState('')
   const [email, setEmail] = useState('')
+  const [requireTwofactor, setRequireTwofactor] = useState(false)
   const [activeUntil, setActiveUntil] = useState('2999-01-01')
 
   function handleSubmit() {
@@ -18,6 +19,7 @@ export default function CreateOrganization() {
       organization_name: organizationName,
       email: email,
       active_until: activeUntil,
+      require_twofactor: requireTwofactor,
     }
 
     api.createOrganization(userData.token, newOrganization, setToast)
@@ -33,6 +35,8 @@ export default function CreateOrganization() {
       setEmail={setEmail}
       activeUntil={activeUntil}
       setActiveUntil={setActiveUntil}
+      requireTwofactor={requireTwofactor}
+      setRequireTwofactor={setRequireTwofactor}
       handleSubmit={handleSubmit}
     />
   )
@@ -7,6 +7,7 @@ import logo from '../assets/go4lage-logo-plain.svg'
 import gopher from '../assets/gopher.svg'
 import { ThemeToggle } from '../themecomps/ThemeToggle'
 import { useTranslation } from 'react-i18next'
+import { LoginResponse, TwofactorEnrollment } from '../util/types'
 export default function Login() {
   const { setUserData, setToast } = useContext(MainContext)
   const navigate = useNavigate()
@@ -14,6 +15,11 @@ export default function Login() {
   const [email, setEmail] = useState('')
   const [password, setPassword] = useState('')
   const [tfa, setTfa] = useState('')
+  const [enrollment, setEnrollment] = useState<TwofactorEnrollment | null>(
+    null
+  )
+  // The user of a login that enabled 2FA, kept until the recovery codes are noted.
+  const [enrolledUser, setEnrolledUser] = useState<LoginResponse | null>(null)
   const { t } = useTranslation()
   useEffect(() => {
     async function getDashboardinfo() {
@@ -27,35 +33,82 @@ export default function Login() {
     getDashboardinfo()
   }, [])
 
-  async function handleSubmit(e: FormEvent<HTMLFormElement>) {
-    e.preventDefault()
-    const response = await api.login(email, password, tfa, setToast)
+  function finishLogin(response: LoginResponse) {
+    setUserData({
+      email: response.email,
+      token: response.token,
+      refresh_token: response.refresh_token,
+      is_superuser: response.is_superuser,
+      is_organizationadmin: response.is_organizationadmin,
+      organization_id: response.organization_id,
+      organization_name: response.organization_name,
+    })
 
-    if (response && response.token) {
-      setUserData({
+    sessionStorage.setItem(
+      'userData',
+      JSON.stringify({
         email: response.email,
         token: response.token,
+        refresh_token: response.refresh_token,
         is_superuser: response.is_superuser,
         is_organizationadmin: response.is_organizationadmin,
         organization_id: response.organization_id,
         organization_name: response.organization_name,
       })
+    )
+    navigate('/')
+  }
 
-      sessionStorage.setItem(
-        'userData',
-        JSON.stringify({
-          email: response.email,
-          token: response.token,
-          is_superuser: response.is_superuser,
-          is_organizationadmin: response.is_organizationadmin,
-          organization_id: response.organization_id,
-          organization_name: response.organization_name,
-        })
-      )
-      navigate('/')
+  async function handleSubmit(e: FormEvent<HTMLFormElement>) {
+    e.preventDefault()
+    const response = await api.login(email, password, tfa, setToast)
+    if (!response) {
+      return
+    }
+
+    // The user has 2FA, or has to set it up first. Every enrollment comes with a new secret.
+    if (response.twofa_required) {
+      setNeedstfa(true)
+      setEnrollment(response.twofa_enrollment ?? null)
+      setTfa('')
+      return
+    }
+
+    if (response.token) {
+      if (response.recovery_codes && response.recovery_codes.length > 0) {
+        setEnrolledUser(response)
+        return
+      }
+      finishLogin(response)
     }
   }
 
+  if (enrolledUser) {
+    return (
+      <div className="min-h-screen flex items-center justify-center bg-surface-secondary">
+        <div className="bg-surface-primary rounded-xl shadow-xl w-full max-w-lg p-8 space-y-6">
+          <h1 className="text-2xl font-bold text-text-primary">
+            {t('recoveryCodesTitle')}
+          </h1>
+          <p className="text-text-muted">{t('recoveryCodesHint')}</p>
+          <ul className="bg-surface-tertiary rounded-lg p-4 font-mono text-text-primary text-center">
+            {enrolledUser.recovery_codes?.map((code) => (
+              <li key={code}>{code}</li>
+            ))}
+          </ul>
+          <Button
+            kind="primary"
+            onClick={() => finishLogin(enrolledUser)}
+            className="w-full flex justify-center py-3 rounded-lg bg-interactive-default hover:bg-interactive-hover 
+                      text-text-inverse font-medium transition-colors duration-200"
+          >
+            {t('continue')}
+          </Button>
+        </div>
+      </div>
+    )
+  }
+
   return (
     <div className="min-h-screen flex items-center justify-center bg-surface-secondary">
       <div className="bg-surface-primary rounded-xl shadow-xl w-full max-w-lg transition-all duration-300 hover:shadow-2xl">
@@ -111,6 +164,28 @@ export default function Login() {
               />
             </div>
 
+            {enrollment && (
+              <div className="bg-surface-tertiary rounded-lg p-4 text-center">
+                <p className="text-sm font-medium text-text-primary">
+                  {t('twofaEnrollTitle')}
+                </p>
+                <p className="text-sm text-text-muted mt-2">
+                  {t('twofaEnrollHint')}
+                </p>
+                <img
+                  src={`data:image/png;base64,${enrollment.qr_png}`}
+                  className="mx-auto mt-4"
+                  alt="QR code"
+                />
+                <p className="text-xs text-text-muted mt-4">
+                  {t('twofaSecret')}
+                </p>
+                <p className="font-mono text-sm text-text-primary break-all">
+                  {enrollment.secret}
+                </p>
+              </div>
+            )}
+
             {needstfa && (
               <div>
                 <label className="block text-sm font-medium text-text-primary mb-2">
@@ -125,7 +200,7 @@ export default function Login() {
                             focus:outline-none focus:ring-2 focus:ring-interactive-default focus:border-transparent
                             transition-colors duration-200"
                   placeholder={t('enterSixDigitCode')}
-                  maxLength={6}
+                  autoComplete="one-time-code"
                 />
               </div>
             )}
@@ -141,6 +216,15 @@ export default function Login() {
               {t('signIn')}
             </Button>
           </div>
+
+          <div className="text-center">
+            <a
+              href="/resetpassword"
+              className="text-sm text-text-muted hover:text-text-primary"
+            >
+              {t('forgotPassword')}
+            </a>
+          </div>
         </form>
       </div>
     </div>
@@ -14,6 +14,7 @@ export default function ManageOrganization() {
 
   const [organizationName, setOrganizationName] = useState('')
   const [email, setEmail] = useState('')
+  const [requireTwofactor, setRequireTwofactor] = useState(false)
   const [activeUntil, setActiveUntil] = useState('')
 
   useEffect(() => {
@@ -25,6 +26,7 @@ export default function ManageOrganization() {
         setOrganizationName(organization.organization_name)
         setEmail(organization.email)
         setActiveUntil(organization.active_until)
+        setRequireTwofactor(organization.require_twofactor)
       }
     }
     getOrganizationInfo()
@@ -35,6 +37,7 @@ export default function ManageOrganization() {
       organization_name: organizationName,
       email: email,
       active_until: activeUntil,
+      require_twofactor: requireTwofactor,
     }
 
     api.editOneOrganization(
@@ -55,6 +58,8 @@ export default function ManageOrganization() {
       setEmail={setEmail}
       activeUntil={activeUntil}
       setActiveUntil={setActiveUntil}
+      requireTwofactor={requireTwofactor}
+      setRequireTwofactor={setRequireTwofactor}
       handleSubmit={handleSubmit}
     />
   )
@@ -12,6 +12,7 @@ import {
   FeedBackT,
   FeedbackMsgT,
   OrganizationT,
+  LoginResponse,
 } from './types'
 
 interface FetchWithTokenProps {
@@ -22,8 +23,37 @@ interface FetchWithTokenProps {
   setToast: ((toast: ToastDetails) => void | null) | null
 }
 
+// The error body of the API. code is stable and can be used for translations.
+interface ApiError {
+  code: string
+  detail: string
+  error: string
+}
+
+async function readApiError(response: Response): Promise<ApiError | null> {
+  const contentType = response.headers.get('content-type')
+  if (!contentType || !contentType.includes('application/json')) {
+    return null
+  }
+  try {
+    return (await response.json()) as ApiError
+  } catch {
+    return null
+  }
+}
+
+// The cause is only sent by the API in debug mode.
+function apiErrorText(apiError: ApiError): string {
+  return apiError.error ? `${apiError.detail} ${apiError.error}` : apiError.detail
+}
+
 class API {
   apiUrl: string
+  // Called with the new tokens when an expired token was refreshed, so the app can use them.
+  onSessionRefresh: ((token: string, refreshToken: string) => void) | null =
+    null
+  // One refresh for all calls that fail at the same time, as a refresh token works only once.
+  private refreshing: Promise<string | null> | null = null
   constructor() {
     const apiUrlstring = '{%Apiurl%}'
 
@@ -54,17 +84,29 @@ class API {
     }
 
     const headers = new Headers(options.headers)
-    headers.append('Authorization', 'Token ' + token)
+    headers.set('Authorization', 'Token ' + token)
+
+    let response = await fetch(url, { ...options, headers })
 
-    const response = await fetch(url, { ...options, headers })
+    // The token expired. It is refreshed once and the call is tried again.
+    if (response.status === 401 && token) {
+      const newToken = await this.refreshToken(token)
+      if (newToken) {
+        headers.set('Authorization', 'Token ' + newToken)
+        response = await fetch(url, { ...options, headers })
+      }
+    }
 
     if (!response.ok) {
       if (setToast) {
+        const apiError = await readApiError(response)
         setToast({
           show: true,
           success: false,
-          header: 'Error outside go4lage',
-          text: `${response.status}`,
+          header: apiError
+            ? toastHeader || 'Toastheader missing'
+            : 'Error outside go4lage',
+          text: apiError ? apiErrorText(apiError) : `${response.status}`,
         })
       }
       return
@@ -112,6 +154,59 @@ class API {
     return response
   }
 
+  // Returns a new token for the expired one, or null if the user has to login again.
+  private async refreshToken(expiredToken: string): Promise<string | null> {
+    const stored = sessionStorage.getItem('userData')
+    if (!stored) {
+      return null
+    }
+    const userData = JSON.parse(stored)
+    // Another call refreshed it already.
+    if (userData.token && userData.token !== expiredToken) {
+      return userData.token
+    }
+    if (!userData.refresh_token) {
+      return null
+    }
+
+    if (!this.refreshing) {
+      this.refreshing = this.refreshSession(userData).finally(() => {
+        this.refreshing = null
+      })
+    }
+    return this.refreshing
+  }
+
+  private async refreshSession(userData: UserDetails): Promise<string | null> {
+    try {
+      const response = await fetch(this.apiUrl + '/refresh', {
+        method: 'POST',
+        headers: {
+          'Content-Type': 'application/json',
+        },
+        body: JSON.stringify({ refresh_token: userData.refresh_token }),
+      })
+      if (!response.ok) {
+        return null
+      }
+      const tokens = await response.json()
+      sessionStorage.setItem(
+        'userData',
+        JSON.stringify({
+          ...userData,
+          token: tokens.token,
+          refresh_token: tokens.refresh_token,
+        })
+      )
+      if (this.onSessionRefresh) {
+        this.onSessionRefresh(tokens.token, tokens.refresh_token)
+      }
+      return tokens.token
+    } catch {
+      return null
+    }
+  }
+
   public async dashboardinfo(): Promise<DashboardInfo> {
     const response = await fetch(this.apiUrl + '/dashboardinfo', {
       method: 'GET',
@@ -120,18 +215,27 @@ class API {
     return data
   }
 
+  // The users are paginated. This loads all pages, as the user table sorts and filters on its own.
   public async allusers(token: string): Promise<User[] | null> {
-    const response = await this.fetchWithToken({
-      url: `${this.apiUrl}/allusers`,
-      options: {
-        method: 'GET',
-      },
-      token: token,
-      toastHeader: null,
-      setToast: null,
-    })
-
-    return response || null
+    const users: User[] = []
+    for (let page = 1; ; page++) {
+      const response = await this.fetchWithToken({
+        url: `${this.apiUrl}/allusers?page=${page}&page_size=200`,
+        options: {
+          method: 'GET',
+        },
+        token: token,
+        toastHeader: null,
+        setToast: null,
+      })
+      if (!response) {
+        return null
+      }
+      users.push(...response.users)
+      if (response.users.length === 0 || users.length >= response.total) {
+        return users
+      }
+    }
   }
 
   public async logout(token: string): Promise<null> {
@@ -444,7 +548,7 @@ class API {
     password: string,
     tfa: string | null,
     setToast: (toast: ToastDetails) => void
-  ): Promise<UserDetails | null> {
+  ): Promise<LoginResponse | null> {
     const response = await fetch(this.apiUrl + '/login', {
       method: 'POST',
       headers: {
@@ -453,16 +557,17 @@ class API {
       body: JSON.stringify({
         email: email,
         password: password,
-        tfa: tfa,
+        twofakey: tfa,
       }),
     })
 
     if (!response.ok) {
+      const apiError = await readApiError(response)
       setToast({
         show: true,
         success: false,
         header: 'Login',
-        text: 'Login failed',
+        text: apiError ? apiErrorText(apiError) : 'Login failed',
       })
       return null
     }
@@ -479,6 +584,11 @@ class API {
       return null
     }
 
+    // The login needs a 2FA code first, the login page asks for it.
+    if (responseJson.twofa_required) {
+      return responseJson
+    }
+
     setToast({
       show: true,
       success: true,
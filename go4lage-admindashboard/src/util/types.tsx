@@ -1,6 +1,7 @@
 export interface UserDetails {
   email: string | null
   token: string | null
+  refresh_token?: string | null
   is_superuser :boolean,
   is_organizationadmin: boolean,
   organization_name? : string | null,
@@ -16,6 +17,21 @@ export interface DashboardInfo {
   tfa: boolean
 }
 
+// What an authenticator app needs. qr_png is a base64 encoded PNG.
+export interface TwofactorEnrollment {
+  secret: string
+  otpauth_uri: string
+  qr_png: string
+}
+
+// The login answers with the user, or with a challenge if it needs a 2FA code.
+export interface LoginResponse extends UserDetails {
+  recovery_codes?: string[]
+  twofa_required?: boolean
+  twofa_enrollment_required?: boolean
+  twofa_enrollment?: TwofactorEnrollment
+}
+
 export interface OrganizationResponse {
   id: string
   created_at: number
@@ -120,4 +136,5 @@ export interface OrganizationT {
   organization_name: string
   email: string
   active_until: string
+  require_twofactor: boolean
 }
@@ -3,6 +3,7 @@ package main
 import (
 	"fmt"
 	"log"
+	"os"
 
 	"github.com/spf13/cobra"
 
@@ -36,6 +37,8 @@ Quickstart:
 		fmt.Println("setupgp")
 		fmt.Println("rungoose")
 		fmt.Println("createfakeusers")
+		fmt.Println("purgetrash")
+		fmt.Println("checktemplates")
 	},
 }
 
@@ -48,12 +51,15 @@ var setup = &cobra.Command{
 	},
 }
 
+var watchRoot bool
+
 var startServer = &cobra.Command{
 	Use:   "startserver",
 	Short: "Starts the server.",
-	Long:  `Starts the server.`,
+	Long: `Starts the server.
+	With --watch, or DEBUG=true, changes in root are served without a restart.`,
 	Run: func(cmd *cobra.Command, args []string) {
-		go4lage.StartServer()
+		go4lage.StartServer(watchRoot)
 	},
 }
 var createSuperuser = &cobra.Command{
@@ -67,14 +73,36 @@ var createSuperuser = &cobra.Command{
 
 var createFakeUsers = &cobra.Command{
 	Use:   "createfakeusers [count]",
-	Short: "Creates test users for development (password: 'test')",
-	Long:  `Creates test organizations and users for development/testing. All users have password 'test'.`,
+	Short: "Creates test users for development (password: 'Go4lage-Test-User')",
+	Long:  `Creates test organizations and users for development/testing. All users have password 'Go4lage-Test-User'.`,
 	Args:  cobra.ExactArgs(1),
 	Run: func(cmd *cobra.Command, args []string) {
 		utils.CreateFakeUsers(args[0])
 	},
 }
 
+var purgeTrash = &cobra.Command{
+	Use:   "purgetrash",
+	Short: "Deletes users and organizations for good that are in the trash longer than TRASH_RETENTION_DAYS.",
+	Long: `Deletes users and organizations for good that are in the trash longer than TRASH_RETENTION_DAYS.
+	Run it regularly, e.g. from a daily cron job.`,
+	Run: func(cmd *cobra.Command, args []string) {
+		utils.PurgeTrash()
+	},
+}
+
+var checkTemplates = &cobra.Command{
+	Use:   "checktemplates",
+	Short: "Checks the includes of root for missing components and cycles.",
+	Long: `Checks the includes of root for missing components and cycles, without starting the server.
+	It exits with 1 if there are problems, so it can run in CI.`,
+	Run: func(cmd *cobra.Command, args []string) {
+		if !utils.CheckRoot() {
+			os.Exit(1)
+		}
+	},
+}
+
 var rungoose = &cobra.Command{
 	Use:   "rungoose",
 	Short: "rungoose runs goose for database migrations",
@@ -86,11 +114,14 @@ var rungoose = &cobra.Command{
 }
 
 func init() {
+	startServer.Flags().BoolVar(&watchRoot, "watch", false, "Rebuild changed files of root while the server runs, for development.")
 	rootCmd.AddCommand(startServer)
 	rootCmd.AddCommand(rungoose)
 	rootCmd.AddCommand(createSuperuser)
 	rootCmd.AddCommand(setup)
 	rootCmd.AddCommand(createFakeUsers)
+	rootCmd.AddCommand(purgeTrash)
+	rootCmd.AddCommand(checkTemplates)
 }
 
 func main() {
@@ -0,0 +1,114 @@
+package admin
+
+import (
+	"context"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5/pgtype"
+	"github.com/karl1b/go4lage/pkg/sql/db"
+	utils "github.com/karl1b/go4lage/pkg/utils"
+)
+
+// AccessLogs lists the logged requests, newest first.
+// The query parameters method, route, status, user, organization, from and to filter, page and page_size paginate.
+func (app *App) AccessLogs(w http.ResponseWriter, r *http.Request) {
+	query := r.URL.Query()
+
+	var filter db.AccessLogCountParams
+	var err error
+
+	filter.UserID, err = queryUUID(query, "user")
+	if err == nil {
+		filter.OrganizationID, err = queryUUID(query, "organization")
+	}
+	if err == nil {
+		filter.CreatedFrom, err = queryTime(query, "from")
+	}
+	if err == nil {
+		filter.CreatedTo, err = queryTime(query, "to")
+	}
+	if err == nil && query.Get("status") != "" {
+		var status int
+		status, err = strconv.Atoi(query.Get("status"))
+		filter.Status = pgtype.Int4{Int32: int32(status), Valid: err == nil}
+	}
+	if err != nil {
+		utils.RespondWithError(w, utils.BadRequest("invalid_filter", "Error parsing filter", err))
+		return
+	}
+
+	if method := query.Get("method"); method != "" {
+		filter.Method = pgtype.Text{String: strings.ToUpper(method), Valid: true}
+	}
+	if route := query.Get("route"); route != "" {
+		filter.Route = pgtype.Text{String: route, Valid: true}
+	}
+
+	page, pageSize := queryPage(query)
+
+	total, err := app.Queries.AccessLogCount(context.Background(), filter)
+	if err != nil {
+		utils.RespondWithError(w, utils.DBError("Error counting access logs", err))
+		return
+	}
+
+	logs, err := app.Queries.AccessLogSelect(context.Background(), db.AccessLogSelectParams{
+		Method:         filter.Method,
+		Route:          filter.Route,
+		Status:         filter.Status,
+		UserID:         filter.UserID,
+		OrganizationID: filter.OrganizationID,
+		CreatedFrom:    filter.CreatedFrom,
+		CreatedTo:      filter.CreatedTo,
+		PageSize:       int32(pageSize),
+		PageOffset:     int32((page - 1) * pageSize),
+	})
+	if err != nil {
+		utils.RespondWithError(w, utils.DBError("Error getting access logs", err))
+		return
+	}
+
+	type LogResponse struct {
+		ID             uuid.UUID  `json:"id"`
+		CreatedAt      time.Time  `json:"created_at"`
+		Method         string     `json:"method"`
+		Route          string     `json:"route"`
+		Path           string     `json:"path"`
+		Status         int32      `json:"status"`
+		LatencyMs      float64    `json:"latency_ms"`
+		UserID         *uuid.UUID `json:"user_id"`
+		OrganizationID *uuid.UUID `json:"organization_id"`
+		RequestID      string     `json:"request_id"`
+		Ip             string     `json:"ip"`
+	}
+
+	type Response struct {
+		Logs     []LogResponse `json:"logs"`
+		Total    int64         `json:"total"`
+		Page     int           `json:"page"`
+		PageSize int           `json:"page_size"`
+	}
+
+	Answer := Response{Logs: []LogResponse{}, Total: total, Page: page, PageSize: pageSize}
+	for _, l := range logs {
+		Answer.Logs = append(Answer.Logs, LogResponse{
+			ID:             uuid.UUID(l.ID.Bytes),
+			CreatedAt:      l.CreatedAt.Time,
+			Method:         l.Method,
+			Route:          l.Route,
+			Path:           l.Path,
+			Status:         l.Status,
+			LatencyMs:      float64(l.LatencyUs) / 1000,
+			UserID:         optionalUUID(l.UserID),
+			OrganizationID: optionalUUID(l.OrganizationID),
+			RequestID:      l.RequestID.String,
+			Ip:             l.Ip.String,
+		})
+	}
+
+	utils.RespondWithJSON(w, Answer)
+}
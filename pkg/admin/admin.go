@@ -3,8 +3,10 @@ package admin
 import (
 	"context"
 	"encoding/json"
+	"errors"
 	"io"
 	"net/http"
+	"slices"
 
 	"github.com/google/uuid"
 	"github.com/jackc/pgx/v5/pgtype"
@@ -24,17 +26,27 @@ func (app *App) EditUserGroups(w http.ResponseWriter, r *http.Request) {
 	userid := r.Header.Get("Id")
 	useriduuid, err := uuid.Parse(userid)
 	if err != nil {
-		utils.RespondWithJSON(w, utils.ErrorResponse{
-			Detail: "Error getting parsing ID",
-			Error:  err.Error(),
-		})
+		utils.RespondWithError(w, utils.BadRequest("invalid_id", "Error getting parsing ID", err))
+
+		return
+	}
+	userID := pgtype.UUID{Bytes: useriduuid, Valid: true}
 
+	rinfo, ok := r.Context().Value(utils.InfoContextKey).(utils.InfoKey)
+	if !ok {
+		utils.RespondWithError(w, utils.Unauthorized("unauthenticated", "User not found in context", errors.New("user not found")))
+		return
+	}
+
+	err = app.canManageUser(rinfo, userID)
+	if err != nil {
+		utils.RespondWithError(w, utils.Forbidden("forbidden", "No permission to edit this user", err))
 		return
 	}
 
 	body, err := io.ReadAll(r.Body)
 	if err != nil {
-		http.Error(w, err.Error(), http.StatusInternalServerError)
+		utils.RespondWithError(w, utils.BadRequest("invalid_body", "Error reading body", err))
 
 		return
 	}
@@ -48,54 +60,94 @@ func (app *App) EditUserGroups(w http.ResponseWriter, r *http.Request) {
 	var reqBody []RequestBody
 	err = json.Unmarshal(body, &reqBody)
 	if err != nil {
-		http.Error(w, err.Error(), http.StatusBadRequest)
+		utils.RespondWithError(w, utils.BadRequest("invalid_body", "Invalid JSON format", err))
 
 		return
 	}
 
-	for _, g := range reqBody {
-
-		if g.Checked {
+	tx, qtx, err := app.beginTx()
+	if err != nil {
+		utils.RespondWithError(w, utils.DBError("Error starting transaction", err))
+		return
+	}
+	defer tx.Rollback(context.Background())
 
-			_, err = app.Queries.InsertUserGroupsByName(context.Background(), db.InsertUserGroupsByNameParams{
-				UserID: pgtype.UUID{Bytes: useriduuid, Valid: true},
+	before, organizationID, err := loadUserAudit(qtx, userID)
+	if err != nil {
+		utils.RespondWithError(w, utils.DBError("Error getting the user", err))
+		return
+	}
 
-				Name: g.Name,
+	// Only the differences are written, so a failing statement really is an error.
+	for _, g := range reqBody {
+		has := slices.Contains(before.Groups, g.Name)
+		switch {
+		case g.Checked && !has:
+			_, err = qtx.InsertUserGroupsByName(context.Background(), db.InsertUserGroupsByNameParams{
+				UserID: userID,
+				Name:   g.Name,
 			})
-			if err != nil {
-
-				continue
-			}
-		} else {
-			err = app.Queries.DeleteUserGroupsByName(context.Background(), db.DeleteUserGroupsByNameParams{
-				UserID: pgtype.UUID{Bytes: useriduuid, Valid: true},
+		case !g.Checked && has:
+			err = qtx.DeleteUserGroupsByName(context.Background(), db.DeleteUserGroupsByNameParams{
+				UserID: userID,
 				Name:   g.Name,
 			})
-			if err != nil {
-
-				continue
-			}
+		}
+		if err != nil {
+			utils.RespondWithError(w, utils.DBError("Error updating group "+g.Name, err))
+			return
 		}
 	}
 
+	after, _, err := loadUserAudit(qtx, userID)
+	if err != nil {
+		utils.RespondWithError(w, utils.DBError("Error getting the updated user", err))
+		return
+	}
+
+	err = writeUserAudit(qtx, r, rinfo.User.ID, AuditUserGroupsChanged, userID, organizationID, before, after)
+	if err != nil {
+		utils.RespondWithError(w, utils.Internal("audit_failed", "Error writing audit event", err))
+		return
+	}
+
+	err = tx.Commit(context.Background())
+	if err != nil {
+		utils.RespondWithError(w, utils.DBError("Error updating groups", err))
+		return
+	}
+
 	utils.RespondWithJSON(w, struct{}{})
 }
 
 func (app *App) EditUserPermissions(w http.ResponseWriter, r *http.Request) {
 	defer cache.NullGroupsAndPermissions()
+
 	userid := r.Header.Get("Id")
 	useriduuid, err := uuid.Parse(userid)
 	if err != nil {
-		utils.RespondWithJSON(w, utils.ErrorResponse{
-			Detail: "Error getting parsing ID",
-			Error:  err.Error(),
-		})
+		utils.RespondWithError(w, utils.BadRequest("invalid_id", "Error getting parsing ID", err))
+
+		return
+	}
+	userID := pgtype.UUID{Bytes: useriduuid, Valid: true}
+
+	rinfo, ok := r.Context().Value(utils.InfoContextKey).(utils.InfoKey)
+	if !ok {
+		utils.RespondWithError(w, utils.Unauthorized("unauthenticated", "User not found in context", errors.New("user not found")))
+		return
+	}
+
+	err = app.canManageUser(rinfo, userID)
+	if err != nil {
+		utils.RespondWithError(w, utils.Forbidden("forbidden", "No permission to edit this user", err))
 		return
 	}
 
 	body, err := io.ReadAll(r.Body)
 	if err != nil {
-		http.Error(w, err.Error(), http.StatusInternalServerError)
+		utils.RespondWithError(w, utils.BadRequest("invalid_body", "Error reading body", err))
+
 		return
 	}
 	defer r.Body.Close()
@@ -108,45 +160,71 @@ func (app *App) EditUserPermissions(w http.ResponseWriter, r *http.Request) {
 	var reqBody []RequestBody
 	err = json.Unmarshal(body, &reqBody)
 	if err != nil {
-		http.Error(w, err.Error(), http.StatusBadRequest)
+		utils.RespondWithError(w, utils.BadRequest("invalid_body", "Invalid JSON format", err))
+
 		return
 	}
 
-	for _, p := range reqBody {
+	tx, qtx, err := app.beginTx()
+	if err != nil {
+		utils.RespondWithError(w, utils.DBError("Error starting transaction", err))
+		return
+	}
+	defer tx.Rollback(context.Background())
 
-		if p.Checked {
-			_, err = app.Queries.InsertUserPermissionByName(context.Background(), db.InsertUserPermissionByNameParams{
-				UserID: pgtype.UUID{Bytes: useriduuid, Valid: true},
+	before, organizationID, err := loadUserAudit(qtx, userID)
+	if err != nil {
+		utils.RespondWithError(w, utils.DBError("Error getting the user", err))
+		return
+	}
+
+	// Only the differences are written, so a failing statement really is an error.
+	for _, p := range reqBody {
+		has := slices.Contains(before.Permissions, p.Name)
+		switch {
+		case p.Checked && !has:
+			_, err = qtx.InsertUserPermissionByName(context.Background(), db.InsertUserPermissionByNameParams{
+				UserID: userID,
 				Name:   p.Name,
 			})
-			if err != nil {
-
-				continue
-			}
-
-		} else {
-			err = app.Queries.DeleteUserPermissionByName(context.Background(), db.DeleteUserPermissionByNameParams{
-				UserID: pgtype.UUID{Bytes: useriduuid, Valid: true},
+		case !p.Checked && has:
+			err = qtx.DeleteUserPermissionByName(context.Background(), db.DeleteUserPermissionByNameParams{
+				UserID: userID,
 				Name:   p.Name,
 			})
-			if err != nil {
-				continue
-			}
+		}
+		if err != nil {
+			utils.RespondWithError(w, utils.DBError("Error updating permission "+p.Name, err))
+			return
 		}
 	}
 
-	utils.RespondWithJSON(w, struct{}{})
+	after, _, err := loadUserAudit(qtx, userID)
+	if err != nil {
+		utils.RespondWithError(w, utils.DBError("Error getting the updated user", err))
+		return
+	}
+
+	err = writeUserAudit(qtx, r, rinfo.User.ID, AuditUserPermissionsChanged, userID, organizationID, before, after)
+	if err != nil {
+		utils.RespondWithError(w, utils.Internal("audit_failed", "Error writing audit event", err))
+		return
+	}
+
+	err = tx.Commit(context.Background())
+	if err != nil {
+		utils.RespondWithError(w, utils.DBError("Error updating permissions", err))
+		return
+	}
 
+	utils.RespondWithJSON(w, struct{}{})
 }
 
 func (app *App) GetGroups(w http.ResponseWriter, _ *http.Request) {
 
 	groups, err := app.Queries.GetGroups(context.Background())
 	if err != nil {
-		utils.RespondWithJSON(w, utils.ErrorResponse{
-			Detail: "error getting all groups",
-			Error:  err.Error(),
-		})
+		utils.RespondWithError(w, utils.DBError("error getting all groups", err))
 		return
 	}
 
@@ -158,19 +236,13 @@ func (app *App) GetGroupById(w http.ResponseWriter, r *http.Request) {
 	groupId := r.Header.Get("Id")
 	groupiduuid, err := uuid.Parse(groupId)
 	if err != nil {
-		utils.RespondWithJSON(w, utils.ErrorResponse{
-			Detail: "Can not parse user ID",
-			Error:  err.Error(),
-		})
+		utils.RespondWithError(w, utils.BadRequest("invalid_id", "Can not parse user ID", err))
 		return
 	}
 
 	groups, err := app.Queries.GetGroupById(context.Background(), pgtype.UUID{Bytes: groupiduuid, Valid: true})
 	if err != nil {
-		utils.RespondWithJSON(w, utils.ErrorResponse{
-			Detail: "error getting all groups",
-			Error:  err.Error(),
-		})
+		utils.RespondWithError(w, utils.DBError("error getting all groups", err))
 		return
 	}
 
@@ -182,19 +254,13 @@ func (app *App) GetPermissionById(w http.ResponseWriter, r *http.Request) {
 	permissionId := r.Header.Get("Id")
 	permissioniduuid, err := uuid.Parse(permissionId)
 	if err != nil {
-		utils.RespondWithJSON(w, utils.ErrorResponse{
-			Detail: "Can not parse user ID",
-			Error:  err.Error(),
-		})
+		utils.RespondWithError(w, utils.BadRequest("invalid_id", "Can not parse user ID", err))
 		return
 	}
 
 	permission, err := app.Queries.GetPermissionById(context.Background(), pgtype.UUID{Bytes: permissioniduuid, Valid: true})
 	if err != nil {
-		utils.RespondWithJSON(w, utils.ErrorResponse{
-			Detail: "error getting permission by Id",
-			Error:  err.Error(),
-		})
+		utils.RespondWithError(w, utils.DBError("error getting permission by Id", err))
 		return
 	}
 
@@ -205,10 +271,7 @@ func (app *App) GetPermissions(w http.ResponseWriter, _ *http.Request) {
 
 	permissions, err := app.Queries.GetPermissions(context.Background())
 	if err != nil {
-		utils.RespondWithJSON(w, utils.ErrorResponse{
-			Detail: "error getting all permissions",
-			Error:  err.Error(),
-		})
+		utils.RespondWithError(w, utils.DBError("error getting all permissions", err))
 		return
 	}
 
@@ -220,10 +283,7 @@ func (app *App) GetPermissionsForGroup(w http.ResponseWriter, r *http.Request) {
 	groupId := r.Header.Get("Id")
 	groupiduuid, err := uuid.Parse(groupId)
 	if err != nil {
-		utils.RespondWithJSON(w, utils.ErrorResponse{
-			Detail: "Can not parse user ID",
-			Error:  err.Error(),
-		})
+		utils.RespondWithError(w, utils.BadRequest("invalid_id", "Can not parse user ID", err))
 		return
 	}
 
@@ -235,19 +295,13 @@ func (app *App) GetPermissionsForGroup(w http.ResponseWriter, r *http.Request) {
 
 	permissions, err := app.Queries.GetPermissions(context.Background())
 	if err != nil {
-		utils.RespondWithJSON(w, utils.ErrorResponse{
-			Detail: "Can not get permissions",
-			Error:  err.Error(),
-		})
+		utils.RespondWithError(w, utils.DBError("Can not get permissions", err))
 		return
 	}
 
 	permissionsForGroup, err := app.Queries.GetPermissionsByGroupId(context.Background(), pgtype.UUID{Bytes: groupiduuid, Valid: true})
 	if err != nil {
-		utils.RespondWithJSON(w, utils.ErrorResponse{
-			Detail: "Can not get permissions from db",
-			Error:  err.Error(),
-		})
+		utils.RespondWithError(w, utils.DBError("Can not get permissions from db", err))
 		return
 	}
 
@@ -272,10 +326,7 @@ func (app *App) GetUserGroups(w http.ResponseWriter, r *http.Request) {
 	userid := r.Header.Get("Id")
 	useriduuid, err := uuid.Parse(userid)
 	if err != nil {
-		utils.RespondWithJSON(w, utils.ErrorResponse{
-			Detail: "Can not parse user ID",
-			Error:  err.Error(),
-		})
+		utils.RespondWithError(w, utils.BadRequest("invalid_id", "Can not parse user ID", err))
 		return
 	}
 
@@ -288,19 +339,13 @@ func (app *App) GetUserGroups(w http.ResponseWriter, r *http.Request) {
 
 	groups, err := app.Queries.GetGroups(context.Background())
 	if err != nil {
-		utils.RespondWithJSON(w, utils.ErrorResponse{
-			Detail: "Can not get groups",
-			Error:  err.Error(),
-		})
+		utils.RespondWithError(w, utils.DBError("Can not get groups", err))
 		return
 	}
 
 	groupsForUser, err := app.Queries.GetGroupsByUserId(context.Background(), pgtype.UUID{Bytes: useriduuid, Valid: true})
 	if err != nil {
-		utils.RespondWithJSON(w, utils.ErrorResponse{
-			Detail: "Can not get groups from db",
-			Error:  err.Error(),
-		})
+		utils.RespondWithError(w, utils.DBError("Can not get groups from db", err))
 		return
 	}
 
@@ -326,10 +371,7 @@ func (app *App) GetUserPermissions(w http.ResponseWriter, r *http.Request) {
 	userid := r.Header.Get("Id")
 	useriduuid, err := uuid.Parse(userid)
 	if err != nil {
-		utils.RespondWithJSON(w, utils.ErrorResponse{
-			Detail: "Can not get permissions from db",
-			Error:  err.Error(),
-		})
+		utils.RespondWithError(w, utils.DBError("Can not get permissions from db", err))
 		return
 	}
 
@@ -342,19 +384,13 @@ func (app *App) GetUserPermissions(w http.ResponseWriter, r *http.Request) {
 
 	permissions, err := app.Queries.GetPermissions(context.Background())
 	if err != nil {
-		utils.RespondWithJSON(w, utils.ErrorResponse{
-			Detail: "Can not get permissions",
-			Error:  err.Error(),
-		})
+		utils.RespondWithError(w, utils.DBError("Can not get permissions", err))
 		return
 	}
 
 	permissionsForUser, err := app.Queries.GetPermissionsByUserId(context.Background(), pgtype.UUID{Bytes: useriduuid, Valid: true})
 	if err != nil {
-		utils.RespondWithJSON(w, utils.ErrorResponse{
-			Detail: "Can not get permissions from db",
-			Error:  err.Error(),
-		})
+		utils.RespondWithError(w, utils.DBError("Can not get permissions from db", err))
 		return
 	}
 
@@ -5,15 +5,20 @@ import (
 	"database/sql"
 	"encoding/json"
 	"errors"
+	"fmt"
 	"io"
+	"log"
 	"net/http"
+	"net/url"
 	"slices"
+	"strconv"
 	"strings"
 	"time"
 
 	"github.com/google/uuid"
 	"github.com/jackc/pgx/v5"
 	"github.com/jackc/pgx/v5/pgtype"
+	"github.com/jackc/pgx/v5/pgxpool"
 	cache "github.com/karl1b/go4lage/pkg/cache"
 	settings "github.com/karl1b/go4lage/pkg/settings"
 	"github.com/karl1b/go4lage/pkg/sql/db"
@@ -24,6 +29,7 @@ import (
 // App
 type App struct {
 	Queries *db.Queries
+	DB      *pgxpool.Pool // For queries that have to run in one transaction.
 }
 
 // Info about dashboard. Is 2FA for SU enabled?
@@ -40,18 +46,21 @@ func (app *App) Dashboardinfo(w http.ResponseWriter, _ *http.Request) {
 
 // Login Endpoint with Auth Throttle.
 func (app *App) Login(w http.ResponseWriter, r *http.Request) {
-	err := cache.Loginthrottler.Check(r.RemoteAddr) // Auth throttle
+	err := cache.Loginthrottler.Check(utils.ClientIP(r)) // Auth throttle
 	if err != nil {
-		utils.RespondWithJSON(w, utils.ErrorResponse{
-			Detail: "Auththrottle",
-			Error:  err.Error(),
-		})
+		utils.RespondWithError(w, utils.TooManyRequests("login_throttled", "Auththrottle", err))
+		return
+	}
+
+	err = cache.IPFailthrottler.Check(utils.ClientIP(r)) // Backoff after failed logins of this IP address
+	if err != nil {
+		utils.RespondWithError(w, utils.TooManyRequests("too_many_failed_logins", "Too many failed logins. Try again later.", err))
 		return
 	}
 
 	body, err := io.ReadAll(r.Body)
 	if err != nil {
-		http.Error(w, err.Error(), http.StatusInternalServerError)
+		utils.RespondWithError(w, utils.BadRequest("invalid_body", "Error reading body", err))
 		return
 	}
 	defer r.Body.Close()
@@ -65,63 +74,71 @@ func (app *App) Login(w http.ResponseWriter, r *http.Request) {
 	var reqBody RequestBody
 	err = json.Unmarshal(body, &reqBody)
 	if err != nil {
-		http.Error(w, err.Error(), http.StatusBadRequest)
+		utils.RespondWithError(w, utils.BadRequest("invalid_body", "Invalid JSON format", err))
 		return
 	}
 
 	type Response struct {
-		Token               string `json:"token"`
-		Email               string `json:"email"`
-		IsSuperuser         bool   `json:"is_superuser"`
-		IsOrganizationAdmin bool   `json:"is_organizationadmin"`
-		OrganizationName    string `json:"organization_name,omitzero"`
-		OrganizationId      string `json:"organization_id,omitzero"`
+		Token               string   `json:"token"`
+		RefreshToken        string   `json:"refresh_token"`
+		Email               string   `json:"email"`
+		IsSuperuser         bool     `json:"is_superuser"`
+		IsOrganizationAdmin bool     `json:"is_organizationadmin"`
+		OrganizationName    string   `json:"organization_name,omitzero"`
+		OrganizationId      string   `json:"organization_id,omitzero"`
+		RecoveryCodes       []string `json:"recovery_codes,omitempty"` // Only set if 2FA was enabled by this login.
 	}
 	var Answer Response
 
+	// Sent instead of the Response if the login needs a 2FA code.
+	type ChallengeResponse struct {
+		TwofaRequired           bool                 `json:"twofa_required"`
+		TwofaEnrollmentRequired bool                 `json:"twofa_enrollment_required"`
+		TwofaEnrollment         *TwofactorEnrollment `json:"twofa_enrollment,omitempty"`
+	}
+
 	user, err := app.Queries.SelectUserByEmail(context.Background(), strings.TrimSpace(strings.ToLower(reqBody.Email)))
 	if err != nil {
-		utils.RespondWithJSON(w, utils.ErrorResponse{Detail: "Select user by mail failed", Error: err.Error()})
+		cache.IPFailthrottler.Fail(utils.ClientIP(r))
+		utils.RespondWithError(w, utils.Unauthorized("login_failed", "Select user by mail failed", err))
+		return
+	}
+
+	// Backoff and lockout per account, so spreading an attack over many IP addresses does not help.
+	err = accountThrottled(user)
+	if err != nil {
+		utils.RespondWithError(w, utils.TooManyRequests("account_locked", "Too many failed logins. Try again later.", err))
 		return
 	}
 
 	err = utils.CompareHashAndPassword(user.Password, reqBody.Password)
 	if err != nil {
-		utils.RespondWithJSON(w, utils.ErrorResponse{
-			Detail: "Error comparing password",
-			Error:  err.Error(),
-		})
+		app.loginFailed(r, user)
+		utils.RespondWithError(w, utils.Unauthorized("login_failed", "Error comparing password", err))
 		return
 	}
 
 	if !user.IsActive.Bool && !user.IsSuperuser.Bool {
-		utils.RespondWithJSON(w, utils.ErrorResponse{Detail: "User is not active", Error: "user is not active"})
+		utils.RespondWithError(w, utils.Forbidden("user_inactive", "User is not active", errors.New("user is not active")))
 		return
 	}
 
-	Answer.Token = user.Token.String
+	app.rehashPassword(user, reqBody.Password)
+
 	Answer.Email = user.Email
 	Answer.IsSuperuser = user.IsSuperuser.Bool
 
-	if settings.Settings.Superuser2FA && user.IsSuperuser.Bool {
-		valid := totp.Validate(reqBody.Twofactorkey, user.Twofactorsecret.String)
-		if !(valid) {
-			utils.RespondWithJSON(w, utils.ErrorResponse{Detail: "2fa not valid", Error: "2fa not valid"})
-			return
-		}
-	}
-
 	var organization db.Organization
 
 	organization, err = app.Queries.OrganizationSelectUserOrganization(context.Background(), user.ID)
 	if err != nil {
 		if (errors.Is(err, sql.ErrNoRows) || errors.Is(err, pgx.ErrNoRows) || strings.Contains(err.Error(), "no rows in result set")) && user.IsSuperuser.Bool {
 
+		} else if errors.Is(err, sql.ErrNoRows) || errors.Is(err, pgx.ErrNoRows) {
+			utils.RespondWithError(w, utils.Forbidden("no_organization", "You are not in an organization", err))
+			return
 		} else {
-			utils.RespondWithJSON(w, utils.ErrorResponse{
-				Detail: "Error getting organization for user in middleware",
-				Error:  err.Error(),
-			})
+			utils.RespondWithError(w, utils.DBError("Error getting organization for user in middleware", err))
 			return
 		}
 	} else {
@@ -130,69 +147,195 @@ func (app *App) Login(w http.ResponseWriter, r *http.Request) {
 		Answer.OrganizationName = organization.OrganizationName
 	}
 
+	switch {
+	case user.TwofactorEnabled:
+		if reqBody.Twofactorkey == "" {
+			utils.RespondWithJSON(w, ChallengeResponse{TwofaRequired: true})
+			return
+		}
+		if !totp.Validate(reqBody.Twofactorkey, user.Twofactorsecret.String) {
+			// A recovery code is accepted in place of the TOTP code.
+			err = app.consumeRecoveryCode(r, user, organization, reqBody.Twofactorkey)
+			if err != nil {
+				app.loginFailed(r, user)
+				utils.RespondWithError(w, utils.Unauthorized("twofa_invalid", "2fa not valid", errors.New("2fa not valid")))
+				return
+			}
+		}
+	case utils.TwofactorMandatory(user, organization):
+		// The user has to enroll before the first login. The code of the pending secret completes the enrollment.
+		if reqBody.Twofactorkey == "" || user.Twofactorsecret.String == "" {
+			enrollment, err := app.startTwofactorEnrollment(user)
+			if err != nil {
+				utils.RespondWithError(w, utils.DBError("Error starting 2FA enrollment", err))
+				return
+			}
+			utils.RespondWithJSON(w, ChallengeResponse{TwofaRequired: true, TwofaEnrollmentRequired: true, TwofaEnrollment: &enrollment})
+			return
+		}
+		if !totp.Validate(reqBody.Twofactorkey, user.Twofactorsecret.String) {
+			app.loginFailed(r, user)
+			utils.RespondWithError(w, utils.Unauthorized("twofa_invalid", "2fa not valid", errors.New("2fa not valid")))
+			return
+		}
+		Answer.RecoveryCodes, err = app.enableTwofactor(user)
+		if err != nil {
+			utils.RespondWithError(w, utils.DBError("Error enabling 2FA", err))
+			return
+		}
+	}
+
 	groups, err := cache.GetGroupsByUser(user.ID, app.Queries)
 	if err != nil {
-		utils.RespondWithJSON(w, utils.ErrorResponse{
-			Detail: "Error getting groups for user in middleware",
-			Error:  err.Error(),
-		})
+		utils.RespondWithError(w, utils.DBError("Error getting groups for user in middleware", err))
 		return
 	}
 
 	Answer.IsOrganizationAdmin = slices.Contains(groups, utils.OrganizationAdminGroup)
 
-	if !user.Token.Valid || user.Token.String == "" || user.TokenCreatedAt.Time.Add(time.Duration(settings.Settings.UserTokenValidMins)*time.Minute).Before(time.Now()) || user.IsSuperuser.Bool {
-		newToken, err := utils.GenerateTokenHex(32)
-		if err != nil {
+	// Every login gets its own session, so logging in on one device does not affect the others.
+	expiredSessions, err := app.Queries.SessionDeleteExpiredByUserId(context.Background(), db.SessionDeleteExpiredByUserIdParams{
+		UserID:      user.ID,
+		RefreshedAt: pgtype.Timestamptz{Time: time.Now().Add(-time.Duration(refreshValidMins(user)) * time.Minute), Valid: true},
+	})
+	if err != nil {
+		utils.RespondWithError(w, utils.DBError("Error deleting expired sessions", err))
+		return
+	}
+	cache.DelSessions(expiredSessions)
 
-			utils.RespondWithJSON(w, utils.ErrorResponse{Detail: "Error logging in", Error: err.Error()})
-			return
-		}
+	newToken, newRefreshToken, err := newTokenPair()
+	if err != nil {
 
-		updatedUser, err := app.Queries.UpdateTokenByID(context.Background(), db.UpdateTokenByIDParams{
-			ID:    user.ID,
-			Token: pgtype.Text{String: newToken, Valid: true},
-		})
-		if err != nil {
+		utils.RespondWithError(w, utils.Internal("internal", "Error logging in", err))
+		return
+	}
 
-			utils.RespondWithJSON(w, utils.ErrorResponse{Detail: "Error writing updated user to database", Error: err.Error()})
-			return
+	_, err = app.Queries.SessionCreate(context.Background(), db.SessionCreateParams{
+		ID:               pgtype.UUID{Bytes: uuid.New(), Valid: true},
+		UserID:           user.ID,
+		TokenHash:        utils.HashToken(newToken),
+		UserAgent:        pgtype.Text{String: r.UserAgent(), Valid: true},
+		Ip:               pgtype.Text{String: utils.ClientIP(r), Valid: true},
+		RefreshTokenHash: pgtype.Text{String: utils.HashToken(newRefreshToken), Valid: true},
+	})
+	if err != nil {
+		utils.RespondWithError(w, utils.DBError("Error writing session to database", err))
+		return
+	}
 
-		}
-		Answer.Token = updatedUser.Token.String
+	_, err = app.Queries.UpdateLastLoginByID(context.Background(), user.ID)
+	if err != nil {
+		utils.RespondWithError(w, utils.DBError("Error updating last login time", err))
+		return
 	}
 
+	err = app.loginSucceeded(user)
+	if err != nil {
+		utils.RespondWithError(w, utils.DBError("Error resetting failed logins", err))
+		return
+	}
+	cache.Go4users.Del(user.ID.Bytes)
+
+	Answer.Token = newToken
+	Answer.RefreshToken = newRefreshToken
+
 	utils.RespondWithJSON(w, Answer)
 }
 
+// Replaces a hash of an older hasher or older parameters, while the plain password is at hand.
+// A failure only means the old hash stays, so it does not fail the login.
+func (app *App) rehashPassword(user db.User, password string) {
+	if !utils.PasswordNeedsRehash(user.Password) {
+		return
+	}
+	newHash, err := utils.HashPassword(password)
+	if err != nil {
+		log.Println("Error rehashing password:", err)
+		return
+	}
+	_, err = app.Queries.UserRehashPassword(context.Background(), db.UserRehashPasswordParams{
+		NewPassword: newHash,
+		ID:          user.ID,
+		OldPassword: user.Password,
+	})
+	if err != nil {
+		log.Println("Error rehashing password:", err)
+		return
+	}
+	cache.Go4users.Del(user.ID.Bytes) // The user is changed and hence needs to be deleted from cache.
+}
+
+// Ends the session of the request token. Other sessions of the user stay valid.
 func (app *App) Logout(w http.ResponseWriter, r *http.Request) {
 	infos, ok := r.Context().Value(utils.InfoContextKey).(utils.InfoKey)
-	user := infos.User
 	if !ok {
-		utils.RespondWithJSON(w, utils.ErrorResponse{
-			Detail: "Failed to get user from context",
-			Error:  "failed to get user from context",
-		})
+		utils.RespondWithError(w, utils.Unauthorized("unauthenticated", "Failed to get user from context", errors.New("failed to get user from context")))
 		return
 	}
 
-	_, err := app.Queries.UpdateTokenByID(context.Background(), db.UpdateTokenByIDParams{
-		ID:    user.ID,
-		Token: pgtype.Text{String: "", Valid: false},
-	})
+	session, err := app.Queries.SessionDeleteById(context.Background(), infos.Session.ID)
 	if err != nil {
-		utils.RespondWithJSON(w, utils.ErrorResponse{
-			Detail: "Failed to null token",
-			Error:  "failed to get user from context",
-		})
+		utils.RespondWithError(w, utils.DBError("Failed to delete session", err))
 		return
 	}
 
-	cache.Go4users.Del(user.Token.String) // The user is changed and hence needs to be deleted from cache.
+	cache.Go4sessions.Del(session.TokenHash) // The session is gone and hence needs to be deleted from cache.
 
-	utils.RespondWithJSON(w, utils.ErrorResponse{
-		Detail: "User token cleared",
-		Error:  "",
+	utils.RespondWithJSON(w, utils.ToastResponse{
+		Header: "Logout",
+		Text:   "User token cleared",
 	})
 
 }
+
+// Reads an optional UUID from the query parameters.
+func queryUUID(query url.Values, name string) (pgtype.UUID, error) {
+	value := query.Get(name)
+	if value == "" {
+		return pgtype.UUID{}, nil
+	}
+	id, err := uuid.Parse(value)
+	if err != nil {
+		return pgtype.UUID{}, fmt.Errorf("%s: %w", name, err)
+	}
+	return pgtype.UUID{Bytes: id, Valid: true}, nil
+}
+
+// Reads an optional RFC3339 time or date from the query parameters.
+func queryTime(query url.Values, name string) (pgtype.Timestamptz, error) {
+	value := query.Get(name)
+	if value == "" {
+		return pgtype.Timestamptz{}, nil
+	}
+	t, err := time.Parse(time.RFC3339, value)
+	if err != nil {
+		t, err = time.Parse(time.DateOnly, value)
+	}
+	if err != nil {
+		return pgtype.Timestamptz{}, fmt.Errorf("%s: %w", name, err)
+	}
+	return pgtype.Timestamptz{Time: t, Valid: true}, nil
+}
+
+// Reads page and page_size from the query parameters. The page size defaults to 50 and is 200 at most.
+func queryPage(query url.Values) (page int, pageSize int) {
+	page, err := strconv.Atoi(query.Get("page"))
+	if err != nil || page < 1 {
+		page = 1
+	}
+	pageSize, err = strconv.Atoi(query.Get("page_size"))
+	if err != nil || pageSize < 1 {
+		pageSize = 50
+	}
+	return page, min(pageSize, 200)
+}
+
+// Nil for an invalid UUID, so it ends up as null in JSON.
+func optionalUUID(id pgtype.UUID) *uuid.UUID {
+	if !id.Valid {
+		return nil
+	}
+	u := uuid.UUID(id.Bytes)
+	return &u
+}
@@ -0,0 +1,373 @@
+package admin
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"reflect"
+	"slices"
+	"time"
+
+	"github.com/go-chi/chi/middleware"
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgtype"
+	"github.com/karl1b/go4lage/pkg/sql/db"
+	utils "github.com/karl1b/go4lage/pkg/utils"
+)
+
+// Audit actions
+const (
+	AuditRecoveryCodeUsed       = "recoverycode.used"
+	AuditRefreshTokenReused     = "refreshtoken.reused"
+	AuditUserLocked             = "user.locked"
+	AuditUserUnlocked           = "user.unlocked"
+	AuditTwofactorReset         = "twofactor.reset"
+	AuditSessionRevoked         = "session.revoked"
+	AuditSessionsRevoked        = "sessions.revoked"
+	AuditUserCreated            = "user.created"
+	AuditUserUpdated            = "user.updated"
+	AuditUserDeleted            = "user.deleted"
+	AuditUserRestored           = "user.restored"
+	AuditUserGroupsChanged      = "user.groups"
+	AuditUserPermissionsChanged = "user.permissions"
+	AuditOrganizationCreated    = "organization.created"
+	AuditOrganizationUpdated    = "organization.updated"
+	AuditOrganizationDeleted    = "organization.deleted"
+	AuditOrganizationRestored   = "organization.restored"
+	AuditInvitationCreated      = "invitation.created"
+	AuditInvitationAccepted     = "invitation.accepted"
+	AuditInvitationRevoked      = "invitation.revoked"
+)
+
+// These fields are compared, but their values never end up in the log.
+var redactedAuditFields = []string{"password"}
+
+// auditEntry describes who did what to whom. Changes is stored as JSON.
+type auditEntry struct {
+	Actor        pgtype.UUID
+	Organization pgtype.UUID
+	Action       string
+	Target       pgtype.UUID
+	Changes      any
+}
+
+// Writes an audit event with the request id and IP of the request.
+// Pass the queries of the transaction that makes the change, so both are committed together.
+func writeAudit(queries *db.Queries, r *http.Request, entry auditEntry) error {
+	var changes []byte
+	if entry.Changes != nil {
+		var err error
+		changes, err = json.Marshal(entry.Changes)
+		if err != nil {
+			return err
+		}
+	}
+
+	requestID := middleware.GetReqID(r.Context())
+
+	_, err := queries.AuditCreate(context.Background(), db.AuditCreateParams{
+		ID:             pgtype.UUID{Bytes: uuid.New(), Valid: true},
+		ActorID:        entry.Actor,
+		OrganizationID: entry.Organization,
+		Action:         entry.Action,
+		TargetID:       entry.Target,
+		Changes:        changes,
+		RequestID:      pgtype.Text{String: requestID, Valid: requestID != ""},
+		Ip:             pgtype.Text{String: utils.ClientIP(r), Valid: true},
+	})
+	return err
+}
+
+// Begins a transaction. Make the change and write its audit event with the returned queries.
+func (app *App) beginTx() (pgx.Tx, *db.Queries, error) {
+	tx, err := app.DB.Begin(context.Background())
+	if err != nil {
+		return nil, nil, err
+	}
+	return tx, app.Queries.WithTx(tx), nil
+}
+
+// auditChange is one changed field. Redacted fields only tell that they changed.
+type auditChange struct {
+	Before   any  `json:"before,omitempty"`
+	After    any  `json:"after,omitempty"`
+	Redacted bool `json:"redacted,omitempty"`
+}
+
+// auditDiff compares the JSON fields of two states. A nil state stands for a created or deleted entity.
+func auditDiff(before any, after any) (map[string]auditChange, error) {
+	beforeFields, err := auditFields(before)
+	if err != nil {
+		return nil, err
+	}
+	afterFields, err := auditFields(after)
+	if err != nil {
+		return nil, err
+	}
+
+	changes := map[string]auditChange{}
+	for _, fields := range []map[string]any{beforeFields, afterFields} {
+		for key := range fields {
+			if _, done := changes[key]; done || reflect.DeepEqual(beforeFields[key], afterFields[key]) {
+				continue
+			}
+			if slices.Contains(redactedAuditFields, key) {
+				changes[key] = auditChange{Redacted: true}
+				continue
+			}
+			changes[key] = auditChange{Before: beforeFields[key], After: afterFields[key]}
+		}
+	}
+	return changes, nil
+}
+
+func auditFields(state any) (map[string]any, error) {
+	fields := map[string]any{}
+	if state == nil {
+		return fields, nil
+	}
+	if v := reflect.ValueOf(state); v.Kind() == reflect.Pointer && v.IsNil() {
+		return fields, nil
+	}
+	dat, err := json.Marshal(state)
+	if err != nil {
+		return nil, err
+	}
+	err = json.Unmarshal(dat, &fields)
+	return fields, err
+}
+
+// userAudit is the audited state of a user.
+type userAudit struct {
+	Username         string   `json:"username"`
+	Email            string   `json:"email"`
+	FirstName        string   `json:"first_name"`
+	LastName         string   `json:"last_name"`
+	Password         string   `json:"password"`
+	IsActive         bool     `json:"is_active"`
+	IsSuperuser      bool     `json:"is_superuser"`
+	TwofactorEnabled bool     `json:"twofactor_enabled"`
+	Organization     string   `json:"organization"`
+	Groups           []string `json:"groups"`
+	Permissions      []string `json:"permissions"` // Only the ones of the user, not the ones of its groups.
+}
+
+// Loads the audited state of a user together with the id of its organization.
+func loadUserAudit(queries *db.Queries, userID pgtype.UUID) (*userAudit, pgtype.UUID, error) {
+	user, err := queries.SelectUserById(context.Background(), userID)
+	if err != nil {
+		return nil, pgtype.UUID{}, err
+	}
+
+	state := userAudit{
+		Username:         user.Username,
+		Email:            user.Email,
+		FirstName:        user.FirstName.String,
+		LastName:         user.LastName.String,
+		Password:         user.Password,
+		IsActive:         user.IsActive.Bool,
+		IsSuperuser:      user.IsSuperuser.Bool,
+		TwofactorEnabled: user.TwofactorEnabled,
+		Groups:           []string{},
+		Permissions:      []string{},
+	}
+
+	groups, err := queries.GetGroupsByUserId(context.Background(), userID)
+	if err != nil {
+		return nil, pgtype.UUID{}, err
+	}
+	for _, g := range groups {
+		state.Groups = append(state.Groups, g.Name)
+	}
+	slices.Sort(state.Groups)
+
+	permissions, err := queries.GetPurePermissionsByUserId(context.Background(), userID)
+	if err != nil {
+		return nil, pgtype.UUID{}, err
+	}
+	for _, p := range permissions {
+		state.Permissions = append(state.Permissions, p.Name)
+	}
+	slices.Sort(state.Permissions)
+
+	organizationID, err := userOrganizationID(queries, userID)
+	if err != nil {
+		return nil, pgtype.UUID{}, err
+	}
+	if organizationID.Valid {
+		state.Organization = uuid.UUID(organizationID.Bytes).String()
+	}
+
+	return &state, organizationID, nil
+}
+
+// The id of the organization of a user, not valid if the user has none.
+func userOrganizationID(queries *db.Queries, userID pgtype.UUID) (pgtype.UUID, error) {
+	organization, err := queries.OrganizationSelectUserOrganization(context.Background(), userID)
+	if err != nil && !errors.Is(err, pgx.ErrNoRows) && !errors.Is(err, sql.ErrNoRows) {
+		return pgtype.UUID{}, err
+	}
+	return organization.ID, nil
+}
+
+// Writes the audit event of a user change.
+// The event belongs to the organization of the user, so the admins of that organization see it.
+func writeUserAudit(queries *db.Queries, r *http.Request, actor pgtype.UUID, action string, userID pgtype.UUID, organization pgtype.UUID, before *userAudit, after *userAudit) error {
+	changes, err := auditDiff(before, after)
+	if err != nil {
+		return err
+	}
+	return writeAudit(queries, r, auditEntry{
+		Actor:        actor,
+		Organization: organization,
+		Action:       action,
+		Target:       userID,
+		Changes:      changes,
+	})
+}
+
+// organizationAudit is the audited state of an organization.
+type organizationAudit struct {
+	OrganizationName string `json:"organization_name"`
+	Email            string `json:"email"`
+	ActiveUntil      string `json:"active_until"`
+	RequireTwofactor bool   `json:"require_twofactor"`
+}
+
+func newOrganizationAudit(organization db.Organization) *organizationAudit {
+	return &organizationAudit{
+		OrganizationName: organization.OrganizationName,
+		Email:            organization.Email,
+		ActiveUntil:      organization.ActiveUntil.Time.UTC().Format(time.RFC3339),
+		RequireTwofactor: organization.RequireTwofactor,
+	}
+}
+
+// Writes the audit event of an organization change.
+func writeOrganizationAudit(queries *db.Queries, r *http.Request, actor pgtype.UUID, action string, organizationID pgtype.UUID, before *organizationAudit, after *organizationAudit) error {
+	changes, err := auditDiff(before, after)
+	if err != nil {
+		return err
+	}
+	entry := auditEntry{
+		Actor:        actor,
+		Organization: organizationID,
+		Action:       action,
+		Target:       organizationID,
+		Changes:      changes,
+	}
+	if action == AuditOrganizationDeleted {
+		entry.Organization = pgtype.UUID{} // The event outlives the organization.
+	}
+	return writeAudit(queries, r, entry)
+}
+
+// AuditLog lists the audit events, newest first.
+// Organization admins only see the events of their organization. Superusers see all of them or filter by organization.
+// The query parameters organization, actor, target, action, from and to filter, page and page_size paginate.
+func (app *App) AuditLog(w http.ResponseWriter, r *http.Request) {
+	rinfo, ok := r.Context().Value(utils.InfoContextKey).(utils.InfoKey)
+	if !ok {
+		utils.RespondWithError(w, utils.Unauthorized("unauthenticated", "User not found in context", errors.New("user not found")))
+		return
+	}
+
+	query := r.URL.Query()
+
+	var filter db.AuditCountParams
+	var err error
+
+	filter.OrganizationID, err = queryUUID(query, "organization")
+	if err == nil {
+		filter.ActorID, err = queryUUID(query, "actor")
+	}
+	if err == nil {
+		filter.TargetID, err = queryUUID(query, "target")
+	}
+	if err == nil {
+		filter.CreatedFrom, err = queryTime(query, "from")
+	}
+	if err == nil {
+		filter.CreatedTo, err = queryTime(query, "to")
+	}
+	if err != nil {
+		utils.RespondWithError(w, utils.BadRequest("invalid_filter", "Error parsing filter", err))
+		return
+	}
+
+	if action := query.Get("action"); action != "" {
+		filter.Action = pgtype.Text{String: action, Valid: true}
+	}
+
+	if !rinfo.User.IsSuperuser.Bool {
+		filter.OrganizationID = rinfo.Organization.ID
+	}
+
+	page, pageSize := queryPage(query)
+
+	total, err := app.Queries.AuditCount(context.Background(), filter)
+	if err != nil {
+		utils.RespondWithError(w, utils.DBError("Error counting audit events", err))
+		return
+	}
+
+	events, err := app.Queries.AuditSelect(context.Background(), db.AuditSelectParams{
+		OrganizationID: filter.OrganizationID,
+		ActorID:        filter.ActorID,
+		TargetID:       filter.TargetID,
+		Action:         filter.Action,
+		CreatedFrom:    filter.CreatedFrom,
+		CreatedTo:      filter.CreatedTo,
+		PageSize:       int32(pageSize),
+		PageOffset:     int32((page - 1) * pageSize),
+	})
+	if err != nil {
+		utils.RespondWithError(w, utils.DBError("Error getting audit events", err))
+		return
+	}
+
+	type EventResponse struct {
+		ID             uuid.UUID       `json:"id"`
+		CreatedAt      time.Time       `json:"created_at"`
+		ActorID        *uuid.UUID      `json:"actor_id"`
+		ActorEmail     string          `json:"actor_email"`
+		OrganizationID *uuid.UUID      `json:"organization_id"`
+		Action         string          `json:"action"`
+		TargetID       *uuid.UUID      `json:"target_id"`
+		Changes        json.RawMessage `json:"changes"`
+		RequestID      string          `json:"request_id"`
+		Ip             string          `json:"ip"`
+	}
+
+	type Response struct {
+		Events   []EventResponse `json:"events"`
+		Total    int64           `json:"total"`
+		Page     int             `json:"page"`
+		PageSize int             `json:"page_size"`
+	}
+
+	Answer := Response{Events: []EventResponse{}, Total: total, Page: page, PageSize: pageSize}
+	for _, e := range events {
+		changes := json.RawMessage(e.Changes)
+		if len(changes) == 0 {
+			changes = json.RawMessage("null")
+		}
+		Answer.Events = append(Answer.Events, EventResponse{
+			ID:             uuid.UUID(e.ID.Bytes),
+			CreatedAt:      e.CreatedAt.Time,
+			ActorID:        optionalUUID(e.ActorID),
+			ActorEmail:     e.ActorEmail.String,
+			OrganizationID: optionalUUID(e.OrganizationID),
+			Action:         e.Action,
+			TargetID:       optionalUUID(e.TargetID),
+			Changes:        changes,
+			RequestID:      e.RequestID.String,
+			Ip:             e.Ip.String,
+		})
+	}
+
+	utils.RespondWithJSON(w, Answer)
+}
@@ -3,6 +3,7 @@ package admin
 import (
 	"context"
 	"encoding/json"
+	"errors"
 	"io"
 	"net/http"
 	"time"
@@ -24,7 +25,7 @@ func (app *App) AllFeedBack(w http.ResponseWriter, _ *http.Request) {
 
 	allFeedBack, err := app.Queries.FeedBackGetAll(context.Background())
 	if err != nil {
-		utils.RespondWithJSON(w, utils.ErrorResponse{Detail: "Error getting all feedback", Error: err.Error()})
+		utils.RespondWithError(w, utils.DBError("Error getting all feedback", err))
 		return
 	}
 
@@ -37,16 +38,13 @@ func (app *App) NewFeedBack(w http.ResponseWriter, r *http.Request) {
 	requestUser := infos.User
 
 	if !ok {
-		utils.RespondWithJSON(w, utils.ErrorResponse{
-			Detail: "Failed to get user from context",
-			Error:  "failed to get user from context",
-		})
+		utils.RespondWithError(w, utils.Unauthorized("unauthenticated", "Failed to get user from context", errors.New("failed to get user from context")))
 		return
 	}
 
 	body, err := io.ReadAll(r.Body)
 	if err != nil {
-		http.Error(w, err.Error(), http.StatusInternalServerError)
+		utils.RespondWithError(w, utils.BadRequest("invalid_body", "Error reading body", err))
 		return
 	}
 	defer r.Body.Close()
@@ -60,7 +58,7 @@ func (app *App) NewFeedBack(w http.ResponseWriter, r *http.Request) {
 	var reqBody RequestBody
 	err = json.Unmarshal(body, &reqBody)
 	if err != nil {
-		http.Error(w, err.Error(), http.StatusBadRequest)
+		utils.RespondWithError(w, utils.BadRequest("invalid_body", "Invalid JSON format", err))
 		return
 	}
 
@@ -85,7 +83,7 @@ func (app *App) NewFeedBack(w http.ResponseWriter, r *http.Request) {
 		Chat: pgtype.Text{},
 	})
 	if err != nil {
-		utils.RespondWithJSON(w, utils.ErrorResponse{Detail: "error creating feedback", Error: err.Error()})
+		utils.RespondWithError(w, utils.DBError("error creating feedback", err))
 	}
 
 	utils.RespondWithJSON(w, feedBack)
@@ -96,19 +94,13 @@ func (app *App) GetUserSpecificFeedBack(w http.ResponseWriter, r *http.Request)
 	infos, ok := r.Context().Value(utils.InfoContextKey).(utils.InfoKey)
 	requestUser := infos.User
 	if !ok {
-		utils.RespondWithJSON(w, utils.ErrorResponse{
-			Detail: "Failed to get user from context",
-			Error:  "failed to get user from context",
-		})
+		utils.RespondWithError(w, utils.Unauthorized("unauthenticated", "Failed to get user from context", errors.New("failed to get user from context")))
 		return
 	}
 
 	userFeedback, err := app.Queries.FeedBackGetByUserId(context.Background(), requestUser.ID)
 	if err != nil {
-		utils.RespondWithJSON(w, utils.ErrorResponse{
-			Detail: "Error getting user feedback",
-			Error:  err.Error(),
-		})
+		utils.RespondWithError(w, utils.DBError("Error getting user feedback", err))
 		return
 	}
 
@@ -121,16 +113,13 @@ func (app *App) UpdateFeedBackUser(w http.ResponseWriter, r *http.Request) {
 	infos, ok := r.Context().Value(utils.InfoContextKey).(utils.InfoKey)
 	requestUser := infos.User
 	if !ok {
-		utils.RespondWithJSON(w, utils.ErrorResponse{
-			Detail: "Failed to get user from context",
-			Error:  "failed to get user from context",
-		})
+		utils.RespondWithError(w, utils.Unauthorized("unauthenticated", "Failed to get user from context", errors.New("failed to get user from context")))
 		return
 	}
 
 	body, err := io.ReadAll(r.Body)
 	if err != nil {
-		http.Error(w, err.Error(), http.StatusInternalServerError)
+		utils.RespondWithError(w, utils.BadRequest("invalid_body", "Error reading body", err))
 		return
 	}
 	defer r.Body.Close()
@@ -143,16 +132,13 @@ func (app *App) UpdateFeedBackUser(w http.ResponseWriter, r *http.Request) {
 	var reqBody RequestBody
 	err = json.Unmarshal(body, &reqBody)
 	if err != nil {
-		http.Error(w, err.Error(), http.StatusBadRequest)
+		utils.RespondWithError(w, utils.BadRequest("invalid_body", "Invalid JSON format", err))
 		return
 	}
 
 	feedbackID, err := uuid.Parse(reqBody.ID)
 	if err != nil {
-		utils.RespondWithJSON(w, utils.ErrorResponse{
-			Detail: "Invalid feedback ID format",
-			Error:  err.Error(),
-		})
+		utils.RespondWithError(w, utils.BadRequest("invalid_id", "Invalid feedback ID format", err))
 		return
 	}
 
@@ -162,19 +148,13 @@ func (app *App) UpdateFeedBackUser(w http.ResponseWriter, r *http.Request) {
 		Valid: true,
 	})
 	if err != nil {
-		utils.RespondWithJSON(w, utils.ErrorResponse{
-			Detail: "Error getting feedback",
-			Error:  err.Error(),
-		})
+		utils.RespondWithError(w, utils.DBError("Error getting feedback", err))
 		return
 	}
 
 	// Check if user owns this feedback
 	if existingFeedback.CreatedBy.Bytes != requestUser.ID.Bytes {
-		utils.RespondWithJSON(w, utils.ErrorResponse{
-			Detail: "Permission denied",
-			Error:  "you can only update your own feedback",
-		})
+		utils.RespondWithError(w, utils.Forbidden("forbidden", "Permission denied", errors.New("you can only update your own feedback")))
 		return
 	}
 
@@ -183,10 +163,7 @@ func (app *App) UpdateFeedBackUser(w http.ResponseWriter, r *http.Request) {
 	if existingFeedback.Chat.Valid && existingFeedback.Chat.String != "" {
 		err = json.Unmarshal([]byte(existingFeedback.Chat.String), &chatMessages)
 		if err != nil {
-			utils.RespondWithJSON(w, utils.ErrorResponse{
-				Detail: "Error parsing existing chat",
-				Error:  err.Error(),
-			})
+			utils.RespondWithError(w, utils.Internal("internal", "Error parsing existing chat", err))
 			return
 		}
 	}
@@ -202,10 +179,7 @@ func (app *App) UpdateFeedBackUser(w http.ResponseWriter, r *http.Request) {
 	// Convert back to JSON
 	updatedChatJSON, err := json.Marshal(chatMessages)
 	if err != nil {
-		utils.RespondWithJSON(w, utils.ErrorResponse{
-			Detail: "Error creating chat JSON",
-			Error:  err.Error(),
-		})
+		utils.RespondWithError(w, utils.Internal("internal", "Error creating chat JSON", err))
 		return
 	}
 
@@ -213,10 +187,7 @@ func (app *App) UpdateFeedBackUser(w http.ResponseWriter, r *http.Request) {
 	var validationCheck []ChatMessage
 	err = json.Unmarshal(updatedChatJSON, &validationCheck)
 	if err != nil {
-		utils.RespondWithJSON(w, utils.ErrorResponse{
-			Detail: "Invalid JSON format",
-			Error:  err.Error(),
-		})
+		utils.RespondWithError(w, utils.BadRequest("invalid_body", "Invalid JSON format", err))
 		return
 	}
 
@@ -232,10 +203,7 @@ func (app *App) UpdateFeedBackUser(w http.ResponseWriter, r *http.Request) {
 		},
 	})
 	if err != nil {
-		utils.RespondWithJSON(w, utils.ErrorResponse{
-			Detail: "Error updating feedback",
-			Error:  err.Error(),
-		})
+		utils.RespondWithError(w, utils.DBError("Error updating feedback", err))
 		return
 	}
 
@@ -248,16 +216,13 @@ func (app *App) UpdateFeedBackStaff(w http.ResponseWriter, r *http.Request) {
 	requestUser := infos.User
 
 	if !ok {
-		utils.RespondWithJSON(w, utils.ErrorResponse{
-			Detail: "Failed to get user from context",
-			Error:  "failed to get user from context",
-		})
+		utils.RespondWithError(w, utils.Unauthorized("unauthenticated", "Failed to get user from context", errors.New("failed to get user from context")))
 		return
 	}
 
 	body, err := io.ReadAll(r.Body)
 	if err != nil {
-		http.Error(w, err.Error(), http.StatusInternalServerError)
+		utils.RespondWithError(w, utils.BadRequest("invalid_body", "Error reading body", err))
 		return
 	}
 	defer r.Body.Close()
@@ -271,16 +236,13 @@ func (app *App) UpdateFeedBackStaff(w http.ResponseWriter, r *http.Request) {
 	var reqBody RequestBody
 	err = json.Unmarshal(body, &reqBody)
 	if err != nil {
-		http.Error(w, err.Error(), http.StatusBadRequest)
+		utils.RespondWithError(w, utils.BadRequest("invalid_body", "Invalid JSON format", err))
 		return
 	}
 
 	feedbackID, err := uuid.Parse(reqBody.ID)
 	if err != nil {
-		utils.RespondWithJSON(w, utils.ErrorResponse{
-			Detail: "Invalid feedback ID format",
-			Error:  err.Error(),
-		})
+		utils.RespondWithError(w, utils.BadRequest("invalid_id", "Invalid feedback ID format", err))
 		return
 	}
 
@@ -290,10 +252,7 @@ func (app *App) UpdateFeedBackStaff(w http.ResponseWriter, r *http.Request) {
 		Valid: true,
 	})
 	if err != nil {
-		utils.RespondWithJSON(w, utils.ErrorResponse{
-			Detail: "Error getting feedback",
-			Error:  err.Error(),
-		})
+		utils.RespondWithError(w, utils.DBError("Error getting feedback", err))
 		return
 	}
 
@@ -306,10 +265,7 @@ func (app *App) UpdateFeedBackStaff(w http.ResponseWriter, r *http.Request) {
 		if existingFeedback.Chat.Valid && existingFeedback.Chat.String != "" {
 			err = json.Unmarshal([]byte(existingFeedback.Chat.String), &chatMessages)
 			if err != nil {
-				utils.RespondWithJSON(w, utils.ErrorResponse{
-					Detail: "Error parsing existing chat",
-					Error:  err.Error(),
-				})
+				utils.RespondWithError(w, utils.Internal("internal", "Error parsing existing chat", err))
 				return
 			}
 		}
@@ -325,10 +281,7 @@ func (app *App) UpdateFeedBackStaff(w http.ResponseWriter, r *http.Request) {
 		// Convert back to JSON
 		updatedChatJSON, err := json.Marshal(chatMessages)
 		if err != nil {
-			utils.RespondWithJSON(w, utils.ErrorResponse{
-				Detail: "Error creating chat JSON",
-				Error:  err.Error(),
-			})
+			utils.RespondWithError(w, utils.Internal("internal", "Error creating chat JSON", err))
 			return
 		}
 
@@ -336,10 +289,7 @@ func (app *App) UpdateFeedBackStaff(w http.ResponseWriter, r *http.Request) {
 		var validationCheck []ChatMessage
 		err = json.Unmarshal(updatedChatJSON, &validationCheck)
 		if err != nil {
-			utils.RespondWithJSON(w, utils.ErrorResponse{
-				Detail: "Invalid JSON format",
-				Error:  err.Error(),
-			})
+			utils.RespondWithError(w, utils.BadRequest("invalid_body", "Invalid JSON format", err))
 			return
 		}
 
@@ -355,10 +305,7 @@ func (app *App) UpdateFeedBackStaff(w http.ResponseWriter, r *http.Request) {
 			},
 		})
 		if err != nil {
-			utils.RespondWithJSON(w, utils.ErrorResponse{
-				Detail: "Error updating feedback chat",
-				Error:  err.Error(),
-			})
+			utils.RespondWithError(w, utils.DBError("Error updating feedback chat", err))
 			return
 		}
 	}
@@ -377,10 +324,7 @@ func (app *App) UpdateFeedBackStaff(w http.ResponseWriter, r *http.Request) {
 			})
 		}
 		if err != nil {
-			utils.RespondWithJSON(w, utils.ErrorResponse{
-				Detail: "Error updating feedback status",
-				Error:  err.Error(),
-			})
+			utils.RespondWithError(w, utils.DBError("Error updating feedback status", err))
 			return
 		}
 	}
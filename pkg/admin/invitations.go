@@ -0,0 +1,504 @@
+package admin
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/binary"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5/pgtype"
+	cache "github.com/karl1b/go4lage/pkg/cache"
+	mail "github.com/karl1b/go4lage/pkg/mail"
+	settings "github.com/karl1b/go4lage/pkg/settings"
+	"github.com/karl1b/go4lage/pkg/sql/db"
+	utils "github.com/karl1b/go4lage/pkg/utils"
+	"github.com/pquerna/otp/totp"
+)
+
+/*
+Invitations let the invited user choose the password, so no admin ever knows it.
+The invited user is inactive and has no usable password until the invitation is accepted.
+The token is signed with SECRET_KEY and carries its expiry. Only its digest is stored, so it can be revoked.
+*/
+
+// Checks that invitations can be signed before anything is created.
+func checkInvitations() error {
+	if settings.Settings.SecretKey == "" {
+		return utils.Internal("invitations_disabled", "Invitations need a SECRET_KEY", utils.ErrNoSecretKey)
+	}
+	return nil
+}
+
+// The payload of a token is the id of the invitation, its expiry and a nonce.
+func newInvitationToken(id uuid.UUID, expiresAt time.Time) (string, error) {
+	payload := make([]byte, 40)
+	copy(payload, id[:])
+	binary.BigEndian.PutUint64(payload[16:24], uint64(expiresAt.Unix()))
+	_, err := rand.Read(payload[24:])
+	if err != nil {
+		return "", err
+	}
+	return utils.SignToken(payload)
+}
+
+func parseInvitationToken(token string) (uuid.UUID, time.Time, error) {
+	payload, err := utils.VerifyToken(token)
+	if err != nil {
+		return uuid.UUID{}, time.Time{}, err
+	}
+	if len(payload) != 40 {
+		return uuid.UUID{}, time.Time{}, utils.ErrInvalidSignature
+	}
+	id, err := uuid.FromBytes(payload[:16])
+	if err != nil {
+		return uuid.UUID{}, time.Time{}, err
+	}
+	return id, time.Unix(int64(binary.BigEndian.Uint64(payload[16:24])), 0), nil
+}
+
+// pendingInvitation is a created invitation whose mail is not sent yet.
+type pendingInvitation struct {
+	user      db.User
+	token     string
+	expiresAt time.Time
+}
+
+// Creates the invitation of a new user and writes its audit event.
+// Pass the queries of the transaction that creates the user and send the mail after the commit.
+func createInvitation(queries *db.Queries, r *http.Request, inviter pgtype.UUID, user db.User, organizationID pgtype.UUID) (pendingInvitation, error) {
+	id := uuid.New()
+	expiresAt := time.Now().Add(time.Duration(settings.Settings.InvitationValidHours) * time.Hour)
+
+	token, err := newInvitationToken(id, expiresAt)
+	if err != nil {
+		return pendingInvitation{}, err
+	}
+
+	_, err = queries.InvitationCreate(context.Background(), db.InvitationCreateParams{
+		ID:             pgtype.UUID{Bytes: id, Valid: true},
+		ExpiresAt:      pgtype.Timestamptz{Time: expiresAt, Valid: true},
+		UserID:         user.ID,
+		OrganizationID: organizationID,
+		InvitedBy:      inviter,
+		TokenHash:      utils.HashToken(token),
+	})
+	if err != nil {
+		return pendingInvitation{}, err
+	}
+
+	err = writeAudit(queries, r, auditEntry{
+		Actor:        inviter,
+		Organization: organizationID,
+		Action:       AuditInvitationCreated,
+		Target:       user.ID,
+		Changes:      map[string]string{"invitation": id.String(), "expires_at": expiresAt.UTC().Format(time.RFC3339)},
+	})
+	if err != nil {
+		return pendingInvitation{}, err
+	}
+
+	return pendingInvitation{user: user, token: token, expiresAt: expiresAt}, nil
+}
+
+// Mails the invitation link with the configured mailer.
+func (i pendingInvitation) send() error {
+	baseurl := strings.TrimSpace(settings.Settings.Baseurl)
+	text := fmt.Sprintf(`Hello %s,
+
+you were invited to %s.
+Open the link below to choose your password. It is valid until %s and can only be used once.
+
+%s/acceptinvitation?token=%s
+
+If you did not expect this invitation, you can ignore this mail.`,
+		i.user.Username, baseurl, i.expiresAt.UTC().Format("2006-01-02 15:04 MST"), baseurl, i.token)
+
+	return mail.Send(i.user.Email, "Invitation", text)
+}
+
+// Loads the open invitation of a token. Every error is an APIError.
+func (app *App) openInvitation(token string) (db.Invitation, error) {
+	id, expiresAt, err := parseInvitationToken(token)
+	if errors.Is(err, utils.ErrNoSecretKey) {
+		return db.Invitation{}, checkInvitations()
+	}
+	if err != nil {
+		return db.Invitation{}, utils.Unprocessable("invitation_invalid", "Invitation not valid", err)
+	}
+	if time.Now().After(expiresAt) {
+		return db.Invitation{}, utils.Unprocessable("invitation_expired", "Invitation expired. Ask for a new one.", errors.New("invitation token outdated"))
+	}
+
+	invitation, err := app.Queries.InvitationSelectByTokenHash(context.Background(), utils.HashToken(token))
+	if err != nil {
+		return db.Invitation{}, utils.Unprocessable("invitation_invalid", "Invitation not valid", err)
+	}
+	if invitation.ID.Bytes != id || invitation.AcceptedAt.Valid || invitation.RevokedAt.Valid {
+		return db.Invitation{}, utils.Unprocessable("invitation_invalid", "Invitation not valid", errors.New("invitation is accepted or revoked"))
+	}
+	return invitation, nil
+}
+
+// Reads the token of the public invitation endpoints. Failed tries count like failed logins.
+func (app *App) readInvitationRequest(w http.ResponseWriter, r *http.Request, reqBody any) bool {
+	err := cache.Loginthrottler.Check(utils.ClientIP(r)) // Auth throttle
+	if err != nil {
+		utils.RespondWithError(w, utils.TooManyRequests("login_throttled", "Auththrottle", err))
+		return false
+	}
+
+	err = cache.IPFailthrottler.Check(utils.ClientIP(r))
+	if err != nil {
+		utils.RespondWithError(w, utils.TooManyRequests("too_many_failed_logins", "Too many failed tries. Try again later.", err))
+		return false
+	}
+
+	body, err := io.ReadAll(r.Body)
+	if err != nil {
+		utils.RespondWithError(w, utils.BadRequest("invalid_body", "Error reading body", err))
+		return false
+	}
+	defer r.Body.Close()
+
+	err = json.Unmarshal(body, reqBody)
+	if err != nil {
+		utils.RespondWithError(w, utils.BadRequest("invalid_body", "Invalid JSON format", err))
+		return false
+	}
+	return true
+}
+
+// InvitationInfo tells the invited user who is invited where, before the invitation is accepted.
+func (app *App) InvitationInfo(w http.ResponseWriter, r *http.Request) {
+	type RequestBody struct {
+		Token string `json:"token"`
+	}
+	var reqBody RequestBody
+	if !app.readInvitationRequest(w, r, &reqBody) {
+		return
+	}
+
+	invitation, err := app.openInvitation(reqBody.Token)
+	if err != nil {
+		cache.IPFailthrottler.Fail(utils.ClientIP(r))
+		utils.RespondWithError(w, err)
+		return
+	}
+
+	user, err := app.Queries.SelectUserById(context.Background(), invitation.UserID)
+	if err != nil {
+		utils.RespondWithError(w, utils.DBError("Error getting the invited user", err))
+		return
+	}
+
+	var organization db.Organization
+	if invitation.OrganizationID.Valid {
+		organization, err = app.Queries.OrganizationSelectById(context.Background(), invitation.OrganizationID)
+		if err != nil {
+			utils.RespondWithError(w, utils.DBError("Error getting the organization", err))
+			return
+		}
+	}
+
+	type Response struct {
+		Email            string    `json:"email"`
+		FirstName        string    `json:"first_name"`
+		LastName         string    `json:"last_name"`
+		OrganizationName string    `json:"organization_name,omitzero"`
+		ExpiresAt        time.Time `json:"expires_at"`
+		TwofaRequired    bool      `json:"twofa_required"`
+	}
+
+	utils.RespondWithJSON(w, Response{
+		Email:            user.Email,
+		FirstName:        user.FirstName.String,
+		LastName:         user.LastName.String,
+		OrganizationName: organization.OrganizationName,
+		ExpiresAt:        invitation.ExpiresAt.Time,
+		TwofaRequired:    utils.TwofactorMandatory(user, organization),
+	})
+}
+
+// AcceptInvitation sets the password of the invited user and activates the account.
+// With twofa_enroll, or if the organization requires it, 2FA is enrolled as well:
+// the first call without twofakey answers with the enrollment, the second one with the code of the authenticator app accepts.
+func (app *App) AcceptInvitation(w http.ResponseWriter, r *http.Request) {
+	type RequestBody struct {
+		Token        string `json:"token"`
+		Password     string `json:"password"`
+		TwofaEnroll  bool   `json:"twofa_enroll"`
+		Twofactorkey string `json:"twofakey"`
+	}
+	var reqBody RequestBody
+	if !app.readInvitationRequest(w, r, &reqBody) {
+		return
+	}
+
+	invitation, err := app.openInvitation(reqBody.Token)
+	if err != nil {
+		cache.IPFailthrottler.Fail(utils.ClientIP(r))
+		utils.RespondWithError(w, err)
+		return
+	}
+
+	if reqBody.Password == "" {
+		utils.RespondWithError(w, utils.Unprocessable("invalid_password", "Error Pasword Is the password valid?", nil))
+		return
+	}
+
+	user, err := app.Queries.SelectUserById(context.Background(), invitation.UserID)
+	if err != nil {
+		utils.RespondWithError(w, utils.DBError("Error getting the invited user", err))
+		return
+	}
+
+	// Checked before the 2FA enrollment, so the user does not enroll for nothing.
+	err = utils.ValidatePassword(reqBody.Password, user.Email, user.Username)
+	if err != nil {
+		utils.RespondWithError(w, err)
+		return
+	}
+
+	var organization db.Organization
+	if invitation.OrganizationID.Valid {
+		organization, err = app.Queries.OrganizationSelectById(context.Background(), invitation.OrganizationID)
+		if err != nil {
+			utils.RespondWithError(w, utils.DBError("Error getting the organization", err))
+			return
+		}
+	}
+
+	// Sent instead of the Response if the 2FA enrollment needs a code.
+	type ChallengeResponse struct {
+		TwofaEnrollmentRequired bool                `json:"twofa_enrollment_required"`
+		TwofaEnrollment         TwofactorEnrollment `json:"twofa_enrollment"`
+	}
+
+	enrollTwofactor := reqBody.TwofaEnroll || utils.TwofactorMandatory(user, organization)
+	if enrollTwofactor {
+		if reqBody.Twofactorkey == "" || user.Twofactorsecret.String == "" {
+			enrollment, err := app.startTwofactorEnrollment(user)
+			if err != nil {
+				utils.RespondWithError(w, utils.DBError("Error starting 2FA enrollment", err))
+				return
+			}
+			utils.RespondWithJSON(w, ChallengeResponse{TwofaEnrollmentRequired: true, TwofaEnrollment: enrollment})
+			return
+		}
+		if !totp.Validate(reqBody.Twofactorkey, user.Twofactorsecret.String) {
+			cache.IPFailthrottler.Fail(utils.ClientIP(r))
+			utils.RespondWithError(w, utils.Unauthorized("twofa_invalid", "2fa not valid", errors.New("2fa not valid")))
+			return
+		}
+	}
+
+	password, err := utils.HashPassword(reqBody.Password)
+	if err != nil {
+		utils.RespondWithError(w, utils.Internal("internal", "Error hashing password", err))
+		return
+	}
+
+	tx, qtx, err := app.beginTx()
+	if err != nil {
+		utils.RespondWithError(w, utils.DBError("Error starting transaction", err))
+		return
+	}
+	defer tx.Rollback(context.Background())
+
+	before, _, err := loadUserAudit(qtx, user.ID)
+	if err != nil {
+		utils.RespondWithError(w, utils.DBError("Error getting the invited user", err))
+		return
+	}
+
+	// Only one of two concurrent accepts gets the invitation.
+	_, err = qtx.InvitationAccept(context.Background(), invitation.ID)
+	if err != nil {
+		utils.RespondWithError(w, utils.Unprocessable("invitation_invalid", "Invitation not valid", err))
+		return
+	}
+
+	_, err = qtx.UserAcceptInvitation(context.Background(), db.UserAcceptInvitationParams{
+		ID:       user.ID,
+		Password: password,
+	})
+	if err != nil {
+		utils.RespondWithError(w, utils.DBError("Error activating the user", err))
+		return
+	}
+
+	type Response struct {
+		Header        string   `json:"header"`
+		Text          string   `json:"text"`
+		RecoveryCodes []string `json:"recovery_codes,omitempty"` // Only set if 2FA was enrolled.
+	}
+	Answer := Response{Header: "Invitation", Text: "Your account is ready. Please login."}
+
+	if enrollTwofactor {
+		_, err = qtx.UpdateTwofactorByID(context.Background(), db.UpdateTwofactorByIDParams{
+			ID:               user.ID,
+			Twofactorsecret:  user.Twofactorsecret,
+			TwofactorEnabled: true,
+		})
+		if err != nil {
+			utils.RespondWithError(w, utils.DBError("Error enabling 2FA", err))
+			return
+		}
+		Answer.RecoveryCodes, err = utils.NewRecoveryCodes(qtx, user.ID)
+		if err != nil {
+			utils.RespondWithError(w, utils.DBError("Error creating recovery codes", err))
+			return
+		}
+	}
+
+	after, _, err := loadUserAudit(qtx, user.ID)
+	if err != nil {
+		utils.RespondWithError(w, utils.DBError("Error getting the invited user", err))
+		return
+	}
+
+	err = writeUserAudit(qtx, r, user.ID, AuditInvitationAccepted, user.ID, invitation.OrganizationID, before, after)
+	if err != nil {
+		utils.RespondWithError(w, utils.Internal("audit_failed", "Error writing audit event", err))
+		return
+	}
+
+	err = tx.Commit(context.Background())
+	if err != nil {
+		utils.RespondWithError(w, utils.DBError("Error accepting the invitation", err))
+		return
+	}
+
+	cache.Go4users.Del(user.ID.Bytes) // The user is changed and hence needs to be deleted from cache.
+
+	utils.RespondWithJSON(w, Answer)
+}
+
+// Invitations lists the invitations that are neither accepted nor revoked, newest first.
+// Organization admins only see the ones of their organization. Superusers see all of them or filter by organization.
+func (app *App) Invitations(w http.ResponseWriter, r *http.Request) {
+	rinfo, ok := r.Context().Value(utils.InfoContextKey).(utils.InfoKey)
+	if !ok {
+		utils.RespondWithError(w, utils.Unauthorized("unauthenticated", "User not found in context", errors.New("user not found")))
+		return
+	}
+
+	organizationID, err := queryUUID(r.URL.Query(), "organization")
+	if err != nil {
+		utils.RespondWithError(w, utils.BadRequest("invalid_filter", "Error parsing filter", err))
+		return
+	}
+	if !rinfo.User.IsSuperuser.Bool {
+		organizationID = rinfo.Organization.ID
+	}
+
+	invitations, err := app.Queries.InvitationSelectPending(context.Background(), organizationID)
+	if err != nil {
+		utils.RespondWithError(w, utils.DBError("Error getting invitations", err))
+		return
+	}
+
+	type InvitationResponse struct {
+		ID               uuid.UUID  `json:"id"`
+		CreatedAt        time.Time  `json:"created_at"`
+		ExpiresAt        time.Time  `json:"expires_at"`
+		Expired          bool       `json:"expired"`
+		UserID           uuid.UUID  `json:"user_id"`
+		Email            string     `json:"email"`
+		FirstName        string     `json:"first_name"`
+		LastName         string     `json:"last_name"`
+		OrganizationID   *uuid.UUID `json:"organization_id"`
+		OrganizationName string     `json:"organization_name"`
+		InvitedByEmail   string     `json:"invited_by_email"`
+	}
+
+	Answer := []InvitationResponse{}
+	for _, i := range invitations {
+		Answer = append(Answer, InvitationResponse{
+			ID:               i.ID.Bytes,
+			CreatedAt:        i.CreatedAt.Time,
+			ExpiresAt:        i.ExpiresAt.Time,
+			Expired:          i.ExpiresAt.Time.Before(time.Now()),
+			UserID:           i.UserID.Bytes,
+			Email:            i.Email,
+			FirstName:        i.FirstName.String,
+			LastName:         i.LastName.String,
+			OrganizationID:   optionalUUID(i.OrganizationID),
+			OrganizationName: i.OrganizationName.String,
+			InvitedByEmail:   i.InvitedByEmail.String,
+		})
+	}
+
+	utils.RespondWithJSON(w, Answer)
+}
+
+// RevokeInvitation revokes the invitation with the id of the Id header. The invited user stays inactive.
+func (app *App) RevokeInvitation(w http.ResponseWriter, r *http.Request) {
+	invitationuuid, err := uuid.Parse(r.Header.Get("Id"))
+	if err != nil {
+		utils.RespondWithError(w, utils.BadRequest("invalid_id", "Error getting parsing ID", err))
+		return
+	}
+
+	rinfo, ok := r.Context().Value(utils.InfoContextKey).(utils.InfoKey)
+	if !ok {
+		utils.RespondWithError(w, utils.Unauthorized("unauthenticated", "User not found in context", errors.New("user not found")))
+		return
+	}
+
+	invitationID := pgtype.UUID{Bytes: invitationuuid, Valid: true}
+
+	invitation, err := app.Queries.InvitationSelectById(context.Background(), invitationID)
+	if err != nil {
+		utils.RespondWithError(w, utils.DBError("Error getting this invitation", err))
+		return
+	}
+
+	if !rinfo.User.IsSuperuser.Bool && invitation.OrganizationID != rinfo.Organization.ID {
+		utils.RespondWithError(w, utils.Forbidden("forbidden", "No permission to revoke this invitation", errors.New("invitation organization is not your organization")))
+		return
+	}
+
+	tx, qtx, err := app.beginTx()
+	if err != nil {
+		utils.RespondWithError(w, utils.DBError("Error starting transaction", err))
+		return
+	}
+	defer tx.Rollback(context.Background())
+
+	_, err = qtx.InvitationRevoke(context.Background(), invitationID)
+	if err != nil {
+		utils.RespondWithError(w, utils.Conflict("invitation_closed", "The invitation is already accepted or revoked", err))
+		return
+	}
+
+	err = writeAudit(qtx, r, auditEntry{
+		Actor:        rinfo.User.ID,
+		Organization: invitation.OrganizationID,
+		Action:       AuditInvitationRevoked,
+		Target:       invitation.UserID,
+		Changes:      map[string]string{"invitation": invitationuuid.String()},
+	})
+	if err != nil {
+		utils.RespondWithError(w, utils.Internal("audit_failed", "Error writing audit event", err))
+		return
+	}
+
+	err = tx.Commit(context.Background())
+	if err != nil {
+		utils.RespondWithError(w, utils.DBError("Error revoking the invitation", err))
+		return
+	}
+
+	utils.RespondWithJSON(w, utils.ToastResponse{
+		Header: "Invitation",
+		Text:   "Invitation revoked",
+	})
+}
@@ -0,0 +1,115 @@
+package admin
+
+import (
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/karl1b/go4lage/pkg/settings"
+	utils "github.com/karl1b/go4lage/pkg/utils"
+)
+
+func withSecretKey(t *testing.T, key string) {
+	previous := settings.Settings.SecretKey
+	settings.Settings.SecretKey = key
+	t.Cleanup(func() { settings.Settings.SecretKey = previous })
+}
+
+func TestInvitationTokenRoundTrip(t *testing.T) {
+	withSecretKey(t, "test secret")
+
+	id := uuid.New()
+	for _, expiresAt := range []time.Time{
+		time.Now().Add(72 * time.Hour),
+		time.Now().Add(-time.Hour), // Expired tokens parse, loadInvitation refuses them.
+	} {
+		token, err := newInvitationToken(id, expiresAt)
+		if err != nil {
+			t.Fatal(err)
+		}
+		gotID, gotExpiresAt, err := parseInvitationToken(token)
+		if err != nil {
+			t.Fatalf("parseInvitationToken() error = %v", err)
+		}
+		if gotID != id {
+			t.Errorf("parseInvitationToken() id = %s, want %s", gotID, id)
+		}
+		if !gotExpiresAt.Equal(expiresAt.Truncate(time.Second)) {
+			t.Errorf("parseInvitationToken() expires at %s, want %s", gotExpiresAt, expiresAt.Truncate(time.Second))
+		}
+	}
+
+	// Every token has its own nonce.
+	first, _ := newInvitationToken(id, time.Unix(1700000000, 0))
+	second, _ := newInvitationToken(id, time.Unix(1700000000, 0))
+	if first == second {
+		t.Error("two tokens of the same invitation are equal")
+	}
+}
+
+func TestParseInvitationTokenRejects(t *testing.T) {
+	withSecretKey(t, "test secret")
+
+	token, err := newInvitationToken(uuid.New(), time.Now().Add(time.Hour))
+	if err != nil {
+		t.Fatal(err)
+	}
+	payload, signature, _ := strings.Cut(token, ".")
+
+	// The first character of the payload is changed, the signature stays.
+	tampered := []byte(payload)
+	if tampered[0] == 'A' {
+		tampered[0] = 'B'
+	} else {
+		tampered[0] = 'A'
+	}
+
+	short, err := utils.SignToken(make([]byte, 39))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	withSecretKey(t, "other secret")
+	foreign, err := newInvitationToken(uuid.New(), time.Now().Add(time.Hour))
+	if err != nil {
+		t.Fatal(err)
+	}
+	withSecretKey(t, "test secret")
+
+	tests := []struct {
+		name  string
+		token string
+	}{
+		{"empty", ""},
+		{"no signature", payload},
+		{"changed payload", string(tampered) + "." + signature},
+		{"signature of another token", payload + "." + strings.Split(foreign, ".")[1]},
+		{"other secret key", foreign},
+		{"not base64", "!!!." + signature},
+		{"wrong payload length", short},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, _, err := parseInvitationToken(tt.token)
+			if !errors.Is(err, utils.ErrInvalidSignature) {
+				t.Errorf("parseInvitationToken(%q) error = %v, want %v", tt.token, err, utils.ErrInvalidSignature)
+			}
+		})
+	}
+}
+
+func TestInvitationTokenWithoutSecretKey(t *testing.T) {
+	withSecretKey(t, "")
+
+	_, err := newInvitationToken(uuid.New(), time.Now().Add(time.Hour))
+	if !errors.Is(err, utils.ErrNoSecretKey) {
+		t.Errorf("newInvitationToken() error = %v, want %v", err, utils.ErrNoSecretKey)
+	}
+	_, _, err = parseInvitationToken("payload.signature")
+	if !errors.Is(err, utils.ErrNoSecretKey) {
+		t.Errorf("parseInvitationToken() error = %v, want %v", err, utils.ErrNoSecretKey)
+	}
+}
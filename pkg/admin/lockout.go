@@ -0,0 +1,140 @@
+package admin
+
+import (
+	"context"
+	"errors"
+	"log"
+	"net/http"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5/pgtype"
+	cache "github.com/karl1b/go4lage/pkg/cache"
+	settings "github.com/karl1b/go4lage/pkg/settings"
+	"github.com/karl1b/go4lage/pkg/sql/db"
+	utils "github.com/karl1b/go4lage/pkg/utils"
+)
+
+// Returns an error if the account has to wait because of failed logins or is locked.
+func accountThrottled(user db.User) error {
+	now := time.Now()
+	if user.LockedUntil.Valid && now.Before(user.LockedUntil.Time) {
+		return errors.New("account locked until " + user.LockedUntil.Time.Format(time.RFC3339))
+	}
+	if user.LastFailedLogin.Valid && now.Before(user.LastFailedLogin.Time.Add(cache.Backoff(int(user.FailedLoginAttempts)))) {
+		return errors.New("too many failed logins")
+	}
+	return nil
+}
+
+// Records a failed login for the account and the IP address. The account is locked once LockoutThreshold is reached.
+func (app *App) loginFailed(r *http.Request, user db.User) {
+	cache.IPFailthrottler.Fail(utils.ClientIP(r))
+
+	user, err := app.Queries.LoginFailedByID(context.Background(), user.ID)
+	if err != nil {
+		log.Println("Error counting failed login:", err)
+		return
+	}
+	cache.Go4users.Del(user.ID.Bytes) // The user is changed and hence needs to be deleted from cache.
+
+	if settings.Settings.LockoutThreshold <= 0 || int(user.FailedLoginAttempts) < settings.Settings.LockoutThreshold {
+		return
+	}
+
+	lockedUntil := time.Now().Add(time.Duration(settings.Settings.LockoutMins) * time.Minute)
+	_, err = app.Queries.LockUserByID(context.Background(), db.LockUserByIDParams{
+		ID:          user.ID,
+		LockedUntil: pgtype.Timestamptz{Time: lockedUntil, Valid: true},
+	})
+	if err != nil {
+		log.Println("Error locking user:", err)
+		return
+	}
+
+	// Superusers may have no organization, then it stays empty.
+	organization, _ := app.Queries.OrganizationSelectUserOrganization(context.Background(), user.ID)
+
+	err = writeAudit(app.Queries, r, auditEntry{
+		Organization: organization.ID,
+		Action:       AuditUserLocked,
+		Target:       user.ID,
+		Changes:      map[string]any{"failed_logins": user.FailedLoginAttempts, "locked_until": lockedUntil},
+	})
+	if err != nil {
+		log.Println("Error writing audit event:", err)
+	}
+}
+
+// Resets the failed login counter after a successful login.
+func (app *App) loginSucceeded(user db.User) error {
+	if user.FailedLoginAttempts == 0 && !user.LockedUntil.Valid {
+		return nil
+	}
+	_, err := app.Queries.UnlockUserByID(context.Background(), user.ID)
+	return err
+}
+
+// Unlocks a user and resets the failed login counter. Organization admins can only unlock their organization members.
+func (app *App) UnlockUser(w http.ResponseWriter, r *http.Request) {
+	rinfo, ok := r.Context().Value(utils.InfoContextKey).(utils.InfoKey)
+	if !ok {
+		utils.RespondWithError(w, utils.Unauthorized("unauthenticated", "User not found in context", errors.New("user not found")))
+		return
+	}
+
+	useriduuid, err := uuid.Parse(r.Header.Get("Id"))
+	if err != nil {
+		utils.RespondWithError(w, utils.BadRequest("invalid_id", "Error getting parsing ID", err))
+		return
+	}
+	userID := pgtype.UUID{Bytes: useriduuid, Valid: true}
+
+	err = app.canManageUser(rinfo, userID)
+	if err != nil {
+		utils.RespondWithError(w, utils.Forbidden("forbidden", "No permission to unlock this user", err))
+		return
+	}
+
+	tx, qtx, err := app.beginTx()
+	if err != nil {
+		utils.RespondWithError(w, utils.DBError("Error starting transaction", err))
+		return
+	}
+	defer tx.Rollback(context.Background())
+
+	organizationID, err := userOrganizationID(qtx, userID)
+	if err != nil {
+		utils.RespondWithError(w, utils.DBError("Error getting organization", err))
+		return
+	}
+
+	user, err := qtx.UnlockUserByID(context.Background(), userID)
+	if err != nil {
+		utils.RespondWithError(w, utils.DBError("Error unlocking user", err))
+		return
+	}
+
+	err = writeAudit(qtx, r, auditEntry{
+		Actor:        rinfo.User.ID,
+		Organization: organizationID,
+		Action:       AuditUserUnlocked,
+		Target:       user.ID,
+	})
+	if err != nil {
+		utils.RespondWithError(w, utils.Internal("audit_failed", "Error writing audit event", err))
+		return
+	}
+
+	err = tx.Commit(context.Background())
+	if err != nil {
+		utils.RespondWithError(w, utils.DBError("Error unlocking user", err))
+		return
+	}
+	cache.Go4users.Del(user.ID.Bytes) // The user is changed and hence needs to be deleted from cache.
+
+	utils.RespondWithJSON(w, utils.ToastResponse{
+		Header: "User",
+		Text:   "User unlocked",
+	})
+}
@@ -0,0 +1,18 @@
+package admin
+
+import (
+	"net/http"
+
+	cache "github.com/karl1b/go4lage/pkg/cache"
+	utils "github.com/karl1b/go4lage/pkg/utils"
+)
+
+// Shows how many entries each throttler tracks and how many calls it allowed, rejected, evicted and expired.
+func (app *App) ThrottleStats(w http.ResponseWriter, r *http.Request) {
+	utils.RespondWithJSON(w, cache.AllThrottleStats())
+}
+
+// Shows the entries, hits, misses, evictions and expirations of the user, session, group, permission and organization caches.
+func (app *App) CacheStats(w http.ResponseWriter, r *http.Request) {
+	utils.RespondWithJSON(w, cache.AllCacheStats())
+}
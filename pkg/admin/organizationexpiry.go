@@ -0,0 +1,142 @@
+package admin
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5/pgtype"
+	"github.com/karl1b/go4lage/pkg/mail"
+	settings "github.com/karl1b/go4lage/pkg/settings"
+	"github.com/karl1b/go4lage/pkg/sql/db"
+	utils "github.com/karl1b/go4lage/pkg/utils"
+)
+
+/*
+Organizations expire at active_until. The AuthMiddleware enforces it, this file tells the members and the organization about it.
+The dashboard shows a banner from the organization status. The email of the organization gets a reminder before it expires.
+*/
+
+func (app *App) OrganizationStatus(w http.ResponseWriter, r *http.Request) {
+
+	rinfo, ok := r.Context().Value(utils.InfoContextKey).(utils.InfoKey)
+	if !ok {
+		utils.RespondWithError(w, utils.Unauthorized("unauthenticated", "User not found in context", errors.New("user not found")))
+		return
+	}
+
+	type OrganizationStatusResponse struct {
+		OrganizationID   *uuid.UUID `json:"organization_id,omitempty"`
+		OrganizationName string     `json:"organization_name,omitempty"`
+		State            string     `json:"state"`
+		ActiveUntil      *time.Time `json:"active_until,omitempty"`
+		GraceUntil       *time.Time `json:"grace_until,omitempty"`
+		DaysLeft         int        `json:"days_left"` // Full days until active_until, negative once it passed.
+		ReadOnly         bool       `json:"read_only"`
+	}
+
+	// Superusers have no organization, so nothing expires for them.
+	if !rinfo.Organization.ID.Valid {
+		utils.RespondWithJSON(w, OrganizationStatusResponse{State: utils.OrganizationActive})
+		return
+	}
+
+	organization := rinfo.Organization
+	organizationID := uuid.UUID(organization.ID.Bytes)
+	activeUntil := organization.ActiveUntil.Time
+	graceUntil := utils.OrganizationGraceUntil(organization)
+	state := utils.OrganizationState(organization, time.Now())
+
+	Answer := OrganizationStatusResponse{
+		OrganizationID:   &organizationID,
+		OrganizationName: organization.OrganizationName,
+		State:            state,
+		ActiveUntil:      &activeUntil,
+		GraceUntil:       &graceUntil,
+		DaysLeft:         int(time.Until(activeUntil).Hours() / 24),
+		ReadOnly:         state == utils.OrganizationExpired && utils.OrganizationReadOnly(),
+	}
+
+	utils.RespondWithJSON(w, Answer)
+}
+
+// StartExpiryReminders mails organizations that expire within ORGANIZATION_REMINDER_DAYS. It checks once an hour.
+func (app *App) StartExpiryReminders() {
+	if settings.Settings.OrganizationReminderDays <= 0 {
+		return
+	}
+
+	go func() {
+		app.sendExpiryReminders()
+		ticker := time.NewTicker(time.Hour)
+		defer ticker.Stop()
+		for range ticker.C {
+			app.sendExpiryReminders()
+		}
+	}()
+}
+
+// Every organization gets one reminder per active_until. The reminder is marked before it is sent,
+// so several instances do not send it twice. If sending fails, the mark is removed and the next run tries again.
+func (app *App) sendExpiryReminders() {
+	remindBefore := time.Now().AddDate(0, 0, settings.Settings.OrganizationReminderDays)
+
+	organizations, err := app.Queries.OrganizationSelectExpiring(context.Background(), pgtype.Timestamptz{Time: remindBefore, Valid: true})
+	if err != nil {
+		log.Println("Error getting expiring organizations:", err)
+		return
+	}
+
+	for _, organization := range organizations {
+		marked, err := app.Queries.OrganizationMarkReminded(context.Background(), db.OrganizationMarkRemindedParams{
+			ID:          organization.ID,
+			ActiveUntil: organization.ActiveUntil,
+		})
+		if err != nil {
+			log.Println("Error marking expiry reminder:", err)
+			continue
+		}
+		if marked == 0 {
+			continue // Another instance sent it.
+		}
+
+		err = sendExpiryReminder(organization)
+		if err != nil {
+			log.Printf("Error sending expiry reminder to %s: %v", organization.Email, err)
+			err = app.Queries.OrganizationUnmarkReminded(context.Background(), organization.ID)
+			if err != nil {
+				log.Println("Error unmarking expiry reminder:", err)
+			}
+		}
+	}
+}
+
+func sendExpiryReminder(organization db.Organization) error {
+	baseurl := strings.TrimSpace(settings.Settings.Baseurl)
+	activeUntil := organization.ActiveUntil.Time.UTC().Format("2006-01-02 15:04 MST")
+
+	after := "After that"
+	if settings.Settings.OrganizationGraceDays > 0 {
+		after = fmt.Sprintf("%d days later", settings.Settings.OrganizationGraceDays)
+	}
+	if utils.OrganizationReadOnly() {
+		after += " your members can only view data."
+	} else {
+		after += " your members can not use it anymore."
+	}
+
+	text := fmt.Sprintf(`Hello %s,
+
+your access to %s expires on %s.
+%s
+
+Contact us to extend it.`,
+		organization.OrganizationName, baseurl, activeUntil, after)
+
+	return mail.Send(organization.Email, "Your access expires soon", text)
+}
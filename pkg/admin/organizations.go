@@ -3,6 +3,7 @@ package admin
 import (
 	"context"
 	"encoding/json"
+	"errors"
 	"io"
 	"net/http"
 	"time"
@@ -18,7 +19,7 @@ import (
 func (app *App) CreateOrganization(w http.ResponseWriter, r *http.Request) {
 	body, err := io.ReadAll(r.Body)
 	if err != nil {
-		http.Error(w, err.Error(), http.StatusInternalServerError)
+		utils.RespondWithError(w, utils.BadRequest("invalid_body", "Error reading body", err))
 		return
 	}
 	defer r.Body.Close()
@@ -27,25 +28,36 @@ func (app *App) CreateOrganization(w http.ResponseWriter, r *http.Request) {
 		OrganizationName string `json:"organization_name"`
 		Email            string `json:"email"`
 		ActiveUntil      string `json:"active_until"`
+		RequireTwofactor *bool  `json:"require_twofactor"`
 	}
 
 	var reqBody RequestBody
 	err = json.Unmarshal(body, &reqBody)
 	if err != nil {
-		http.Error(w, err.Error(), http.StatusBadRequest)
+		utils.RespondWithError(w, utils.BadRequest("invalid_body", "Invalid JSON format", err))
 		return
 	}
 
 	activeUntil, err := time.Parse(time.DateOnly, reqBody.ActiveUntil)
 	if err != nil {
-		utils.RespondWithJSON(w, utils.ErrorResponse{
-			Detail: "Error parsing time",
-			Error:  err.Error(),
-		})
+		utils.RespondWithError(w, utils.Unprocessable("invalid_date", "Error parsing time", err))
 		return
 	}
 
-	organization, err := app.Queries.OrganizationCreate(context.Background(), db.OrganizationCreateParams{
+	rinfo, ok := r.Context().Value(utils.InfoContextKey).(utils.InfoKey)
+	if !ok {
+		utils.RespondWithError(w, utils.Unauthorized("unauthenticated", "User not found in context", errors.New("user not found")))
+		return
+	}
+
+	tx, qtx, err := app.beginTx()
+	if err != nil {
+		utils.RespondWithError(w, utils.DBError("Error starting transaction", err))
+		return
+	}
+	defer tx.Rollback(context.Background())
+
+	organization, err := qtx.OrganizationCreate(context.Background(), db.OrganizationCreateParams{
 		ID: pgtype.UUID{
 			Bytes: uuid.New(),
 			Valid: true,
@@ -56,12 +68,22 @@ func (app *App) CreateOrganization(w http.ResponseWriter, r *http.Request) {
 			Time:  activeUntil,
 			Valid: true,
 		},
+		RequireTwofactor: reqBody.RequireTwofactor != nil && *reqBody.RequireTwofactor,
 	})
 	if err != nil {
-		utils.RespondWithJSON(w, utils.ErrorResponse{
-			Detail: "Error creating organization",
-			Error:  err.Error(),
-		})
+		utils.RespondWithError(w, utils.DBError("Error creating organization", err))
+		return
+	}
+
+	err = writeOrganizationAudit(qtx, r, rinfo.User.ID, AuditOrganizationCreated, organization.ID, nil, newOrganizationAudit(organization))
+	if err != nil {
+		utils.RespondWithError(w, utils.Internal("audit_failed", "Error writing audit event", err))
+		return
+	}
+
+	err = tx.Commit(context.Background())
+	if err != nil {
+		utils.RespondWithError(w, utils.DBError("Error creating organization", err))
 		return
 	}
 
@@ -72,33 +94,58 @@ func (app *App) DeleteOrganization(w http.ResponseWriter, r *http.Request) {
 	organizationId := r.Header.Get("Id")
 	organizationUUID, err := uuid.Parse(organizationId)
 	if err != nil {
-		utils.RespondWithJSON(w, utils.ErrorResponse{
-			Detail: "Cannot parse organization ID",
-			Error:  err.Error(),
-		})
+		utils.RespondWithError(w, utils.BadRequest("invalid_id", "Cannot parse organization ID", err))
 		return
 	}
 
-	err = app.Queries.OrganizationDeleteByID(context.Background(), pgtype.UUID{
-		Bytes: organizationUUID,
-		Valid: true,
-	})
+	rinfo, ok := r.Context().Value(utils.InfoContextKey).(utils.InfoKey)
+	if !ok {
+		utils.RespondWithError(w, utils.Unauthorized("unauthenticated", "User not found in context", errors.New("user not found")))
+		return
+	}
+
+	organizationID := pgtype.UUID{Bytes: organizationUUID, Valid: true}
+
+	tx, qtx, err := app.beginTx()
 	if err != nil {
-		utils.RespondWithJSON(w, utils.ErrorResponse{
-			Detail: "Error deleting organization",
-			Error:  err.Error(),
-		})
+		utils.RespondWithError(w, utils.DBError("Error starting transaction", err))
+		return
+	}
+	defer tx.Rollback(context.Background())
+
+	organization, err := qtx.OrganizationSelectById(context.Background(), organizationID)
+	if err != nil {
+		utils.RespondWithError(w, utils.DBError("Error getting organization", err))
+		return
+	}
+
+	// The organization is moved to the trash. Its members can not log in until a superuser restores it.
+	_, err = qtx.OrganizationSoftDelete(context.Background(), organizationID)
+	if err != nil {
+		utils.RespondWithError(w, utils.DBError("Error deleting organization", err))
 		return
 	}
 
-	cache.Go4Organizations.Del(organizationUUID)
+	err = writeOrganizationAudit(qtx, r, rinfo.User.ID, AuditOrganizationDeleted, organizationID, newOrganizationAudit(organization), nil)
+	if err != nil {
+		utils.RespondWithError(w, utils.Internal("audit_failed", "Error writing audit event", err))
+		return
+	}
+
+	err = tx.Commit(context.Background())
+	if err != nil {
+		utils.RespondWithError(w, utils.DBError("Error deleting organization", err))
+		return
+	}
+
+	cache.Go4Organizations.Flush() // The cache is keyed by user id, so all members are dropped this way.
 	utils.RespondWithJSON(w, struct{}{})
 }
 
 func (app *App) EditOrganization(w http.ResponseWriter, r *http.Request) {
 	body, err := io.ReadAll(r.Body)
 	if err != nil {
-		http.Error(w, err.Error(), http.StatusInternalServerError)
+		utils.RespondWithError(w, utils.BadRequest("invalid_body", "Error reading body", err))
 		return
 	}
 	defer r.Body.Close()
@@ -107,48 +154,57 @@ func (app *App) EditOrganization(w http.ResponseWriter, r *http.Request) {
 		OrganizationName string `json:"organization_name"`
 		Email            string `json:"email"`
 		ActiveUntil      string `json:"active_until"`
+		RequireTwofactor *bool  `json:"require_twofactor"`
 	}
 
 	var reqBody RequestBody
 	err = json.Unmarshal(body, &reqBody)
 	if err != nil {
-		http.Error(w, err.Error(), http.StatusBadRequest)
+		utils.RespondWithError(w, utils.BadRequest("invalid_body", "Invalid JSON format", err))
 		return
 	}
 
 	id := r.Header.Get("Id")
 	organizationUUID, err := uuid.Parse(id)
 	if err != nil {
-		utils.RespondWithJSON(w, utils.ErrorResponse{
-			Detail: "Error parsing ID",
-			Error:  err.Error(),
-		})
+		utils.RespondWithError(w, utils.BadRequest("invalid_id", "Error parsing ID", err))
 		return
 	}
 
 	newActiveUntil, err := time.Parse(time.RFC3339, reqBody.ActiveUntil)
 	if err != nil {
-		utils.RespondWithJSON(w, utils.ErrorResponse{
-			Detail: "Error parsing time",
-			Error:  err.Error(),
-		})
+		utils.RespondWithError(w, utils.Unprocessable("invalid_date", "Error parsing time", err))
 		return
 	}
 
 	user, ok := r.Context().Value(utils.InfoContextKey).(utils.InfoKey)
 	if !ok {
-		utils.RespondWithJSON(w, utils.ErrorResponse{
-			Detail: "User not found in context",
-			Error:  "user not found",
-		})
+		utils.RespondWithError(w, utils.Unauthorized("unauthenticated", "User not found in context", errors.New("user not found")))
+		return
+	}
+
+	organizationID := pgtype.UUID{Bytes: organizationUUID, Valid: true}
+
+	tx, qtx, err := app.beginTx()
+	if err != nil {
+		utils.RespondWithError(w, utils.DBError("Error starting transaction", err))
+		return
+	}
+	defer tx.Rollback(context.Background())
+
+	organization, err := qtx.OrganizationSelectById(context.Background(), organizationID)
+	if err != nil {
+		utils.RespondWithError(w, utils.DBError("Error getting organization", err))
 		return
 	}
 
-	if !user.User.IsSuperuser.Bool {
-		organizationUUID = uuid.UUID(user.Organization.ID.Bytes)
+	// Clients that do not know the setting keep it as it is.
+	requireTwofactor := organization.RequireTwofactor
+	if reqBody.RequireTwofactor != nil {
+		requireTwofactor = *reqBody.RequireTwofactor
 	}
 
-	_, err = app.Queries.OrganizationUpdateById(context.Background(), db.OrganizationUpdateByIdParams{
+	updated, err := qtx.OrganizationUpdateById(context.Background(), db.OrganizationUpdateByIdParams{
 		ID: pgtype.UUID{
 			Bytes: organizationUUID,
 			Valid: true,
@@ -159,16 +215,26 @@ func (app *App) EditOrganization(w http.ResponseWriter, r *http.Request) {
 			Time:  newActiveUntil,
 			Valid: true,
 		},
+		RequireTwofactor: requireTwofactor,
 	})
 	if err != nil {
-		utils.RespondWithJSON(w, utils.ErrorResponse{
-			Detail: "Error updating organization",
-			Error:  err.Error(),
-		})
+		utils.RespondWithError(w, utils.DBError("Error updating organization", err))
 		return
 	}
 
-	cache.Go4Organizations.Del(organizationUUID)
+	err = writeOrganizationAudit(qtx, r, user.User.ID, AuditOrganizationUpdated, organizationID, newOrganizationAudit(organization), newOrganizationAudit(updated))
+	if err != nil {
+		utils.RespondWithError(w, utils.Internal("audit_failed", "Error writing audit event", err))
+		return
+	}
+
+	err = tx.Commit(context.Background())
+	if err != nil {
+		utils.RespondWithError(w, utils.DBError("Error updating organization", err))
+		return
+	}
+
+	cache.Go4Organizations.Flush() // The cache is keyed by user id, so all members are dropped this way.
 	utils.RespondWithJSON(w, utils.ToastResponse{
 		Header: "Organization updated",
 		Text:   "",
@@ -179,10 +245,7 @@ func (app *App) AllOrganizations(w http.ResponseWriter, r *http.Request) {
 
 	rinfo, ok := r.Context().Value(utils.InfoContextKey).(utils.InfoKey)
 	if !ok {
-		utils.RespondWithJSON(w, utils.ErrorResponse{
-			Detail: "User not found in context",
-			Error:  "user not found",
-		})
+		utils.RespondWithError(w, utils.Unauthorized("unauthenticated", "User not found in context", errors.New("user not found")))
 		return
 	}
 
@@ -192,6 +255,7 @@ func (app *App) AllOrganizations(w http.ResponseWriter, r *http.Request) {
 		OrganizationName string    `json:"organization_name"`
 		Email            string    `json:"email"`
 		ActiveUntil      time.Time `json:"active_until"`
+		RequireTwofactor bool      `json:"require_twofactor"`
 	}
 
 	var Response []OrganizationResponse
@@ -201,10 +265,7 @@ func (app *App) AllOrganizations(w http.ResponseWriter, r *http.Request) {
 	if rinfo.User.IsSuperuser.Bool {
 		organizations, err = app.Queries.OrganizationAll(context.Background())
 		if err != nil {
-			utils.RespondWithJSON(w, utils.ErrorResponse{
-				Detail: "Error getting all organizations",
-				Error:  err.Error(),
-			})
+			utils.RespondWithError(w, utils.DBError("Error getting all organizations", err))
 			return
 		}
 	} else {
@@ -218,6 +279,7 @@ func (app *App) AllOrganizations(w http.ResponseWriter, r *http.Request) {
 			OrganizationName: org.OrganizationName,
 			Email:            org.Email,
 			ActiveUntil:      org.ActiveUntil.Time,
+			RequireTwofactor: org.RequireTwofactor,
 		}
 
 		Response = append(Response, r)
@@ -230,20 +292,14 @@ func (app *App) OneOrganization(w http.ResponseWriter, r *http.Request) {
 
 	rinfo, ok := r.Context().Value(utils.InfoContextKey).(utils.InfoKey)
 	if !ok {
-		utils.RespondWithJSON(w, utils.ErrorResponse{
-			Detail: "User not found in context",
-			Error:  "user not found",
-		})
+		utils.RespondWithError(w, utils.Unauthorized("unauthenticated", "User not found in context", errors.New("user not found")))
 		return
 	}
 
 	id := r.Header.Get("Id")
 	organizationUUID, err := uuid.Parse(id)
 	if err != nil {
-		utils.RespondWithJSON(w, utils.ErrorResponse{
-			Detail: "Error parsing ID",
-			Error:  err.Error(),
-		})
+		utils.RespondWithError(w, utils.BadRequest("invalid_id", "Error parsing ID", err))
 		return
 	}
 	var organization db.Organization
@@ -254,10 +310,7 @@ func (app *App) OneOrganization(w http.ResponseWriter, r *http.Request) {
 			Valid: true,
 		})
 		if err != nil {
-			utils.RespondWithJSON(w, utils.ErrorResponse{
-				Detail: "Error getting organization",
-				Error:  err.Error(),
-			})
+			utils.RespondWithError(w, utils.DBError("Error getting organization", err))
 			return
 		}
 	} else {
@@ -270,6 +323,7 @@ func (app *App) OneOrganization(w http.ResponseWriter, r *http.Request) {
 		OrganizationName string    `json:"organization_name"`
 		Email            string    `json:"email"`
 		ActiveUntil      time.Time `json:"active_until"`
+		RequireTwofactor bool      `json:"require_twofactor"`
 	}
 
 	response := OrganizationResponse{
@@ -278,6 +332,7 @@ func (app *App) OneOrganization(w http.ResponseWriter, r *http.Request) {
 		OrganizationName: organization.OrganizationName,
 		Email:            organization.Email,
 		ActiveUntil:      organization.ActiveUntil.Time,
+		RequireTwofactor: organization.RequireTwofactor,
 	}
 
 	utils.RespondWithJSON(w, response)
@@ -0,0 +1,195 @@
+package admin
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"log"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/jackc/pgx/v5/pgtype"
+	cache "github.com/karl1b/go4lage/pkg/cache"
+	mail "github.com/karl1b/go4lage/pkg/mail"
+	settings "github.com/karl1b/go4lage/pkg/settings"
+	"github.com/karl1b/go4lage/pkg/sql/db"
+	utils "github.com/karl1b/go4lage/pkg/utils"
+)
+
+// RequestPasswordReset mails a single use reset token to the user.
+// It always answers the same way, so it can not be used to find out which emails are registered.
+func (app *App) RequestPasswordReset(w http.ResponseWriter, r *http.Request) {
+	err := cache.Loginthrottler.Check(utils.ClientIP(r)) // Auth throttle
+	if err != nil {
+		utils.RespondWithError(w, utils.TooManyRequests("login_throttled", "Auththrottle", err))
+		return
+	}
+
+	body, err := io.ReadAll(r.Body)
+	if err != nil {
+		utils.RespondWithError(w, utils.BadRequest("invalid_body", "Error reading body", err))
+		return
+	}
+	defer r.Body.Close()
+
+	type RequestBody struct {
+		Email string `json:"email"`
+	}
+
+	var reqBody RequestBody
+	err = json.Unmarshal(body, &reqBody)
+	if err != nil {
+		utils.RespondWithError(w, utils.BadRequest("invalid_body", "Invalid JSON format", err))
+		return
+	}
+
+	answer := utils.ToastResponse{
+		Header: "Password reset",
+		Text:   "If the email is registered, a reset link was sent to it.",
+	}
+
+	user, err := app.Queries.SelectUserByEmail(context.Background(), strings.TrimSpace(strings.ToLower(reqBody.Email)))
+	if err != nil {
+		utils.RespondWithJSON(w, answer)
+		return
+	}
+
+	if !user.IsActive.Bool && !user.IsSuperuser.Bool {
+		utils.RespondWithJSON(w, answer)
+		return
+	}
+
+	resetToken, err := utils.GenerateTokenHex(32)
+	if err != nil {
+		utils.RespondWithError(w, utils.Internal("internal", "Error generating reset token", err))
+		return
+	}
+
+	// Only the digest is stored, the plain token is only known to the mail receiver.
+	_, err = app.Queries.UpdateResetTokenByID(context.Background(), db.UpdateResetTokenByIDParams{
+		ID:         user.ID,
+		ResetToken: pgtype.Text{String: utils.HashToken(resetToken), Valid: true},
+	})
+	if err != nil {
+		utils.RespondWithError(w, utils.DBError("Error writing reset token to database", err))
+		return
+	}
+
+	text := fmt.Sprintf(`Hello %s,
+
+someone requested a password reset for your account.
+Open the link below to choose a new password. It is valid for %d minutes and can only be used once.
+
+%s/resetpassword?token=%s
+
+If you did not request this, you can ignore this mail.`,
+		user.Username, settings.Settings.ResetTokenValidMins, strings.TrimSpace(settings.Settings.Baseurl), resetToken)
+
+	err = mail.Send(user.Email, "Password reset", text)
+	if err != nil {
+		log.Println("Error sending password reset mail:", err)
+	}
+
+	utils.RespondWithJSON(w, answer)
+}
+
+// ConfirmPasswordReset sets a new password for the owner of a valid reset token.
+// The reset token and all sessions of the user are invalidated afterwards.
+func (app *App) ConfirmPasswordReset(w http.ResponseWriter, r *http.Request) {
+	err := cache.Loginthrottler.Check(utils.ClientIP(r)) // Auth throttle
+	if err != nil {
+		utils.RespondWithError(w, utils.TooManyRequests("login_throttled", "Auththrottle", err))
+		return
+	}
+
+	body, err := io.ReadAll(r.Body)
+	if err != nil {
+		utils.RespondWithError(w, utils.BadRequest("invalid_body", "Error reading body", err))
+		return
+	}
+	defer r.Body.Close()
+
+	type RequestBody struct {
+		Token    string `json:"token"`
+		Password string `json:"password"`
+	}
+
+	var reqBody RequestBody
+	err = json.Unmarshal(body, &reqBody)
+	if err != nil {
+		utils.RespondWithError(w, utils.BadRequest("invalid_body", "Invalid JSON format", err))
+		return
+	}
+
+	if reqBody.Password == "" {
+		utils.RespondWithError(w, utils.Unprocessable("invalid_password", "Error Pasword Is the password valid?", nil))
+		return
+	}
+
+	if reqBody.Token == "" {
+		utils.RespondWithError(w, utils.Unprocessable("reset_token_invalid", "Reset token not valid", errors.New("reset token may not be blank")))
+		return
+	}
+
+	user, err := app.Queries.SelectUserByResetToken(context.Background(), pgtype.Text{String: utils.HashToken(reqBody.Token), Valid: true})
+	if err != nil {
+		utils.RespondWithError(w, utils.Unprocessable("reset_token_invalid", "Reset token not valid", err))
+		return
+	}
+
+	if !user.ResetTokenCreatedAt.Valid || user.ResetTokenCreatedAt.Time.Add(time.Duration(settings.Settings.ResetTokenValidMins)*time.Minute).Before(time.Now()) {
+		utils.RespondWithError(w, utils.Unprocessable("reset_token_expired", "Reset token expired. Request a new one.", errors.New("reset token outdated")))
+		return
+	}
+
+	err = utils.ValidatePassword(reqBody.Password, user.Email, user.Username)
+	if err != nil {
+		utils.RespondWithError(w, err)
+		return
+	}
+
+	newpassword, err := utils.HashPassword(reqBody.Password)
+	if err != nil {
+		utils.RespondWithError(w, utils.Internal("internal", "Error hashing password", err))
+		return
+	}
+
+	tx, qtx, err := app.beginTx()
+	if err != nil {
+		utils.RespondWithError(w, utils.DBError("Error starting transaction", err))
+		return
+	}
+	defer tx.Rollback(context.Background())
+
+	_, err = qtx.ResetPasswordByID(context.Background(), db.ResetPasswordByIDParams{
+		ID:       user.ID,
+		Password: newpassword,
+	})
+	if err != nil {
+		utils.RespondWithError(w, utils.DBError("Error writing new password to database", err))
+		return
+	}
+
+	// All sessions end, whoever used them has to login with the new password.
+	sessions, err := qtx.SessionDeleteByUserId(context.Background(), user.ID)
+	if err != nil {
+		utils.RespondWithError(w, utils.DBError("Error deleting sessions", err))
+		return
+	}
+
+	err = tx.Commit(context.Background())
+	if err != nil {
+		utils.RespondWithError(w, utils.DBError("Error writing new password to database", err))
+		return
+	}
+	cache.DelSessions(sessions)
+	cache.Go4users.Del(user.ID.Bytes) // The user is changed and hence needs to be deleted from cache.
+
+	utils.RespondWithJSON(w, utils.ToastResponse{
+		Header: "Password reset",
+		Text:   "Your password was changed. Please login again.",
+	})
+}
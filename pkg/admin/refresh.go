@@ -0,0 +1,161 @@
+package admin
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"log"
+	"net/http"
+	"time"
+
+	"github.com/jackc/pgx/v5/pgtype"
+	cache "github.com/karl1b/go4lage/pkg/cache"
+	"github.com/karl1b/go4lage/pkg/sql/db"
+	utils "github.com/karl1b/go4lage/pkg/utils"
+)
+
+// Creates a new bearer token and a new refresh token.
+func newTokenPair() (string, string, error) {
+	token, err := utils.GenerateTokenHex(32)
+	if err != nil {
+		return "", "", err
+	}
+	refreshToken, err := utils.GenerateTokenHex(32)
+	if err != nil {
+		return "", "", err
+	}
+	return token, refreshToken, nil
+}
+
+// Refresh replaces the bearer token and the refresh token of a session. Every refresh token works only once.
+// If a used refresh token is sent again, someone else has a copy of it and the whole session is revoked.
+func (app *App) Refresh(w http.ResponseWriter, r *http.Request) {
+	body, err := io.ReadAll(r.Body)
+	if err != nil {
+		utils.RespondWithError(w, utils.BadRequest("invalid_body", "Error reading body", err))
+		return
+	}
+	defer r.Body.Close()
+
+	type RequestBody struct {
+		RefreshToken string `json:"refresh_token"`
+	}
+
+	var reqBody RequestBody
+	err = json.Unmarshal(body, &reqBody)
+	if err != nil {
+		utils.RespondWithError(w, utils.BadRequest("invalid_body", "Invalid JSON format", err))
+		return
+	}
+
+	if reqBody.RefreshToken == "" {
+		utils.RespondWithError(w, utils.Unauthorized("refresh_token_invalid", "Login again.", errors.New("refresh token may not be blank")))
+		return
+	}
+
+	refreshTokenHash := utils.HashToken(reqBody.RefreshToken)
+
+	session, err := app.Queries.SessionSelectByRefreshTokenHash(context.Background(), pgtype.Text{String: refreshTokenHash, Valid: true})
+	if err != nil {
+		used, usedErr := app.Queries.UsedRefreshTokenSelectByTokenHash(context.Background(), refreshTokenHash)
+		if usedErr == nil {
+			app.revokeReusedSession(r, used.SessionID)
+		}
+		utils.RespondWithError(w, utils.Unauthorized("refresh_token_invalid", "Login again.", errors.New("refresh token not valid")))
+		return
+	}
+
+	user, err := cache.GetUserByID(session.UserID, app.Queries)
+	if err != nil {
+		utils.RespondWithError(w, utils.Unauthorized("refresh_token_invalid", "Login again.", err))
+		return
+	}
+
+	if !user.IsActive.Bool && !user.IsSuperuser.Bool {
+		utils.RespondWithError(w, utils.Forbidden("user_inactive", "User is not active", errors.New("user is not active")))
+		return
+	}
+
+	if session.RefreshedAt.Time.Add(time.Duration(refreshValidMins(user)) * time.Minute).Before(time.Now()) {
+		_, err = app.Queries.SessionDeleteById(context.Background(), session.ID)
+		if err != nil {
+			log.Println("Error deleting outdated session:", err)
+		}
+		cache.Go4sessions.Del(session.TokenHash)
+		utils.RespondWithError(w, utils.Unauthorized("refresh_token_invalid", "Login again.", errors.New("refresh token outdated")))
+		return
+	}
+
+	newToken, newRefreshToken, err := newTokenPair()
+	if err != nil {
+		utils.RespondWithError(w, utils.DBError("Error refreshing token", err))
+		return
+	}
+
+	// Marking the old refresh token as used and rotating must happen together.
+	// The primary key on the used token makes sure only one of two parallel refreshes wins.
+	tx, err := app.DB.Begin(context.Background())
+	if err != nil {
+		utils.RespondWithError(w, utils.DBError("Error refreshing token", err))
+		return
+	}
+	defer tx.Rollback(context.Background())
+	qtx := app.Queries.WithTx(tx)
+
+	err = qtx.UsedRefreshTokenCreate(context.Background(), db.UsedRefreshTokenCreateParams{
+		TokenHash: refreshTokenHash,
+		SessionID: session.ID,
+	})
+	if err != nil {
+		utils.RespondWithError(w, utils.Unauthorized("refresh_token_invalid", "Login again.", errors.New("refresh token not valid")))
+		return
+	}
+
+	_, err = qtx.SessionRotate(context.Background(), db.SessionRotateParams{
+		ID:               session.ID,
+		TokenHash:        utils.HashToken(newToken),
+		RefreshTokenHash: pgtype.Text{String: utils.HashToken(newRefreshToken), Valid: true},
+	})
+	if err != nil {
+		utils.RespondWithError(w, utils.DBError("Error writing session to database", err))
+		return
+	}
+
+	err = tx.Commit(context.Background())
+	if err != nil {
+		utils.RespondWithError(w, utils.DBError("Error writing session to database", err))
+		return
+	}
+	cache.Go4sessions.Del(session.TokenHash) // The old bearer token must not work from cache.
+
+	type Response struct {
+		Token        string `json:"token"`
+		RefreshToken string `json:"refresh_token"`
+	}
+
+	utils.RespondWithJSON(w, Response{Token: newToken, RefreshToken: newRefreshToken})
+}
+
+// Revokes the session a replayed refresh token belongs to and audits it.
+func (app *App) revokeReusedSession(r *http.Request, sessionID pgtype.UUID) {
+	session, err := app.Queries.SessionDeleteById(context.Background(), sessionID)
+	if err != nil {
+		log.Println("Error revoking session of reused refresh token:", err)
+		return
+	}
+	cache.Go4sessions.Del(session.TokenHash)
+
+	// Superusers may have no organization, then it stays empty.
+	organization, _ := app.Queries.OrganizationSelectUserOrganization(context.Background(), session.UserID)
+
+	err = writeAudit(app.Queries, r, auditEntry{
+		Organization: organization.ID,
+		Action:       AuditRefreshTokenReused,
+		Target:       session.UserID,
+		Changes:      map[string]string{"user_agent": session.UserAgent.String, "ip": session.Ip.String},
+	})
+	if err != nil {
+		log.Println("Error writing audit event:", err)
+	}
+}
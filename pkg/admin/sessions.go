@@ -0,0 +1,272 @@
+package admin
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5/pgtype"
+	cache "github.com/karl1b/go4lage/pkg/cache"
+	settings "github.com/karl1b/go4lage/pkg/settings"
+	"github.com/karl1b/go4lage/pkg/sql/db"
+	utils "github.com/karl1b/go4lage/pkg/utils"
+)
+
+// SessionResponse is a session as the dashboard sees it. The token hash is never sent out.
+type SessionResponse struct {
+	ID        uuid.UUID `json:"id"`
+	CreatedAt time.Time `json:"created_at"`
+	LastSeen  time.Time `json:"last_seen"`
+	UserAgent string    `json:"user_agent"`
+	Ip        string    `json:"ip"`
+	Current   bool      `json:"current"`
+}
+
+func toSessionResponses(sessions []db.Session, current pgtype.UUID) []SessionResponse {
+	response := []SessionResponse{}
+	for _, s := range sessions {
+		response = append(response, SessionResponse{
+			ID:        uuid.UUID(s.ID.Bytes),
+			CreatedAt: s.CreatedAt.Time,
+			LastSeen:  s.LastSeen.Time,
+			UserAgent: s.UserAgent.String,
+			Ip:        s.Ip.String,
+			Current:   s.ID == current,
+		})
+	}
+	return response
+}
+
+// How long the refresh token of a session of this user is valid. Every refresh starts this time again.
+func refreshValidMins(user db.User) int {
+	if user.IsSuperuser.Bool {
+		return settings.Settings.SuperuserRefreshValidMins
+	}
+	return settings.Settings.RefreshTokenValidMins
+}
+
+// Superusers can manage every user, everyone else only the users of the own organization.
+func (app *App) canManageUser(rinfo utils.InfoKey, userID pgtype.UUID) error {
+	if rinfo.User.IsSuperuser.Bool {
+		return nil
+	}
+	userOrganization, err := app.Queries.OrganizationSelectUserOrganization(context.Background(), userID)
+	if err != nil {
+		return err
+	}
+	if userOrganization.ID != rinfo.Organization.ID {
+		return errors.New("user organization is not your organization")
+	}
+	return nil
+}
+
+// Lists the sessions of the requesting user.
+func (app *App) MySessions(w http.ResponseWriter, r *http.Request) {
+	infos, ok := r.Context().Value(utils.InfoContextKey).(utils.InfoKey)
+	if !ok {
+		utils.RespondWithError(w, utils.Unauthorized("unauthenticated", "Failed to get user from context", errors.New("failed to get user from context")))
+		return
+	}
+
+	sessions, err := app.Queries.SessionSelectByUserId(context.Background(), infos.User.ID)
+	if err != nil {
+		utils.RespondWithError(w, utils.DBError("Error getting sessions", err))
+		return
+	}
+
+	utils.RespondWithJSON(w, toSessionResponses(sessions, infos.Session.ID))
+}
+
+// Revokes one session of the requesting user.
+func (app *App) RevokeMySession(w http.ResponseWriter, r *http.Request) {
+	infos, ok := r.Context().Value(utils.InfoContextKey).(utils.InfoKey)
+	if !ok {
+		utils.RespondWithError(w, utils.Unauthorized("unauthenticated", "Failed to get user from context", errors.New("failed to get user from context")))
+		return
+	}
+
+	sessionUUID, err := uuid.Parse(r.Header.Get("Id"))
+	if err != nil {
+		utils.RespondWithError(w, utils.BadRequest("invalid_id", "Error parsing ID", err))
+		return
+	}
+
+	session, err := app.Queries.SessionSelectById(context.Background(), pgtype.UUID{Bytes: sessionUUID, Valid: true})
+	if err != nil || session.UserID != infos.User.ID {
+		utils.RespondWithError(w, utils.NotFound("session_not_found", "Session not found", errors.New("session not found")))
+		return
+	}
+
+	_, err = app.Queries.SessionDeleteById(context.Background(), session.ID)
+	if err != nil {
+		utils.RespondWithError(w, utils.DBError("Error deleting session", err))
+		return
+	}
+	cache.Go4sessions.Del(session.TokenHash)
+
+	utils.RespondWithJSON(w, utils.ToastResponse{
+		Header: "Session",
+		Text:   "Session revoked",
+	})
+}
+
+// Revokes all sessions of the requesting user except the one of the request.
+func (app *App) RevokeMyOtherSessions(w http.ResponseWriter, r *http.Request) {
+	infos, ok := r.Context().Value(utils.InfoContextKey).(utils.InfoKey)
+	if !ok {
+		utils.RespondWithError(w, utils.Unauthorized("unauthenticated", "Failed to get user from context", errors.New("failed to get user from context")))
+		return
+	}
+
+	sessions, err := app.Queries.SessionDeleteOthersByUserId(context.Background(), db.SessionDeleteOthersByUserIdParams{
+		UserID: infos.User.ID,
+		ID:     infos.Session.ID,
+	})
+	if err != nil {
+		utils.RespondWithError(w, utils.DBError("Error deleting sessions", err))
+		return
+	}
+	cache.DelSessions(sessions)
+
+	utils.RespondWithJSON(w, utils.ToastResponse{
+		Header: "Sessions",
+		Text:   "All other sessions revoked",
+	})
+}
+
+// Revokes one session of a user. Organization admins can only revoke sessions of their organization members.
+func (app *App) RevokeUserSession(w http.ResponseWriter, r *http.Request) {
+	rinfo, ok := r.Context().Value(utils.InfoContextKey).(utils.InfoKey)
+	if !ok {
+		utils.RespondWithError(w, utils.Unauthorized("unauthenticated", "User not found in context", errors.New("user not found")))
+		return
+	}
+
+	sessionUUID, err := uuid.Parse(r.Header.Get("Id"))
+	if err != nil {
+		utils.RespondWithError(w, utils.BadRequest("invalid_id", "Error parsing ID", err))
+		return
+	}
+
+	session, err := app.Queries.SessionSelectById(context.Background(), pgtype.UUID{Bytes: sessionUUID, Valid: true})
+	if err != nil {
+		utils.RespondWithError(w, utils.NotFound("session_not_found", "Session not found", err))
+		return
+	}
+
+	err = app.canManageUser(rinfo, session.UserID)
+	if err != nil {
+		utils.RespondWithError(w, utils.Forbidden("forbidden", "No permission to revoke this session", err))
+		return
+	}
+
+	tx, qtx, err := app.beginTx()
+	if err != nil {
+		utils.RespondWithError(w, utils.DBError("Error starting transaction", err))
+		return
+	}
+	defer tx.Rollback(context.Background())
+
+	organizationID, err := userOrganizationID(qtx, session.UserID)
+	if err != nil {
+		utils.RespondWithError(w, utils.DBError("Error getting organization", err))
+		return
+	}
+
+	_, err = qtx.SessionDeleteById(context.Background(), session.ID)
+	if err != nil {
+		utils.RespondWithError(w, utils.DBError("Error deleting session", err))
+		return
+	}
+
+	err = writeAudit(qtx, r, auditEntry{
+		Actor:        rinfo.User.ID,
+		Organization: organizationID,
+		Action:       AuditSessionRevoked,
+		Target:       session.UserID,
+		Changes:      map[string]string{"session": sessionUUID.String()},
+	})
+	if err != nil {
+		utils.RespondWithError(w, utils.Internal("audit_failed", "Error writing audit event", err))
+		return
+	}
+
+	err = tx.Commit(context.Background())
+	if err != nil {
+		utils.RespondWithError(w, utils.DBError("Error deleting session", err))
+		return
+	}
+	cache.Go4sessions.Del(session.TokenHash)
+
+	utils.RespondWithJSON(w, utils.ToastResponse{
+		Header: "Session",
+		Text:   "Session revoked",
+	})
+}
+
+// Revokes all sessions of a user. Organization admins can only revoke sessions of their organization members.
+func (app *App) RevokeUserSessions(w http.ResponseWriter, r *http.Request) {
+	rinfo, ok := r.Context().Value(utils.InfoContextKey).(utils.InfoKey)
+	if !ok {
+		utils.RespondWithError(w, utils.Unauthorized("unauthenticated", "User not found in context", errors.New("user not found")))
+		return
+	}
+
+	useriduuid, err := uuid.Parse(r.Header.Get("Id"))
+	if err != nil {
+		utils.RespondWithError(w, utils.BadRequest("invalid_id", "Error getting parsing ID", err))
+		return
+	}
+	userID := pgtype.UUID{Bytes: useriduuid, Valid: true}
+
+	err = app.canManageUser(rinfo, userID)
+	if err != nil {
+		utils.RespondWithError(w, utils.Forbidden("forbidden", "No permission to revoke sessions of this user", err))
+		return
+	}
+
+	tx, qtx, err := app.beginTx()
+	if err != nil {
+		utils.RespondWithError(w, utils.DBError("Error starting transaction", err))
+		return
+	}
+	defer tx.Rollback(context.Background())
+
+	organizationID, err := userOrganizationID(qtx, userID)
+	if err != nil {
+		utils.RespondWithError(w, utils.DBError("Error getting organization", err))
+		return
+	}
+
+	sessions, err := qtx.SessionDeleteByUserId(context.Background(), userID)
+	if err != nil {
+		utils.RespondWithError(w, utils.DBError("Error deleting sessions", err))
+		return
+	}
+
+	err = writeAudit(qtx, r, auditEntry{
+		Actor:        rinfo.User.ID,
+		Organization: organizationID,
+		Action:       AuditSessionsRevoked,
+		Target:       userID,
+		Changes:      map[string]int{"sessions": len(sessions)},
+	})
+	if err != nil {
+		utils.RespondWithError(w, utils.Internal("audit_failed", "Error writing audit event", err))
+		return
+	}
+
+	err = tx.Commit(context.Background())
+	if err != nil {
+		utils.RespondWithError(w, utils.DBError("Error deleting sessions", err))
+		return
+	}
+	cache.DelSessions(sessions)
+
+	utils.RespondWithJSON(w, utils.ToastResponse{
+		Header: "Sessions",
+		Text:   "All sessions of the user revoked",
+	})
+}
@@ -0,0 +1,191 @@
+package admin
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5/pgtype"
+	"github.com/karl1b/go4lage/pkg/cache"
+	settings "github.com/karl1b/go4lage/pkg/settings"
+	utils "github.com/karl1b/go4lage/pkg/utils"
+)
+
+/*
+Deleted users and organizations are only marked with deleted_at and are hidden everywhere else.
+Superusers see them in the trash and can restore them. After TRASH_RETENTION_DAYS "go4lage purgetrash" deletes them for good.
+*/
+
+// Tells when a deleted record is purged.
+func purgeAt(deletedAt pgtype.Timestamptz) time.Time {
+	return deletedAt.Time.AddDate(0, 0, settings.Settings.TrashRetentionDays)
+}
+
+func (app *App) Trash(w http.ResponseWriter, r *http.Request) {
+
+	type TrashUser struct {
+		ID        uuid.UUID `json:"id"`
+		Email     string    `json:"email"`
+		Username  string    `json:"username"`
+		DeletedAt time.Time `json:"deleted_at"`
+		PurgeAt   time.Time `json:"purge_at"`
+	}
+
+	type TrashOrganization struct {
+		ID               uuid.UUID `json:"id"`
+		OrganizationName string    `json:"organization_name"`
+		Email            string    `json:"email"`
+		DeletedAt        time.Time `json:"deleted_at"`
+		PurgeAt          time.Time `json:"purge_at"`
+	}
+
+	type TrashResponse struct {
+		RetentionDays int                 `json:"retention_days"`
+		Users         []TrashUser         `json:"users"`
+		Organizations []TrashOrganization `json:"organizations"`
+	}
+
+	users, err := app.Queries.UserSelectDeleted(context.Background())
+	if err != nil {
+		utils.RespondWithError(w, utils.DBError("Error getting deleted users", err))
+		return
+	}
+
+	organizations, err := app.Queries.OrganizationSelectDeleted(context.Background())
+	if err != nil {
+		utils.RespondWithError(w, utils.DBError("Error getting deleted organizations", err))
+		return
+	}
+
+	Answer := TrashResponse{
+		RetentionDays: settings.Settings.TrashRetentionDays,
+		Users:         []TrashUser{},
+		Organizations: []TrashOrganization{},
+	}
+
+	for _, user := range users {
+		Answer.Users = append(Answer.Users, TrashUser{
+			ID:        uuid.UUID(user.ID.Bytes),
+			Email:     user.Email,
+			Username:  user.Username,
+			DeletedAt: user.DeletedAt.Time,
+			PurgeAt:   purgeAt(user.DeletedAt),
+		})
+	}
+
+	for _, organization := range organizations {
+		Answer.Organizations = append(Answer.Organizations, TrashOrganization{
+			ID:               uuid.UUID(organization.ID.Bytes),
+			OrganizationName: organization.OrganizationName,
+			Email:            organization.Email,
+			DeletedAt:        organization.DeletedAt.Time,
+			PurgeAt:          purgeAt(organization.DeletedAt),
+		})
+	}
+
+	utils.RespondWithJSON(w, Answer)
+}
+
+func (app *App) RestoreUser(w http.ResponseWriter, r *http.Request) {
+	useruuid, err := uuid.Parse(r.Header.Get("Id"))
+	if err != nil {
+		utils.RespondWithError(w, utils.BadRequest("invalid_id", "Error parsing ID", err))
+		return
+	}
+
+	rinfo, ok := r.Context().Value(utils.InfoContextKey).(utils.InfoKey)
+	if !ok {
+		utils.RespondWithError(w, utils.Unauthorized("unauthenticated", "User not found in context", errors.New("user not found")))
+		return
+	}
+
+	userID := pgtype.UUID{Bytes: useruuid, Valid: true}
+
+	tx, qtx, err := app.beginTx()
+	if err != nil {
+		utils.RespondWithError(w, utils.DBError("Error starting transaction", err))
+		return
+	}
+	defer tx.Rollback(context.Background())
+
+	_, err = qtx.UserRestore(context.Background(), userID)
+	if err != nil {
+		utils.RespondWithError(w, utils.DBError("This user is not in the trash", err))
+		return
+	}
+
+	after, userOrganizationID, err := loadUserAudit(qtx, userID)
+	if err != nil {
+		utils.RespondWithError(w, utils.DBError("Error getting this user", err))
+		return
+	}
+
+	err = writeUserAudit(qtx, r, rinfo.User.ID, AuditUserRestored, userID, userOrganizationID, nil, after)
+	if err != nil {
+		utils.RespondWithError(w, utils.Internal("audit_failed", "Error writing audit event", err))
+		return
+	}
+
+	err = tx.Commit(context.Background())
+	if err != nil {
+		utils.RespondWithError(w, utils.DBError("Error restoring this user", err))
+		return
+	}
+
+	cache.Go4users.Del(useruuid)
+
+	utils.RespondWithJSON(w, utils.ToastResponse{
+		Header: "User restored",
+		Text:   "",
+	})
+}
+
+func (app *App) RestoreOrganization(w http.ResponseWriter, r *http.Request) {
+	organizationUUID, err := uuid.Parse(r.Header.Get("Id"))
+	if err != nil {
+		utils.RespondWithError(w, utils.BadRequest("invalid_id", "Cannot parse organization ID", err))
+		return
+	}
+
+	rinfo, ok := r.Context().Value(utils.InfoContextKey).(utils.InfoKey)
+	if !ok {
+		utils.RespondWithError(w, utils.Unauthorized("unauthenticated", "User not found in context", errors.New("user not found")))
+		return
+	}
+
+	organizationID := pgtype.UUID{Bytes: organizationUUID, Valid: true}
+
+	tx, qtx, err := app.beginTx()
+	if err != nil {
+		utils.RespondWithError(w, utils.DBError("Error starting transaction", err))
+		return
+	}
+	defer tx.Rollback(context.Background())
+
+	organization, err := qtx.OrganizationRestore(context.Background(), organizationID)
+	if err != nil {
+		utils.RespondWithError(w, utils.DBError("This organization is not in the trash", err))
+		return
+	}
+
+	err = writeOrganizationAudit(qtx, r, rinfo.User.ID, AuditOrganizationRestored, organizationID, nil, newOrganizationAudit(organization))
+	if err != nil {
+		utils.RespondWithError(w, utils.Internal("audit_failed", "Error writing audit event", err))
+		return
+	}
+
+	err = tx.Commit(context.Background())
+	if err != nil {
+		utils.RespondWithError(w, utils.DBError("Error restoring organization", err))
+		return
+	}
+
+	cache.Go4Organizations.Flush() // The cache is keyed by user id, so all members are dropped this way.
+
+	utils.RespondWithJSON(w, utils.ToastResponse{
+		Header: "Organization restored",
+		Text:   "",
+	})
+}
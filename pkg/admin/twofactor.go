@@ -0,0 +1,403 @@
+package admin
+
+import (
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"io"
+	"log"
+	"net/http"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5/pgtype"
+	cache "github.com/karl1b/go4lage/pkg/cache"
+	"github.com/karl1b/go4lage/pkg/sql/db"
+	utils "github.com/karl1b/go4lage/pkg/utils"
+	"github.com/pquerna/otp/totp"
+)
+
+// TwofactorEnrollment is everything an authenticator app needs. QrPng is a base64 encoded PNG.
+type TwofactorEnrollment struct {
+	Secret     string `json:"secret"`
+	OtpauthURI string `json:"otpauth_uri"`
+	QrPng      string `json:"qr_png"`
+}
+
+// RecoveryCodesResponse holds the plain recovery codes. They are only sent out once.
+type RecoveryCodesResponse struct {
+	RecoveryCodes []string `json:"recovery_codes"`
+}
+
+// Stores a new pending secret for the user. It gets enabled by the first valid code.
+func (app *App) startTwofactorEnrollment(user db.User) (TwofactorEnrollment, error) {
+	key, qrCode, err := utils.NewTOTPKey(user.Email)
+	if err != nil {
+		return TwofactorEnrollment{}, err
+	}
+
+	_, err = app.Queries.UpdateTwofactorByID(context.Background(), db.UpdateTwofactorByIDParams{
+		ID:               user.ID,
+		Twofactorsecret:  pgtype.Text{String: key.Secret(), Valid: true},
+		TwofactorEnabled: false,
+	})
+	if err != nil {
+		return TwofactorEnrollment{}, err
+	}
+	cache.Go4users.Del(user.ID.Bytes) // The user is changed and hence needs to be deleted from cache.
+
+	return TwofactorEnrollment{
+		Secret:     key.Secret(),
+		OtpauthURI: key.URL(),
+		QrPng:      base64.StdEncoding.EncodeToString(qrCode),
+	}, nil
+}
+
+// Enables the pending secret of the user and returns a fresh set of recovery codes.
+// Both happen in one transaction, so 2FA is never enabled without recovery codes.
+func (app *App) enableTwofactor(user db.User) ([]string, error) {
+	tx, qtx, err := app.beginTx()
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback(context.Background())
+
+	_, err = qtx.UpdateTwofactorByID(context.Background(), db.UpdateTwofactorByIDParams{
+		ID:               user.ID,
+		Twofactorsecret:  user.Twofactorsecret,
+		TwofactorEnabled: true,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	recoveryCodes, err := utils.NewRecoveryCodes(qtx, user.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	err = tx.Commit(context.Background())
+	if err != nil {
+		return nil, err
+	}
+	cache.Go4users.Del(user.ID.Bytes) // The user is changed and hence needs to be deleted from cache.
+
+	return recoveryCodes, nil
+}
+
+// Removes the secret and the recovery codes of the user and disables 2FA.
+func (app *App) removeTwofactor(userID pgtype.UUID) error {
+	tx, qtx, err := app.beginTx()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback(context.Background())
+
+	err = clearTwofactor(qtx, userID)
+	if err != nil {
+		return err
+	}
+
+	err = tx.Commit(context.Background())
+	if err != nil {
+		return err
+	}
+	cache.Go4users.Del(userID.Bytes) // The user is changed and hence needs to be deleted from cache.
+
+	return nil
+}
+
+// Disables 2FA of the user with the queries of a transaction. The caller commits and clears the cache.
+func clearTwofactor(queries *db.Queries, userID pgtype.UUID) error {
+	_, err := queries.UpdateTwofactorByID(context.Background(), db.UpdateTwofactorByIDParams{
+		ID:               userID,
+		Twofactorsecret:  pgtype.Text{},
+		TwofactorEnabled: false,
+	})
+	if err != nil {
+		return err
+	}
+
+	return queries.RecoveryCodeDeleteByUserId(context.Background(), userID)
+}
+
+// Checks a recovery code in place of a TOTP code. A valid code is used up and the use is audited.
+func (app *App) consumeRecoveryCode(r *http.Request, user db.User, organization db.Organization, code string) error {
+	_, err := app.Queries.RecoveryCodeConsume(context.Background(), db.RecoveryCodeConsumeParams{
+		UserID:   user.ID,
+		CodeHash: utils.HashRecoveryCode(code),
+	})
+	if err != nil {
+		return err
+	}
+
+	// The code is already used up at this point, a failing audit must not lock the user out.
+	remaining, err := app.Queries.RecoveryCodeCountUnused(context.Background(), user.ID)
+	if err != nil {
+		log.Println("Error counting recovery codes:", err)
+	}
+	err = writeAudit(app.Queries, r, auditEntry{
+		Actor:        user.ID,
+		Organization: organization.ID,
+		Action:       AuditRecoveryCodeUsed,
+		Target:       user.ID,
+		Changes:      map[string]int64{"remaining": remaining},
+	})
+	if err != nil {
+		log.Println("Error writing audit event:", err)
+	}
+	return nil
+}
+
+// Starts the 2FA enrollment of the requesting user. Confirm it with TwofactorVerify.
+func (app *App) TwofactorEnroll(w http.ResponseWriter, r *http.Request) {
+	infos, ok := r.Context().Value(utils.InfoContextKey).(utils.InfoKey)
+	if !ok {
+		utils.RespondWithError(w, utils.Unauthorized("unauthenticated", "Failed to get user from context", errors.New("failed to get user from context")))
+		return
+	}
+
+	if infos.User.TwofactorEnabled {
+		utils.RespondWithError(w, utils.Conflict("twofa_already_enabled", "2FA is already enabled. Disable it first.", errors.New("2fa already enabled")))
+		return
+	}
+
+	enrollment, err := app.startTwofactorEnrollment(infos.User)
+	if err != nil {
+		utils.RespondWithError(w, utils.DBError("Error starting 2FA enrollment", err))
+		return
+	}
+
+	utils.RespondWithJSON(w, enrollment)
+}
+
+// Enables the pending 2FA secret of the requesting user with a valid code.
+func (app *App) TwofactorVerify(w http.ResponseWriter, r *http.Request) {
+	infos, ok := r.Context().Value(utils.InfoContextKey).(utils.InfoKey)
+	if !ok {
+		utils.RespondWithError(w, utils.Unauthorized("unauthenticated", "Failed to get user from context", errors.New("failed to get user from context")))
+		return
+	}
+
+	body, err := io.ReadAll(r.Body)
+	if err != nil {
+		utils.RespondWithError(w, utils.BadRequest("invalid_body", "Error reading body", err))
+		return
+	}
+	defer r.Body.Close()
+
+	type RequestBody struct {
+		Twofactorkey string `json:"twofakey"`
+	}
+
+	var reqBody RequestBody
+	err = json.Unmarshal(body, &reqBody)
+	if err != nil {
+		utils.RespondWithError(w, utils.BadRequest("invalid_body", "Invalid JSON format", err))
+		return
+	}
+
+	user := infos.User
+
+	if user.TwofactorEnabled {
+		utils.RespondWithError(w, utils.Conflict("twofa_already_enabled", "2FA is already enabled", errors.New("2fa already enabled")))
+		return
+	}
+
+	if user.Twofactorsecret.String == "" {
+		utils.RespondWithError(w, utils.Conflict("twofa_enrollment_missing", "Start the 2FA enrollment first", errors.New("no pending 2fa secret")))
+		return
+	}
+
+	if !totp.Validate(reqBody.Twofactorkey, user.Twofactorsecret.String) {
+		utils.RespondWithError(w, utils.Unprocessable("twofa_invalid", "2fa not valid", errors.New("2fa not valid")))
+		return
+	}
+
+	recoveryCodes, err := app.enableTwofactor(user)
+	if err != nil {
+		utils.RespondWithError(w, utils.DBError("Error enabling 2FA", err))
+		return
+	}
+
+	utils.RespondWithJSON(w, RecoveryCodesResponse{RecoveryCodes: recoveryCodes})
+}
+
+// Replaces the recovery codes of the requesting user. A valid TOTP code is needed.
+func (app *App) TwofactorRecoveryCodes(w http.ResponseWriter, r *http.Request) {
+	infos, ok := r.Context().Value(utils.InfoContextKey).(utils.InfoKey)
+	if !ok {
+		utils.RespondWithError(w, utils.Unauthorized("unauthenticated", "Failed to get user from context", errors.New("failed to get user from context")))
+		return
+	}
+
+	body, err := io.ReadAll(r.Body)
+	if err != nil {
+		utils.RespondWithError(w, utils.BadRequest("invalid_body", "Error reading body", err))
+		return
+	}
+	defer r.Body.Close()
+
+	type RequestBody struct {
+		Twofactorkey string `json:"twofakey"`
+	}
+
+	var reqBody RequestBody
+	err = json.Unmarshal(body, &reqBody)
+	if err != nil {
+		utils.RespondWithError(w, utils.BadRequest("invalid_body", "Invalid JSON format", err))
+		return
+	}
+
+	user := infos.User
+
+	if !user.TwofactorEnabled {
+		utils.RespondWithError(w, utils.Conflict("twofa_not_enabled", "2FA is not enabled", errors.New("2fa not enabled")))
+		return
+	}
+
+	if !totp.Validate(reqBody.Twofactorkey, user.Twofactorsecret.String) {
+		utils.RespondWithError(w, utils.Unprocessable("twofa_invalid", "2fa not valid", errors.New("2fa not valid")))
+		return
+	}
+
+	tx, qtx, err := app.beginTx()
+	if err != nil {
+		utils.RespondWithError(w, utils.DBError("Error starting transaction", err))
+		return
+	}
+	defer tx.Rollback(context.Background())
+
+	recoveryCodes, err := utils.NewRecoveryCodes(qtx, user.ID)
+	if err != nil {
+		utils.RespondWithError(w, utils.DBError("Error creating recovery codes", err))
+		return
+	}
+
+	err = tx.Commit(context.Background())
+	if err != nil {
+		utils.RespondWithError(w, utils.DBError("Error creating recovery codes", err))
+		return
+	}
+
+	utils.RespondWithJSON(w, RecoveryCodesResponse{RecoveryCodes: recoveryCodes})
+}
+
+// Disables 2FA of the requesting user. A valid code is needed and it is refused if 2FA is mandatory for the user.
+func (app *App) TwofactorDisable(w http.ResponseWriter, r *http.Request) {
+	infos, ok := r.Context().Value(utils.InfoContextKey).(utils.InfoKey)
+	if !ok {
+		utils.RespondWithError(w, utils.Unauthorized("unauthenticated", "Failed to get user from context", errors.New("failed to get user from context")))
+		return
+	}
+
+	body, err := io.ReadAll(r.Body)
+	if err != nil {
+		utils.RespondWithError(w, utils.BadRequest("invalid_body", "Error reading body", err))
+		return
+	}
+	defer r.Body.Close()
+
+	type RequestBody struct {
+		Twofactorkey string `json:"twofakey"`
+	}
+
+	var reqBody RequestBody
+	err = json.Unmarshal(body, &reqBody)
+	if err != nil {
+		utils.RespondWithError(w, utils.BadRequest("invalid_body", "Invalid JSON format", err))
+		return
+	}
+
+	user := infos.User
+
+	if !user.TwofactorEnabled {
+		utils.RespondWithError(w, utils.Conflict("twofa_not_enabled", "2FA is not enabled", errors.New("2fa not enabled")))
+		return
+	}
+
+	if utils.TwofactorMandatory(user, infos.Organization) {
+		utils.RespondWithError(w, utils.Forbidden("twofa_mandatory", "2FA is mandatory for you", errors.New("2fa mandatory")))
+		return
+	}
+
+	if !totp.Validate(reqBody.Twofactorkey, user.Twofactorsecret.String) {
+		utils.RespondWithError(w, utils.Unprocessable("twofa_invalid", "2fa not valid", errors.New("2fa not valid")))
+		return
+	}
+
+	err = app.removeTwofactor(user.ID)
+	if err != nil {
+		utils.RespondWithError(w, utils.DBError("Error disabling 2FA", err))
+		return
+	}
+
+	utils.RespondWithJSON(w, utils.ToastResponse{
+		Header: "2FA",
+		Text:   "2FA disabled",
+	})
+}
+
+// Resets 2FA of a user that lost the device. If 2FA is mandatory, the user enrolls again on the next login.
+func (app *App) ResetUserTwofactor(w http.ResponseWriter, r *http.Request) {
+	rinfo, ok := r.Context().Value(utils.InfoContextKey).(utils.InfoKey)
+	if !ok {
+		utils.RespondWithError(w, utils.Unauthorized("unauthenticated", "User not found in context", errors.New("user not found")))
+		return
+	}
+
+	useriduuid, err := uuid.Parse(r.Header.Get("Id"))
+	if err != nil {
+		utils.RespondWithError(w, utils.BadRequest("invalid_id", "Error getting parsing ID", err))
+		return
+	}
+	userID := pgtype.UUID{Bytes: useriduuid, Valid: true}
+
+	err = app.canManageUser(rinfo, userID)
+	if err != nil {
+		utils.RespondWithError(w, utils.Forbidden("forbidden", "No permission to reset 2FA of this user", err))
+		return
+	}
+
+	tx, qtx, err := app.beginTx()
+	if err != nil {
+		utils.RespondWithError(w, utils.DBError("Error starting transaction", err))
+		return
+	}
+	defer tx.Rollback(context.Background())
+
+	organizationID, err := userOrganizationID(qtx, userID)
+	if err != nil {
+		utils.RespondWithError(w, utils.DBError("Error getting organization", err))
+		return
+	}
+
+	err = clearTwofactor(qtx, userID)
+	if err != nil {
+		utils.RespondWithError(w, utils.DBError("Error resetting 2FA", err))
+		return
+	}
+
+	err = writeAudit(qtx, r, auditEntry{
+		Actor:        rinfo.User.ID,
+		Organization: organizationID,
+		Action:       AuditTwofactorReset,
+		Target:       userID,
+	})
+	if err != nil {
+		utils.RespondWithError(w, utils.Internal("audit_failed", "Error writing audit event", err))
+		return
+	}
+
+	err = tx.Commit(context.Background())
+	if err != nil {
+		utils.RespondWithError(w, utils.DBError("Error resetting 2FA", err))
+		return
+	}
+	cache.Go4users.Del(userID.Bytes) // The user is changed and hence needs to be deleted from cache.
+
+	utils.RespondWithJSON(w, utils.ToastResponse{
+		Header: "2FA",
+		Text:   "2FA of the user reset",
+	})
+}
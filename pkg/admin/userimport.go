@@ -0,0 +1,557 @@
+package admin
+
+import (
+	"context"
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"log"
+	"net/http"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5/pgtype"
+	"github.com/karl1b/go4lage/pkg/sql/db"
+	utils "github.com/karl1b/go4lage/pkg/utils"
+)
+
+const (
+	importMaxBytes = 10 << 20
+	importMaxRows  = 5000
+)
+
+// importColumns are the columns of an import and the first ones of an export, so an export can be imported again.
+var importColumns = []string{"email", "username", "first_name", "last_name", "password", "groups", "organization", "is_active"}
+
+// importUser is one row of an import. Groups are separated by "|", the organization is its id or name.
+type importUser struct {
+	Email        string `json:"email"`
+	Username     string `json:"username"`
+	FirstName    string `json:"first_name"`
+	LastName     string `json:"last_name"`
+	Password     string `json:"password"`
+	Groups       string `json:"groups"`
+	Organization string `json:"organization"`
+	IsActive     *bool  `json:"is_active"` // Defaults to true.
+}
+
+// ImportRowError is a validation error of one row. Rows are counted from 1, without the CSV header.
+type ImportRowError struct {
+	Row   int    `json:"row"`
+	Email string `json:"email"`
+	Field string `json:"field"`
+	Error string `json:"error"`
+}
+
+// A valid row with everything resolved that is needed to create the user.
+type importRow struct {
+	importUser
+	Row          int
+	IsActive     bool
+	Groups       []string
+	Organization pgtype.UUID
+	Invite       bool
+}
+
+// ImportUsers creates many users at once from a CSV (Content-Type text/csv) or a JSON array.
+// With dry_run=true it only reports the rows that are not valid. Otherwise all users are created in one transaction or none at all.
+// Rows without password need invite=true, these users are invited to choose their password.
+// Organization admins can only import into their own organization.
+func (app *App) ImportUsers(w http.ResponseWriter, r *http.Request) {
+	rinfo, ok := r.Context().Value(utils.InfoContextKey).(utils.InfoKey)
+	if !ok {
+		utils.RespondWithError(w, utils.Unauthorized("unauthenticated", "User not found in context", errors.New("user not found")))
+		return
+	}
+
+	if !(rinfo.User.IsSuperuser.Bool || slices.Contains(rinfo.Permissions, utils.HandleOrganizationPermission)) {
+		utils.RespondWithError(w, utils.Forbidden("forbidden", "No permission to create users", errors.New("only superusers and OrganizationStaff can create users")))
+		return
+	}
+
+	query := r.URL.Query()
+	dryRun := query.Get("dry_run") == "true"
+	invite := query.Get("invite") == "true"
+
+	if invite {
+		err := checkInvitations()
+		if err != nil {
+			utils.RespondWithError(w, err)
+			return
+		}
+	}
+
+	body, err := io.ReadAll(http.MaxBytesReader(w, r.Body, importMaxBytes))
+	if err != nil {
+		utils.RespondWithError(w, utils.BadRequest("invalid_body", "Error reading body", err))
+		return
+	}
+	defer r.Body.Close()
+
+	var users []importUser
+	var rowErrors []ImportRowError
+	if strings.Contains(r.Header.Get("Content-Type"), "csv") {
+		users, rowErrors, err = parseImportCSV(body)
+	} else {
+		err = json.Unmarshal(body, &users)
+	}
+	if err != nil {
+		utils.RespondWithError(w, utils.BadRequest("invalid_body", "Error parsing the import", err))
+		return
+	}
+	if len(users) > importMaxRows {
+		utils.RespondWithError(w, utils.Unprocessable("import_too_large", fmt.Sprintf("Import at most %d users at once", importMaxRows), nil))
+		return
+	}
+
+	rows, validationErrors, err := app.validateImport(users, rinfo, invite)
+	if err != nil {
+		utils.RespondWithError(w, utils.DBError("Error validating the import", err))
+		return
+	}
+	rowErrors = append(rowErrors, validationErrors...)
+	slices.SortStableFunc(rowErrors, func(a, b ImportRowError) int { return a.Row - b.Row })
+
+	type Response struct {
+		DryRun  bool             `json:"dry_run"`
+		Total   int              `json:"total"`
+		Valid   int              `json:"valid"`
+		Created int              `json:"created"`
+		Invited int              `json:"invited"`
+		Errors  []ImportRowError `json:"errors"`
+	}
+	invalidRows := map[int]bool{}
+	for _, e := range rowErrors {
+		invalidRows[e.Row] = true
+	}
+	Answer := Response{DryRun: dryRun, Total: len(users), Valid: len(users) - len(invalidRows), Errors: rowErrors}
+	if Answer.Errors == nil {
+		Answer.Errors = []ImportRowError{}
+	}
+
+	if dryRun {
+		utils.RespondWithJSON(w, Answer)
+		return
+	}
+	if len(rowErrors) > 0 {
+		utils.RespondWithStatus(w, http.StatusUnprocessableEntity, Answer)
+		return
+	}
+
+	tx, qtx, err := app.beginTx()
+	if err != nil {
+		utils.RespondWithError(w, utils.DBError("Error starting transaction", err))
+		return
+	}
+	defer tx.Rollback(context.Background())
+
+	var invitations []pendingInvitation
+
+	for _, row := range rows {
+		params := db.CreateUserParams{
+			ID:          pgtype.UUID{Bytes: uuid.New(), Valid: true},
+			Username:    row.Username,
+			Email:       row.Email,
+			FirstName:   pgtype.Text{String: row.FirstName, Valid: true},
+			LastName:    pgtype.Text{String: row.LastName, Valid: true},
+			IsActive:    pgtype.Bool{Bool: row.IsActive, Valid: true},
+			IsSuperuser: pgtype.Bool{Bool: false, Valid: true},
+		}
+
+		// An invited user is inactive until the invitation is accepted.
+		if row.Invite {
+			params.Password = utils.UnusablePassword
+			params.IsActive = pgtype.Bool{Bool: false, Valid: true}
+		} else {
+			params.Password, err = utils.HashPassword(row.Password)
+			if err != nil {
+				utils.RespondWithError(w, utils.Internal("internal", "Error hashing password", err))
+				return
+			}
+		}
+
+		newuser, err := qtx.CreateUser(context.Background(), params)
+		if err != nil {
+			utils.RespondWithError(w, utils.DBError(fmt.Sprintf("Error creating user of row %d", row.Row), err))
+			return
+		}
+
+		if row.Organization.Valid {
+			_, err = qtx.OrganizationLinkUser(context.Background(), db.OrganizationLinkUserParams{
+				UsersID:         newuser.ID,
+				OrganizationsID: row.Organization,
+			})
+			if err != nil {
+				utils.RespondWithError(w, utils.DBError("Error Organization link user", err))
+				return
+			}
+		}
+
+		for _, g := range row.Groups {
+			_, err = qtx.InsertUserGroupsByName(context.Background(), db.InsertUserGroupsByNameParams{
+				UserID: newuser.ID,
+				Name:   g,
+			})
+			if err != nil {
+				utils.RespondWithError(w, utils.DBError("error instering into usergroups", err))
+				return
+			}
+		}
+
+		after, _, err := loadUserAudit(qtx, newuser.ID)
+		if err != nil {
+			utils.RespondWithError(w, utils.DBError("Error getting the new user", err))
+			return
+		}
+		err = writeUserAudit(qtx, r, rinfo.User.ID, AuditUserCreated, newuser.ID, row.Organization, nil, after)
+		if err != nil {
+			utils.RespondWithError(w, utils.Internal("audit_failed", "Error writing audit event", err))
+			return
+		}
+
+		if row.Invite {
+			invitation, err := createInvitation(qtx, r, rinfo.User.ID, newuser, row.Organization)
+			if err != nil {
+				utils.RespondWithError(w, utils.DBError("Error creating invitation", err))
+				return
+			}
+			invitations = append(invitations, invitation)
+		}
+		Answer.Created++
+	}
+
+	err = tx.Commit(context.Background())
+	if err != nil {
+		utils.RespondWithError(w, utils.DBError("Error creating users", err))
+		return
+	}
+
+	// Mails are sent after the commit, so nobody is invited to an account that does not exist.
+	for _, invitation := range invitations {
+		err = invitation.send()
+		if err != nil {
+			log.Println("Error sending invitation mail:", err)
+			continue
+		}
+		Answer.Invited++
+	}
+
+	utils.RespondWithJSON(w, Answer)
+}
+
+// Reads a CSV with a header row. The columns are matched by name, unknown columns are ignored.
+func parseImportCSV(body []byte) ([]importUser, []ImportRowError, error) {
+	reader := csv.NewReader(strings.NewReader(strings.TrimPrefix(string(body), "\ufeff"))) // Spreadsheets like to write a BOM.
+	reader.FieldsPerRecord = -1
+	reader.TrimLeadingSpace = true
+
+	header, err := reader.Read()
+	if err != nil {
+		return nil, nil, fmt.Errorf("reading the header: %w", err)
+	}
+	columns := map[string]int{}
+	for i, name := range header {
+		columns[strings.ToLower(strings.TrimSpace(name))] = i
+	}
+	if _, ok := columns["email"]; !ok {
+		return nil, nil, errors.New("the header has no email column")
+	}
+
+	var users []importUser
+	var rowErrors []ImportRowError
+	for {
+		record, err := reader.Read()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			return nil, nil, err
+		}
+		field := func(name string) string {
+			i, ok := columns[name]
+			if !ok || i >= len(record) {
+				return ""
+			}
+			return strings.TrimSpace(record[i])
+		}
+
+		user := importUser{
+			Email:        field("email"),
+			Username:     field("username"),
+			FirstName:    field("first_name"),
+			LastName:     field("last_name"),
+			Password:     field("password"),
+			Groups:       field("groups"),
+			Organization: field("organization"),
+		}
+		if value := field("is_active"); value != "" {
+			isActive, err := strconv.ParseBool(value)
+			if err != nil {
+				rowErrors = append(rowErrors, ImportRowError{Row: len(users) + 1, Email: user.Email, Field: "is_active", Error: "is not true or false"})
+			}
+			user.IsActive = &isActive
+		}
+		users = append(users, user)
+	}
+	return users, rowErrors, nil
+}
+
+// Checks all rows and resolves their groups and organization. Only the valid rows are returned.
+func (app *App) validateImport(users []importUser, rinfo utils.InfoKey, invite bool) ([]importRow, []ImportRowError, error) {
+	// Without an organization, the users of an organization admin would end up in none.
+	if !rinfo.User.IsSuperuser.Bool && !rinfo.Organization.ID.Valid {
+		return nil, nil, utils.Forbidden("forbidden", "No permission to import users without an organization", errors.New("user has no organization"))
+	}
+
+	groups, err := app.Queries.GetGroups(context.Background())
+	if err != nil {
+		return nil, nil, err
+	}
+	groupNames := map[string]bool{}
+	for _, g := range groups {
+		groupNames[g.Name] = true
+	}
+
+	organizations, err := app.Queries.OrganizationAll(context.Background())
+	if err != nil {
+		return nil, nil, err
+	}
+	organizationIDs := map[string]pgtype.UUID{}
+	for _, o := range organizations {
+		organizationIDs[strings.ToLower(o.OrganizationName)] = o.ID
+		organizationIDs[uuid.UUID(o.ID.Bytes).String()] = o.ID
+	}
+
+	var emails, usernames []string
+	for i := range users {
+		users[i].Email = strings.ToLower(strings.TrimSpace(users[i].Email))
+		users[i].Username = strings.TrimSpace(users[i].Username)
+		if users[i].Username == "" {
+			users[i].Username = users[i].Email
+		}
+		emails = append(emails, users[i].Email)
+		usernames = append(usernames, users[i].Username)
+	}
+
+	taken, err := app.Queries.UserSelectTaken(context.Background(), db.UserSelectTakenParams{Emails: emails, Usernames: usernames})
+	if err != nil {
+		return nil, nil, err
+	}
+	takenEmails := map[string]bool{}
+	takenUsernames := map[string]bool{}
+	for _, t := range taken {
+		takenEmails[t.Email] = true
+		takenUsernames[t.Username] = true
+	}
+
+	seenEmails := map[string]int{}
+	seenUsernames := map[string]int{}
+
+	var rows []importRow
+	var rowErrors []ImportRowError
+	for i, user := range users {
+		row := importRow{importUser: user, Row: i + 1, IsActive: true}
+		valid := true
+		fail := func(field string, message string) {
+			rowErrors = append(rowErrors, ImportRowError{Row: row.Row, Email: user.Email, Field: field, Error: message})
+			valid = false
+		}
+
+		switch {
+		case !utils.IsValidEmail(user.Email):
+			fail("email", "is not a valid email")
+		case takenEmails[user.Email]:
+			fail("email", "is already registered")
+		case seenEmails[user.Email] > 0:
+			fail("email", fmt.Sprintf("is already used in row %d", seenEmails[user.Email]))
+		}
+		seenEmails[user.Email] = firstRow(seenEmails[user.Email], row.Row)
+
+		switch {
+		case takenUsernames[user.Username]:
+			fail("username", "is already taken")
+		case seenUsernames[user.Username] > 0:
+			fail("username", fmt.Sprintf("is already used in row %d", seenUsernames[user.Username]))
+		}
+		seenUsernames[user.Username] = firstRow(seenUsernames[user.Username], row.Row)
+
+		if user.Password == "" {
+			if invite {
+				row.Invite = true
+			} else {
+				fail("password", "is missing, set one or import with invitations")
+			}
+		} else {
+			for _, v := range utils.CheckPassword(user.Password, user.Email, user.Username) {
+				fail("password", v.Message)
+			}
+		}
+
+		for g := range strings.SplitSeq(user.Groups, "|") {
+			g = strings.TrimSpace(g)
+			if g == "" || slices.Contains(row.Groups, g) {
+				continue
+			}
+			if !groupNames[g] {
+				fail("groups", fmt.Sprintf("group %q does not exist", g))
+				continue
+			}
+			row.Groups = append(row.Groups, g)
+		}
+
+		if user.Organization != "" {
+			id, ok := organizationIDs[strings.ToLower(user.Organization)]
+			if !ok {
+				fail("organization", "does not exist")
+			}
+			row.Organization = id
+		}
+		// Organization admins import into their own organization only.
+		if !rinfo.User.IsSuperuser.Bool {
+			if row.Organization.Valid && row.Organization != rinfo.Organization.ID {
+				fail("organization", "is not your organization")
+			}
+			row.Organization = rinfo.Organization.ID
+		}
+
+		if user.IsActive != nil {
+			row.IsActive = *user.IsActive
+		}
+
+		if valid {
+			rows = append(rows, row)
+		}
+	}
+	return rows, rowErrors, nil
+}
+
+// Keeps the first row a value was seen in.
+func firstRow(first int, row int) int {
+	if first > 0 {
+		return first
+	}
+	return row
+}
+
+// ExportUsers sends the users as CSV or, with format=json, as JSON.
+// Organization admins only get the users of their organization, superusers may filter by organization.
+// The export has the columns of the import, without passwords, so it can be imported again.
+func (app *App) ExportUsers(w http.ResponseWriter, r *http.Request) {
+	rinfo, ok := r.Context().Value(utils.InfoContextKey).(utils.InfoKey)
+	if !ok {
+		utils.RespondWithError(w, utils.Unauthorized("unauthenticated", "User not found in context", errors.New("user not found")))
+		return
+	}
+
+	query := r.URL.Query()
+
+	format := query.Get("format")
+	switch format {
+	case "":
+		format = "csv"
+	case "csv", "json":
+	default:
+		utils.RespondWithError(w, utils.BadRequest("invalid_format", "Export as csv or json", nil))
+		return
+	}
+
+	organizationID, err := queryUUID(query, "organization")
+	if err != nil {
+		utils.RespondWithError(w, utils.BadRequest("invalid_filter", "Error parsing filter", err))
+		return
+	}
+	if !rinfo.User.IsSuperuser.Bool {
+		organizationID = rinfo.Organization.ID
+	}
+
+	type ExportUser struct {
+		Email        string    `json:"email"`
+		Username     string    `json:"username"`
+		FirstName    string    `json:"first_name"`
+		LastName     string    `json:"last_name"`
+		Groups       string    `json:"groups"`
+		Organization string    `json:"organization"`
+		IsActive     bool      `json:"is_active"`
+		CreatedAt    time.Time `json:"created_at"`
+		LastLogin    time.Time `json:"last_login"`
+	}
+
+	users := []ExportUser{}
+	const pageSize = 1000
+	for offset := 0; ; offset += pageSize {
+		page, err := app.Queries.UserSelectPage(context.Background(), db.UserSelectPageParams{
+			OrganizationID: organizationID,
+			Sort:           "email",
+			PageSize:       pageSize,
+			PageOffset:     int32(offset),
+		})
+		if err != nil {
+			utils.RespondWithError(w, utils.DBError("Error getting users", err))
+			return
+		}
+		for _, u := range page {
+			users = append(users, ExportUser{
+				Email:        u.Email,
+				Username:     u.Username,
+				FirstName:    u.FirstName.String,
+				LastName:     u.LastName.String,
+				Groups:       u.GroupNames,
+				Organization: u.OrganizationName.String,
+				IsActive:     u.IsActive.Bool,
+				CreatedAt:    u.UserCreatedAt.Time,
+				LastLogin:    u.LastLogin.Time,
+			})
+		}
+		if len(page) < pageSize {
+			break
+		}
+	}
+
+	filename := "users-" + time.Now().UTC().Format("20060102") + "." + format
+	w.Header().Set("Content-Disposition", `attachment; filename="`+filename+`"`)
+
+	if format == "json" {
+		utils.RespondWithJSON(w, users)
+		return
+	}
+
+	w.Header().Set("Content-Type", "text/csv; charset=utf-8")
+	writer := csv.NewWriter(w)
+	header := append(slices.Clone(importColumns), "created_at", "last_login")
+	writer.Write(header)
+	for _, u := range users {
+		var lastLogin string
+		if !u.LastLogin.IsZero() {
+			lastLogin = u.LastLogin.UTC().Format(time.RFC3339)
+		}
+		writer.Write([]string{
+			csvSafe(u.Email),
+			csvSafe(u.Username),
+			csvSafe(u.FirstName),
+			csvSafe(u.LastName),
+			"",
+			csvSafe(u.Groups),
+			csvSafe(u.Organization),
+			strconv.FormatBool(u.IsActive),
+			u.CreatedAt.UTC().Format(time.RFC3339),
+			lastLogin,
+		})
+	}
+	writer.Flush()
+	if err := writer.Error(); err != nil {
+		log.Println("Error writing user export:", err)
+	}
+}
+
+// Spreadsheets run cells that start like a formula. A leading quote makes them text.
+func csvSafe(value string) string {
+	if value != "" && strings.ContainsRune("=+-@\t\r", rune(value[0])) {
+		return "'" + value
+	}
+	return value
+}
@@ -0,0 +1,115 @@
+package admin
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestCsvSafe(t *testing.T) {
+	tests := []struct {
+		value string
+		want  string
+	}{
+		{"", ""},
+		{"alice@example.com", "alice@example.com"},
+		{"=HYPERLINK(\"http://evil\")", "'=HYPERLINK(\"http://evil\")"},
+		{"+49 123", "'+49 123"},
+		{"-1", "'-1"},
+		{"@SUM(A1)", "'@SUM(A1)"},
+		{"\tindented", "'\tindented"},
+		{"\rreturn", "'\rreturn"},
+		{"a=b", "a=b"},
+		{"'quoted", "'quoted"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.value, func(t *testing.T) {
+			if got := csvSafe(tt.value); got != tt.want {
+				t.Errorf("csvSafe(%q) = %q, want %q", tt.value, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestParseImportCSV(t *testing.T) {
+	yes, no := true, false
+
+	tests := []struct {
+		name       string
+		body       string
+		want       []importUser
+		wantErrors []ImportRowError
+		wantErr    bool
+	}{
+		{
+			name: "all columns",
+			body: "email,username,first_name,last_name,password,groups,organization,is_active\n" +
+				"alice@example.com,alice,Alice,Smith,secret,staff|admins,Acme,true\n",
+			want: []importUser{{
+				Email: "alice@example.com", Username: "alice", FirstName: "Alice", LastName: "Smith",
+				Password: "secret", Groups: "staff|admins", Organization: "Acme", IsActive: &yes,
+			}},
+		},
+		{
+			name: "BOM, other order, case and spaces",
+			body: "\ufeffFirst_Name, EMAIL\n Bob , bob@example.com \n",
+			want: []importUser{{Email: "bob@example.com", FirstName: "Bob"}},
+		},
+		{
+			name: "short rows",
+			body: "email,username,is_active\nalice@example.com\nbob@example.com,bob,false\n",
+			want: []importUser{
+				{Email: "alice@example.com"},
+				{Email: "bob@example.com", Username: "bob", IsActive: &no},
+			},
+		},
+		{
+			name: "quoted values",
+			body: "email,last_name\n\"carol@example.com\",\"Smith, Jr.\"\n",
+			want: []importUser{{Email: "carol@example.com", LastName: "Smith, Jr."}},
+		},
+		{
+			name: "is_active that is no bool",
+			body: "email,is_active\nalice@example.com,true\nbob@example.com,maybe\n",
+			want: []importUser{
+				{Email: "alice@example.com", IsActive: &yes},
+				{Email: "bob@example.com", IsActive: &no},
+			},
+			wantErrors: []ImportRowError{{Row: 2, Email: "bob@example.com", Field: "is_active", Error: "is not true or false"}},
+		},
+		{
+			name: "header only",
+			body: "email\n",
+		},
+		{
+			name:    "no email column",
+			body:    "username\nalice\n",
+			wantErr: true,
+		},
+		{
+			name:    "empty body",
+			body:    "",
+			wantErr: true,
+		},
+		{
+			name:    "broken quotes",
+			body:    "email\n\"alice@example.com\n",
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, gotErrors, err := parseImportCSV([]byte(tt.body))
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("parseImportCSV() error = %v, want error %v", err, tt.wantErr)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("parseImportCSV() = %+v, want %+v", got, tt.want)
+			}
+			if !reflect.DeepEqual(gotErrors, tt.wantErrors) {
+				t.Errorf("parseImportCSV() row errors = %+v, want %+v", gotErrors, tt.wantErrors)
+			}
+		})
+	}
+}
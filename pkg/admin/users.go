@@ -6,10 +6,13 @@ import (
 	"encoding/json"
 	"errors"
 	"io"
+	"log"
 	"net/http"
 	"slices"
+	"strconv"
 	"strings"
 	"time"
+	"unicode"
 
 	"github.com/google/uuid"
 	"github.com/jackc/pgx/v5"
@@ -19,37 +22,81 @@ import (
 	utils "github.com/karl1b/go4lage/pkg/utils"
 )
 
+// AllUsers lists the users page by page. Organization admins only get the users of their organization.
+// Query parameters:
+// page, page_size; sort is created_at, last_login or email and order asc or desc;
+// is_active, group, organization (superusers only) and search in names and email filter.
 func (app *App) AllUsers(w http.ResponseWriter, r *http.Request) {
 	rinfo, ok := r.Context().Value(utils.InfoContextKey).(utils.InfoKey)
 	if !ok {
-		utils.RespondWithJSON(w, utils.ErrorResponse{
-			Detail: "User not found in context",
-			Error:  "user not found",
-		})
+		utils.RespondWithError(w, utils.Unauthorized("unauthenticated", "User not found in context", errors.New("user not found")))
 		return
 	}
 
-	var allUsers []db.User
+	query := r.URL.Query()
+
+	var filter db.UserCountParams
 	var err error
 
-	if rinfo.User.IsSuperuser.Bool {
-		allUsers, err = app.Queries.SelectAllUsers(context.Background())
-		if err != nil {
-			utils.RespondWithJSON(w, utils.ErrorResponse{
-				Detail: "Error getting all users",
-				Error:  err.Error(),
-			})
-			return
-		}
-	} else {
-		allUsers, err = app.Queries.OrganizationSelectAllUsers(context.Background(), rinfo.Organization.ID)
+	filter.OrganizationID, err = queryUUID(query, "organization")
+	if err != nil {
+		utils.RespondWithError(w, utils.BadRequest("invalid_filter", "Error parsing filter", err))
+		return
+	}
+	if !rinfo.User.IsSuperuser.Bool {
+		filter.OrganizationID = rinfo.Organization.ID
+	}
+
+	if value := query.Get("is_active"); value != "" {
+		isActive, err := strconv.ParseBool(value)
 		if err != nil {
-			utils.RespondWithJSON(w, utils.ErrorResponse{
-				Detail: "Error getting users for organization",
-				Error:  err.Error(),
-			})
+			utils.RespondWithError(w, utils.BadRequest("invalid_filter", "Error parsing filter", err))
 			return
 		}
+		filter.IsActive = pgtype.Bool{Bool: isActive, Valid: true}
+	}
+
+	if group := query.Get("group"); group != "" {
+		filter.GroupName = pgtype.Text{String: group, Valid: true}
+	}
+
+	if search := userSearchQuery(query.Get("search")); search != "" {
+		filter.Search = pgtype.Text{String: search, Valid: true}
+	}
+
+	sort := query.Get("sort")
+	switch sort {
+	case "":
+		sort = "created_at"
+	case "created_at", "last_login", "email":
+	default:
+		utils.RespondWithError(w, utils.BadRequest("invalid_sort", "Sort by created_at, last_login or email", nil))
+		return
+	}
+	descending := query.Get("order") == "desc"
+
+	page, pageSize := queryPage(query)
+
+	total, err := app.Queries.UserCount(context.Background(), filter)
+	if err != nil {
+		utils.RespondWithError(w, utils.DBError("Error counting users", err))
+		return
+	}
+
+	// Groups and organization come with the users, so it is one query for the whole page.
+	users, err := app.Queries.UserSelectPage(context.Background(), db.UserSelectPageParams{
+		OrganizationID: filter.OrganizationID,
+		IsActive:       filter.IsActive,
+		GroupName:      filter.GroupName,
+		Search:         filter.Search,
+		Sort:           sort,
+		Descending:     descending,
+		PageSize:       int32(pageSize),
+		PageOffset:     int32((page - 1) * pageSize),
+	})
+	if err != nil {
+		utils.RespondWithError(w, utils.DBError("Error getting all users", err))
+		return
 	}
 
 	type OrganizationResponse struct {
@@ -74,96 +121,68 @@ func (app *App) AllUsers(w http.ResponseWriter, r *http.Request) {
 		Organization OrganizationResponse `json:"organization,omitzero"`
 	}
 
-	var responseUsers []ResponseUser
-
-	for _, dbUser := range allUsers {
-		userGroups, err := app.Queries.GetGroupsByUserId(context.Background(), dbUser.ID)
-		if err != nil {
-			utils.RespondWithJSON(w, utils.ErrorResponse{
-				Detail: "Error getting groups for user",
-				Error:  err.Error(),
-			})
-			return
-		}
+	type Response struct {
+		Users    []ResponseUser `json:"users"`
+		Total    int64          `json:"total"`
+		Page     int            `json:"page"`
+		PageSize int            `json:"page_size"`
+	}
 
-		var groupNames []string
-		for _, userGroup := range userGroups {
-			groupNames = append(groupNames, userGroup.Name)
-		}
-		groupString := strings.Join(groupNames, "|")
+	Answer := Response{Users: []ResponseUser{}, Total: total, Page: page, PageSize: pageSize}
 
+	for _, dbUser := range users {
 		var organizationInfo OrganizationResponse
-
-		userOrganization, err := app.Queries.OrganizationSelectUserOrganization(context.Background(), dbUser.ID)
-		if err != nil {
-			if errors.Is(err, sql.ErrNoRows) || errors.Is(err, pgx.ErrNoRows) || strings.Contains(err.Error(), "no rows in result set") {
-
-			} else {
-				utils.RespondWithJSON(w, utils.ErrorResponse{
-					Detail: "Error getting organization for user",
-					Error:  err.Error(),
-				})
-				return
-			}
-		} else {
+		if dbUser.OrganizationID.Valid {
 			organizationInfo = OrganizationResponse{
-				ID:               userOrganization.ID.Bytes,
-				CreatedAt:        userOrganization.CreatedAt.Time,
-				OrganizationName: userOrganization.OrganizationName,
-				Email:            userOrganization.Email,
-				ActiveUntil:      userOrganization.ActiveUntil.Time,
+				ID:               dbUser.OrganizationID.Bytes,
+				CreatedAt:        dbUser.OrganizationCreatedAt.Time,
+				OrganizationName: dbUser.OrganizationName.String,
+				Email:            dbUser.OrganizationEmail.String,
+				ActiveUntil:      dbUser.OrganizationActiveUntil.Time,
 			}
 		}
 
-		// Handle potential null timestamps - return actual time.Time values
-		var createdAt time.Time
-		var lastLogin time.Time
-
-		if dbUser.UserCreatedAt.Valid {
-			createdAt = dbUser.UserCreatedAt.Time
-		}
-
-		if dbUser.LastLogin.Valid {
-			lastLogin = dbUser.LastLogin.Time
-		}
-
-		id := uuid.UUID(dbUser.ID.Bytes).String()
-
-		responseUsers = append(responseUsers, ResponseUser{
+		Answer.Users = append(Answer.Users, ResponseUser{
 			Username:     dbUser.Username,
 			Email:        dbUser.Email,
 			FirstName:    dbUser.FirstName.String,
 			LastName:     dbUser.LastName.String,
-			CreatedAt:    createdAt,
-			LastLogin:    lastLogin,
+			CreatedAt:    dbUser.UserCreatedAt.Time,
+			LastLogin:    dbUser.LastLogin.Time,
 			IsActive:     dbUser.IsActive.Bool,
 			IsSuperuser:  dbUser.IsSuperuser.Bool,
-			ID:           id,
-			Groups:       groupString,
+			ID:           uuid.UUID(dbUser.ID.Bytes).String(),
+			Groups:       dbUser.GroupNames,
 			Organization: organizationInfo,
 		})
 	}
 
-	utils.RespondWithJSON(w, responseUsers)
+	utils.RespondWithJSON(w, Answer)
+}
+
+// Turns the search input into a prefix tsquery, so "jo do" finds John Doe.
+// Everything but letters and digits is dropped, so the input can not break the query syntax.
+func userSearchQuery(search string) string {
+	words := strings.FieldsFunc(strings.ToLower(search), func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r)
+	})
+	for i, word := range words {
+		words[i] = word + ":*"
+	}
+	return strings.Join(words, " & ")
 }
 
 func (app *App) OneUser(w http.ResponseWriter, r *http.Request) {
 	userid := r.Header.Get("Id")
 	useriduuid, err := uuid.Parse(userid)
 	if err != nil {
-		utils.RespondWithJSON(w, utils.ErrorResponse{
-			Detail: "Error getting parsing ID",
-			Error:  err.Error(),
-		})
+		utils.RespondWithError(w, utils.BadRequest("invalid_id", "Error getting parsing ID", err))
 		return
 	}
 
 	rinfo, ok := r.Context().Value(utils.InfoContextKey).(utils.InfoKey)
 	if !ok {
-		utils.RespondWithJSON(w, utils.ErrorResponse{
-			Detail: "User not found in context",
-			Error:  "user not found",
-		})
+		utils.RespondWithError(w, utils.Unauthorized("unauthenticated", "User not found in context", errors.New("user not found")))
 		return
 	}
 
@@ -172,10 +191,7 @@ func (app *App) OneUser(w http.ResponseWriter, r *http.Request) {
 		Valid: true,
 	})
 	if err != nil {
-		utils.RespondWithJSON(w, utils.ErrorResponse{
-			Detail: "Error getting this user",
-			Error:  err.Error(),
-		})
+		utils.RespondWithError(w, utils.DBError("Error getting this user", err))
 		return
 	}
 
@@ -187,20 +203,14 @@ func (app *App) OneUser(w http.ResponseWriter, r *http.Request) {
 		if errors.Is(err, pgx.ErrNoRows) || errors.Is(err, sql.ErrNoRows) {
 		} else {
 
-			utils.RespondWithJSON(w, utils.ErrorResponse{
-				Detail: "Error getting this user's organization",
-				Error:  err.Error(),
-			})
+			utils.RespondWithError(w, utils.DBError("Error getting this user's organization", err))
 			return
 		}
 	}
 
 	if userOrganization.ID != rinfo.Organization.ID || !userOrganization.ID.Valid {
 		if !(rinfo.User.IsSuperuser.Bool) {
-			utils.RespondWithJSON(w, utils.ErrorResponse{
-				Detail: "No permission to see this user",
-				Error:  "user organization is not your organization",
-			})
+			utils.RespondWithError(w, utils.Forbidden("forbidden", "No permission to see this user", errors.New("user organization is not your organization")))
 			return
 		}
 
@@ -215,26 +225,28 @@ func (app *App) OneUser(w http.ResponseWriter, r *http.Request) {
 	}
 
 	type ResponseUser struct {
-		Username     string               `json:"username"`
-		Email        string               `json:"email"`
-		FirstName    string               `json:"first_name"`
-		LastName     string               `json:"last_name"`
-		CreatedAt    int64                `json:"created_at"`
-		LastLogin    int64                `json:"last_login"`
-		IsSuperuser  bool                 `json:"is_superuser"`
-		IsActive     bool                 `json:"is_active"`
-		ID           uuid.UUID            `json:"id"`
-		Groups       string               `json:"groups"`
-		Permissions  string               `json:"permissions"`
-		Organization OrganizationResponse `json:"organization,omitzero"`
+		Username         string               `json:"username"`
+		Email            string               `json:"email"`
+		FirstName        string               `json:"first_name"`
+		LastName         string               `json:"last_name"`
+		CreatedAt        int64                `json:"created_at"`
+		LastLogin        int64                `json:"last_login"`
+		IsSuperuser      bool                 `json:"is_superuser"`
+		IsActive         bool                 `json:"is_active"`
+		ID               uuid.UUID            `json:"id"`
+		Groups           string               `json:"groups"`
+		Permissions      string               `json:"permissions"`
+		TwofactorEnabled bool                 `json:"twofactor_enabled"`
+		FailedLogins     int32                `json:"failed_logins"`
+		LastFailedLogin  int64                `json:"last_failed_login"`
+		LockedUntil      int64                `json:"locked_until"`
+		Organization     OrganizationResponse `json:"organization,omitzero"`
+		Sessions         []SessionResponse    `json:"sessions"`
 	}
 
 	usergroups, err := app.Queries.GetGroupsByUserId(context.Background(), user.ID)
 	if err != nil {
-		utils.RespondWithJSON(w, utils.ErrorResponse{
-			Detail: "Error getting groups for user",
-			Error:  err.Error(),
-		})
+		utils.RespondWithError(w, utils.DBError("Error getting groups for user", err))
 		return
 	}
 
@@ -246,10 +258,7 @@ func (app *App) OneUser(w http.ResponseWriter, r *http.Request) {
 
 	userpermissions, err := app.Queries.GetPurePermissionsByUserId(context.Background(), user.ID)
 	if err != nil {
-		utils.RespondWithJSON(w, utils.ErrorResponse{
-			Detail: "Error getting permissions for user",
-			Error:  err.Error(),
-		})
+		utils.RespondWithError(w, utils.DBError("Error getting permissions for user", err))
 		return
 	}
 
@@ -270,6 +279,17 @@ func (app *App) OneUser(w http.ResponseWriter, r *http.Request) {
 		lastLogin = user.LastLogin.Time.Unix()
 	}
 
+	var lastFailedLogin int64
+	var lockedUntil int64
+
+	if user.LastFailedLogin.Valid {
+		lastFailedLogin = user.LastFailedLogin.Time.Unix()
+	}
+
+	if user.LockedUntil.Valid && user.LockedUntil.Time.After(time.Now()) {
+		lockedUntil = user.LockedUntil.Time.Unix()
+	}
+
 	var organizationInfo OrganizationResponse
 
 	userOrganization, err = app.Queries.OrganizationSelectUserOrganization(context.Background(), user.ID)
@@ -277,20 +297,14 @@ func (app *App) OneUser(w http.ResponseWriter, r *http.Request) {
 		if errors.Is(err, sql.ErrNoRows) || errors.Is(err, pgx.ErrNoRows) {
 			// User has no organization - leave organizationInfo as zero value
 		} else {
-			utils.RespondWithJSON(w, utils.ErrorResponse{
-				Detail: "Error getting organization for user",
-				Error:  err.Error(),
-			})
+			utils.RespondWithError(w, utils.DBError("Error getting organization for user", err))
 			return
 		}
 	} else {
 
 		uuid, err := userOrganization.ID.UUIDValue()
 		if err != nil {
-			utils.RespondWithJSON(w, utils.ErrorResponse{
-				Detail: "Error parsing uuid for user organization",
-				Error:  err.Error(),
-			})
+			utils.RespondWithError(w, utils.Internal("internal", "Error parsing uuid for user organization", err))
 			return
 		}
 
@@ -303,19 +317,30 @@ func (app *App) OneUser(w http.ResponseWriter, r *http.Request) {
 		}
 	}
 
+	sessions, err := app.Queries.SessionSelectByUserId(context.Background(), user.ID)
+	if err != nil {
+		utils.RespondWithError(w, utils.DBError("Error getting sessions for user", err))
+		return
+	}
+
 	responseuser := ResponseUser{
-		Username:     user.Username,
-		Email:        user.Email,
-		FirstName:    user.FirstName.String,
-		LastName:     user.LastName.String,
-		CreatedAt:    createdAt,
-		LastLogin:    lastLogin,
-		IsActive:     user.IsActive.Bool,
-		IsSuperuser:  user.IsSuperuser.Bool,
-		ID:           uuid.UUID(user.ID.Bytes),
-		Groups:       groupstring,
-		Permissions:  permissionstring,
-		Organization: organizationInfo,
+		Username:         user.Username,
+		Email:            user.Email,
+		FirstName:        user.FirstName.String,
+		LastName:         user.LastName.String,
+		CreatedAt:        createdAt,
+		LastLogin:        lastLogin,
+		IsActive:         user.IsActive.Bool,
+		IsSuperuser:      user.IsSuperuser.Bool,
+		ID:               uuid.UUID(user.ID.Bytes),
+		Groups:           groupstring,
+		Permissions:      permissionstring,
+		TwofactorEnabled: user.TwofactorEnabled,
+		FailedLogins:     user.FailedLoginAttempts,
+		LastFailedLogin:  lastFailedLogin,
+		LockedUntil:      lockedUntil,
+		Organization:     organizationInfo,
+		Sessions:         toSessionResponses(sessions, rinfo.Session.ID),
 	}
 
 	utils.RespondWithJSON(w, responseuser)
@@ -325,20 +350,14 @@ func (app *App) Deleteoneuser(w http.ResponseWriter, r *http.Request) {
 	userid := r.Header.Get("Id")
 	useriduuid, err := uuid.Parse(userid)
 	if err != nil {
-		utils.RespondWithJSON(w, utils.ErrorResponse{
-			Detail: "Error getting parsing ID",
-			Error:  err.Error(),
-		})
+		utils.RespondWithError(w, utils.BadRequest("invalid_id", "Error getting parsing ID", err))
 		return
 	}
 
 	// Get the requesting user's info from context
 	rinfo, ok := r.Context().Value(utils.InfoContextKey).(utils.InfoKey)
 	if !ok {
-		utils.RespondWithJSON(w, utils.ErrorResponse{
-			Detail: "User not found in context",
-			Error:  "user not found",
-		})
+		utils.RespondWithError(w, utils.Unauthorized("unauthenticated", "User not found in context", errors.New("user not found")))
 		return
 	}
 
@@ -349,36 +368,59 @@ func (app *App) Deleteoneuser(w http.ResponseWriter, r *http.Request) {
 			Valid: true,
 		})
 		if err != nil {
-			utils.RespondWithJSON(w, utils.ErrorResponse{
-				Detail: "Error getting this user's organization",
-				Error:  err.Error(),
-			})
+			utils.RespondWithError(w, utils.DBError("Error getting this user's organization", err))
 			return
 		}
 		if userOrganization.ID != rinfo.Organization.ID {
-			utils.RespondWithJSON(w, utils.ErrorResponse{
-				Detail: "No permission to delete this user",
-				Error:  "user organization is not your organization",
-			})
+			utils.RespondWithError(w, utils.Forbidden("forbidden", "No permission to delete this user", errors.New("user organization is not your organization")))
 			return
 		}
 	}
 
-	// Now perform the deletion
-	dbuser, err := app.Queries.DeleteUserById(context.Background(), pgtype.UUID{
-		Bytes: useriduuid,
-		Valid: true,
-	})
+	userID := pgtype.UUID{Bytes: useriduuid, Valid: true}
+
+	tx, qtx, err := app.beginTx()
 	if err != nil {
-		utils.RespondWithJSON(w, utils.ErrorResponse{
-			Detail: "Error deleting this user",
-			Error:  err.Error(),
-		})
+		utils.RespondWithError(w, utils.DBError("Error starting transaction", err))
 		return
 	}
+	defer tx.Rollback(context.Background())
 
+	before, userOrganizationID, err := loadUserAudit(qtx, userID)
+	if err != nil {
+		utils.RespondWithError(w, utils.DBError("Error getting this user", err))
+		return
+	}
+
+	// The sessions are deleted first, so they can be removed from the cache as well.
+	sessions, err := qtx.SessionDeleteByUserId(context.Background(), userID)
+	if err != nil {
+		utils.RespondWithError(w, utils.DBError("Error deleting the sessions of this user", err))
+		return
+	}
+
+	// The user is moved to the trash. A superuser can restore it until purgetrash deletes it for good.
+	dbuser, err := qtx.UserSoftDelete(context.Background(), userID)
+	if err != nil {
+		utils.RespondWithError(w, utils.DBError("Error deleting this user", err))
+		return
+	}
+
+	err = writeUserAudit(qtx, r, rinfo.User.ID, AuditUserDeleted, userID, userOrganizationID, before, nil)
+	if err != nil {
+		utils.RespondWithError(w, utils.Internal("audit_failed", "Error writing audit event", err))
+		return
+	}
+
+	err = tx.Commit(context.Background())
+	if err != nil {
+		utils.RespondWithError(w, utils.DBError("Error deleting this user", err))
+		return
+	}
+
+	cache.DelSessions(sessions)
 	// The user is changed and hence needs to be deleted from cache.
-	cache.Go4users.Del(dbuser.Token.String)
+	cache.Go4users.Del(dbuser.ID.Bytes)
 
 	utils.RespondWithJSON(w, struct{}{})
 }
@@ -386,7 +428,7 @@ func (app *App) Createoneuser(w http.ResponseWriter, r *http.Request) {
 
 	body, err := io.ReadAll(r.Body)
 	if err != nil {
-		http.Error(w, err.Error(), http.StatusInternalServerError)
+		utils.RespondWithError(w, utils.BadRequest("invalid_body", "Error reading body", err))
 		return
 	}
 	defer r.Body.Close()
@@ -402,22 +444,20 @@ func (app *App) Createoneuser(w http.ResponseWriter, r *http.Request) {
 		Groups         string `json:"groups"`
 		Permissions    string `json:"permissions"`
 		OrganizationID string `json:"organization_id"` // Added to optionally specify org
+		Invite         bool   `json:"invite"`          // The user gets an invitation to choose the password instead.
 	}
 
 	var reqBody RequestBody
 	err = json.Unmarshal(body, &reqBody)
 	if err != nil {
-		http.Error(w, err.Error(), http.StatusBadRequest)
+		utils.RespondWithError(w, utils.BadRequest("invalid_body", "Invalid JSON format", err))
 		return
 	}
 
 	// Get the requesting user's info from context
 	rinfo, ok := r.Context().Value(utils.InfoContextKey).(utils.InfoKey)
 	if !ok {
-		utils.RespondWithJSON(w, utils.ErrorResponse{
-			Detail: "User not found in context",
-			Error:  "user not found",
-		})
+		utils.RespondWithError(w, utils.Unauthorized("unauthenticated", "User not found in context", errors.New("user not found")))
 		return
 	}
 
@@ -425,10 +465,7 @@ func (app *App) Createoneuser(w http.ResponseWriter, r *http.Request) {
 
 	// Regular users cannot create users at all (they don't have permission to add users to their org)
 	if !(rinfo.User.IsSuperuser.Bool || hasPermToHandle) {
-		utils.RespondWithJSON(w, utils.ErrorResponse{
-			Detail: "No permission to create users",
-			Error:  "only superusers and OrganizationStaff can create users",
-		})
+		utils.RespondWithError(w, utils.Forbidden("forbidden", "No permission to create users", errors.New("only superusers and OrganizationStaff can create users")))
 		return
 	}
 
@@ -438,10 +475,7 @@ func (app *App) Createoneuser(w http.ResponseWriter, r *http.Request) {
 		// If organization ID is provided, parse it
 		orgUUID, err := uuid.Parse(reqBody.OrganizationID)
 		if err != nil {
-			utils.RespondWithJSON(w, utils.ErrorResponse{
-				Detail: "Error parsing organization ID",
-				Error:  err.Error(),
-			})
+			utils.RespondWithError(w, utils.BadRequest("invalid_id", "Error parsing organization ID", err))
 			return
 		}
 		targetOrgID = pgtype.UUID{Bytes: orgUUID, Valid: true}
@@ -449,10 +483,7 @@ func (app *App) Createoneuser(w http.ResponseWriter, r *http.Request) {
 		// Non-superusers can only create users in their own organization
 		if !(rinfo.User.IsSuperuser.Bool) {
 			if targetOrgID != rinfo.Organization.ID {
-				utils.RespondWithJSON(w, utils.ErrorResponse{
-					Detail: "No permission to create users in other organizations",
-					Error:  "you can only create users in your own organization",
-				})
+				utils.RespondWithError(w, utils.Forbidden("forbidden", "No permission to create users in other organizations", errors.New("you can only create users in your own organization")))
 				return
 			}
 		}
@@ -466,24 +497,7 @@ func (app *App) Createoneuser(w http.ResponseWriter, r *http.Request) {
 
 	email := strings.ToLower(strings.TrimSpace(reqBody.Email))
 	if !utils.IsValidEmail(email) {
-		utils.RespondWithJSON(w, utils.ErrorResponse{
-			Detail: "Error Email format. Is the email valid?",
-			Error:  "",
-		})
-		return
-	}
-
-	password := reqBody.Password
-	if password == "" {
-		utils.RespondWithJSON(w, utils.ErrorResponse{
-			Detail: "Error Pasword Is the password valid?",
-			Error:  "",
-		})
-		return
-	}
-
-	newToken, err := utils.GenerateTokenHex(32)
-	if err != nil {
+		utils.RespondWithError(w, utils.Unprocessable("invalid_email", "Error Email format. Is the email valid?", nil))
 		return
 	}
 
@@ -491,19 +505,43 @@ func (app *App) Createoneuser(w http.ResponseWriter, r *http.Request) {
 	if newusername == "" {
 		newusername = reqBody.Email
 	}
-	newpassword, err := utils.HashPassword(password)
+
+	// An invited user is inactive until the invitation is accepted.
+	newpassword := utils.UnusablePassword
+	if reqBody.Invite {
+		err = checkInvitations()
+		if err != nil {
+			utils.RespondWithError(w, err)
+			return
+		}
+		reqBody.IsActive = false
+	} else {
+		if reqBody.Password == "" {
+			utils.RespondWithError(w, utils.Unprocessable("invalid_password", "Error Pasword Is the password valid?", nil))
+			return
+		}
+		err = utils.ValidatePassword(reqBody.Password, email, newusername)
+		if err != nil {
+			utils.RespondWithError(w, err)
+			return
+		}
+		newpassword, err = utils.HashPassword(reqBody.Password)
+		if err != nil {
+			utils.RespondWithError(w, utils.Internal("internal", "Error hashing password", err))
+			return
+		}
+	}
+
+	tx, qtx, err := app.beginTx()
 	if err != nil {
-		utils.RespondWithJSON(w, utils.ErrorResponse{
-			Detail: "Error hashing password",
-			Error:  err.Error(),
-		})
+		utils.RespondWithError(w, utils.DBError("Error starting transaction", err))
 		return
 	}
+	defer tx.Rollback(context.Background())
 
-	newuser, err := app.Queries.CreateUser(context.Background(), db.CreateUserParams{
+	newuser, err := qtx.CreateUser(context.Background(), db.CreateUserParams{
 		ID:          pgtype.UUID{Bytes: uuid.New(), Valid: true},
 		Username:    newusername,
-		Token:       pgtype.Text{String: newToken, Valid: true},
 		Email:       email,
 		Password:    newpassword,
 		FirstName:   pgtype.Text{String: reqBody.FirstName, Valid: true},
@@ -512,10 +550,7 @@ func (app *App) Createoneuser(w http.ResponseWriter, r *http.Request) {
 		IsSuperuser: pgtype.Bool{Bool: reqBody.IsSuperuser, Valid: true},
 	})
 	if err != nil {
-		utils.RespondWithJSON(w, utils.ErrorResponse{
-			Detail: "nice",
-			Error:  err.Error(),
-		})
+		utils.RespondWithError(w, utils.DBError("Error creating user", err))
 
 		return
 	}
@@ -523,15 +558,12 @@ func (app *App) Createoneuser(w http.ResponseWriter, r *http.Request) {
 	// Associate user with organization if targetOrgID is set
 	if targetOrgID.Valid {
 
-		_, err = app.Queries.OrganizationLinkUser(context.Background(), db.OrganizationLinkUserParams{
+		_, err = qtx.OrganizationLinkUser(context.Background(), db.OrganizationLinkUserParams{
 			UsersID:         newuser.ID,
 			OrganizationsID: targetOrgID,
 		})
 		if err != nil {
-			utils.RespondWithJSON(w, utils.ErrorResponse{
-				Detail: "Error Organization link user",
-				Error:  err.Error(),
-			})
+			utils.RespondWithError(w, utils.DBError("Error Organization link user", err))
 
 			return
 		}
@@ -546,30 +578,24 @@ func (app *App) Createoneuser(w http.ResponseWriter, r *http.Request) {
 			continue
 		}
 
-		dbGroup, err = app.Queries.GetGroupByName(context.Background(), g)
+		dbGroup, err = qtx.GetGroupByName(context.Background(), g)
 		if err != nil {
-			dbGroup, err = app.Queries.CreateGroup(context.Background(), db.CreateGroupParams{
+			dbGroup, err = qtx.CreateGroup(context.Background(), db.CreateGroupParams{
 				ID:   pgtype.UUID{Bytes: uuid.New(), Valid: true},
 				Name: g,
 			})
 			if err != nil {
-				utils.RespondWithJSON(w, utils.ErrorResponse{
-					Detail: "error creating new group",
-					Error:  err.Error(),
-				})
+				utils.RespondWithError(w, utils.DBError("error creating new group", err))
 				return
 			}
 		}
 
-		_, err = app.Queries.InsertUserGroups(context.Background(), db.InsertUserGroupsParams{
+		_, err = qtx.InsertUserGroups(context.Background(), db.InsertUserGroupsParams{
 			UserID:  newuser.ID,
 			GroupID: dbGroup.ID,
 		})
 		if err != nil {
-			utils.RespondWithJSON(w, utils.ErrorResponse{
-				Detail: "error instering into usergroups",
-				Error:  err.Error(),
-			})
+			utils.RespondWithError(w, utils.DBError("error instering into usergroups", err))
 			return
 		}
 
@@ -583,33 +609,71 @@ func (app *App) Createoneuser(w http.ResponseWriter, r *http.Request) {
 			continue
 		}
 
-		dbPermission, err = app.Queries.GetPermissionByName(context.Background(), p)
+		dbPermission, err = qtx.GetPermissionByName(context.Background(), p)
 		if err != nil {
-			dbPermission, err = app.Queries.CreatePermission(context.Background(), db.CreatePermissionParams{
+			dbPermission, err = qtx.CreatePermission(context.Background(), db.CreatePermissionParams{
 				ID:   pgtype.UUID{Bytes: uuid.New(), Valid: true},
 				Name: p,
 			})
 			if err != nil {
-				utils.RespondWithJSON(w, utils.ErrorResponse{
-					Detail: "error creating new group",
-					Error:  err.Error(),
-				})
+				utils.RespondWithError(w, utils.DBError("error creating new group", err))
 				return
 			}
 		}
 
-		_, err = app.Queries.InsertUserPermission(context.Background(), db.InsertUserPermissionParams{
+		_, err = qtx.InsertUserPermission(context.Background(), db.InsertUserPermissionParams{
 			UserID:       newuser.ID,
 			PermissionID: dbPermission.ID,
 		})
 		if err != nil {
-			utils.RespondWithJSON(w, utils.ErrorResponse{
-				Detail: "error instering into userpermissions",
-				Error:  err.Error(),
-			})
+			utils.RespondWithError(w, utils.DBError("error instering into userpermissions", err))
+			return
+		}
+
+	}
+
+	after, _, err := loadUserAudit(qtx, newuser.ID)
+	if err != nil {
+		utils.RespondWithError(w, utils.DBError("Error getting the new user", err))
+		return
+	}
+
+	err = writeUserAudit(qtx, r, rinfo.User.ID, AuditUserCreated, newuser.ID, targetOrgID, nil, after)
+	if err != nil {
+		utils.RespondWithError(w, utils.Internal("audit_failed", "Error writing audit event", err))
+		return
+	}
+
+	var invitation pendingInvitation
+	if reqBody.Invite {
+		invitation, err = createInvitation(qtx, r, rinfo.User.ID, newuser, targetOrgID)
+		if err != nil {
+			utils.RespondWithError(w, utils.DBError("Error creating invitation", err))
 			return
 		}
+	}
+
+	err = tx.Commit(context.Background())
+	if err != nil {
+		utils.RespondWithError(w, utils.DBError("Error creating user", err))
+		return
+	}
 
+	if reqBody.Invite {
+		err = invitation.send()
+		if err != nil {
+			log.Println("Error sending invitation mail:", err)
+			utils.RespondWithJSON(w, utils.ToastResponse{
+				Header: "User",
+				Text:   "User created, but the invitation mail could not be sent",
+			})
+			return
+		}
+		utils.RespondWithJSON(w, utils.ToastResponse{
+			Header: "User",
+			Text:   "User invited",
+		})
+		return
 	}
 
 	utils.RespondWithJSON(w, utils.ToastResponse{
@@ -623,10 +687,7 @@ func (app *App) Editoneuser(w http.ResponseWriter, r *http.Request) {
 	userid := r.Header.Get("Id")
 	body, err := io.ReadAll(r.Body)
 	if err != nil {
-		utils.RespondWithJSON(w, utils.ErrorResponse{
-			Detail: "Error reading body",
-			Error:  err.Error(),
-		})
+		utils.RespondWithError(w, utils.BadRequest("invalid_body", "Error reading body", err))
 		return
 	}
 	defer r.Body.Close()
@@ -647,38 +708,26 @@ func (app *App) Editoneuser(w http.ResponseWriter, r *http.Request) {
 	var reqBody RequestBody
 	err = json.Unmarshal(body, &reqBody)
 	if err != nil {
-		utils.RespondWithJSON(w, utils.ErrorResponse{
-			Detail: "Error unmarshall body",
-			Error:  err.Error(),
-		})
+		utils.RespondWithError(w, utils.BadRequest("invalid_body", "Error unmarshall body", err))
 		return
 	}
 
 	email := strings.TrimSpace(strings.ToLower(reqBody.Email))
 	if !utils.IsValidEmail(email) {
-		utils.RespondWithJSON(w, utils.ErrorResponse{
-			Detail: "Error Email format. Is the email valid?",
-			Error:  "",
-		})
+		utils.RespondWithError(w, utils.Unprocessable("invalid_email", "Error Email format. Is the email valid?", nil))
 		return
 	}
 
 	useriduuid, err := uuid.Parse(userid)
 	if err != nil {
-		utils.RespondWithJSON(w, utils.ErrorResponse{
-			Detail: "Error getting parsing ID",
-			Error:  err.Error(),
-		})
+		utils.RespondWithError(w, utils.BadRequest("invalid_id", "Error getting parsing ID", err))
 		return
 	}
 
 	// Get the requesting user's info from context
 	rinfo, ok := r.Context().Value(utils.InfoContextKey).(utils.InfoKey)
 	if !ok {
-		utils.RespondWithJSON(w, utils.ErrorResponse{
-			Detail: "User not found in context",
-			Error:  "user not found",
-		})
+		utils.RespondWithError(w, utils.Unauthorized("unauthenticated", "User not found in context", errors.New("user not found")))
 		return
 	}
 
@@ -687,10 +736,7 @@ func (app *App) Editoneuser(w http.ResponseWriter, r *http.Request) {
 		Valid: true,
 	})
 	if err != nil {
-		utils.RespondWithJSON(w, utils.ErrorResponse{
-			Detail: "Error getting olduser from db",
-			Error:  err.Error(),
-		})
+		utils.RespondWithError(w, utils.DBError("Error getting olduser from db", err))
 		return
 	}
 
@@ -701,17 +747,11 @@ func (app *App) Editoneuser(w http.ResponseWriter, r *http.Request) {
 			Valid: true,
 		})
 		if err != nil {
-			utils.RespondWithJSON(w, utils.ErrorResponse{
-				Detail: "Error getting this user's organization",
-				Error:  err.Error(),
-			})
+			utils.RespondWithError(w, utils.DBError("Error getting this user's organization", err))
 			return
 		}
 		if userOrganization.ID != rinfo.Organization.ID {
-			utils.RespondWithJSON(w, utils.ErrorResponse{
-				Detail: "No permission to edit this user",
-				Error:  "user organization is not your organization",
-			})
+			utils.RespondWithError(w, utils.Forbidden("forbidden", "No permission to edit this user", errors.New("user organization is not your organization")))
 			return
 		}
 	}
@@ -725,25 +765,27 @@ func (app *App) Editoneuser(w http.ResponseWriter, r *http.Request) {
 		IsActive: pgtype.Bool{Bool: reqBody.IsActive, Valid: true},
 
 		IsSuperuser: pgtype.Bool{Bool: reqBody.IsSuperuser, Valid: true},
+
+		Twofactorsecret: olduser.Twofactorsecret,
 	}
 
 	if reqBody.Email != "" && utils.IsValidEmail(reqBody.Email) {
 		updateParams.Email = email
 	} else if reqBody.Email != "" && !utils.IsValidEmail(reqBody.Email) {
-		utils.RespondWithJSON(w, utils.ErrorResponse{
-			Detail: "Error Email format. Is the email valid?",
-			Error:  "",
-		})
+		utils.RespondWithError(w, utils.Unprocessable("invalid_email", "Error Email format. Is the email valid?", nil))
 		return
 	}
 
 	if reqBody.Password != "" {
+		err = utils.ValidatePassword(reqBody.Password, email, reqBody.Username, olduser.Username)
+		if err != nil {
+			utils.RespondWithError(w, err)
+			return
+		}
 		updateParams.Password, err = utils.HashPassword(reqBody.Password)
 		if err != nil {
-			utils.RespondWithJSON(w, utils.ErrorResponse{
-				Detail: "Error hashing password",
-				Error:  err.Error(),
-			})
+			utils.RespondWithError(w, utils.Internal("internal", "Error hashing password", err))
+			return
 		}
 	} else {
 		updateParams.Password = olduser.Password
@@ -766,158 +808,164 @@ func (app *App) Editoneuser(w http.ResponseWriter, r *http.Request) {
 		updateParams.Username = reqBody.Username
 	}
 
-	allgroups, err := app.Queries.GetGroups(context.Background())
-	if err != nil {
-		utils.RespondWithJSON(w, utils.ErrorResponse{
-			Detail: "Error hashing password",
-			Error:  err.Error(),
-		})
+	var targetOrgID pgtype.UUID
+	if reqBody.OrganizationID != "" {
+		// If organization ID is provided, parse it
+		orgUUID, err := uuid.Parse(reqBody.OrganizationID)
+		if err != nil {
+			utils.RespondWithError(w, utils.BadRequest("invalid_id", "Error parsing organization ID", err))
+			return
+		}
+		targetOrgID = pgtype.UUID{Bytes: orgUUID, Valid: true}
+
+		// Non-superusers can only create users in their own organization
+		if !(rinfo.User.IsSuperuser.Bool) {
+			if targetOrgID != rinfo.Organization.ID {
+				utils.RespondWithError(w, utils.Forbidden("forbidden", "No permission to create users in other organizations", errors.New("you can only create users in your own organization")))
+				return
+			}
+		}
+	} else {
+		// If no organization specified, use the creator's organization (unless they're a superuser)
+		if !(rinfo.User.IsSuperuser.Bool) {
+			targetOrgID = rinfo.Organization.ID
+		}
+		// Superusers can create users without an organization if they don't specify one
 	}
-	allpermissions, err := app.Queries.GetPermissions(context.Background())
+
+	tx, qtx, err := app.beginTx()
 	if err != nil {
-		utils.RespondWithJSON(w, utils.ErrorResponse{
-			Detail: "Error hashing password",
-			Error:  err.Error(),
-		})
+		utils.RespondWithError(w, utils.DBError("Error starting transaction", err))
+		return
 	}
+	defer tx.Rollback(context.Background())
 
-	newGroups := strings.Split(reqBody.Groups, "|")
-	newPermissions := strings.Split(reqBody.Permissions, "|")
-
-	oldGroups, _ := cache.GetGroupsByUser(pgtype.UUID{
-		Bytes: useriduuid,
-		Valid: true,
-	}, app.Queries)
+	before, oldOrgID, err := loadUserAudit(qtx, olduser.ID)
+	if err != nil {
+		utils.RespondWithError(w, utils.DBError("Error getting olduser from db", err))
+		return
+	}
 
-	oldPurePermissions, err := app.Queries.GetPurePermissionsByUserId(context.Background(), pgtype.UUID{
-		Bytes: useriduuid,
-		Valid: true,
-	})
+	allgroups, err := qtx.GetGroups(context.Background())
 	if err != nil {
-		utils.RespondWithJSON(w, utils.ErrorResponse{
-			Detail: "Error hashing password",
-			Error:  err.Error(),
-		})
+		utils.RespondWithError(w, utils.DBError("Error getting groups", err))
+		return
 	}
-	var oldPurePerms []string
-	for _, op := range oldPurePermissions {
-		oldPurePerms = append(oldPurePerms, op.Name)
+	allpermissions, err := qtx.GetPermissions(context.Background())
+	if err != nil {
+		utils.RespondWithError(w, utils.DBError("Error getting permissions", err))
+		return
 	}
 
+	newGroups := strings.Split(reqBody.Groups, "|")
+	newPermissions := strings.Split(reqBody.Permissions, "|")
+
 	for _, g := range allgroups {
-		oldHasgroup := slices.Contains(oldGroups, g.Name)
+		oldHasgroup := slices.Contains(before.Groups, g.Name)
 		if slices.Contains(newGroups, g.Name) {
 			if !oldHasgroup {
-				app.Queries.InsertUserGroupsByName(context.Background(), db.InsertUserGroupsByNameParams{
-
-					UserID: pgtype.UUID{
-						Bytes: useriduuid,
-						Valid: true,
-					},
-					Name: g.Name,
+				_, err = qtx.InsertUserGroupsByName(context.Background(), db.InsertUserGroupsByNameParams{
+					UserID: olduser.ID,
+					Name:   g.Name,
 				})
 			}
 		} else {
 			if oldHasgroup {
-				app.Queries.DeleteUserGroupsByName(context.Background(), db.DeleteUserGroupsByNameParams{
-					UserID: pgtype.UUID{
-						Bytes: useriduuid,
-						Valid: true,
-					},
-					Name: g.Name,
+				err = qtx.DeleteUserGroupsByName(context.Background(), db.DeleteUserGroupsByNameParams{
+					UserID: olduser.ID,
+					Name:   g.Name,
 				})
 			}
 		}
+		if err != nil {
+			utils.RespondWithError(w, utils.DBError("Error updating the groups of the user", err))
+			return
+		}
 	}
 
 	for _, p := range allpermissions {
-		oldhasperm := slices.Contains(oldPurePerms, p.Name)
+		oldhasperm := slices.Contains(before.Permissions, p.Name)
 		if slices.Contains(newPermissions, p.Name) {
 			if !oldhasperm {
-				app.Queries.InsertUserPermissionByName(context.Background(), db.InsertUserPermissionByNameParams{
-					UserID: pgtype.UUID{
-						Bytes: useriduuid,
-						Valid: true,
-					},
-					Name: p.Name,
+				_, err = qtx.InsertUserPermissionByName(context.Background(), db.InsertUserPermissionByNameParams{
+					UserID: olduser.ID,
+					Name:   p.Name,
 				})
-
 			}
 		} else {
 			if oldhasperm {
-				app.Queries.DeleteUserPermissionByName(
-					context.Background(),
-					db.DeleteUserPermissionByNameParams{
-						UserID: pgtype.UUID{
-							Bytes: useriduuid,
-							Valid: true,
-						},
-						Name: p.Name,
-					},
-				)
+				err = qtx.DeleteUserPermissionByName(context.Background(), db.DeleteUserPermissionByNameParams{
+					UserID: olduser.ID,
+					Name:   p.Name,
+				})
 			}
 		}
+		if err != nil {
+			utils.RespondWithError(w, utils.DBError("Error updating the permissions of the user", err))
+			return
+		}
 	}
 
-	_, err = app.Queries.UpdateUserByID(context.Background(), updateParams)
+	_, err = qtx.UpdateUserByID(context.Background(), updateParams)
 	if err != nil {
-		utils.RespondWithJSON(w, utils.ErrorResponse{
-			Detail: "Error updating User",
-			Error:  err.Error(),
+		utils.RespondWithError(w, utils.DBError("Error updating User", err))
+
+		return
+	}
+
+	// A user belongs to one organization at most, so it is linked or moved.
+	switch {
+	case !targetOrgID.Valid || targetOrgID == oldOrgID:
+	case !oldOrgID.Valid:
+		_, err = qtx.OrganizationLinkUser(context.Background(), db.OrganizationLinkUserParams{
+			UsersID:         olduser.ID,
+			OrganizationsID: targetOrgID,
 		})
+	default:
+		err = qtx.OrganizationUpdateUserOrganization(context.Background(), db.OrganizationUpdateUserOrganizationParams{
+			UsersID:         olduser.ID,
+			OrganizationsID: targetOrgID,
+		})
+	}
+	if err != nil {
+		utils.RespondWithError(w, utils.DBError("Error Organization link user", err))
 
 		return
 	}
 
-	var targetOrgID pgtype.UUID
-	if reqBody.OrganizationID != "" {
-		// If organization ID is provided, parse it
-		orgUUID, err := uuid.Parse(reqBody.OrganizationID)
+	// A new password ends all sessions of the user.
+	var sessions []db.Session
+	if reqBody.Password != "" {
+		sessions, err = qtx.SessionDeleteByUserId(context.Background(), olduser.ID)
 		if err != nil {
-			utils.RespondWithJSON(w, utils.ErrorResponse{
-				Detail: "Error parsing organization ID",
-				Error:  err.Error(),
-			})
+			utils.RespondWithError(w, utils.DBError("Error deleting the sessions of this user", err))
 			return
 		}
-		targetOrgID = pgtype.UUID{Bytes: orgUUID, Valid: true}
+	}
 
-		// Non-superusers can only create users in their own organization
-		if !(rinfo.User.IsSuperuser.Bool) {
-			if targetOrgID != rinfo.Organization.ID {
-				utils.RespondWithJSON(w, utils.ErrorResponse{
-					Detail: "No permission to create users in other organizations",
-					Error:  "you can only create users in your own organization",
-				})
-				return
-			}
-		}
-	} else {
-		// If no organization specified, use the creator's organization (unless they're a superuser)
-		if !(rinfo.User.IsSuperuser.Bool) {
-			targetOrgID = rinfo.Organization.ID
-		}
-		// Superusers can create users without an organization if they don't specify one
+	after, newOrgID, err := loadUserAudit(qtx, olduser.ID)
+	if err != nil {
+		utils.RespondWithError(w, utils.DBError("Error getting the updated user", err))
+		return
 	}
 
-	if targetOrgID.Valid {
-		_, err = app.Queries.OrganizationLinkUser(context.Background(), db.OrganizationLinkUserParams{
-			UsersID:         olduser.ID,
-			OrganizationsID: targetOrgID,
-		})
-		if err != nil {
-			utils.RespondWithJSON(w, utils.ErrorResponse{
-				Detail: "Error Organization link user",
-				Error:  err.Error(),
-			})
+	err = writeUserAudit(qtx, r, rinfo.User.ID, AuditUserUpdated, olduser.ID, newOrgID, before, after)
+	if err != nil {
+		utils.RespondWithError(w, utils.Internal("audit_failed", "Error writing audit event", err))
+		return
+	}
 
-			return
-		}
+	err = tx.Commit(context.Background())
+	if err != nil {
+		utils.RespondWithError(w, utils.DBError("Error updating User", err))
+		return
 	}
 
-	cache.Go4users.Del(olduser.Token.String) // The user is changed and hence needs to be deleted from cache.
+	cache.DelSessions(sessions)
+	cache.Go4users.Del(olduser.ID.Bytes) // The user is changed and hence needs to be deleted from cache.
 	cache.Go4groups.Del(olduser.ID.Bytes)
 	cache.Go4permissions.Del(olduser.ID.Bytes)
+	cache.Go4Organizations.Del(olduser.ID.Bytes) // The organization of the user may have changed.
 
 	utils.RespondWithJSON(w, utils.ToastResponse{
 		Header: "User updated",
@@ -1,68 +1,237 @@
 package cache
 
 import (
+	"container/list"
 	"context"
+	"encoding/json"
 	"errors"
 	"sync"
+	"time"
 
 	"github.com/google/uuid"
 	"github.com/jackc/pgx/v5/pgtype"
+	settings "github.com/karl1b/go4lage/pkg/settings"
 	"github.com/karl1b/go4lage/pkg/sql/db"
 )
 
 type go4Cache[K comparable, T any] struct {
-	mu    sync.RWMutex
-	items map[K]T
+	mu         sync.Mutex
+	name       string // Named caches publish their deletions to the other instances.
+	items      map[K]*list.Element
+	order      *list.List // Front is the most recently used entry.
+	ttl        time.Duration
+	maxEntries int
+	hits       uint64
+	misses     uint64
+	evictions  uint64
+	expired    uint64
 }
 
-func NewGo4Cache[K comparable, T any]() *go4Cache[K, T] {
-	return &go4Cache[K, T]{
-		items: make(map[K]T),
+type go4CacheEntry[K comparable, T any] struct {
+	key     K
+	value   T
+	expires time.Time // Zero if the entry does not expire.
+}
+
+type go4CacheOptions struct {
+	name       string
+	ttl        time.Duration
+	maxEntries int
+}
+
+// Option configures a go4Cache.
+type Option func(*go4CacheOptions)
+
+// WithName registers the cache for the invalidation between instances. Deletions and flushes are published under this name.
+func WithName(name string) Option {
+	return func(o *go4CacheOptions) {
+		o.name = name
+	}
+}
+
+// WithTTL lets entries expire after the given time. Zero keeps them until they are deleted.
+func WithTTL(ttl time.Duration) Option {
+	return func(o *go4CacheOptions) {
+		o.ttl = ttl
+	}
+}
+
+// WithMaxEntries evicts the least recently used entry once the cache holds more entries. Zero means no limit.
+func WithMaxEntries(maxEntries int) Option {
+	return func(o *go4CacheOptions) {
+		o.maxEntries = maxEntries
 	}
 }
 
+func NewGo4Cache[K comparable, T any](opts ...Option) *go4Cache[K, T] {
+	var o go4CacheOptions
+	for _, opt := range opts {
+		opt(&o)
+	}
+	c := &go4Cache[K, T]{
+		name:       o.name,
+		items:      make(map[K]*list.Element),
+		order:      list.New(),
+		ttl:        o.ttl,
+		maxEntries: o.maxEntries,
+	}
+	if c.name != "" {
+		remoteCaches[c.name] = c
+	}
+	return c
+}
+
 func (c *go4Cache[K, T]) Set(key K, value T) {
 	c.mu.Lock()
 	defer c.mu.Unlock()
-	c.items[key] = value
+
+	var expires time.Time
+	if c.ttl > 0 {
+		expires = time.Now().Add(c.ttl)
+	}
+
+	if element, exists := c.items[key]; exists {
+		entry := element.Value.(*go4CacheEntry[K, T])
+		entry.value = value
+		entry.expires = expires
+		c.order.MoveToFront(element)
+		return
+	}
+
+	c.items[key] = c.order.PushFront(&go4CacheEntry[K, T]{key: key, value: value, expires: expires})
+	for c.maxEntries > 0 && c.order.Len() > c.maxEntries {
+		c.remove(c.order.Back())
+		c.evictions++
+	}
 }
 
 func (c *go4Cache[K, T]) Get(key K) (T, bool) {
-	c.mu.RLock()
-	defer c.mu.RUnlock()
-	val, exists := c.items[key]
-	return val, exists
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	element, exists := c.items[key]
+	if !exists {
+		c.misses++
+		var zero T
+		return zero, false
+	}
+
+	entry := element.Value.(*go4CacheEntry[K, T])
+	if !entry.expires.IsZero() && time.Now().After(entry.expires) {
+		c.remove(element)
+		c.expired++
+		c.misses++
+		var zero T
+		return zero, false
+	}
+
+	c.order.MoveToFront(element)
+	c.hits++
+	return entry.value, true
 }
 
+// Del removes the entry here and on all other instances.
 func (c *go4Cache[K, T]) Del(key K) bool {
+	exists := c.delLocal(key)
+	publishInvalidation(c.name, key)
+	return exists
+}
+
+// Flush empties the cache here and on all other instances.
+func (c *go4Cache[K, T]) Flush() {
+	c.flushLocal()
+	publishInvalidation(c.name, nil)
+}
+
+func (c *go4Cache[K, T]) delLocal(key K) bool {
 	c.mu.Lock()
 	defer c.mu.Unlock()
-	if _, exists := c.items[key]; exists {
-		delete(c.items, key)
+	if element, exists := c.items[key]; exists {
+		c.remove(element)
 		return true
 	}
 	return false
 }
 
-func (c *go4Cache[K, T]) Flush() {
+func (c *go4Cache[K, T]) flushLocal() {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.items = make(map[K]*list.Element)
+	c.order.Init()
+}
+
+// Removes the entry of a key published by another instance.
+func (c *go4Cache[K, T]) delRemote(key json.RawMessage) error {
+	var k K
+	err := json.Unmarshal(key, &k)
+	if err != nil {
+		return err
+	}
+	c.delLocal(k)
+	return nil
+}
+
+func (c *go4Cache[K, T]) remove(element *list.Element) {
+	c.order.Remove(element)
+	delete(c.items, element.Value.(*go4CacheEntry[K, T]).key)
+}
+
+// CacheStats are the counters of one cache.
+type CacheStats struct {
+	Entries    int    `json:"entries"`
+	MaxEntries int    `json:"max_entries"`
+	TTLSeconds int64  `json:"ttl_s"`
+	Hits       uint64 `json:"hits"`
+	Misses     uint64 `json:"misses"`
+	Evictions  uint64 `json:"evictions"`
+	Expired    uint64 `json:"expired"`
+}
+
+func (c *go4Cache[K, T]) Stats() CacheStats {
 	c.mu.Lock()
 	defer c.mu.Unlock()
-	c.items = make(map[K]T)
+	return CacheStats{
+		Entries:    c.order.Len(),
+		MaxEntries: c.maxEntries,
+		TTLSeconds: int64(c.ttl / time.Second),
+		Hits:       c.hits,
+		Misses:     c.misses,
+		Evictions:  c.evictions,
+		Expired:    c.expired,
+	}
 }
 
-var Go4users *go4Cache[string, db.User]
+var Go4users *go4Cache[[16]byte, db.User]
+var Go4sessions *go4Cache[string, db.Session] // The key is the token hash.
 var Go4permissions *go4Cache[[16]byte, []string]
 var Go4groups *go4Cache[[16]byte, []string]
 var Go4Organizations *go4Cache[[16]byte, db.Organization]
 
 func init() {
-	Go4users = NewGo4Cache[string, db.User]()
-	Go4groups = NewGo4Cache[[16]byte, []string]()
-	Go4permissions = NewGo4Cache[[16]byte, []string]()
-	Go4Organizations = NewGo4Cache[[16]byte, db.Organization]()
+	// The TTL bounds how long changes made outside of this process, e.g. by another replica, stay unnoticed.
+	opts := []Option{
+		WithTTL(time.Duration(settings.Settings.CacheTTLS) * time.Second),
+		WithMaxEntries(settings.Settings.CacheMaxEntries),
+	}
+	Go4users = NewGo4Cache[[16]byte, db.User](append(opts, WithName("users"))...)
+	Go4sessions = NewGo4Cache[string, db.Session](append(opts, WithName("sessions"))...)
+	Go4groups = NewGo4Cache[[16]byte, []string](append(opts, WithName("groups"))...)
+	Go4permissions = NewGo4Cache[[16]byte, []string](append(opts, WithName("permissions"))...)
+	Go4Organizations = NewGo4Cache[[16]byte, db.Organization](append(opts, WithName("organizations"))...)
 
 }
 
+// AllCacheStats returns the counters of all global caches.
+func AllCacheStats() map[string]CacheStats {
+	return map[string]CacheStats{
+		"users":         Go4users.Stats(),
+		"sessions":      Go4sessions.Stats(),
+		"groups":        Go4groups.Stats(),
+		"permissions":   Go4permissions.Stats(),
+		"organizations": Go4Organizations.Stats(),
+	}
+}
+
 /*
 Those are the cache functions.
 1) Those functions below are the only functions that update the global cache by reading from db if needed.
@@ -76,35 +245,87 @@ func NullGroupsAndPermissions() {
 	Go4groups.Flush()
 }
 
-func GetUserByToken(token string, queries *db.Queries) (result db.User, err error) {
+// Removes sessions from the cache. Use it with the rows returned by the session delete queries.
+// The deletions are queued and reach the other instances together.
+func DelSessions(sessions []db.Session) {
+	for _, session := range sessions {
+		Go4sessions.Del(session.TokenHash)
+	}
+}
+
+// GetUserByToken resolves the session of a token hash and returns it together with its user.
+func GetUserByToken(tokenHash string, queries *db.Queries) (user db.User, session db.Session, err error) {
+
+	if tokenHash == "" {
+		return db.User{}, db.Session{}, errors.New("token may not be blank")
+	}
+
+	session, err = GetSessionByTokenHash(tokenHash, queries)
+	if err != nil {
+		return db.User{}, db.Session{}, err
+	}
 
-	if token == "" {
-		return db.User{}, errors.New("token may not be blank")
+	user, err = GetUserByID(session.UserID, queries)
+	if err != nil {
+		return db.User{}, db.Session{}, err
 	}
 
-	getFromDB := func(token string, queries *db.Queries) (db.User, error) {
-		user, err := queries.SelectUserByToken(context.Background(), pgtype.Text{String: token, Valid: true})
+	return user, session, nil
+}
+
+func GetSessionByTokenHash(tokenHash string, queries *db.Queries) (result db.Session, err error) {
+
+	getFromDB := func(tokenHash string, queries *db.Queries) (db.Session, error) {
+		session, err := queries.SessionSelectByTokenHash(context.Background(), tokenHash)
 		if err != nil {
-			return db.User{}, err // Handle error properly
+			return db.Session{}, err
 		}
-		Go4users.Set(token, user)
+		Go4sessions.Set(tokenHash, session)
+
+		return session, nil
+	}
+
+	defer func() {
+		if r := recover(); r != nil {
+			result, err = getFromDB(tokenHash, queries)
+		}
+	}()
+
+	cached_result, cacheFound := Go4sessions.Get(tokenHash)
+
+	if cacheFound {
+		return cached_result, nil
+	}
+
+	result, err = getFromDB(tokenHash, queries)
+	return result, err
+}
+
+func GetUserByID(id pgtype.UUID, queries *db.Queries) (result db.User, err error) {
+
+	getFromDB := func(id pgtype.UUID, queries *db.Queries) (db.User, error) {
+		user, err := queries.SelectUserById(context.Background(), id)
+		if err != nil {
+			return db.User{}, err
+		}
+		Go4users.Set(id.Bytes, user)
 
 		return user, nil
 	}
 
 	defer func() {
 		if r := recover(); r != nil {
-			result, err = getFromDB(token, queries)
+			result, err = getFromDB(id, queries)
 		}
 	}()
 
-	cached_result, cacheFound := Go4users.Get(token)
+	cached_result, cacheFound := Go4users.Get(id.Bytes)
 
 	if cacheFound {
 		return cached_result, nil
 	}
 
-	result, err = getFromDB(token, queries)
+	result, err = getFromDB(id, queries)
 	return result, err
 }
 
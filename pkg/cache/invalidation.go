@@ -0,0 +1,206 @@
+package cache
+
+import (
+	"context"
+	"encoding/json"
+	"log"
+	"sync/atomic"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+/*
+Several instances of go4lage can run against the same database.
+Each deletion or flush of a named cache is published with pg_notify, and every instance listens and evicts the same keys.
+Del and Flush do not wait for the database: the events are queued and one goroutine publishes
+everything that is waiting with a single NOTIFY, so deleting many keys costs few round trips.
+*/
+
+const invalidationChannel = "go4lage_cache"
+
+// How many events can wait to be published. If more are queued, the other instances flush all caches instead.
+const invalidationQueueSize = 1024
+
+// The payload of NOTIFY must be shorter than 8000 bytes. A batch is sent once it gets this big.
+const invalidationBatchBytes = 7000
+
+// Every instance ignores its own events.
+var instanceID = uuid.NewString()
+
+// One NOTIFY carries the events of one instance in the order they happened.
+type invalidationBatch struct {
+	Origin string         `json:"origin"`
+	Events []invalidation `json:"events"`
+}
+
+type invalidation struct {
+	Cache string          `json:"cache"`
+	Key   json.RawMessage `json:"key,omitempty"` // Empty flushes the whole cache.
+}
+
+type remoteCache interface {
+	delRemote(key json.RawMessage) error
+	flushLocal()
+}
+
+// The named caches. They are registered by NewGo4Cache during init and only read afterwards.
+var remoteCaches = map[string]remoteCache{}
+
+// Nil until StartInvalidation is called, so CLI commands do not publish.
+var publisher atomic.Pointer[pgxpool.Pool]
+
+var queuedInvalidations = make(chan invalidation, invalidationQueueSize)
+
+// Set if an event did not fit into the queue. The other instances are told to flush all caches then.
+var invalidationsDropped atomic.Bool
+
+func publishInvalidation(cacheName string, key any) {
+	if publisher.Load() == nil || cacheName == "" {
+		return
+	}
+
+	event := invalidation{Cache: cacheName}
+	if key != nil {
+		dat, err := json.Marshal(key)
+		if err != nil {
+			log.Println("Error encoding cache invalidation:", err)
+			return
+		}
+		event.Key = dat
+	}
+
+	select {
+	case queuedInvalidations <- event:
+	default:
+		invalidationsDropped.Store(true)
+	}
+}
+
+// Publishes the queued events until the program ends.
+func publishInvalidations(pool *pgxpool.Pool) {
+	for event := range queuedInvalidations {
+		batch := invalidationBatch{Origin: instanceID, Events: []invalidation{event}}
+		size := invalidationSize(event)
+
+		// Everything else that is waiting goes into the same NOTIFY.
+	collect:
+		for size < invalidationBatchBytes {
+			select {
+			case event := <-queuedInvalidations:
+				batch.Events = append(batch.Events, event)
+				size += invalidationSize(event)
+			default:
+				break collect
+			}
+		}
+
+		if invalidationsDropped.Swap(false) {
+			for name := range remoteCaches {
+				batch.Events = append(batch.Events, invalidation{Cache: name})
+			}
+		}
+
+		payload, err := json.Marshal(batch)
+		if err != nil {
+			log.Println("Error encoding cache invalidation:", err)
+			continue
+		}
+
+		ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+		_, err = pool.Exec(ctx, "SELECT pg_notify($1, $2)", invalidationChannel, string(payload))
+		cancel()
+		if err != nil {
+			log.Println("Error publishing cache invalidation:", err)
+		}
+	}
+}
+
+// The bytes the event adds to the payload, with a margin for the JSON around it.
+func invalidationSize(event invalidation) int {
+	return len(event.Cache) + len(event.Key) + 24
+}
+
+func applyInvalidation(payload string) {
+	var batch invalidationBatch
+	err := json.Unmarshal([]byte(payload), &batch)
+	if err != nil {
+		log.Println("Error decoding cache invalidation:", err)
+		return
+	}
+	if batch.Origin == instanceID {
+		return
+	}
+
+	for _, event := range batch.Events {
+		c, exists := remoteCaches[event.Cache]
+		if !exists {
+			continue
+		}
+		if len(event.Key) == 0 {
+			c.flushLocal()
+			continue
+		}
+		err = c.delRemote(event.Key)
+		if err != nil {
+			log.Println("Error decoding cache invalidation key:", err)
+		}
+	}
+}
+
+func flushAllLocal() {
+	for _, c := range remoteCaches {
+		c.flushLocal()
+	}
+}
+
+// StartInvalidation publishes the deletions of this instance with the pool and
+// listens on its own connection for the deletions of the other instances.
+// If the connection drops, it reconnects and flushes all caches, because events may have been missed meanwhile.
+func StartInvalidation(pool *pgxpool.Pool, dbURL string) {
+	publisher.Store(pool)
+	go publishInvalidations(pool)
+
+	go func() {
+		wait := time.Second
+		connectedBefore := false
+		for {
+			err := listenInvalidations(dbURL, func() {
+				if connectedBefore {
+					flushAllLocal()
+				}
+				connectedBefore = true
+				wait = time.Second
+			})
+			log.Printf("Cache invalidation listener stopped: %v. Reconnecting in %s.", err, wait)
+			time.Sleep(wait)
+			wait = min(wait*2, 30*time.Second)
+		}
+	}()
+}
+
+// Listens until the connection fails. onListen is called once the LISTEN is active.
+func listenInvalidations(dbURL string, onListen func()) error {
+	ctx := context.Background()
+	conn, err := pgx.Connect(ctx, dbURL)
+	if err != nil {
+		return err
+	}
+	defer conn.Close(ctx)
+
+	_, err = conn.Exec(ctx, "LISTEN "+invalidationChannel)
+	if err != nil {
+		return err
+	}
+	onListen()
+
+	for {
+		notification, err := conn.WaitForNotification(ctx)
+		if err != nil {
+			return err
+		}
+		applyInvalidation(notification.Payload)
+	}
+}
@@ -1,6 +1,7 @@
 package cache
 
 import (
+	"container/list"
 	"errors"
 	"sync"
 	"time"
@@ -9,36 +10,318 @@ import (
 )
 
 /*
-The throttlecache is there to prevent a login force attack.
+The throttlestore keeps the entries of the throttlers in least recently used order.
+Expired entries are removed by the janitor. If the store is full, the least recently used entry is evicted,
+so one-off IP addresses can not grow it without bounds.
 */
-type Throttlecache struct {
-	mu        sync.Mutex
-	breaktime map[string]int64
+type throttleStore[V any] struct {
+	items      map[string]*list.Element
+	order      *list.List // Front is the most recently used entry.
+	maxEntries int
+	evicted    uint64
+	expired    uint64
+}
+
+type throttleEntry[V any] struct {
+	key     string
+	value   V
+	expires int64 // Unix nano. The janitor removes the entry after this time.
 }
 
-var Loginthrottler Throttlecache
+func newThrottleStore[V any](maxEntries int) throttleStore[V] {
+	return throttleStore[V]{
+		items:      make(map[string]*list.Element),
+		order:      list.New(),
+		maxEntries: maxEntries,
+	}
+}
 
-func init() {
-	Loginthrottler = Throttlecache{
-		breaktime: make(map[string]int64),
+func (s *throttleStore[V]) get(key string) (V, bool) {
+	element, exists := s.items[key]
+	if !exists {
+		var zero V
+		return zero, false
 	}
+	s.order.MoveToFront(element)
+	return element.Value.(*throttleEntry[V]).value, true
+}
+
+func (s *throttleStore[V]) set(key string, value V, expires int64) {
+	if element, exists := s.items[key]; exists {
+		entry := element.Value.(*throttleEntry[V])
+		entry.value = value
+		entry.expires = expires
+		s.order.MoveToFront(element)
+		return
+	}
+	s.items[key] = s.order.PushFront(&throttleEntry[V]{key: key, value: value, expires: expires})
+	for s.maxEntries > 0 && s.order.Len() > s.maxEntries {
+		s.remove(s.order.Back())
+		s.evicted++
+	}
+}
+
+func (s *throttleStore[V]) del(key string) {
+	if element, exists := s.items[key]; exists {
+		s.remove(element)
+	}
+}
+
+func (s *throttleStore[V]) remove(element *list.Element) {
+	s.order.Remove(element)
+	delete(s.items, element.Value.(*throttleEntry[V]).key)
+}
+
+// Removes all expired entries.
+func (s *throttleStore[V]) sweep(now int64) {
+	for element := s.order.Back(); element != nil; {
+		prev := element.Prev()
+		if element.Value.(*throttleEntry[V]).expires <= now {
+			s.remove(element)
+			s.expired++
+		}
+		element = prev
+	}
+}
+
+// ThrottleStats are the metrics of one throttler.
+type ThrottleStats struct {
+	Name       string `json:"name"`
+	Entries    int    `json:"entries"`
+	MaxEntries int    `json:"max_entries"`
+	Allowed    uint64 `json:"allowed"`
+	Rejected   uint64 `json:"rejected"`
+	Evicted    uint64 `json:"evicted"`
+	Expired    uint64 `json:"expired"`
+}
+
+type throttler interface {
+	sweep()
+	Stats() ThrottleStats
+}
+
+var throttlers []throttler
 
+/*
+The throttlecache is there to prevent a login force attack and to rate limit routes.
+Each key (the IP address) may call limit times per window. A limit of 0 or less disables it.
+With penalize, every call above the limit extends the window, so the caller has to stop calling to get through again.
+Without it, the window stays fixed and the calls get through again when it is over.
+*/
+type Throttlecache struct {
+	mu       sync.Mutex
+	name     string
+	limit    int
+	window   func() time.Duration
+	penalize bool
+	calls    throttleStore[throttleWindow]
+	allowed  uint64
+	rejected uint64
 }
 
-// Checks if the user can call again.
+type throttleWindow struct {
+	count int
+	reset int64 // Unix nano
+}
+
+// NewThrottlecache creates a throttler and registers it for the janitor and the metrics.
+// The window is a function, so it can be read from the settings on every call.
+func NewThrottlecache(name string, limit int, window func() time.Duration, penalize bool) *Throttlecache {
+	t := &Throttlecache{
+		name:     name,
+		limit:    limit,
+		window:   window,
+		penalize: penalize,
+		calls:    newThrottleStore[throttleWindow](settings.Settings.ThrottleMaxEntries),
+	}
+	throttlers = append(throttlers, t)
+	return t
+}
+
+var Loginthrottler = NewThrottlecache("login", 1, func() time.Duration {
+	return time.Duration(settings.Settings.LoginThrottleTimeS) * time.Second
+}, true)
+
+var Apithrottler = NewThrottlecache("adminapi", settings.Settings.RateLimitRequests, func() time.Duration {
+	return time.Duration(settings.Settings.RateLimitWindowS) * time.Second
+}, false)
+
+// Checks if the key (IP address) can call again.
 // Returns an error if not.
 func (t *Throttlecache) Check(ip string) error {
+	if t.limit <= 0 {
+		return nil
+	}
 	defer t.mu.Unlock()
 	t.mu.Lock()
-	unixTimeNow := time.Now().Unix()
-	if nextAllowedTime, exists := t.breaktime[ip]; exists && unixTimeNow < nextAllowedTime {
-		t.breaktime[ip] = t.breaktime[ip] + int64(settings.Settings.LoginThrottleTimeS) // the next login attempt is allowed in 1 sec.
+	now := time.Now().UnixNano()
+	window := int64(t.window())
+
+	calls, exists := t.calls.get(ip)
+	if !exists || now >= calls.reset {
+		calls = throttleWindow{count: 0, reset: now + window}
+	}
+	if calls.count >= t.limit {
+		if t.penalize {
+			calls.reset = calls.reset + window // the next call is allowed one window later.
+			t.calls.set(ip, calls, calls.reset)
+		}
+		t.rejected++
 		return errors.New("too many requests")
 	}
-	if nextAllowedTime, exists := t.breaktime[ip]; exists && unixTimeNow >= nextAllowedTime {
-		delete(t.breaktime, ip) // If a user was okay its breaktime will be deleted. Very small performance gain.
+	calls.count++
+	t.calls.set(ip, calls, calls.reset)
+	t.allowed++
+	return nil
+}
+
+func (t *Throttlecache) sweep() {
+	defer t.mu.Unlock()
+	t.mu.Lock()
+	t.calls.sweep(time.Now().UnixNano())
+}
+
+// Stats returns the metrics of the throttler.
+func (t *Throttlecache) Stats() ThrottleStats {
+	defer t.mu.Unlock()
+	t.mu.Lock()
+	return ThrottleStats{
+		Name:       t.name,
+		Entries:    t.calls.order.Len(),
+		MaxEntries: t.calls.maxEntries,
+		Allowed:    t.allowed,
+		Rejected:   t.rejected,
+		Evicted:    t.calls.evicted,
+		Expired:    t.calls.expired,
+	}
+}
+
+/*
+The failthrottler counts failed logins per IP address.
+Every failure doubles the wait time until the next login attempt is allowed.
+*/
+type Failthrottler struct {
+	mu       sync.Mutex
+	failures throttleStore[failure]
+	allowed  uint64
+	rejected uint64
+}
+
+type failure struct {
+	count int
+	last  int64 // Unix
+}
+
+var IPFailthrottler = newFailthrottler()
+
+func newFailthrottler() *Failthrottler {
+	f := &Failthrottler{
+		failures: newThrottleStore[failure](settings.Settings.ThrottleMaxEntries),
+	}
+	throttlers = append(throttlers, f)
+	return f
+}
+
+// Backoff is the wait time after the given number of failed logins.
+// It starts at LoginThrottleTimeS and doubles with every failure up to LoginBackoffMaxS.
+func Backoff(failures int) time.Duration {
+	if failures <= 0 {
+		return 0
+	}
+	base := max(int64(settings.Settings.LoginThrottleTimeS), 1)
+	maxWait := int64(settings.Settings.LoginBackoffMaxS)
+	if failures > 30 {
+		return time.Duration(maxWait) * time.Second
+	}
+	return time.Duration(min(base<<(failures-1), maxWait)) * time.Second
+}
+
+// Checks if the IP address has to wait after failed logins.
+// Returns an error if so.
+func (f *Failthrottler) Check(ip string) error {
+	defer f.mu.Unlock()
+	f.mu.Lock()
+	fail, exists := f.failures.get(ip)
+	if !exists {
+		f.allowed++
 		return nil
 	}
-	t.breaktime[ip] = unixTimeNow + int64(settings.Settings.LoginThrottleTimeS)
+	now := time.Now().Unix()
+	if f.expired(fail, now) {
+		f.failures.del(ip)
+		f.allowed++
+		return nil
+	}
+	if now < fail.last+int64(Backoff(fail.count)/time.Second) {
+		f.rejected++
+		return errors.New("too many failed logins")
+	}
+	f.allowed++
 	return nil
 }
+
+// Records a failed login of the IP address.
+func (f *Failthrottler) Fail(ip string) {
+	defer f.mu.Unlock()
+	f.mu.Lock()
+	now := time.Now().Unix()
+	fail, _ := f.failures.get(ip)
+	if f.expired(fail, now) {
+		fail.count = 0
+	}
+	fail.count++
+	fail.last = now
+	f.failures.set(ip, fail, time.Unix(now+f.forgetAfter(), 0).UnixNano())
+}
+
+// Failures are forgotten after LockoutMins without a new failure.
+func (f *Failthrottler) forgetAfter() int64 {
+	return int64(settings.Settings.LockoutMins) * 60
+}
+
+func (f *Failthrottler) expired(fail failure, now int64) bool {
+	return now-fail.last > f.forgetAfter()
+}
+
+func (f *Failthrottler) sweep() {
+	defer f.mu.Unlock()
+	f.mu.Lock()
+	f.failures.sweep(time.Now().UnixNano())
+}
+
+// Stats returns the metrics of the throttler.
+func (f *Failthrottler) Stats() ThrottleStats {
+	defer f.mu.Unlock()
+	f.mu.Lock()
+	return ThrottleStats{
+		Name:       "loginfailures",
+		Entries:    f.failures.order.Len(),
+		MaxEntries: f.failures.maxEntries,
+		Allowed:    f.allowed,
+		Rejected:   f.rejected,
+		Evicted:    f.failures.evicted,
+		Expired:    f.failures.expired,
+	}
+}
+
+// AllThrottleStats returns the metrics of all throttlers.
+func AllThrottleStats() []ThrottleStats {
+	stats := []ThrottleStats{}
+	for _, t := range throttlers {
+		stats = append(stats, t.Stats())
+	}
+	return stats
+}
+
+// StartThrottleJanitor removes the expired entries of all throttlers in the background.
+func StartThrottleJanitor(interval time.Duration) {
+	go func() {
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+		for range ticker.C {
+			for _, t := range throttlers {
+				t.sweep()
+			}
+		}
+	}()
+}
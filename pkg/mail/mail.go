@@ -0,0 +1,104 @@
+package mail
+
+import (
+	"fmt"
+	"log"
+	"net/smtp"
+	"os"
+	"strings"
+	"sync"
+	"time"
+
+	settings "github.com/karl1b/go4lage/pkg/settings"
+)
+
+/*
+The mail package delivers mails like password reset links.
+Set MAIL_BACKEND to "smtp" for production. Everything else uses the LogMailer, which is handy for local development.
+You can plug in your own implementation by assigning it to Sender.
+*/
+type Mailer interface {
+	Send(to string, subject string, body string) error
+}
+
+var Sender Mailer
+
+func init() {
+	switch settings.Settings.MailBackend {
+	case "smtp":
+		Sender = &SMTPMailer{
+			Host:     settings.Settings.SmtpHost,
+			Port:     settings.Settings.SmtpPort,
+			User:     settings.Settings.SmtpUser,
+			Password: settings.Settings.SmtpPassword,
+			From:     settings.Settings.MailFrom,
+		}
+	default:
+		Sender = &LogMailer{Path: settings.Settings.MailLogFile}
+	}
+}
+
+// Send delivers a mail with the configured Sender.
+func Send(to string, subject string, body string) error {
+	return Sender.Send(to, subject, body)
+}
+
+// SMTPMailer sends mails via a plain SMTP server with PLAIN auth.
+type SMTPMailer struct {
+	Host     string
+	Port     string
+	User     string
+	Password string
+	From     string
+}
+
+func (m *SMTPMailer) Send(to string, subject string, body string) error {
+	if m.Host == "" {
+		return fmt.Errorf("smtp host is not set")
+	}
+
+	var auth smtp.Auth
+	if m.User != "" {
+		auth = smtp.PlainAuth("", m.User, m.Password, m.Host)
+	}
+
+	msg := strings.Join([]string{
+		"From: " + m.From,
+		"To: " + to,
+		"Subject: " + subject,
+		"Date: " + time.Now().Format(time.RFC1123Z),
+		"MIME-Version: 1.0",
+		"Content-Type: text/plain; charset=\"utf-8\"",
+		"",
+		body,
+	}, "\r\n")
+
+	return smtp.SendMail(m.Host+":"+m.Port, auth, m.From, []string{to}, []byte(msg))
+}
+
+// LogMailer does not send anything. It writes the mail to the log or appends it to the file at Path.
+type LogMailer struct {
+	mu   sync.Mutex
+	Path string
+}
+
+func (m *LogMailer) Send(to string, subject string, body string) error {
+	entry := fmt.Sprintf("=== %s\nTo: %s\nSubject: %s\n\n%s\n\n", time.Now().Format(time.RFC3339), to, subject, body)
+
+	if m.Path == "" {
+		log.Print(entry)
+		return nil
+	}
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	f, err := os.OpenFile(m.Path, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0600)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+
+	_, err = f.WriteString(entry)
+	return err
+}
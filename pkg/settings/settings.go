@@ -21,13 +21,50 @@ type Go4lageSettings struct {
 	Debug                     bool   `env:"DEBUG"`
 	Baseurl                   string `env:"BASEURL"`
 	Apiurl                    string `env:"APIURL"`
+	StaticMaxAgeS             int    `env:"STATIC_MAX_AGE_S" default:"60"`
+	StaticCompressMinBytes    int    `env:"STATIC_COMPRESS_MIN_BYTES" default:"1024"`
 	GooseDriver               string `env:"GOOSE_DRIVER"`
 	GooseDbString             string `env:"GOOSE_DBSTRING"`
 	LoginThrottleTimeS        int    `env:"LOGINTHROTTLE_TIME_S"`
+	LoginBackoffMaxS          int    `env:"LOGIN_BACKOFF_MAX_S" default:"300"`
+	LockoutThreshold          int    `env:"LOCKOUT_THRESHOLD" default:"10"`
+	LockoutMins               int    `env:"LOCKOUT_MINS" default:"15"`
+	ThrottleMaxEntries        int    `env:"THROTTLE_MAX_ENTRIES" default:"100000"`
+	CacheTTLS                 int    `env:"CACHE_TTL_S" default:"300"`
+	CacheMaxEntries           int    `env:"CACHE_MAX_ENTRIES" default:"10000"`
+	RateLimitRequests         int    `env:"RATELIMIT_REQUESTS" default:"300"`
+	RateLimitWindowS          int    `env:"RATELIMIT_WINDOW_S" default:"60"`
+	AccessLogRetentionDays    int    `env:"ACCESSLOG_RETENTION_DAYS" default:"30"` // 0 keeps the access logs forever.
 	Superuser2FA              bool   `env:"SUPERUSER_2FA"`
 	UserTokenValidMins        int    `env:"USER_TOKEN_VALID_MINS"`
 	SuperuserTokenValidMins   int    `env:"SUPERUSER_TOKEN_VALID_MINS"`
+	RefreshTokenValidMins     int    `env:"REFRESH_TOKEN_VALID_MINS" default:"10080"`
+	SuperuserRefreshValidMins int    `env:"SUPERUSER_REFRESH_TOKEN_VALID_MINS" default:"1440"`
 	UserLoginTrackingTimeMins int    `env:"USER_LOGIN_TRACKING_MINS"`
 	AppName                   string `env:"APP_NAME"`
 	DbURL                     string `env:"DB_URL"`
+	ResetTokenValidMins       int    `env:"RESET_TOKEN_VALID_MINS" default:"30"`
+	PasswordMinLength         int    `env:"PASSWORD_MIN_LENGTH" default:"12"`
+	PasswordMinClasses        int    `env:"PASSWORD_MIN_CLASSES" default:"3"` // Of lowercase, uppercase, digits and symbols.
+	PasswordRejectIdentity    bool   `env:"PASSWORD_REJECT_IDENTITY" default:"true"`
+	PasswordCheckBreached     bool   `env:"PASSWORD_CHECK_BREACHED" default:"true"`
+	BreachedPasswordsFile     string `env:"BREACHED_PASSWORDS_FILE,optional"`
+	PasswordHasher            string `env:"PASSWORD_HASHER" default:"bcrypt"` // bcrypt or argon2id
+	BcryptCost                int    `env:"BCRYPT_COST" default:"12"`
+	Argon2MemoryKiB           int    `env:"ARGON2_MEMORY_KIB" default:"65536"`
+	Argon2Iterations          int    `env:"ARGON2_ITERATIONS" default:"3"`
+	Argon2Parallelism         int    `env:"ARGON2_PARALLELISM" default:"2"`
+	InvitationValidHours      int    `env:"INVITATION_VALID_HOURS" default:"72"`
+	TrashRetentionDays        int    `env:"TRASH_RETENTION_DAYS" default:"30"`
+	OrganizationExpiredMode   string `env:"ORGANIZATION_EXPIRED_MODE" default:"reject"`
+	OrganizationGraceDays     int    `env:"ORGANIZATION_GRACE_DAYS" default:"7"`
+	OrganizationReminderDays  int    `env:"ORGANIZATION_REMINDER_DAYS" default:"14"`
+	SecretKey                 string `env:"SECRET_KEY,optional"` // Signs tokens like invitations. Without it, no invitations can be sent.
+	MailBackend               string `env:"MAIL_BACKEND" default:"log"`
+	MailLogFile               string `env:"MAIL_LOGFILE,optional"`
+	MailFrom                  string `env:"MAIL_FROM,optional"`
+	SmtpHost                  string `env:"SMTP_HOST,optional"`
+	SmtpPort                  string `env:"SMTP_PORT" default:"587"`
+	SmtpUser                  string `env:"SMTP_USER,optional"`
+	SmtpPassword              string `env:"SMTP_PASSWORD,optional"`
 }
@@ -1,8 +1,8 @@
 -- name: CreateUser :one
 INSERT INTO users 
-(id, user_created_at, token, token_created_at, reset_token, reset_token_created_at, email, password, first_name, last_name, is_active, is_superuser, twofactorsecret, username)
+(id, user_created_at, reset_token, reset_token_created_at, email, password, first_name, last_name, is_active, is_superuser, twofactorsecret, username, twofactor_enabled)
 VALUES
-($1, CURRENT_TIMESTAMP, $2, CURRENT_TIMESTAMP, $3, CURRENT_TIMESTAMP, $4, $5, $6, $7, $8, $9, $10, $11)
+($1, CURRENT_TIMESTAMP, $2, CURRENT_TIMESTAMP, $3, $4, $5, $6, $7, $8, $9, $10, $11)
 RETURNING *;
 
 -- name: DeleteUserById :one
@@ -11,48 +11,171 @@ DELETE FROM users WHERE id = $1 RETURNING *;
 -- name: UpdateUserByID :one
 UPDATE users
 SET
-    token = $2, 
-    token_created_at = $3, 
-    reset_token = $4, 
-    reset_token_created_at = $5, 
-    email = $6, 
-    password = $7, 
-    first_name = $8, 
-    last_name = $9,
-    is_active = $10,
-    is_superuser = $11,
-    twofactorsecret = $12,
-    username = $13
+    reset_token = $2, 
+    reset_token_created_at = $3, 
+    email = $4, 
+    password = $5, 
+    first_name = $6, 
+    last_name = $7,
+    is_active = $8,
+    is_superuser = $9,
+    twofactorsecret = $10,
+    username = $11
 WHERE id = $1
 RETURNING *;
 
--- name: UpdateTokenByID :one
+-- name: UpdateLastLoginByID :one
 UPDATE users
 SET
-    token = $2, 
-    token_created_at = CURRENT_TIMESTAMP,
     last_login = CURRENT_TIMESTAMP
 WHERE id = $1
 RETURNING *;
 
--- name: UpdateLastLoginByID :one
+-- name: SelectUserById :one
+SELECT * FROM users WHERE id = $1 AND deleted_at IS NULL;
+
+-- name: SelectUserByEmailPassword :one
+SELECT * FROM users WHERE email = $1 AND password = $2 AND deleted_at IS NULL;
+
+-- name: SelectUserByEmail :one
+SELECT * FROM users WHERE email = $1 AND deleted_at IS NULL;
+
+-- name: SelectAllUsers :many
+SELECT * FROM users WHERE deleted_at IS NULL;
+
+-- name: UpdateResetTokenByID :one
 UPDATE users
 SET
-    last_login = CURRENT_TIMESTAMP
+    reset_token = $2,
+    reset_token_created_at = CURRENT_TIMESTAMP
 WHERE id = $1
 RETURNING *;
 
--- name: SelectUserById :one
-SELECT * FROM users WHERE id = $1;
+-- name: SelectUserByResetToken :one
+SELECT * FROM users WHERE reset_token = $1 AND deleted_at IS NULL;
 
--- name: SelectUserByToken :one
-SELECT * FROM users WHERE token = $1;
+-- name: ResetPasswordByID :one
+UPDATE users
+SET
+    password = $2,
+    reset_token = NULL,
+    reset_token_created_at = NULL,
+    failed_login_attempts = 0,
+    last_failed_login = NULL,
+    locked_until = NULL
+WHERE id = $1
+RETURNING *;
 
--- name: SelectUserByEmailPassword :one
-SELECT * FROM users WHERE email = $1 AND password = $2;
+-- name: UpdateTwofactorByID :one
+UPDATE users
+SET
+    twofactorsecret = $2,
+    twofactor_enabled = $3
+WHERE id = $1
+RETURNING *;
 
--- name: SelectUserByEmail :one
-SELECT * FROM users WHERE email = $1;
+-- name: LoginFailedByID :one
+UPDATE users
+SET
+    failed_login_attempts = failed_login_attempts + 1,
+    last_failed_login = CURRENT_TIMESTAMP
+WHERE id = $1
+RETURNING *;
 
--- name: SelectAllUsers :many
-SELECT * FROM users;
\ No newline at end of file
+-- name: LockUserByID :one
+UPDATE users
+SET
+    locked_until = $2
+WHERE id = $1
+RETURNING *;
+
+-- name: UnlockUserByID :one
+UPDATE users
+SET
+    failed_login_attempts = 0,
+    last_failed_login = NULL,
+    locked_until = NULL
+WHERE id = $1
+RETURNING *;
+
+-- name: UserSelectPage :many
+SELECT u.id, u.user_created_at, u.username, u.first_name, u.last_name, u.email, u.is_active, u.is_superuser, u.last_login,
+    COALESCE(g.group_names, '')::text AS group_names,
+    o.id AS organization_id,
+    o.created_at AS organization_created_at,
+    o.organization_name,
+    o.email AS organization_email,
+    o.active_until AS organization_active_until
+FROM users AS u
+LEFT JOIN users_organizations AS uo ON uo.users_id = u.id
+LEFT JOIN organizations AS o ON o.id = uo.organizations_id AND o.deleted_at IS NULL
+LEFT JOIN LATERAL (
+    SELECT string_agg(gr.name, '|' ORDER BY gr.name) AS group_names
+    FROM users_groups AS ug
+    JOIN groups AS gr ON gr.id = ug.group_id
+    WHERE ug.user_id = u.id
+) AS g ON true
+WHERE u.deleted_at IS NULL
+    AND (sqlc.narg('organization_id')::uuid IS NULL OR uo.organizations_id = sqlc.narg('organization_id'))
+    AND (sqlc.narg('is_active')::boolean IS NULL OR u.is_active = sqlc.narg('is_active'))
+    AND (sqlc.narg('group_name')::text IS NULL OR EXISTS (
+        SELECT 1 FROM users_groups AS fug
+        JOIN groups AS fg ON fg.id = fug.group_id
+        WHERE fug.user_id = u.id AND fg.name = sqlc.narg('group_name')
+    ))
+    -- Same expression as users_search_idx, so the index is used.
+    AND (sqlc.narg('search')::text IS NULL OR to_tsvector('simple', COALESCE(u.first_name, '') || ' ' || COALESCE(u.last_name, '') || ' ' || translate(u.email, '@.', '  ')) @@ to_tsquery('simple', sqlc.narg('search')))
+ORDER BY
+    CASE WHEN sqlc.arg('sort')::text = 'email' AND NOT sqlc.arg('descending')::boolean THEN u.email END ASC,
+    CASE WHEN sqlc.arg('sort')::text = 'email' AND sqlc.arg('descending')::boolean THEN u.email END DESC,
+    CASE WHEN sqlc.arg('sort')::text = 'last_login' AND NOT sqlc.arg('descending')::boolean THEN u.last_login END ASC NULLS FIRST,
+    CASE WHEN sqlc.arg('sort')::text = 'last_login' AND sqlc.arg('descending')::boolean THEN u.last_login END DESC NULLS LAST,
+    CASE WHEN sqlc.arg('sort')::text = 'created_at' AND NOT sqlc.arg('descending')::boolean THEN u.user_created_at END ASC,
+    CASE WHEN sqlc.arg('sort')::text = 'created_at' AND sqlc.arg('descending')::boolean THEN u.user_created_at END DESC,
+    u.id
+LIMIT sqlc.arg('page_size') OFFSET sqlc.arg('page_offset');
+
+-- name: UserCount :one
+SELECT COUNT(*)
+FROM users AS u
+LEFT JOIN users_organizations AS uo ON uo.users_id = u.id
+WHERE u.deleted_at IS NULL
+    AND (sqlc.narg('organization_id')::uuid IS NULL OR uo.organizations_id = sqlc.narg('organization_id'))
+    AND (sqlc.narg('is_active')::boolean IS NULL OR u.is_active = sqlc.narg('is_active'))
+    AND (sqlc.narg('group_name')::text IS NULL OR EXISTS (
+        SELECT 1 FROM users_groups AS fug
+        JOIN groups AS fg ON fg.id = fug.group_id
+        WHERE fug.user_id = u.id AND fg.name = sqlc.narg('group_name')
+    ))
+    AND (sqlc.narg('search')::text IS NULL OR to_tsvector('simple', COALESCE(u.first_name, '') || ' ' || COALESCE(u.last_name, '') || ' ' || translate(u.email, '@.', '  ')) @@ to_tsquery('simple', sqlc.narg('search')));
+
+-- name: UserSelectTaken :many
+-- Deleted users are included, as their email and username are still taken.
+SELECT email, username FROM users
+WHERE email = ANY(sqlc.arg('emails')::text[]) OR username = ANY(sqlc.arg('usernames')::text[]);
+
+-- name: UserAcceptInvitation :one
+UPDATE users
+SET
+    password = $2,
+    is_active = true
+WHERE id = $1
+RETURNING *;
+
+-- name: UserRehashPassword :execrows
+-- Only replaces the hash that was verified, so a password changed in the meantime is kept.
+UPDATE users
+SET password = sqlc.arg('new_password')
+WHERE id = sqlc.arg('id') AND password = sqlc.arg('old_password');
+
+-- name: UserSoftDelete :one
+UPDATE users SET deleted_at = CURRENT_TIMESTAMP WHERE id = $1 AND deleted_at IS NULL RETURNING *;
+
+-- name: UserRestore :one
+UPDATE users SET deleted_at = NULL WHERE id = $1 AND deleted_at IS NOT NULL RETURNING *;
+
+-- name: UserSelectDeleted :many
+SELECT * FROM users WHERE deleted_at IS NOT NULL ORDER BY deleted_at DESC;
+
+-- name: UserPurgeDeleted :execrows
+DELETE FROM users WHERE deleted_at < $1;
@@ -1,6 +1,6 @@
 -- name: OrganizationCreate :one
-INSERT INTO organizations (id, organization_name, email, active_until) 
-VALUES ($1, $2, $3, $4) 
+INSERT INTO organizations (id, organization_name, email, active_until, require_twofactor) 
+VALUES ($1, $2, $3, $4, $5) 
 RETURNING *;
 
 -- name: OrganizationLinkUser :one
@@ -12,14 +12,14 @@ RETURNING *;
 UPDATE users_organizations SET organizations_id = $2 WHERE users_id = $1;
 
 -- name: OrganizationAll :many
-SELECT * FROM organizations;
+SELECT * FROM organizations WHERE deleted_at IS NULL;
 
 -- name: OrganizationSelectById :one
-SELECT * FROM organizations WHERE id = $1;
+SELECT * FROM organizations WHERE id = $1 AND deleted_at IS NULL;
 
 -- name: OrganizationUpdateById :one
 UPDATE organizations
-SET organization_name = $2, email = $3, active_until = $4
+SET organization_name = $2, email = $3, active_until = $4, require_twofactor = $5
 WHERE id = $1
 RETURNING *;
 
@@ -27,14 +27,42 @@ RETURNING *;
 SELECT o.*
 FROM organizations o
 JOIN users_organizations uo ON o.id = uo.organizations_id
-WHERE uo.users_id = $1
+WHERE uo.users_id = $1 AND o.deleted_at IS NULL
 LIMIT 1;
 
 -- name: OrganizationSelectAllUsers :many
 SELECT u.*
 FROM users u
 JOIN users_organizations uo ON u.id = uo.users_id
-WHERE uo.organizations_id = $1;
+WHERE uo.organizations_id = $1 AND u.deleted_at IS NULL;
 
 -- name: OrganizationDeleteByID :exec
-DELETE FROM organizations WHERE id = $1;
\ No newline at end of file
+DELETE FROM organizations WHERE id = $1;
+
+-- name: OrganizationSoftDelete :one
+UPDATE organizations SET deleted_at = CURRENT_TIMESTAMP WHERE id = $1 AND deleted_at IS NULL RETURNING *;
+
+-- name: OrganizationRestore :one
+UPDATE organizations SET deleted_at = NULL WHERE id = $1 AND deleted_at IS NOT NULL RETURNING *;
+
+-- name: OrganizationSelectDeleted :many
+SELECT * FROM organizations WHERE deleted_at IS NOT NULL ORDER BY deleted_at DESC;
+
+-- name: OrganizationPurgeDeleted :execrows
+DELETE FROM organizations WHERE deleted_at < $1;
+
+-- name: OrganizationSelectExpiring :many
+SELECT * FROM organizations
+WHERE deleted_at IS NULL
+    AND active_until > CURRENT_TIMESTAMP
+    AND active_until <= sqlc.arg('remind_before')
+    AND expiry_reminder_for IS DISTINCT FROM active_until
+ORDER BY active_until;
+
+-- name: OrganizationMarkReminded :execrows
+-- Only one instance wins, so the reminder is sent once.
+UPDATE organizations SET expiry_reminder_for = active_until
+WHERE id = $1 AND active_until = $2 AND expiry_reminder_for IS DISTINCT FROM active_until;
+
+-- name: OrganizationUnmarkReminded :exec
+UPDATE organizations SET expiry_reminder_for = NULL WHERE id = $1;
@@ -0,0 +1,37 @@
+-- name: SessionCreate :one
+INSERT INTO sessions (id, user_id, token_hash, user_agent, ip, refresh_token_hash)
+VALUES ($1, $2, $3, $4, $5, $6)
+RETURNING *;
+
+-- name: SessionSelectByTokenHash :one
+SELECT * FROM sessions WHERE token_hash = $1;
+
+-- name: SessionSelectByRefreshTokenHash :one
+SELECT * FROM sessions WHERE refresh_token_hash = $1;
+
+-- name: SessionSelectById :one
+SELECT * FROM sessions WHERE id = $1;
+
+-- name: SessionSelectByUserId :many
+SELECT * FROM sessions WHERE user_id = $1 ORDER BY last_seen DESC;
+
+-- name: SessionUpdateLastSeen :exec
+UPDATE sessions SET last_seen = CURRENT_TIMESTAMP WHERE id = $1;
+
+-- name: SessionRotate :one
+UPDATE sessions
+SET token_hash = $2, refresh_token_hash = $3, refreshed_at = CURRENT_TIMESTAMP, last_seen = CURRENT_TIMESTAMP
+WHERE id = $1
+RETURNING *;
+
+-- name: SessionDeleteById :one
+DELETE FROM sessions WHERE id = $1 RETURNING *;
+
+-- name: SessionDeleteByUserId :many
+DELETE FROM sessions WHERE user_id = $1 RETURNING *;
+
+-- name: SessionDeleteOthersByUserId :many
+DELETE FROM sessions WHERE user_id = $1 AND id <> $2 RETURNING *;
+
+-- name: SessionDeleteExpiredByUserId :many
+DELETE FROM sessions WHERE user_id = $1 AND refreshed_at < $2 RETURNING *;
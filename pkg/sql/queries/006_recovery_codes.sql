@@ -0,0 +1,16 @@
+-- name: RecoveryCodeCreate :one
+INSERT INTO recovery_codes (id, user_id, code_hash)
+VALUES ($1, $2, $3)
+RETURNING *;
+
+-- name: RecoveryCodeDeleteByUserId :exec
+DELETE FROM recovery_codes WHERE user_id = $1;
+
+-- name: RecoveryCodeConsume :one
+UPDATE recovery_codes
+SET used_at = CURRENT_TIMESTAMP
+WHERE user_id = $1 AND code_hash = $2 AND used_at IS NULL
+RETURNING *;
+
+-- name: RecoveryCodeCountUnused :one
+SELECT COUNT(*) FROM recovery_codes WHERE user_id = $1 AND used_at IS NULL;
@@ -0,0 +1,27 @@
+-- name: AuditCreate :one
+INSERT INTO audit_events (id, actor_id, organization_id, action, target_id, changes, request_id, ip)
+VALUES ($1, $2, $3, $4, $5, $6, $7, $8)
+RETURNING *;
+
+-- name: AuditSelect :many
+SELECT a.*, u.email AS actor_email
+FROM audit_events AS a
+LEFT JOIN users AS u ON u.id = a.actor_id
+WHERE (sqlc.narg('organization_id')::uuid IS NULL OR a.organization_id = sqlc.narg('organization_id'))
+    AND (sqlc.narg('actor_id')::uuid IS NULL OR a.actor_id = sqlc.narg('actor_id'))
+    AND (sqlc.narg('target_id')::uuid IS NULL OR a.target_id = sqlc.narg('target_id'))
+    AND (sqlc.narg('action')::text IS NULL OR a.action = sqlc.narg('action'))
+    AND (sqlc.narg('created_from')::timestamptz IS NULL OR a.created_at >= sqlc.narg('created_from'))
+    AND (sqlc.narg('created_to')::timestamptz IS NULL OR a.created_at < sqlc.narg('created_to'))
+ORDER BY a.created_at DESC, a.id DESC
+LIMIT sqlc.arg('page_size') OFFSET sqlc.arg('page_offset');
+
+-- name: AuditCount :one
+SELECT COUNT(*)
+FROM audit_events AS a
+WHERE (sqlc.narg('organization_id')::uuid IS NULL OR a.organization_id = sqlc.narg('organization_id'))
+    AND (sqlc.narg('actor_id')::uuid IS NULL OR a.actor_id = sqlc.narg('actor_id'))
+    AND (sqlc.narg('target_id')::uuid IS NULL OR a.target_id = sqlc.narg('target_id'))
+    AND (sqlc.narg('action')::text IS NULL OR a.action = sqlc.narg('action'))
+    AND (sqlc.narg('created_from')::timestamptz IS NULL OR a.created_at >= sqlc.narg('created_from'))
+    AND (sqlc.narg('created_to')::timestamptz IS NULL OR a.created_at < sqlc.narg('created_to'));
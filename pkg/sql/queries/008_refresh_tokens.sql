@@ -0,0 +1,6 @@
+-- name: UsedRefreshTokenCreate :exec
+INSERT INTO used_refresh_tokens (token_hash, session_id)
+VALUES ($1, $2);
+
+-- name: UsedRefreshTokenSelectByTokenHash :one
+SELECT * FROM used_refresh_tokens WHERE token_hash = $1;
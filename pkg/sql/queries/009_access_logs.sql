@@ -0,0 +1,25 @@
+-- name: AccessLogCreate :exec
+INSERT INTO access_logs (id, created_at, method, route, path, status, latency_us, user_id, organization_id, request_id, ip)
+VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11);
+
+-- name: AccessLogSelect :many
+SELECT * FROM access_logs
+WHERE (sqlc.narg('method')::text IS NULL OR method = sqlc.narg('method'))
+    AND (sqlc.narg('route')::text IS NULL OR route = sqlc.narg('route'))
+    AND (sqlc.narg('status')::integer IS NULL OR status = sqlc.narg('status'))
+    AND (sqlc.narg('user_id')::uuid IS NULL OR user_id = sqlc.narg('user_id'))
+    AND (sqlc.narg('organization_id')::uuid IS NULL OR organization_id = sqlc.narg('organization_id'))
+    AND (sqlc.narg('created_from')::timestamptz IS NULL OR created_at >= sqlc.narg('created_from'))
+    AND (sqlc.narg('created_to')::timestamptz IS NULL OR created_at < sqlc.narg('created_to'))
+ORDER BY created_at DESC, id DESC
+LIMIT sqlc.arg('page_size') OFFSET sqlc.arg('page_offset');
+
+-- name: AccessLogCount :one
+SELECT COUNT(*) FROM access_logs
+WHERE (sqlc.narg('method')::text IS NULL OR method = sqlc.narg('method'))
+    AND (sqlc.narg('route')::text IS NULL OR route = sqlc.narg('route'))
+    AND (sqlc.narg('status')::integer IS NULL OR status = sqlc.narg('status'))
+    AND (sqlc.narg('user_id')::uuid IS NULL OR user_id = sqlc.narg('user_id'))
+    AND (sqlc.narg('organization_id')::uuid IS NULL OR organization_id = sqlc.narg('organization_id'))
+    AND (sqlc.narg('created_from')::timestamptz IS NULL OR created_at >= sqlc.narg('created_from'))
+    AND (sqlc.narg('created_to')::timestamptz IS NULL OR created_at < sqlc.narg('created_to'));
@@ -0,0 +1,32 @@
+-- name: InvitationCreate :one
+INSERT INTO invitations (id, expires_at, user_id, organization_id, invited_by, token_hash)
+VALUES ($1, $2, $3, $4, $5, $6)
+RETURNING *;
+
+-- name: InvitationSelectById :one
+SELECT * FROM invitations WHERE id = $1;
+
+-- name: InvitationSelectByTokenHash :one
+SELECT * FROM invitations WHERE token_hash = $1;
+
+-- name: InvitationSelectPending :many
+SELECT i.*, u.email, u.first_name, u.last_name, o.organization_name, inviter.email AS invited_by_email
+FROM invitations AS i
+JOIN users AS u ON u.id = i.user_id
+LEFT JOIN organizations AS o ON o.id = i.organization_id
+LEFT JOIN users AS inviter ON inviter.id = i.invited_by
+WHERE i.accepted_at IS NULL AND i.revoked_at IS NULL
+    AND (sqlc.narg('organization_id')::uuid IS NULL OR i.organization_id = sqlc.narg('organization_id'))
+ORDER BY i.created_at DESC;
+
+-- name: InvitationAccept :one
+UPDATE invitations
+SET accepted_at = CURRENT_TIMESTAMP
+WHERE id = $1 AND accepted_at IS NULL AND revoked_at IS NULL
+RETURNING *;
+
+-- name: InvitationRevoke :one
+UPDATE invitations
+SET revoked_at = CURRENT_TIMESTAMP
+WHERE id = $1 AND accepted_at IS NULL AND revoked_at IS NULL
+RETURNING *;
@@ -0,0 +1,21 @@
+-- +goose Up
+CREATE TABLE sessions (
+    id UUID PRIMARY KEY NOT NULL,
+    user_id UUID NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    token_hash VARCHAR(64) UNIQUE NOT NULL,
+    created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    last_seen TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    user_agent TEXT,
+    ip TEXT
+);
+
+CREATE INDEX sessions_user_id_idx ON sessions (user_id);
+
+-- Carry over the current logins, so nobody is logged out by this migration.
+INSERT INTO sessions (id, user_id, token_hash, created_at, last_seen)
+SELECT gen_random_uuid(), id, encode(sha256(token::bytea), 'hex'), COALESCE(token_created_at, CURRENT_TIMESTAMP), last_login
+FROM users
+WHERE token IS NOT NULL AND token <> '' AND last_login IS NOT NULL;
+
+-- +goose Down
+DROP TABLE sessions;
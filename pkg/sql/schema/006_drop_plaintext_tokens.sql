@@ -0,0 +1,11 @@
+-- +goose Up
+-- Bearer tokens live hashed in sessions since 005_sessions.sql, which carried over every active login.
+-- The plaintext copies are wiped before the columns are dropped, so they can not survive in a dump.
+UPDATE users SET token = NULL, token_created_at = NULL;
+
+ALTER TABLE users DROP COLUMN token;
+ALTER TABLE users DROP COLUMN token_created_at;
+
+-- +goose Down
+ALTER TABLE users ADD COLUMN token VARCHAR(64) UNIQUE;
+ALTER TABLE users ADD COLUMN token_created_at TIMESTAMP WITH TIME ZONE;
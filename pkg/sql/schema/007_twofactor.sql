@@ -0,0 +1,12 @@
+-- +goose Up
+-- The secret of a user is pending until the first valid code enables it.
+ALTER TABLE users ADD COLUMN twofactor_enabled BOOLEAN NOT NULL DEFAULT false;
+
+UPDATE users SET twofactor_enabled = true WHERE twofactorsecret IS NOT NULL AND twofactorsecret <> '';
+
+-- If true, all members of the organization have to use 2FA.
+ALTER TABLE organizations ADD COLUMN require_twofactor BOOLEAN NOT NULL DEFAULT false;
+
+-- +goose Down
+ALTER TABLE organizations DROP COLUMN require_twofactor;
+ALTER TABLE users DROP COLUMN twofactor_enabled;
@@ -0,0 +1,19 @@
+-- +goose Up
+ALTER TABLE sessions ADD COLUMN refresh_token_hash VARCHAR(64) UNIQUE;
+ALTER TABLE sessions ADD COLUMN refreshed_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT CURRENT_TIMESTAMP;
+
+UPDATE sessions SET refreshed_at = created_at;
+
+-- Refresh tokens that were already rotated. If one of them is sent again, the session it belongs to is revoked.
+CREATE TABLE used_refresh_tokens (
+    token_hash VARCHAR(64) PRIMARY KEY NOT NULL,
+    session_id UUID NOT NULL REFERENCES sessions(id) ON DELETE CASCADE,
+    used_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX used_refresh_tokens_session_id_idx ON used_refresh_tokens (session_id);
+
+-- +goose Down
+DROP TABLE used_refresh_tokens;
+ALTER TABLE sessions DROP COLUMN refreshed_at;
+ALTER TABLE sessions DROP COLUMN refresh_token_hash;
@@ -0,0 +1,19 @@
+-- +goose Up
+CREATE TABLE audit_events (
+    id UUID PRIMARY KEY NOT NULL,
+    created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    actor_id UUID REFERENCES users(id) ON DELETE SET NULL,
+    organization_id UUID REFERENCES organizations(id) ON DELETE SET NULL,
+    action VARCHAR(64) NOT NULL,
+    target_id UUID,
+    changes JSONB, -- JSON
+    request_id TEXT,
+    ip TEXT
+);
+
+CREATE INDEX audit_events_created_at_idx ON audit_events (created_at);
+CREATE INDEX audit_events_organization_id_idx ON audit_events (organization_id, created_at);
+CREATE INDEX audit_events_target_id_idx ON audit_events (target_id);
+
+-- +goose Down
+DROP TABLE audit_events;
@@ -0,0 +1,24 @@
+-- +goose Up
+-- Partitioned by day. The partitions are created and dropped by the server according to the retention setting.
+CREATE TABLE access_logs (
+    id UUID NOT NULL,
+    created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    method VARCHAR(16) NOT NULL,
+    route TEXT NOT NULL,
+    path TEXT NOT NULL,
+    status INTEGER NOT NULL,
+    latency_us BIGINT NOT NULL,
+    user_id UUID,
+    organization_id UUID,
+    request_id TEXT,
+    ip TEXT,
+    PRIMARY KEY (id, created_at)
+) PARTITION BY RANGE (created_at);
+
+-- Catches the rows of days without a partition.
+CREATE TABLE access_logs_default PARTITION OF access_logs DEFAULT;
+
+CREATE INDEX access_logs_created_at_idx ON access_logs (created_at);
+
+-- +goose Down
+DROP TABLE access_logs;
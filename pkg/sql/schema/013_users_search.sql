@@ -0,0 +1,14 @@
+-- +goose Up
+-- The user list searches names and the parts of the email with this expression.
+CREATE INDEX users_search_idx ON users USING GIN (
+    to_tsvector('simple', COALESCE(first_name, '') || ' ' || COALESCE(last_name, '') || ' ' || translate(email, '@.', '  '))
+);
+CREATE INDEX users_user_created_at_idx ON users (user_created_at);
+CREATE INDEX users_last_login_idx ON users (last_login);
+CREATE INDEX users_organizations_organizations_id_idx ON users_organizations (organizations_id);
+
+-- +goose Down
+DROP INDEX users_organizations_organizations_id_idx;
+DROP INDEX users_last_login_idx;
+DROP INDEX users_user_created_at_idx;
+DROP INDEX users_search_idx;
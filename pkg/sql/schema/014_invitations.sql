@@ -0,0 +1,19 @@
+-- +goose Up
+-- An invited user is inactive and has no usable password until the invitation is accepted.
+CREATE TABLE invitations (
+    id UUID PRIMARY KEY,
+    created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    expires_at TIMESTAMP WITH TIME ZONE NOT NULL,
+    user_id UUID NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    organization_id UUID REFERENCES organizations(id) ON DELETE CASCADE,
+    invited_by UUID REFERENCES users(id) ON DELETE SET NULL,
+    token_hash TEXT NOT NULL UNIQUE,
+    accepted_at TIMESTAMP WITH TIME ZONE,
+    revoked_at TIMESTAMP WITH TIME ZONE
+);
+
+CREATE INDEX invitations_organization_id_idx ON invitations (organization_id, created_at);
+CREATE INDEX invitations_user_id_idx ON invitations (user_id);
+
+-- +goose Down
+DROP TABLE invitations;
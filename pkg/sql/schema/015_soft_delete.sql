@@ -0,0 +1,13 @@
+-- +goose Up
+-- Deleted users and organizations stay in the trash until they are restored or purged.
+ALTER TABLE users ADD COLUMN deleted_at TIMESTAMP WITH TIME ZONE;
+ALTER TABLE organizations ADD COLUMN deleted_at TIMESTAMP WITH TIME ZONE;
+
+CREATE INDEX users_deleted_at_idx ON users (deleted_at) WHERE deleted_at IS NOT NULL;
+CREATE INDEX organizations_deleted_at_idx ON organizations (deleted_at) WHERE deleted_at IS NOT NULL;
+
+-- +goose Down
+DROP INDEX organizations_deleted_at_idx;
+DROP INDEX users_deleted_at_idx;
+ALTER TABLE organizations DROP COLUMN deleted_at;
+ALTER TABLE users DROP COLUMN deleted_at;
@@ -0,0 +1,6 @@
+-- +goose Up
+-- The active_until the last expiry reminder was sent for. Extending an organization makes it due again.
+ALTER TABLE organizations ADD COLUMN expiry_reminder_for TIMESTAMP WITH TIME ZONE;
+
+-- +goose Down
+ALTER TABLE organizations DROP COLUMN expiry_reminder_for;
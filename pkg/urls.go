@@ -10,6 +10,7 @@ import (
 	"github.com/go-chi/chi/middleware"
 	"github.com/go-chi/cors"
 	admin "github.com/karl1b/go4lage/pkg/admin"
+	cache "github.com/karl1b/go4lage/pkg/cache"
 
 	settings "github.com/karl1b/go4lage/pkg/settings"
 	"github.com/karl1b/go4lage/pkg/sql/db"
@@ -18,7 +19,8 @@ import (
 	_ "github.com/lib/pq"
 )
 
-func StartServer() {
+// StartServer starts the webserver. With watchRoot, changes in root are served without a restart.
+func StartServer(watchRoot bool) {
 	conn, cleanup := utils.SetUp()
 	defer cleanup()
 	queries := db.New(conn)
@@ -29,8 +31,18 @@ func StartServer() {
 
 	adminApp := admin.App{
 		Queries: queries,
+		DB:      conn,
 	}
 
+	utils.LoadRoot()
+	cache.StartThrottleJanitor(time.Minute)
+	cache.StartInvalidation(conn, settings.Settings.DbURL)
+	utils.StartAccessLog(conn)
+	if watchRoot || settings.Settings.Debug {
+		utils.WatchRoot(500 * time.Millisecond)
+	}
+	adminApp.StartExpiryReminders()
+
 	r := chi.NewRouter()
 	r.Use(middleware.RequestID)
 	r.Use(middleware.RealIP)
@@ -49,10 +61,22 @@ func StartServer() {
 	// * for statics, serves the root folder content
 	r.Get("/*", utils.Root)
 
-	r.Post("/adminapi/login", adminApp.Login)
-	r.Get("/adminapi/dashboardinfo", adminApp.Dashboardinfo)
+	// Only the API calls are access logged, not the static files.
+	r.Group(func(r chi.Router) {
+		r.Use(utils.AccessLog)
+		r.Post("/adminapi/login", adminApp.Login)
+		r.Post("/adminapi/refresh", adminApp.Refresh)
+		r.Get("/adminapi/dashboardinfo", adminApp.Dashboardinfo)
+		r.Post("/adminapi/requestpasswordreset", adminApp.RequestPasswordReset)
+		r.Post("/adminapi/confirmpasswordreset", adminApp.ConfirmPasswordReset)
+		r.Post("/adminapi/invitation", adminApp.InvitationInfo)
+		r.Post("/adminapi/acceptinvitation", adminApp.AcceptInvitation)
+	})
 
 	r.Route("/adminapi", func(r chi.Router) {
+		r.Use(utils.AccessLog)
+		r.Use(utils.RateLimit(cache.Apithrottler))
+
 		// Routes with basic auth middleware
 		r.Group(func(r chi.Router) {
 			r.Use(app.AuthMiddleware("", "")) // "", "" means everyone who is logged in can use it.
@@ -60,12 +84,31 @@ func StartServer() {
 			/* Logout */
 			r.Get("/logout", adminApp.Logout)
 
+			/* Sessions */
+			r.Get("/mysessions", adminApp.MySessions)
+			r.Delete("/mysession", adminApp.RevokeMySession)
+			r.Delete("/myothersessions", adminApp.RevokeMyOtherSessions)
+
+			/* 2FA */
+			r.Post("/twofactorenroll", adminApp.TwofactorEnroll)
+			r.Post("/twofactorverify", adminApp.TwofactorVerify)
+			r.Post("/twofactordisable", adminApp.TwofactorDisable)
+			r.Post("/twofactorrecoverycodes", adminApp.TwofactorRecoveryCodes)
+
 			/* Feedback */
 			r.Post("/updatefeedbackuser", adminApp.UpdateFeedBackUser)
 			r.Get("/getuserspecificfeedback", adminApp.GetUserSpecificFeedBack)
 			r.Post("/newfeedback", adminApp.NewFeedBack)
 		})
 
+		// Routes that members of expired organizations can still use
+		r.Group(func(r chi.Router) {
+			r.Use(app.AuthMiddlewareAllowExpired("", ""))
+
+			/* Organization status */
+			r.Get("/organizationstatus", adminApp.OrganizationStatus)
+		})
+
 		// Routes for organization admins
 		r.Group(func(r chi.Router) {
 			r.Use(app.AuthMiddleware(utils.OrganizationAdminGroup, ""))
@@ -76,6 +119,16 @@ func StartServer() {
 			r.Delete("/deleteuser", adminApp.Deleteoneuser)
 			r.Put("/oneuser", adminApp.Editoneuser)
 			r.Post("/oneuser", adminApp.Createoneuser)
+			r.Delete("/usersession", adminApp.RevokeUserSession)
+			r.Delete("/usersessions", adminApp.RevokeUserSessions)
+			r.Delete("/usertwofactor", adminApp.ResetUserTwofactor)
+			r.Delete("/userlockout", adminApp.UnlockUser)
+			r.Post("/users/import", adminApp.ImportUsers)
+			r.Get("/users/export", adminApp.ExportUsers)
+
+			/* Invitations */
+			r.Get("/invitations", adminApp.Invitations)
+			r.Delete("/invitation", adminApp.RevokeInvitation)
 
 			/* User Groups and Permissions */
 			r.Get("/getusergroups", adminApp.GetUserGroups)
@@ -93,6 +146,9 @@ func StartServer() {
 			/* ORGANIZATIONS */
 			r.Get("/allorganizations", adminApp.AllOrganizations)
 			r.Get("/oneorganization", adminApp.OneOrganization)
+
+			/* Audit log */
+			r.Get("/auditlog", adminApp.AuditLog)
 		})
 
 		// Routes for only superusers
@@ -104,17 +160,24 @@ func StartServer() {
 			r.Delete("/deleteorganization", adminApp.DeleteOrganization)
 			r.Put("/editoneorganization", adminApp.EditOrganization)
 
+			/* Trash */
+			r.Get("/trash", adminApp.Trash)
+			r.Put("/restoreuser", adminApp.RestoreUser)
+			r.Put("/restoreorganization", adminApp.RestoreOrganization)
+
 			/* Feedback */
 			r.Get("/allfeedback", adminApp.AllFeedBack)
 			r.Get("/newfeedback", adminApp.NewFeedBack)
 			r.Post("/updatefeedbackstaff", adminApp.UpdateFeedBackStaff)
 
+			/* Monitoring */
+			r.Get("/throttlestats", adminApp.ThrottleStats)
+			r.Get("/cachestats", adminApp.CacheStats)
+			r.Get("/accesslogs", adminApp.AccessLogs)
 		})
 
 	})
 
-	//r.Get("/accesslogs", app.getAccessLogs) */
-
 	srv := &http.Server{
 		Handler: r,
 		Addr:    ":" + settings.Settings.Port,
@@ -0,0 +1,195 @@
+package utils
+
+import (
+	"context"
+	"log"
+	"net/http"
+	"strings"
+	"sync/atomic"
+	"time"
+
+	"github.com/go-chi/chi"
+	"github.com/go-chi/chi/middleware"
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5/pgtype"
+	"github.com/jackc/pgx/v5/pgxpool"
+	settings "github.com/karl1b/go4lage/pkg/settings"
+	"github.com/karl1b/go4lage/pkg/sql/db"
+)
+
+/*
+The access log records every request into the partitioned access_logs table.
+Requests only queue their entry, a background writer inserts it, so a slow database does not slow down the requests.
+If the queue is full, the entry is dropped.
+*/
+
+const accessLogQueueSize = 4096
+
+// Nil until StartAccessLog is called, so nothing is queued in CLI commands.
+var accessLogQueue atomic.Pointer[chan db.AccessLogCreateParams]
+
+var droppedAccessLogs atomic.Uint64
+
+type accessLogKey string
+
+const accessLogContextKey accessLogKey = "accesslog"
+
+// The user is only known inside of the AuthMiddleware, so it fills this in for the access log.
+type accessLogUser struct {
+	user         pgtype.UUID
+	organization pgtype.UUID
+}
+
+func setAccessLogUser(ctx context.Context, user pgtype.UUID, organization pgtype.UUID) {
+	if logUser, ok := ctx.Value(accessLogContextKey).(*accessLogUser); ok {
+		logUser.user = user
+		logUser.organization = organization
+	}
+}
+
+// AccessLog records method, route pattern, status, latency, user and organization of every request. It is used on the API routes.
+func AccessLog(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		queue := accessLogQueue.Load()
+		if queue == nil {
+			next.ServeHTTP(w, r)
+			return
+		}
+
+		start := time.Now()
+		logUser := &accessLogUser{}
+		ww := middleware.NewWrapResponseWriter(w, r.ProtoMajor)
+
+		defer func() {
+			status := ww.Status()
+			if status == 0 {
+				status = http.StatusOK
+			}
+			route := ""
+			if rctx := chi.RouteContext(r.Context()); rctx != nil {
+				route = rctx.RoutePattern()
+			}
+			requestID := middleware.GetReqID(r.Context())
+
+			entry := db.AccessLogCreateParams{
+				ID:             pgtype.UUID{Bytes: uuid.New(), Valid: true},
+				CreatedAt:      pgtype.Timestamptz{Time: start, Valid: true},
+				Method:         r.Method,
+				Route:          route,
+				Path:           r.URL.Path,
+				Status:         int32(status),
+				LatencyUs:      time.Since(start).Microseconds(),
+				UserID:         logUser.user,
+				OrganizationID: logUser.organization,
+				RequestID:      pgtype.Text{String: requestID, Valid: requestID != ""},
+				Ip:             pgtype.Text{String: ClientIP(r), Valid: true},
+			}
+			select {
+			case *queue <- entry:
+			default:
+				droppedAccessLogs.Add(1)
+			}
+		}()
+
+		next.ServeHTTP(ww, r.WithContext(context.WithValue(r.Context(), accessLogContextKey, logUser)))
+	})
+}
+
+// StartAccessLog starts the writer of the access log and the maintenance of its partitions.
+func StartAccessLog(pool *pgxpool.Pool) {
+	queue := make(chan db.AccessLogCreateParams, accessLogQueueSize)
+	queries := db.New(pool)
+
+	// The partitions of today have to exist before the first request is logged.
+	maintainAccessLogPartitions(pool)
+
+	go func() {
+		for entry := range queue {
+			ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+			err := queries.AccessLogCreate(ctx, entry)
+			cancel()
+			if err != nil {
+				log.Println("Error writing access log:", err)
+			}
+		}
+	}()
+
+	go func() {
+		ticker := time.NewTicker(time.Hour)
+		defer ticker.Stop()
+		for range ticker.C {
+			maintainAccessLogPartitions(pool)
+			if dropped := droppedAccessLogs.Swap(0); dropped > 0 {
+				log.Printf("Access log queue was full, %d entries dropped.", dropped)
+			}
+		}
+	}()
+
+	accessLogQueue.Store(&queue)
+}
+
+const accessLogPartitionPrefix = "access_logs_p"
+
+// Creates the partitions of today and the next days and drops the ones older than the retention.
+func maintainAccessLogPartitions(pool *pgxpool.Pool) {
+	ctx, cancel := context.WithTimeout(context.Background(), time.Minute)
+	defer cancel()
+
+	today := time.Now().UTC().Truncate(24 * time.Hour)
+
+	for day := range 3 {
+		from := today.AddDate(0, 0, day)
+		to := from.AddDate(0, 0, 1)
+		_, err := pool.Exec(ctx, "CREATE TABLE IF NOT EXISTS "+accessLogPartitionPrefix+from.Format("20060102")+
+			" PARTITION OF access_logs FOR VALUES FROM ('"+from.Format(time.RFC3339)+"') TO ('"+to.Format(time.RFC3339)+"')")
+		if err != nil {
+			log.Println("Error creating access log partition:", err)
+		}
+	}
+
+	if settings.Settings.AccessLogRetentionDays <= 0 {
+		return
+	}
+	oldest := today.AddDate(0, 0, -settings.Settings.AccessLogRetentionDays)
+
+	rows, err := pool.Query(ctx, `SELECT c.relname FROM pg_inherits i
+		JOIN pg_class c ON c.oid = i.inhrelid
+		JOIN pg_class p ON p.oid = i.inhparent
+		WHERE p.relname = 'access_logs'`)
+	if err != nil {
+		log.Println("Error listing access log partitions:", err)
+		return
+	}
+	var expired []string
+	for rows.Next() {
+		var name string
+		err = rows.Scan(&name)
+		if err != nil {
+			break
+		}
+		day, err := time.Parse("20060102", strings.TrimPrefix(name, accessLogPartitionPrefix))
+		if err != nil {
+			continue // The default partition
+		}
+		if day.Before(oldest) {
+			expired = append(expired, name)
+		}
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		log.Println("Error listing access log partitions:", err)
+		return
+	}
+
+	for _, name := range expired {
+		_, err = pool.Exec(ctx, "DROP TABLE IF EXISTS "+name)
+		if err != nil {
+			log.Println("Error dropping access log partition:", err)
+		}
+	}
+
+	_, err = pool.Exec(ctx, "DELETE FROM access_logs_default WHERE created_at < $1", oldest)
+	if err != nil {
+		log.Println("Error deleting old access logs:", err)
+	}
+}
@@ -0,0 +1,139 @@
+package utils
+
+import (
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"log"
+	"net/http"
+	"strings"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
+	settings "github.com/karl1b/go4lage/pkg/settings"
+)
+
+/*
+APIError is the error of an API call. Respond with it by RespondWithError.
+The code is stable, so the dashboard can translate it. The message is shown to the user.
+The details are sent along, e.g. the single violations of the password policy.
+The cause stays internal, it is only sent out in debug mode.
+*/
+type APIError struct {
+	Status  int
+	Code    string
+	Message string
+	Details any
+	Cause   error
+}
+
+func (e *APIError) Error() string {
+	if e.Cause == nil {
+		return e.Code + ": " + e.Message
+	}
+	return e.Code + ": " + e.Message + ": " + e.Cause.Error()
+}
+
+func (e *APIError) Unwrap() error {
+	return e.Cause
+}
+
+func NewAPIError(status int, code string, message string, cause error) *APIError {
+	return &APIError{Status: status, Code: code, Message: message, Cause: cause}
+}
+
+func (e *APIError) WithDetails(details any) *APIError {
+	e.Details = details
+	return e
+}
+
+// The request is malformed, e.g. an invalid id or body. 400
+func BadRequest(code string, message string, cause error) *APIError {
+	return NewAPIError(http.StatusBadRequest, code, message, cause)
+}
+
+// The caller is not logged in or the token is not valid. 401
+func Unauthorized(code string, message string, cause error) *APIError {
+	return NewAPIError(http.StatusUnauthorized, code, message, cause)
+}
+
+// The caller is logged in, but may not do this. 403
+func Forbidden(code string, message string, cause error) *APIError {
+	return NewAPIError(http.StatusForbidden, code, message, cause)
+}
+
+// 404
+func NotFound(code string, message string, cause error) *APIError {
+	return NewAPIError(http.StatusNotFound, code, message, cause)
+}
+
+// The request collides with the current state, e.g. an email that is already taken. 409
+func Conflict(code string, message string, cause error) *APIError {
+	return NewAPIError(http.StatusConflict, code, message, cause)
+}
+
+// The request is well formed, but its values are not valid. 422
+func Unprocessable(code string, message string, cause error) *APIError {
+	return NewAPIError(http.StatusUnprocessableEntity, code, message, cause)
+}
+
+// 429
+func TooManyRequests(code string, message string, cause error) *APIError {
+	return NewAPIError(http.StatusTooManyRequests, code, message, cause)
+}
+
+// 500
+func Internal(code string, message string, cause error) *APIError {
+	return NewAPIError(http.StatusInternalServerError, code, message, cause)
+}
+
+// DBError classifies the error of a query.
+// No rows is a 404, a unique violation a 409, other constraint violations a 422 and everything else a 500.
+func DBError(message string, err error) *APIError {
+	var apiErr *APIError
+	if errors.As(err, &apiErr) {
+		return apiErr
+	}
+	if errors.Is(err, pgx.ErrNoRows) || errors.Is(err, sql.ErrNoRows) {
+		return NotFound("not_found", message, err)
+	}
+	var pgErr *pgconn.PgError
+	if errors.As(err, &pgErr) {
+		switch {
+		case pgErr.Code == "23505": // unique_violation
+			return Conflict("already_exists", message, err)
+		case pgErr.Code == "23503": // foreign_key_violation
+			return Unprocessable("invalid_reference", message, err)
+		case strings.HasPrefix(pgErr.Code, "23"), strings.HasPrefix(pgErr.Code, "22"): // other integrity constraints and data exceptions
+			return Unprocessable("invalid_value", message, err)
+		}
+	}
+	return Internal("internal", message, err)
+}
+
+// RespondWithError sends the error with its status. Errors that are no APIError are a 500.
+// The cause is stripped unless in debug mode.
+func RespondWithError(w http.ResponseWriter, err error) {
+	var apiErr *APIError
+	if !errors.As(err, &apiErr) {
+		apiErr = Internal("internal", "Internal server error", err)
+	}
+
+	if apiErr.Status >= http.StatusInternalServerError {
+		log.Println("Error:", apiErr)
+	}
+
+	payload := ErrorResponse{
+		Code:    apiErr.Code,
+		Detail:  apiErr.Message,
+		Details: apiErr.Details,
+	}
+	if settings.Settings.Debug && apiErr.Cause != nil {
+		payload.Error = apiErr.Cause.Error()
+	}
+
+	dat, _ := json.Marshal(payload)
+	w.Header().Add("Content-Type", "application/json")
+	w.WriteHeader(apiErr.Status)
+	w.Write(dat)
+}
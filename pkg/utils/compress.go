@@ -0,0 +1,150 @@
+package utils
+
+import (
+	"bytes"
+	"compress/gzip"
+	"fmt"
+	"path/filepath"
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/andybalholm/brotli"
+)
+
+/*
+Static files are compressed once when they are built, not for every request.
+Only text files of at least STATIC_COMPRESS_MIN_BYTES are compressed, images and fonts are compressed already.
+A variant is only kept if it is smaller than the file.
+*/
+
+// The extensions of files worth compressing.
+var compressibleExtensions = []string{".html", ".css", ".js", ".mjs", ".map", ".svg", ".json", ".xml", ".txt"}
+
+func compressible(path string, data []byte, minBytes int) bool {
+	if len(data) < minBytes {
+		return false
+	}
+	return slices.Contains(compressibleExtensions, strings.ToLower(filepath.Ext(path)))
+}
+
+// GzipData compresses data with the best gzip compression.
+func GzipData(data []byte) ([]byte, error) {
+	var buf bytes.Buffer
+	writer, err := gzip.NewWriterLevel(&buf, gzip.BestCompression)
+	if err != nil {
+		return nil, err
+	}
+	_, err = writer.Write(data)
+	if err != nil {
+		return nil, err
+	}
+	err = writer.Close()
+	if err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+// BrotliData compresses data with the best brotli compression.
+func BrotliData(data []byte) ([]byte, error) {
+	var buf bytes.Buffer
+	writer := brotli.NewWriterLevel(&buf, brotli.BestCompression)
+	_, err := writer.Write(data)
+	if err != nil {
+		return nil, err
+	}
+	err = writer.Close()
+	if err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+// Returns the compressed data, or nil if it does not get smaller.
+func smallerVariant(data []byte, compress func([]byte) ([]byte, error)) ([]byte, error) {
+	compressed, err := compress(data)
+	if err != nil {
+		return nil, err
+	}
+	if len(compressed) >= len(data) {
+		return nil, nil
+	}
+	return compressed, nil
+}
+
+// Tells if the Accept-Encoding header accepts the encoding, like "gzip" or "br".
+// An explicit entry wins over "*", and q=0 refuses the encoding.
+func acceptsEncoding(header string, encoding string) bool {
+	accepted := false
+	for _, part := range strings.Split(header, ",") {
+		name, params, _ := strings.Cut(part, ";")
+		name = strings.TrimSpace(name)
+		if !strings.EqualFold(name, encoding) && name != "*" {
+			continue
+		}
+
+		q := 1.0
+		for _, param := range strings.Split(params, ";") {
+			value, found := strings.CutPrefix(strings.TrimSpace(param), "q=")
+			if !found {
+				continue
+			}
+			parsed, err := strconv.ParseFloat(value, 64)
+			if err != nil {
+				parsed = 0
+			}
+			q = parsed
+		}
+
+		if name != "*" {
+			return q > 0
+		}
+		accepted = q > 0
+	}
+	return accepted
+}
+
+// CompressionSummary tells how much the gzip and brotli variants of the files save.
+func CompressionSummary(files map[string]*StaticFile) string {
+	var count, original, gzipped, brotlied int
+	for _, file := range files {
+		if file.Gzip == nil && file.Brotli == nil {
+			continue
+		}
+		count++
+		original += len(file.Data)
+		gzipped += len(variantOrData(file.Gzip, file.Data))
+		brotlied += len(variantOrData(file.Brotli, file.Data))
+	}
+	if count == 0 {
+		return "No files compressed."
+	}
+	return fmt.Sprintf("Compressed %d of %d files: %s to %s with gzip (%s), %s with brotli (%s).",
+		count, len(files),
+		formatBytes(original),
+		formatBytes(gzipped), savedPercent(original, gzipped),
+		formatBytes(brotlied), savedPercent(original, brotlied))
+}
+
+func variantOrData(variant []byte, data []byte) []byte {
+	if variant == nil {
+		return data
+	}
+	return variant
+}
+
+func savedPercent(original int, compressed int) string {
+	return fmt.Sprintf("-%.0f%%", 100*(1-float64(compressed)/float64(original)))
+}
+
+func formatBytes(n int) string {
+	switch {
+	case n >= 1<<20:
+		return fmt.Sprintf("%.1f MB", float64(n)/(1<<20))
+	case n >= 1<<10:
+		return fmt.Sprintf("%.1f KB", float64(n)/(1<<10))
+	default:
+		return fmt.Sprintf("%d B", n)
+	}
+}
@@ -0,0 +1,35 @@
+package utils
+
+import "testing"
+
+func TestAcceptsEncoding(t *testing.T) {
+	tests := []struct {
+		name     string
+		header   string
+		encoding string
+		want     bool
+	}{
+		{"empty header", "", "gzip", false},
+		{"listed", "gzip, deflate, br", "br", true},
+		{"not listed", "gzip, deflate", "br", false},
+		{"case is ignored", "GZIP", "gzip", true},
+		{"with quality", "gzip;q=0.5", "gzip", true},
+		{"refused with q=0", "gzip;q=0, br", "gzip", false},
+		{"refused with q=0.0", "br;q=0.0", "br", false},
+		{"invalid quality refuses", "br;q=high", "br", false},
+		{"wildcard", "*", "br", true},
+		{"wildcard refused", "*;q=0", "gzip", false},
+		{"explicit entry wins over the wildcard", "br;q=0, *", "br", false},
+		{"explicit entry wins over a refused wildcard", "*;q=0, gzip", "gzip", true},
+		{"spaces around the parameters", "gzip ; q=1 , br", "gzip", true},
+		{"prefix of another name", "gzipx", "gzip", false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := acceptsEncoding(tt.header, tt.encoding); got != tt.want {
+				t.Errorf("acceptsEncoding(%q, %q) = %v, want %v", tt.header, tt.encoding, got, tt.want)
+			}
+		})
+	}
+}
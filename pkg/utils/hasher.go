@@ -0,0 +1,209 @@
+package utils
+
+import (
+	"crypto/rand"
+	"crypto/subtle"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"strings"
+
+	settings "github.com/karl1b/go4lage/pkg/settings"
+	"golang.org/x/crypto/argon2"
+	"golang.org/x/crypto/bcrypt"
+)
+
+/*
+Passwords are hashed with the hasher of PASSWORD_HASHER. Every hash tells its algorithm and parameters,
+so hashes of older settings can still be verified. The login rehashes them with the current settings.
+bcrypt hashes look like $2a$12$..., Argon2id hashes use the PHC format $argon2id$v=19$m=65536,t=3,p=2$salt$hash.
+*/
+
+var ErrPasswordMismatch = errors.New("password does not match")
+var ErrUnknownHash = errors.New("unknown password hash")
+
+// PasswordHasher hashes passwords with one algorithm and its parameters.
+type PasswordHasher interface {
+	// Hash returns a self-describing hash of the password.
+	Hash(password string) (string, error)
+	// Matches tells if the hash is one of this algorithm. Only then Verify and NeedsRehash are called with it.
+	Matches(hash string) bool
+	// Verify returns ErrPasswordMismatch if the password does not match.
+	Verify(hash string, password string) error
+	// NeedsRehash tells if the hash uses other parameters than this hasher.
+	NeedsRehash(hash string) bool
+}
+
+// BcryptHasher hashes with bcrypt. Note that bcrypt only uses the first 72 bytes and refuses longer passwords.
+type BcryptHasher struct {
+	Cost int
+}
+
+func (h BcryptHasher) Hash(password string) (string, error) {
+	hash, err := bcrypt.GenerateFromPassword([]byte(password), h.Cost)
+	if err != nil {
+		return "", err
+	}
+	return string(hash), nil
+}
+
+func (h BcryptHasher) Matches(hash string) bool {
+	return strings.HasPrefix(hash, "$2a$") || strings.HasPrefix(hash, "$2b$") || strings.HasPrefix(hash, "$2y$")
+}
+
+func (h BcryptHasher) Verify(hash string, password string) error {
+	err := bcrypt.CompareHashAndPassword([]byte(hash), []byte(password))
+	if errors.Is(err, bcrypt.ErrMismatchedHashAndPassword) {
+		return ErrPasswordMismatch
+	}
+	return err
+}
+
+func (h BcryptHasher) NeedsRehash(hash string) bool {
+	cost, err := bcrypt.Cost([]byte(hash))
+	return err != nil || cost != h.Cost
+}
+
+// Argon2idHasher hashes with Argon2id. Memory is in KiB.
+type Argon2idHasher struct {
+	Memory      uint32
+	Iterations  uint32
+	Parallelism uint8
+	SaltLength  uint32
+	KeyLength   uint32
+}
+
+// Parameters and parts of an Argon2id hash.
+type argon2idHash struct {
+	memory      uint32
+	iterations  uint32
+	parallelism uint8
+	salt        []byte
+	key         []byte
+}
+
+func (h Argon2idHasher) Hash(password string) (string, error) {
+	salt := make([]byte, h.SaltLength)
+	_, err := rand.Read(salt)
+	if err != nil {
+		return "", err
+	}
+	key := argon2.IDKey([]byte(password), salt, h.Iterations, h.Memory, h.Parallelism, h.KeyLength)
+	return fmt.Sprintf("$argon2id$v=%d$m=%d,t=%d,p=%d$%s$%s", argon2.Version, h.Memory, h.Iterations, h.Parallelism,
+		base64.RawStdEncoding.EncodeToString(salt), base64.RawStdEncoding.EncodeToString(key)), nil
+}
+
+func (h Argon2idHasher) Matches(hash string) bool {
+	return strings.HasPrefix(hash, "$argon2id$")
+}
+
+func (h Argon2idHasher) Verify(hash string, password string) error {
+	parsed, err := parseArgon2id(hash)
+	if err != nil {
+		return err
+	}
+	key := argon2.IDKey([]byte(password), parsed.salt, parsed.iterations, parsed.memory, parsed.parallelism, uint32(len(parsed.key)))
+	if subtle.ConstantTimeCompare(key, parsed.key) != 1 {
+		return ErrPasswordMismatch
+	}
+	return nil
+}
+
+func (h Argon2idHasher) NeedsRehash(hash string) bool {
+	parsed, err := parseArgon2id(hash)
+	if err != nil {
+		return true
+	}
+	return parsed.memory != h.Memory || parsed.iterations != h.Iterations || parsed.parallelism != h.Parallelism ||
+		uint32(len(parsed.salt)) != h.SaltLength || uint32(len(parsed.key)) != h.KeyLength
+}
+
+func parseArgon2id(hash string) (argon2idHash, error) {
+	// "", "argon2id", "v=19", "m=65536,t=3,p=2", salt, key
+	parts := strings.Split(hash, "$")
+	if len(parts) != 6 || parts[1] != "argon2id" {
+		return argon2idHash{}, ErrUnknownHash
+	}
+
+	var version int
+	_, err := fmt.Sscanf(parts[2], "v=%d", &version)
+	if err != nil || version != argon2.Version {
+		return argon2idHash{}, fmt.Errorf("unsupported argon2 version: %s", parts[2])
+	}
+
+	var parsed argon2idHash
+	_, err = fmt.Sscanf(parts[3], "m=%d,t=%d,p=%d", &parsed.memory, &parsed.iterations, &parsed.parallelism)
+	if err != nil {
+		return argon2idHash{}, fmt.Errorf("invalid argon2 parameters: %w", err)
+	}
+	parsed.salt, err = base64.RawStdEncoding.DecodeString(parts[4])
+	if err != nil {
+		return argon2idHash{}, fmt.Errorf("invalid argon2 salt: %w", err)
+	}
+	parsed.key, err = base64.RawStdEncoding.DecodeString(parts[5])
+	if err != nil {
+		return argon2idHash{}, fmt.Errorf("invalid argon2 key: %w", err)
+	}
+	return parsed, nil
+}
+
+// Hasher is the hasher of new hashes. It is set from the settings, but you can plug in your own.
+var Hasher PasswordHasher
+
+// Hashers verify existing hashes. Keep the hashers of algorithms you moved away from, so old hashes still work.
+var Hashers []PasswordHasher
+
+func init() {
+	bcryptHasher := BcryptHasher{Cost: settings.Settings.BcryptCost}
+	argon2idHasher := Argon2idHasher{
+		Memory:      uint32(settings.Settings.Argon2MemoryKiB),
+		Iterations:  uint32(settings.Settings.Argon2Iterations),
+		Parallelism: uint8(settings.Settings.Argon2Parallelism),
+		SaltLength:  16,
+		KeyLength:   32,
+	}
+
+	switch settings.Settings.PasswordHasher {
+	case "argon2id":
+		Hasher = argon2idHasher
+	default:
+		Hasher = bcryptHasher
+	}
+	Hashers = []PasswordHasher{bcryptHasher, argon2idHasher}
+}
+
+// Finds the hasher of an existing hash. The current Hasher wins, so its parameters are compared for the rehash.
+func hasherOf(hash string) (PasswordHasher, error) {
+	if Hasher.Matches(hash) {
+		return Hasher, nil
+	}
+	for _, h := range Hashers {
+		if h.Matches(hash) {
+			return h, nil
+		}
+	}
+	return nil, ErrUnknownHash
+}
+
+// HashPassword hashes the password with the current Hasher.
+func HashPassword(password string) (string, error) {
+	return Hasher.Hash(password)
+}
+
+// CompareHashAndPassword returns nil if the password matches the hash, whatever algorithm made it.
+func CompareHashAndPassword(hash, password string) error {
+	h, err := hasherOf(hash)
+	if err != nil {
+		return err
+	}
+	return h.Verify(hash, password)
+}
+
+// PasswordNeedsRehash tells if the hash was made with another algorithm or other parameters than the current Hasher.
+// Unusable passwords never need a rehash.
+func PasswordNeedsRehash(hash string) bool {
+	if hash == UnusablePassword {
+		return false
+	}
+	return !Hasher.Matches(hash) || Hasher.NeedsRehash(hash)
+}
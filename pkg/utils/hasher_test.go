@@ -0,0 +1,158 @@
+package utils
+
+import (
+	"errors"
+	"testing"
+
+	"golang.org/x/crypto/bcrypt"
+)
+
+// Cheap parameters, the tests check the format and not the strength.
+var (
+	testBcrypt   = BcryptHasher{Cost: bcrypt.MinCost}
+	testArgon2id = Argon2idHasher{Memory: 64, Iterations: 1, Parallelism: 1, SaltLength: 16, KeyLength: 32}
+)
+
+func TestHashers(t *testing.T) {
+	tests := []struct {
+		name   string
+		hasher PasswordHasher
+		other  PasswordHasher // The same algorithm with other parameters.
+	}{
+		{"bcrypt", testBcrypt, BcryptHasher{Cost: bcrypt.MinCost + 1}},
+		{"argon2id", testArgon2id, Argon2idHasher{Memory: 128, Iterations: 1, Parallelism: 1, SaltLength: 16, KeyLength: 32}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			hash, err := tt.hasher.Hash("correct horse")
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !tt.hasher.Matches(hash) {
+				t.Errorf("Matches(%q) = false, want true", hash)
+			}
+			if err := tt.hasher.Verify(hash, "correct horse"); err != nil {
+				t.Errorf("Verify() with the password = %v, want nil", err)
+			}
+			if err := tt.hasher.Verify(hash, "wrong horse"); !errors.Is(err, ErrPasswordMismatch) {
+				t.Errorf("Verify() with another password = %v, want %v", err, ErrPasswordMismatch)
+			}
+			if tt.hasher.NeedsRehash(hash) {
+				t.Error("NeedsRehash() with the same parameters = true, want false")
+			}
+			if !tt.other.NeedsRehash(hash) {
+				t.Error("NeedsRehash() with other parameters = false, want true")
+			}
+
+			again, err := tt.hasher.Hash("correct horse")
+			if err != nil {
+				t.Fatal(err)
+			}
+			if again == hash {
+				t.Error("two hashes of the same password are equal, the salt is missing")
+			}
+		})
+	}
+}
+
+func TestHasherMatches(t *testing.T) {
+	tests := []struct {
+		hash         string
+		wantBcrypt   bool
+		wantArgon2id bool
+	}{
+		{"$2a$12$R9h/cIPz0gi.URNNX3kh2OPST9/PgBkqquzi.Ss7KIUgO2t0jWMUW", true, false},
+		{"$2b$12$R9h/cIPz0gi.URNNX3kh2OPST9/PgBkqquzi.Ss7KIUgO2t0jWMUW", true, false},
+		{"$2y$12$R9h/cIPz0gi.URNNX3kh2OPST9/PgBkqquzi.Ss7KIUgO2t0jWMUW", true, false},
+		{"$argon2id$v=19$m=65536,t=3,p=2$c2FsdA$a2V5", false, true},
+		{"$argon2i$v=19$m=65536,t=3,p=2$c2FsdA$a2V5", false, false},
+		{UnusablePassword, false, false},
+		{"", false, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.hash, func(t *testing.T) {
+			if got := testBcrypt.Matches(tt.hash); got != tt.wantBcrypt {
+				t.Errorf("BcryptHasher.Matches(%q) = %v, want %v", tt.hash, got, tt.wantBcrypt)
+			}
+			if got := testArgon2id.Matches(tt.hash); got != tt.wantArgon2id {
+				t.Errorf("Argon2idHasher.Matches(%q) = %v, want %v", tt.hash, got, tt.wantArgon2id)
+			}
+		})
+	}
+}
+
+func TestArgon2idInvalidHashes(t *testing.T) {
+	tests := []struct {
+		name string
+		hash string
+	}{
+		{"too few parts", "$argon2id$v=19$m=64,t=1,p=1$c2FsdA"},
+		{"other version", "$argon2id$v=16$m=64,t=1,p=1$c2FsdA$a2V5"},
+		{"broken parameters", "$argon2id$v=19$m=lots$c2FsdA$a2V5"},
+		{"salt is no base64", "$argon2id$v=19$m=64,t=1,p=1$!!!$a2V5"},
+		{"key is no base64", "$argon2id$v=19$m=64,t=1,p=1$c2FsdA$!!!"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := testArgon2id.Verify(tt.hash, "correct horse")
+			if err == nil || errors.Is(err, ErrPasswordMismatch) {
+				t.Errorf("Verify(%q) = %v, want a parse error", tt.hash, err)
+			}
+			if !testArgon2id.NeedsRehash(tt.hash) {
+				t.Errorf("NeedsRehash(%q) = false, want true", tt.hash)
+			}
+		})
+	}
+}
+
+// Moving from bcrypt to Argon2id: old hashes still verify and are rehashed on the next login.
+func TestHasherMigration(t *testing.T) {
+	previousHasher, previousHashers := Hasher, Hashers
+	t.Cleanup(func() { Hasher, Hashers = previousHasher, previousHashers })
+
+	Hasher = testBcrypt
+	oldHash, err := HashPassword("correct horse")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	Hasher = testArgon2id
+	Hashers = []PasswordHasher{testBcrypt, testArgon2id}
+
+	if err := CompareHashAndPassword(oldHash, "correct horse"); err != nil {
+		t.Errorf("CompareHashAndPassword() of the bcrypt hash = %v, want nil", err)
+	}
+	if err := CompareHashAndPassword(oldHash, "wrong horse"); !errors.Is(err, ErrPasswordMismatch) {
+		t.Errorf("CompareHashAndPassword() with another password = %v, want %v", err, ErrPasswordMismatch)
+	}
+	if !PasswordNeedsRehash(oldHash) {
+		t.Error("PasswordNeedsRehash() of the bcrypt hash = false, want true")
+	}
+
+	newHash, err := HashPassword("correct horse")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !testArgon2id.Matches(newHash) {
+		t.Errorf("HashPassword() = %q, want an Argon2id hash", newHash)
+	}
+	if PasswordNeedsRehash(newHash) {
+		t.Error("PasswordNeedsRehash() of the Argon2id hash = true, want false")
+	}
+
+	if PasswordNeedsRehash(UnusablePassword) {
+		t.Error("PasswordNeedsRehash() of the unusable password = true, want false")
+	}
+	if err := CompareHashAndPassword(UnusablePassword, ""); err == nil {
+		t.Error("CompareHashAndPassword() of the unusable password = nil, want an error")
+	}
+
+	// Without the bcrypt hasher, the old hash is unknown.
+	Hashers = []PasswordHasher{testArgon2id}
+	if err := CompareHashAndPassword(oldHash, "correct horse"); !errors.Is(err, ErrUnknownHash) {
+		t.Errorf("CompareHashAndPassword() without bcrypt = %v, want %v", err, ErrUnknownHash)
+	}
+}
@@ -2,62 +2,82 @@ package utils
 
 import (
 	"context"
+	"database/sql"
+	"errors"
 	"net/http"
 	"slices"
 	"strings"
 	"time"
 
+	"github.com/jackc/pgx/v5"
 	cache "github.com/karl1b/go4lage/pkg/cache"
 	settings "github.com/karl1b/go4lage/pkg/settings"
 
 	"github.com/karl1b/go4lage/pkg/sql/db"
 )
 
+// RateLimit limits the requests per IP address with the given throttler. It can be used on any route group.
+func RateLimit(throttler *cache.Throttlecache) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			err := throttler.Check(ClientIP(r))
+			if err != nil {
+				RespondWithError(w, TooManyRequests("rate_limited", "Too many requests. Try again later.", err))
+				return
+			}
+			next.ServeHTTP(w, r)
+		})
+	}
+}
+
 // This makes sure that only logged in users can access the route.
 // If you enter a group or permission only users with one of them will be able to use this route.
 // It adds the user to the context as well.
+// Members of expired organizations are rejected, see ORGANIZATION_EXPIRED_MODE.
 func (app *App) AuthMiddleware(group string, permission string) func(http.Handler) http.Handler {
+	return app.authMiddleware(group, permission, false)
+}
+
+// AuthMiddlewareAllowExpired works like AuthMiddleware, but lets members of expired organizations through.
+// Use it for routes they still need, like the organization status.
+func (app *App) AuthMiddlewareAllowExpired(group string, permission string) func(http.Handler) http.Handler {
+	return app.authMiddleware(group, permission, true)
+}
+
+func (app *App) authMiddleware(group string, permission string, allowExpired bool) func(http.Handler) http.Handler {
 	return func(next http.Handler) http.Handler {
 		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
 
 			// Extract the token from the Authorization header
 			authorization := r.Header.Get("Authorization")
 			token := strings.TrimPrefix(authorization, "Token ")
+			if token == "" {
+				RespondWithError(w, Unauthorized("token_invalid", "Error Getting User By Token", errors.New("token may not be blank")))
+				return
+			}
 
-			// Retrieve the user by token
-			user, err := cache.GetUserByToken(token, app.Queries)
+			// Retrieve the user by the session of the token
+			user, session, err := cache.GetUserByToken(HashToken(token), app.Queries)
 			if err != nil {
-				RespondWithJSON(w, ErrorResponse{
-					Detail: "Error Getting User By Token",
-					Error:  err.Error(),
-				})
+				RespondWithError(w, Unauthorized("token_invalid", "Error Getting User By Token", err))
 				return
 			}
 
 			// Inactive users are not permitted to user the app. Superusers are always permitted
 			if !user.IsActive.Bool && !user.IsSuperuser.Bool {
-				RespondWithJSON(w, ErrorResponse{
-					Detail: "User inactive.",
-					Error:  "user is not active",
-				})
+				RespondWithError(w, Forbidden("user_inactive", "User inactive.", errors.New("user is not active")))
 				return
 			}
 
 			// Superusers have a a different timeout setting
-			if user.IsSuperuser.Bool && user.TokenCreatedAt.Time.Add(time.Duration(settings.Settings.SuperuserTokenValidMins)*time.Minute).Before(time.Now()) {
-				RespondWithJSON(w, ErrorResponse{
-					Detail: "Login again.",
-					Error:  "token outdated",
-				})
+			if user.IsSuperuser.Bool && session.RefreshedAt.Time.Add(time.Duration(settings.Settings.SuperuserTokenValidMins)*time.Minute).Before(time.Now()) {
+				RespondWithError(w, Unauthorized("token_expired", "Refresh the token or login again.", errors.New("token outdated")))
 				return
 			}
 
 			// User token timeout check
-			if !user.IsSuperuser.Bool && user.TokenCreatedAt.Time.Add(time.Duration(settings.Settings.UserTokenValidMins)*time.Minute).Before(time.Now()) {
-				RespondWithJSON(w, ErrorResponse{
-					Detail: "Login again.",
-					Error:  "token outdated",
-				})
+			if !user.IsSuperuser.Bool && session.RefreshedAt.Time.Add(time.Duration(settings.Settings.UserTokenValidMins)*time.Minute).Before(time.Now()) {
+				RespondWithError(w, Unauthorized("token_expired", "Refresh the token or login again.", errors.New("token outdated")))
 				return
 			}
 
@@ -65,10 +85,7 @@ func (app *App) AuthMiddleware(group string, permission string) func(http.Handle
 			var perms []string
 			perms, err = cache.GetPermissionsByUser(user.ID, app.Queries)
 			if err != nil {
-				RespondWithJSON(w, ErrorResponse{
-					Detail: "Error getting permission for user",
-					Error:  err.Error(),
-				})
+				RespondWithError(w, DBError("Error getting permission for user", err))
 				return
 			}
 
@@ -79,10 +96,7 @@ func (app *App) AuthMiddleware(group string, permission string) func(http.Handle
 			var groups []string
 			groups, err = cache.GetGroupsByUser(user.ID, app.Queries)
 			if err != nil {
-				RespondWithJSON(w, ErrorResponse{
-					Detail: "Error getting group for user",
-					Error:  err.Error(),
-				})
+				RespondWithError(w, DBError("Error getting group for user", err))
 				return
 
 			}
@@ -91,46 +105,72 @@ func (app *App) AuthMiddleware(group string, permission string) func(http.Handle
 			}
 			if !(hasPermission || hasGroup) && !(group == "" && permission == "") {
 
-				RespondWithJSON(w, ErrorResponse{
-					Detail: "You do not have the permission or are not in the correct group to do this",
-					Error:  "permission check failed",
-				})
+				RespondWithError(w, Forbidden("forbidden", "You do not have the permission or are not in the correct group to do this", errors.New("permission check failed")))
 				return
 			}
 
 			if user.LastLogin.Time.Add(time.Duration(settings.Settings.UserLoginTrackingTimeMins) * time.Minute).Before(time.Now()) {
 				_, err = app.Queries.UpdateLastLoginByID(context.Background(), user.ID)
 				if err != nil {
-					RespondWithJSON(w, ErrorResponse{
-						Detail: "Error updating last login time",
-						Error:  err.Error(),
-					})
+					RespondWithError(w, DBError("Error updating last login time", err))
 					return
 				}
-				cache.Go4users.Del(user.Token.String)
+				cache.Go4users.Del(user.ID.Bytes)
 
 			}
 
+			// The session last seen time is tracked with the same accuracy as the last login.
+			if session.LastSeen.Time.Add(time.Duration(settings.Settings.UserLoginTrackingTimeMins) * time.Minute).Before(time.Now()) {
+				err = app.Queries.SessionUpdateLastSeen(context.Background(), session.ID)
+				if err != nil {
+					RespondWithError(w, DBError("Error updating session last seen time", err))
+					return
+				}
+				cache.Go4sessions.Del(session.TokenHash)
+			}
+
 			var organization db.Organization
 
 			if !(user.IsSuperuser.Bool) {
 				organization, err = cache.GetOrganizationByUserID(user.ID.Bytes, app.Queries)
 				if err != nil {
-					RespondWithJSON(w, ErrorResponse{
-						Detail: "Error getting organization for user in middleware",
-						Error:  err.Error(),
-					})
+					if errors.Is(err, pgx.ErrNoRows) || errors.Is(err, sql.ErrNoRows) {
+						RespondWithError(w, Forbidden("no_organization", "You are not in an organization", err))
+						return
+					}
+					RespondWithError(w, DBError("Error getting organization for user in middleware", err))
 					return
 				}
 			}
 
+			// Superusers have no organization, so this only applies to members.
+			if !user.IsSuperuser.Bool && !allowExpired && OrganizationState(organization, time.Now()) == OrganizationExpired {
+				if !OrganizationReadOnly() {
+					RespondWithError(w, Forbidden("organization_expired", "Your organization expired.", errors.New("organization expired")))
+					return
+				}
+				if !readOnlyMethod(r.Method) {
+					RespondWithError(w, Forbidden("organization_read_only", "Your organization expired. You can only view data.", errors.New("organization expired")))
+					return
+				}
+			}
+
+			// Users that have to use 2FA but did not enroll yet, have to login again. The login walks them through the enrollment.
+			if !user.TwofactorEnabled && TwofactorMandatory(user, organization) {
+				RespondWithError(w, Forbidden("twofa_enrollment_required", "2FA is mandatory. Login again.", errors.New("2fa enrollment required")))
+				return
+			}
+
 			infos := InfoKey{
 				User:         user,
+				Session:      session,
 				Organization: organization,
 				Groups:       groups,
 				Permissions:  perms,
 			}
 
+			setAccessLogUser(r.Context(), user.ID, organization.ID)
+
 			ctx := context.WithValue(r.Context(), InfoContextKey, infos)
 
 			r = r.WithContext(ctx)
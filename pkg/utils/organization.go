@@ -0,0 +1,51 @@
+package utils
+
+import (
+	"time"
+
+	settings "github.com/karl1b/go4lage/pkg/settings"
+	"github.com/karl1b/go4lage/pkg/sql/db"
+)
+
+/*
+An organization is usable until its active_until plus ORGANIZATION_GRACE_DAYS.
+After that its members are rejected, or may only read if ORGANIZATION_EXPIRED_MODE is "readonly".
+*/
+
+// Organization states, from the point of view of its members.
+const (
+	OrganizationActive   = "active"
+	OrganizationExpiring = "expiring" // Expires within ORGANIZATION_REMINDER_DAYS.
+	OrganizationGrace    = "grace"    // Expired, but still usable within the grace period.
+	OrganizationExpired  = "expired"
+)
+
+// OrganizationGraceUntil returns when the grace period of the organization ends.
+func OrganizationGraceUntil(organization db.Organization) time.Time {
+	return organization.ActiveUntil.Time.AddDate(0, 0, settings.Settings.OrganizationGraceDays)
+}
+
+// OrganizationState returns the state of the organization at the given time.
+func OrganizationState(organization db.Organization, now time.Time) string {
+	activeUntil := organization.ActiveUntil.Time
+	switch {
+	case now.After(OrganizationGraceUntil(organization)):
+		return OrganizationExpired
+	case now.After(activeUntil):
+		return OrganizationGrace
+	case settings.Settings.OrganizationReminderDays > 0 && now.AddDate(0, 0, settings.Settings.OrganizationReminderDays).After(activeUntil):
+		return OrganizationExpiring
+	default:
+		return OrganizationActive
+	}
+}
+
+// OrganizationReadOnly tells if members of expired organizations may still read.
+func OrganizationReadOnly() bool {
+	return settings.Settings.OrganizationExpiredMode == "readonly"
+}
+
+// Requests with these methods do not change anything, so they are allowed for read-only members.
+func readOnlyMethod(method string) bool {
+	return method == "GET" || method == "HEAD" || method == "OPTIONS"
+}
@@ -0,0 +1,244 @@
+package utils
+
+import (
+	"bytes"
+	"crypto/sha1"
+	_ "embed"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"log"
+	"os"
+	"strings"
+	"sync"
+	"unicode"
+
+	settings "github.com/karl1b/go4lage/pkg/settings"
+)
+
+/*
+The password policy is configured in the settings and applies to every password a user chooses.
+Breached passwords are looked up by their SHA-1 in a file sorted by hash, like the downloads of Have I Been Pwned.
+Every line is a hash, optionally followed by ":count". The file is searched on disk, so it can be as large as those downloads.
+Without BREACHED_PASSWORDS_FILE, the bundled list of the most common passwords is used.
+*/
+
+//go:embed breachedpasswords.txt
+var bundledBreachedPasswords []byte
+
+// PasswordViolation is one rule of the password policy that a password breaks.
+type PasswordViolation struct {
+	Rule    string `json:"rule"`
+	Message string `json:"message"`
+}
+
+// CheckPassword returns the rules of the password policy the password breaks.
+// The identity is the email and username of the user, the password may not contain them.
+func CheckPassword(password string, identity ...string) []PasswordViolation {
+	violations := []PasswordViolation{}
+
+	if length := len([]rune(password)); length < settings.Settings.PasswordMinLength {
+		violations = append(violations, PasswordViolation{
+			Rule:    "min_length",
+			Message: fmt.Sprintf("Use at least %d characters", settings.Settings.PasswordMinLength),
+		})
+	}
+
+	if classes := passwordClasses(password); classes < settings.Settings.PasswordMinClasses {
+		violations = append(violations, PasswordViolation{
+			Rule:    "character_classes",
+			Message: fmt.Sprintf("Use at least %d of lowercase letters, uppercase letters, digits and symbols", settings.Settings.PasswordMinClasses),
+		})
+	}
+
+	if settings.Settings.PasswordRejectIdentity && containsIdentity(password, identity) {
+		violations = append(violations, PasswordViolation{
+			Rule:    "contains_identity",
+			Message: "Do not use your email or username in the password",
+		})
+	}
+
+	if settings.Settings.PasswordCheckBreached && password != "" {
+		breached, err := PasswordBreached(password)
+		if err != nil {
+			log.Println("Error checking breached passwords:", err)
+		}
+		if breached {
+			violations = append(violations, PasswordViolation{
+				Rule:    "breached",
+				Message: "This password is known from data breaches, choose another one",
+			})
+		}
+	}
+
+	return violations
+}
+
+// ValidatePassword returns an APIError that lists the violations, or nil if the password follows the policy.
+func ValidatePassword(password string, identity ...string) error {
+	violations := CheckPassword(password, identity...)
+	if len(violations) == 0 {
+		return nil
+	}
+	messages := make([]string, len(violations))
+	for i, v := range violations {
+		messages[i] = v.Message
+	}
+	return Unprocessable("weak_password", "Password not valid: "+strings.Join(messages, ". "), nil).WithDetails(violations)
+}
+
+// Counts lowercase letters, uppercase letters, digits and symbols.
+func passwordClasses(password string) int {
+	var lower, upper, digit, symbol int
+	for _, r := range password {
+		switch {
+		case unicode.IsLower(r):
+			lower = 1
+		case unicode.IsUpper(r):
+			upper = 1
+		case unicode.IsDigit(r):
+			digit = 1
+		default:
+			symbol = 1
+		}
+	}
+	return lower + upper + digit + symbol
+}
+
+// Checks the whole email, its local part and the username. Parts shorter than 3 characters are ignored.
+func containsIdentity(password string, identity []string) bool {
+	password = strings.ToLower(password)
+	for _, value := range identity {
+		value = strings.ToLower(strings.TrimSpace(value))
+		parts := []string{value}
+		if local, _, found := strings.Cut(value, "@"); found {
+			parts = append(parts, local)
+		}
+		for _, part := range parts {
+			if len(part) >= 3 && strings.Contains(password, part) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+var breachedPasswords struct {
+	once   sync.Once
+	reader io.ReaderAt
+	size   int64
+}
+
+// Opens BREACHED_PASSWORDS_FILE once. It stays open, as every check reads from it.
+func breachedPasswordList() (io.ReaderAt, int64) {
+	breachedPasswords.once.Do(func() {
+		breachedPasswords.reader = bytes.NewReader(bundledBreachedPasswords)
+		breachedPasswords.size = int64(len(bundledBreachedPasswords))
+
+		if settings.Settings.BreachedPasswordsFile == "" {
+			return
+		}
+		file, err := os.Open(settings.Settings.BreachedPasswordsFile)
+		if err != nil {
+			log.Println("Error opening BREACHED_PASSWORDS_FILE, the bundled list is used instead:", err)
+			return
+		}
+		info, err := file.Stat()
+		if err != nil {
+			file.Close()
+			log.Println("Error opening BREACHED_PASSWORDS_FILE, the bundled list is used instead:", err)
+			return
+		}
+		breachedPasswords.reader = file
+		breachedPasswords.size = info.Size()
+	})
+	return breachedPasswords.reader, breachedPasswords.size
+}
+
+// PasswordBreached tells if the password is in the list of breached passwords.
+func PasswordBreached(password string) (bool, error) {
+	reader, size := breachedPasswordList()
+	return passwordInList(reader, size, password)
+}
+
+// Searches the list of hashes, sorted by hash, for the SHA-1 of the password.
+func passwordInList(reader io.ReaderAt, size int64, password string) (bool, error) {
+	sum := sha1.Sum([]byte(password))
+	hash := strings.ToUpper(hex.EncodeToString(sum[:]))
+
+	// Binary search over the byte offsets. Only lines that start in [lo, hi) are left.
+	lo, hi := int64(0), size
+	for lo < hi {
+		mid := lo + (hi-lo)/2
+		line, start, err := lineAt(reader, size, mid)
+		if err != nil {
+			return false, err
+		}
+		if start >= hi {
+			hi = mid
+			continue
+		}
+		lineHash, _, _ := strings.Cut(strings.TrimSpace(line), ":")
+		switch strings.Compare(strings.ToUpper(lineHash), hash) {
+		case 0:
+			return true, nil
+		case -1:
+			lo = start + int64(len(line)) + 1
+		default:
+			hi = mid
+		}
+	}
+	return false, nil
+}
+
+// Reads the first line that starts at or after the offset. Returns the line without newline and its start.
+func lineAt(reader io.ReaderAt, size int64, offset int64) (string, int64, error) {
+	start := offset
+	if offset > 0 {
+		// The line starts after the next newline, counted from the byte before the offset.
+		i, err := indexNewline(reader, size, offset-1)
+		if err != nil {
+			return "", 0, err
+		}
+		if i < 0 {
+			return "", size, nil
+		}
+		start = i + 1
+	}
+	if start >= size {
+		return "", size, nil
+	}
+
+	end, err := indexNewline(reader, size, start)
+	if err != nil {
+		return "", 0, err
+	}
+	if end < 0 {
+		end = size
+	}
+	line := make([]byte, end-start)
+	_, err = reader.ReadAt(line, start)
+	if err != nil && err != io.EOF {
+		return "", 0, err
+	}
+	return string(line), start, nil
+}
+
+// Returns the offset of the next newline at or after the offset, or -1.
+func indexNewline(reader io.ReaderAt, size int64, offset int64) (int64, error) {
+	buf := make([]byte, 128)
+	for offset < size {
+		n, err := reader.ReadAt(buf, offset)
+		if i := bytes.IndexByte(buf[:n], '\n'); i >= 0 {
+			return offset + int64(i), nil
+		}
+		if err != nil && err != io.EOF {
+			return 0, err
+		}
+		if n == 0 {
+			break
+		}
+		offset += int64(n)
+	}
+	return -1, nil
+}
@@ -0,0 +1,107 @@
+package utils
+
+import (
+	"crypto/sha1"
+	"encoding/hex"
+	"slices"
+	"strings"
+	"testing"
+)
+
+// Builds a list like the downloads of Have I Been Pwned: uppercase hashes sorted, with a count.
+func hashList(passwords []string, newline string) string {
+	var lines []string
+	for _, password := range passwords {
+		sum := sha1.Sum([]byte(password))
+		lines = append(lines, strings.ToUpper(hex.EncodeToString(sum[:]))+":42")
+	}
+	slices.Sort(lines)
+	return strings.Join(lines, newline) + newline
+}
+
+func TestPasswordInList(t *testing.T) {
+	listed := []string{"password", "123456", "qwerty", "letmein", "dragon", "monkey", "sunshine", "iloveyou", "admin", "welcome"}
+
+	tests := []struct {
+		name     string
+		newline  string
+		password string
+		want     bool
+	}{
+		{"hit", "\n", "letmein", true},
+		{"hit on the first line", "\n", "password", true},
+		{"hit on the last line", "\n", "welcome", true},
+		{"miss", "\n", "correct horse battery staple", false},
+		{"miss of an empty password", "\n", "", false},
+		{"hit with CRLF", "\r\n", "dragon", true},
+		{"hit on the last line with CRLF", "\r\n", "welcome", true},
+		{"miss with CRLF", "\r\n", "Tr0ub4dor&3", false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			list := hashList(listed, tt.newline)
+			reader := strings.NewReader(list)
+			got, err := passwordInList(reader, int64(len(list)), tt.password)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if got != tt.want {
+				t.Errorf("passwordInList(%q) = %v, want %v", tt.password, got, tt.want)
+			}
+		})
+	}
+
+	// Every listed password is found, wherever the binary search lands.
+	for _, newline := range []string{"\n", "\r\n"} {
+		list := hashList(listed, newline)
+		for _, password := range listed {
+			got, err := passwordInList(strings.NewReader(list), int64(len(list)), password)
+			if err != nil || !got {
+				t.Errorf("passwordInList(%q) with %q lines = %v, %v, want true", password, newline, got, err)
+			}
+		}
+	}
+}
+
+func TestPasswordBreachedBundledList(t *testing.T) {
+	got, err := PasswordBreached("123456")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !got {
+		t.Error("123456 is not found in the bundled list")
+	}
+}
+
+func TestLineAt(t *testing.T) {
+	const list = "AAA:1\nBBB:2\r\nCCC:3"
+
+	tests := []struct {
+		name      string
+		offset    int64
+		wantLine  string
+		wantStart int64
+	}{
+		{"start of the list", 0, "AAA:1", 0},
+		{"inside the first line", 2, "BBB:2\r", 6},
+		{"on the newline", 5, "BBB:2\r", 6},
+		{"start of a line", 6, "BBB:2\r", 6},
+		{"inside a CRLF line", 9, "CCC:3", 13},
+		{"last line without newline", 13, "CCC:3", 13},
+		{"inside the last line", 15, "", 18},
+		{"end of the list", 18, "", 18},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			line, start, err := lineAt(strings.NewReader(list), int64(len(list)), tt.offset)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if line != tt.wantLine || start != tt.wantStart {
+				t.Errorf("lineAt(%d) = %q, %d, want %q, %d", tt.offset, line, start, tt.wantLine, tt.wantStart)
+			}
+		})
+	}
+}
@@ -1,11 +1,9 @@
 package utils
 
 import (
-	"bytes"
 	"context"
 	"database/sql"
 	"fmt"
-	"image/png"
 	"log"
 	"os"
 	"strconv"
@@ -48,8 +46,17 @@ func CreateSuperuser() {
 		fmt.Println("Enter a valid email")
 	}
 
-	fmt.Println("Enter Password:")
-	fmt.Scanln(&password)
+	for {
+		fmt.Println("Enter Password:")
+		fmt.Scanln(&password)
+		violations := CheckPassword(password, email)
+		if len(violations) == 0 {
+			break
+		}
+		for _, v := range violations {
+			fmt.Println(v.Message)
+		}
+	}
 	fmt.Println("Repeat Password:")
 	fmt.Scanln(&repeatPassword)
 	if password != repeatPassword {
@@ -61,11 +68,6 @@ func CreateSuperuser() {
 	defer cleanup()
 	queries := db.New(conn)
 
-	newToken, err := GenerateTokenHex(32)
-	if err != nil {
-		return
-	}
-
 	newpassword, err := HashPassword(password)
 	if err != nil {
 		panic(err)
@@ -74,23 +76,13 @@ func CreateSuperuser() {
 	tfsecret := pgtype.Text{String: "", Valid: true}
 
 	if settings.Settings.Superuser2FA {
-		key, err := totp.Generate(totp.GenerateOpts{
-			Issuer:      settings.Settings.Baseurl,
-			AccountName: email,
-		})
-		if err != nil {
-			panic(err)
-		}
-		// Convert TOTP key into a PNG
-		var buf bytes.Buffer
-		img, err := key.Image(200, 200)
+		key, qrCode, err := NewTOTPKey(email)
 		if err != nil {
 			panic(err)
 		}
-		png.Encode(&buf, img)
 
 		// display the QR code to the user.
-		displayTFASecret(key, buf.Bytes())
+		displayTFASecret(key, qrCode)
 
 		fmt.Println("Enter passcode")
 		fmt.Scanln(&passcode)
@@ -104,12 +96,20 @@ func CreateSuperuser() {
 
 	id := uuid.New()
 
-	user, err := queries.CreateUser(context.Background(), db.CreateUserParams{
+	// The superuser and its recovery codes are created together.
+	tx, err := conn.Begin(context.Background())
+	if err != nil {
+		fmt.Println(err)
+		return
+	}
+	defer tx.Rollback(context.Background())
+	qtx := queries.WithTx(tx)
+
+	user, err := qtx.CreateUser(context.Background(), db.CreateUserParams{
 		ID: pgtype.UUID{
 			Bytes: id,
 			Valid: true,
 		},
-		Token:     pgtype.Text{String: newToken, Valid: true},
 		Email:     email,
 		FirstName: pgtype.Text{String: "Super", Valid: true},
 		LastName:  pgtype.Text{String: "User", Valid: true},
@@ -118,16 +118,39 @@ func CreateSuperuser() {
 			Bool:  true,
 			Valid: true,
 		},
-		Twofactorsecret: tfsecret,
-		Username:        email,
+		Twofactorsecret:  tfsecret,
+		TwofactorEnabled: settings.Settings.Superuser2FA,
+		Username:         email,
 	})
 	if err != nil {
 		fmt.Println(err)
 		return
 	}
+
+	var recoveryCodes []string
+	if settings.Settings.Superuser2FA {
+		recoveryCodes, err = NewRecoveryCodes(qtx, user.ID)
+		if err != nil {
+			fmt.Println(err)
+			return
+		}
+	}
+
+	err = tx.Commit(context.Background())
+	if err != nil {
+		fmt.Println(err)
+		return
+	}
 	fmt.Println("Superuser created:")
 	fmt.Println(user)
 
+	if settings.Settings.Superuser2FA {
+		fmt.Println("")
+		fmt.Println("Recovery codes. Each one can be used once instead of a passcode. Store them safely, they are not shown again:")
+		for _, code := range recoveryCodes {
+			fmt.Println(code)
+		}
+	}
 }
 
 /*
@@ -200,6 +223,9 @@ func SetupGroupsAndPermissions() {
 	}
 }
 
+// Follows the default password policy, so the test users could choose it themselves.
+const testPassword = "Go4lage-Test-User"
+
 func CreateFakeUsers(a string) {
 	count, err := strconv.Atoi(a)
 	if err != nil {
@@ -209,7 +235,7 @@ func CreateFakeUsers(a string) {
 	// Display warning and get user confirmation
 	fmt.Printf("\n⚠️  WARNING ⚠️\n")
 	fmt.Printf("This script will create 3 organizations and  %d test user(s) per organization.\n", count)
-	fmt.Printf("All users will have the password: 'test'\n")
+	fmt.Printf("All users will have the password: '%s'\n", testPassword)
 	fmt.Printf("\nDo you wish to continue? (y/n): ")
 
 	var response string
@@ -231,8 +257,6 @@ func CreateFakeUsers(a string) {
 	queries := db.New(conn)
 
 	// 2. Define enhanced test data
-	const testPassword = "test"
-
 	companies := []struct {
 		name   string
 		domain string
@@ -369,6 +393,30 @@ func CreateFakeUsers(a string) {
 
 }
 
+// PurgeTrash deletes users and organizations for good that are in the trash longer than TRASH_RETENTION_DAYS.
+func PurgeTrash() {
+	conn, cleanup := SetUp()
+	defer cleanup()
+	queries := db.New(conn)
+
+	before := pgtype.Timestamptz{
+		Time:  time.Now().AddDate(0, 0, -settings.Settings.TrashRetentionDays),
+		Valid: true,
+	}
+
+	users, err := queries.UserPurgeDeleted(context.Background(), before)
+	if err != nil {
+		log.Fatalf("Error purging deleted users: %v", err)
+	}
+
+	organizations, err := queries.OrganizationPurgeDeleted(context.Background(), before)
+	if err != nil {
+		log.Fatalf("Error purging deleted organizations: %v", err)
+	}
+
+	log.Printf("Purged %d users and %d organizations deleted before %s", users, organizations, before.Time.Format(time.RFC3339))
+}
+
 // This runs goose out of go4lage.
 func RunGoose(cmd string) {
 	// Open the DB connection
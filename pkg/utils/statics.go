@@ -1,19 +1,230 @@
 package utils
 
 import (
+	"bytes"
+	"fmt"
 	"log"
+	"maps"
 	"net/http"
+	"os"
+	"path/filepath"
+	"slices"
 	"strings"
+	"sync/atomic"
+	"time"
 
 	settings "github.com/karl1b/go4lage/pkg/settings"
 )
 
-var rootFiles map[string][]byte
+// StaticFile is a built file of the cache.
+type StaticFile struct {
+	Data    []byte
+	Hash    string    // The ContentHash of Data, which is its ETag and cache buster.
+	ModTime time.Time // When Data last changed.
+	Gzip    []byte    // Data compressed with gzip, nil if it is not compressed.
+	Brotli  []byte    // Data compressed with brotli, nil if it is not compressed.
+}
+
+// NewStaticFiles hashes and compresses the built files.
+// Files that did not change keep the ModTime and the compressed variants of the previous files.
+func NewStaticFiles(files map[string][]byte, previous map[string]*StaticFile) map[string]*StaticFile {
+	now := time.Now()
+	staticFiles := make(map[string]*StaticFile, len(files))
+	for path, data := range files {
+		file := &StaticFile{Data: data, Hash: ContentHash(data), ModTime: now}
+		if old, ok := previous[path]; ok && old.Hash == file.Hash {
+			file.ModTime = old.ModTime
+			file.Gzip = old.Gzip
+			file.Brotli = old.Brotli
+		} else if compressible(path, data, settings.Settings.StaticCompressMinBytes) {
+			var err error
+			file.Gzip, err = smallerVariant(data, GzipData)
+			if err != nil {
+				log.Printf("Error compressing %s with gzip: %v", path, err)
+			}
+			file.Brotli, err = smallerVariant(data, BrotliData)
+			if err != nil {
+				log.Printf("Error compressing %s with brotli: %v", path, err)
+			}
+		}
+		staticFiles[path] = file
+	}
+	return staticFiles
+}
+
+// The folders that Root looks up a path in.
+var rootPathVariants = []string{"", "/admin"}
+
+// The built files of root. WatchRoot swaps in a new map, so every request serves from one consistent map.
+var rootFiles atomic.Pointer[map[string]*StaticFile]
+
+// The rendered files of root before busting. Only LoadRoot and WatchRoot use them.
+var rootRendered map[string][]byte
+
+// LoadRoot builds the files of root. The server calls it before it serves them.
+func LoadRoot() {
+	sources, err := ReadFiles("root")
+	if err != nil {
+		log.Fatalf("Failed to load static files: %v", err)
+	}
+
+	rootRendered = make(map[string][]byte, len(sources))
+	err = BuildFiles(sources, slices.Collect(maps.Keys(sources)), settings.Settings.Baseurl, settings.Settings.Apiurl, &rootRendered)
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	files := NewStaticFiles(BustFiles(rootRendered, settings.Settings.Baseurl, rootPathVariants), nil)
+	rootFiles.Store(&files)
+	log.Println("Root:", CompressionSummary(files))
+}
+
+// CheckRoot renders root without serving it, and prints every problem.
+// It returns false if there are problems.
+func CheckRoot() bool {
+	sources, err := ReadFiles("root")
+	if err != nil {
+		fmt.Println("Error reading root:", err)
+		return false
+	}
+
+	graph := NewTemplateGraph(sources)
+	problems := graph.Problems()
+	if len(problems) == 0 {
+		// Variables that are not set are only found by rendering, which needs a graph without cycles.
+		builtins := TemplateBuiltins(settings.Settings.Baseurl, settings.Settings.Apiurl, settings.Settings.AppName)
+		for path := range sources {
+			_, renderProblems := graph.Render(path, builtins)
+			problems = append(problems, renderProblems...)
+		}
+		sortProblems(problems)
+	}
+	for _, problem := range problems {
+		fmt.Println(problem)
+	}
+	if len(problems) > 0 {
+		fmt.Printf("%d problems in root.\n", len(problems))
+		return false
+	}
+
+	fmt.Printf("root is fine: %d files, %d includes.\n", len(sources), graph.Includes())
+	return true
+}
+
+// WatchRoot checks root for changed files in the interval, for development.
+// Changed files and the files that include them are built again, without restarting the server.
+func WatchRoot(interval time.Duration) {
+	sources, err := ReadFiles("root")
+	if err != nil {
+		log.Println("Error watching root:", err)
+		return
+	}
+	stats, err := rootStats()
+	if err != nil {
+		log.Println("Error watching root:", err)
+		return
+	}
+	log.Println("Watching root for changes.")
+
+	go func() {
+		// Changes of a failed build are tried again with the next change.
+		var pending []string
 
-func init() {
-	rootFiles = make(map[string][]byte)
-	FileCacheInit("root", settings.Settings.Baseurl, settings.Settings.Apiurl, &rootFiles)
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+		for range ticker.C {
+			current, err := rootStats()
+			if err != nil {
+				log.Println("Error watching root:", err)
+				continue
+			}
+
+			var changed, removed []string
+			for path, stat := range current {
+				if old, ok := stats[path]; !ok || old != stat {
+					changed = append(changed, path)
+				}
+			}
+			for path := range stats {
+				if _, ok := current[path]; !ok {
+					removed = append(removed, path)
+				}
+			}
+			if len(changed) == 0 && len(removed) == 0 {
+				continue
+			}
+			stats = current
+
+			for _, path := range changed {
+				data, err := os.ReadFile(filepath.Join("root", path))
+				if err != nil {
+					log.Println("Error reading changed file:", err)
+					delete(stats, path) // Read again with the next check.
+					continue
+				}
+				sources[path] = data
+			}
+			for _, path := range removed {
+				delete(sources, path)
+			}
 
+			pending = append(pending, changed...)
+			pending = append(pending, removed...)
+			err = rebuildRoot(sources, pending)
+			if err != nil {
+				log.Println("Error rebuilding root, the previous files are still served:", err)
+				continue
+			}
+			pending = nil
+		}
+	}()
+}
+
+// The modification time and size of a file, so changes are noticed without reading it.
+type fileStat struct {
+	modTime time.Time
+	size    int64
+}
+
+func rootStats() (map[string]fileStat, error) {
+	stats := make(map[string]fileStat)
+	err := filepath.Walk("root", func(path string, info os.FileInfo, err error) error {
+		if err != nil {
+			return err
+		}
+		if !info.IsDir() {
+			stats[rootPath(path)] = fileStat{modTime: info.ModTime(), size: info.Size()}
+		}
+		return nil
+	})
+	return stats, err
+}
+
+// Builds the changed files and the files that include them into a copy of the current files and swaps it in.
+// All files are busted again, as the busters of the files that reference them change as well.
+// Changed files that are not in the sources anymore were removed.
+func rebuildRoot(sources map[string][]byte, changed []string) error {
+	rendered := maps.Clone(rootRendered)
+
+	var affected []string
+	for _, path := range NewTemplateGraph(sources).IncludingFiles(changed) {
+		if _, ok := sources[path]; ok {
+			affected = append(affected, path)
+		} else {
+			delete(rendered, path)
+		}
+	}
+
+	err := BuildFiles(sources, affected, settings.Settings.Baseurl, settings.Settings.Apiurl, &rendered)
+	if err != nil {
+		return err
+	}
+
+	files := NewStaticFiles(BustFiles(rendered, settings.Settings.Baseurl, rootPathVariants), *rootFiles.Load())
+	rootRendered = rendered
+	rootFiles.Store(&files)
+	log.Printf("Rebuilt %d files of root: %v", len(affected), affected)
+	return nil
 }
 
 func Root(w http.ResponseWriter, r *http.Request) {
@@ -23,11 +234,12 @@ func Root(w http.ResponseWriter, r *http.Request) {
 	if r.URL.Path == "/admin" {
 		r.URL.Path = "/admin/index.html"
 	}
-	serveFiles(w, r, &rootFiles, []string{"", "/admin"})
+	serveFiles(w, r, *rootFiles.Load(), rootPathVariants)
 }
 
-// Get the correct static file from the cache and serve it
-func serveFiles(w http.ResponseWriter, r *http.Request, files *map[string][]byte, pathVariants []string) {
+// Get the correct static file from the cache and serve it.
+// A URL with the current cache buster can be cached forever. Everything else is revalidated with its ETag after STATIC_MAX_AGE_S.
+func serveFiles(w http.ResponseWriter, r *http.Request, files map[string]*StaticFile, pathVariants []string) {
 
 	searcherFunc := func(pathVariant string) bool {
 
@@ -38,7 +250,7 @@ func serveFiles(w http.ResponseWriter, r *http.Request, files *map[string][]byte
 		if err != nil {
 			log.Println("Error cache reader", err)
 		}
-		if data, ok := (*files)[path]; ok {
+		if file, ok := files[path]; ok {
 			switch {
 			case strings.HasSuffix(path, ".html"):
 				w.Header().Set("Content-Type", "text/html")
@@ -63,7 +275,33 @@ func serveFiles(w http.ResponseWriter, r *http.Request, files *map[string][]byte
 			default:
 				w.Header().Set("Content-Type", "text/html") // Setting default as text html.
 			}
-			w.Write(data)
+
+			switch {
+			case CacheBuster(r.URL.Path) == file.Hash:
+				w.Header().Set("Cache-Control", "public, max-age=31536000, immutable")
+			case settings.Settings.StaticMaxAgeS > 0:
+				w.Header().Set("Cache-Control", fmt.Sprintf("public, max-age=%d, must-revalidate", settings.Settings.StaticMaxAgeS))
+			default:
+				w.Header().Set("Cache-Control", "no-cache")
+			}
+			// Every encoding has its own ETag, as the bytes differ.
+			data, etag := file.Data, file.Hash
+			if file.Gzip != nil || file.Brotli != nil {
+				w.Header().Add("Vary", "Accept-Encoding")
+				acceptEncoding := r.Header.Get("Accept-Encoding")
+				switch {
+				case file.Brotli != nil && acceptsEncoding(acceptEncoding, "br"):
+					w.Header().Set("Content-Encoding", "br")
+					data, etag = file.Brotli, file.Hash+"-br"
+				case file.Gzip != nil && acceptsEncoding(acceptEncoding, "gzip"):
+					w.Header().Set("Content-Encoding", "gzip")
+					data, etag = file.Gzip, file.Hash+"-gzip"
+				}
+			}
+			w.Header().Set("ETag", `"`+etag+`"`)
+
+			// Answers If-None-Match and If-Modified-Since with 304 Not Modified.
+			http.ServeContent(w, r, path, file.ModTime, bytes.NewReader(data))
 			return true
 		}
 		return false
@@ -0,0 +1,358 @@
+package utils
+
+import (
+	"bytes"
+	"fmt"
+	"maps"
+	"regexp"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+	"unicode/utf8"
+)
+
+/*
+Files in root are templates, which are rendered once at startup:
+
+	{%comps/header.html%}                              includes a component
+	{%comps/header.html title="Guide" active="guide"%} includes it with arguments
+	{%title%}                                          is replaced by a variable
+
+Names that contain a "/" or "." are components, all others are variables.
+A .html file can start with front matter, which sets its variables. For components, these are the defaults:
+
+	---
+	title: Go4lage
+	---
+
+A component sees the variables of the file that includes it, which override its front matter,
+and the arguments of the include, which override both. The built-ins are Baseurl, Apiurl, Appname, Version and Year.
+The includes of all files form a graph, which is checked for missing components and cycles before anything is rendered.
+*/
+
+// Version is the build version, set it with -ldflags "-X github.com/karl1b/go4lage/pkg/utils.Version=1.2.3".
+var Version = "dev"
+
+var placeholderRegex = regexp.MustCompile(`\{%([^%}])*%\}`)
+var variableRegex = regexp.MustCompile(`^[A-Za-z_][A-Za-z0-9_]*$`)
+var argumentRegex = regexp.MustCompile(`([A-Za-z_][A-Za-z0-9_]*)=("[^"]*"|'[^']*'|[^\s"']+)`)
+
+// TemplateBuiltins returns the variables every file sees.
+func TemplateBuiltins(baseUrl string, apiUrl string, appName string) map[string]string {
+	return map[string]string{
+		"Baseurl": strings.TrimSpace(baseUrl),
+		"Apiurl":  strings.TrimSpace(apiUrl),
+		"Appname": strings.TrimSpace(appName),
+		"Version": Version,
+		"Year":    strconv.Itoa(time.Now().Year()),
+	}
+}
+
+// TemplateInclude is one {%...%} include in a file.
+type TemplateInclude struct {
+	File      string // The including file.
+	Line      int
+	Component string // The path of the included file, like "comps/header.html".
+	Args      map[string]string
+}
+
+// TemplateProblem is a missing component, a cycle of includes or a variable that is not set.
+type TemplateProblem struct {
+	File    string
+	Line    int
+	Message string
+}
+
+func (p TemplateProblem) String() string {
+	return fmt.Sprintf("%s:%d: %s", p.File, p.Line, p.Message)
+}
+
+// TemplateError lists the problems that keep the files from being built.
+type TemplateError []TemplateProblem
+
+func (e TemplateError) Error() string {
+	lines := make([]string, len(e))
+	for i, problem := range e {
+		lines[i] = problem.String()
+	}
+	return "invalid templates:\n" + strings.Join(lines, "\n")
+}
+
+// A {%...%} in a template.
+type placeholder struct {
+	start    int // Offsets in the source.
+	end      int
+	line     int
+	name     string
+	args     map[string]string
+	variable bool
+}
+
+// A parsed file. The front matter ends at body.
+type template struct {
+	vars         map[string]string
+	body         int
+	placeholders []placeholder
+	problems     []TemplateProblem
+}
+
+// TemplateGraph holds the parsed templates and their includes.
+type TemplateGraph struct {
+	sources    map[string][]byte
+	templates  map[string]*template
+	includes   map[string][]TemplateInclude
+	includedBy map[string][]string
+}
+
+// NewTemplateGraph parses all sources. Files that are not valid UTF-8, like images, are no templates.
+func NewTemplateGraph(sources map[string][]byte) *TemplateGraph {
+	graph := &TemplateGraph{
+		sources:    sources,
+		templates:  make(map[string]*template),
+		includes:   make(map[string][]TemplateInclude),
+		includedBy: make(map[string][]string),
+	}
+
+	for path, content := range sources {
+		if !utf8.Valid(content) {
+			continue
+		}
+		t := parseTemplate(path, content)
+		graph.templates[path] = t
+		for _, p := range t.placeholders {
+			if p.variable {
+				continue
+			}
+			graph.includes[path] = append(graph.includes[path], TemplateInclude{File: path, Line: p.line, Component: p.name, Args: p.args})
+			graph.includedBy[p.name] = append(graph.includedBy[p.name], path)
+		}
+	}
+	return graph
+}
+
+func parseTemplate(path string, content []byte) *template {
+	t := &template{vars: map[string]string{}}
+
+	if strings.HasSuffix(path, ".html") {
+		t.body = parseFrontMatter(path, content, t)
+	}
+
+	for _, loc := range placeholderRegex.FindAllIndex(content[t.body:], -1) {
+		start, end := t.body+loc[0], t.body+loc[1]
+		line := bytes.Count(content[:start], []byte("\n")) + 1
+
+		inner := strings.TrimSpace(string(content[start+2 : end-2]))
+		name, rest, _ := strings.Cut(inner, " ")
+		p := placeholder{start: start, end: end, line: line, name: name, args: map[string]string{}}
+
+		rest = strings.TrimSpace(rest)
+		for _, arg := range argumentRegex.FindAllStringSubmatch(rest, -1) {
+			value := arg[2]
+			if len(value) >= 2 && (value[0] == '"' || value[0] == '\'') {
+				value = value[1 : len(value)-1]
+			}
+			p.args[arg[1]] = value
+		}
+		if strings.TrimSpace(argumentRegex.ReplaceAllString(rest, "")) != "" {
+			t.problems = append(t.problems, TemplateProblem{File: path, Line: line, Message: fmt.Sprintf("invalid arguments in %s, use name=\"value\"", inner)})
+		}
+
+		if variableRegex.MatchString(name) {
+			p.variable = true
+			if len(p.args) > 0 {
+				t.problems = append(t.problems, TemplateProblem{File: path, Line: line, Message: fmt.Sprintf("variable %s takes no arguments", name)})
+			}
+		} else {
+			p.name, _ = CacheReader(name)
+		}
+		t.placeholders = append(t.placeholders, p)
+	}
+	return t
+}
+
+// Parses the front matter between two --- lines at the start of the file and returns where the body starts.
+func parseFrontMatter(path string, content []byte, t *template) int {
+	rest, found := bytes.CutPrefix(content, []byte("---\n"))
+	if !found {
+		return 0
+	}
+	end := bytes.Index(rest, []byte("\n---\n"))
+	if end == -1 {
+		t.problems = append(t.problems, TemplateProblem{File: path, Line: 1, Message: "front matter is not closed with ---"})
+		return 0
+	}
+
+	for i, line := range strings.Split(string(rest[:end]), "\n") {
+		if strings.TrimSpace(line) == "" || strings.HasPrefix(strings.TrimSpace(line), "#") {
+			continue
+		}
+		key, value, found := strings.Cut(line, ":")
+		key = strings.TrimSpace(key)
+		if !found || !variableRegex.MatchString(key) {
+			t.problems = append(t.problems, TemplateProblem{File: path, Line: i + 2, Message: "invalid front matter, use name: value"})
+			continue
+		}
+		value = strings.TrimSpace(value)
+		if len(value) >= 2 && (value[0] == '"' || value[0] == '\'') && value[len(value)-1] == value[0] {
+			value = value[1 : len(value)-1]
+		}
+		t.vars[key] = value
+	}
+	return len("---\n") + end + len("\n---\n")
+}
+
+// Problems returns every invalid placeholder, missing component and cycle, sorted by file and line.
+// Variables that are not set are only found by rendering.
+func (g *TemplateGraph) Problems() []TemplateProblem {
+	var problems []TemplateProblem
+
+	for _, t := range g.templates {
+		problems = append(problems, t.problems...)
+	}
+
+	for _, includes := range g.includes {
+		for _, include := range includes {
+			if _, ok := g.templates[include.Component]; !ok {
+				message := fmt.Sprintf("component %s not found", include.Component)
+				if _, ok := g.sources[include.Component]; ok {
+					message = fmt.Sprintf("component %s is no text file", include.Component)
+				}
+				problems = append(problems, TemplateProblem{File: include.File, Line: include.Line, Message: message})
+			}
+		}
+	}
+
+	// A depth first search from every file. A file that is on the current path again closes a cycle.
+	// Every cycle is reported once, from the include that closes it.
+	const (
+		unvisited = iota
+		visiting
+		done
+	)
+	state := make(map[string]int)
+	var stack []TemplateInclude
+
+	var visit func(path string)
+	visit = func(path string) {
+		state[path] = visiting
+		for _, include := range g.includes[path] {
+			switch state[include.Component] {
+			case visiting:
+				cycle := []string{}
+				start := slices.IndexFunc(stack, func(i TemplateInclude) bool { return i.File == include.Component })
+				if start == -1 {
+					start = len(stack)
+				}
+				for _, i := range stack[start:] {
+					cycle = append(cycle, i.File)
+				}
+				cycle = append(cycle, include.File, include.Component)
+				problems = append(problems, TemplateProblem{
+					File:    include.File,
+					Line:    include.Line,
+					Message: "cyclic include: " + strings.Join(cycle, " -> "),
+				})
+			case unvisited:
+				if _, ok := g.templates[include.Component]; ok {
+					stack = append(stack, include)
+					visit(include.Component)
+					stack = stack[:len(stack)-1]
+				}
+			}
+		}
+		state[path] = done
+	}
+
+	for _, path := range slices.Sorted(maps.Keys(g.templates)) {
+		if state[path] == unvisited {
+			visit(path)
+		}
+	}
+
+	sortProblems(problems)
+	return problems
+}
+
+func sortProblems(problems []TemplateProblem) {
+	slices.SortFunc(problems, func(a, b TemplateProblem) int {
+		if c := strings.Compare(a.File, b.File); c != 0 {
+			return c
+		}
+		return a.Line - b.Line
+	})
+}
+
+// Render renders the file with its front matter variables. Files that are no templates are returned as they are.
+// Components are served on their own as well. There the variables of the including file are missing, so they stay empty.
+// The graph has to be free of problems, otherwise cycles never end.
+func (g *TemplateGraph) Render(path string, builtins map[string]string) ([]byte, []TemplateProblem) {
+	t, ok := g.templates[path]
+	if !ok {
+		return g.sources[path], nil
+	}
+	component := len(g.includedBy[path]) > 0
+	return g.render(path, t, maps.Clone(t.vars), builtins, !component, "")
+}
+
+func (g *TemplateGraph) render(path string, t *template, vars map[string]string, builtins map[string]string, strict bool, includedFrom string) ([]byte, []TemplateProblem) {
+	source := g.sources[path]
+	var problems []TemplateProblem
+	var out bytes.Buffer
+	out.Grow(len(source))
+
+	last := t.body
+	for _, p := range t.placeholders {
+		out.Write(source[last:p.start])
+		last = p.end
+
+		if p.variable {
+			value, ok := vars[p.name]
+			if !ok {
+				value, ok = builtins[p.name]
+			}
+			if !ok && strict {
+				problems = append(problems, TemplateProblem{File: path, Line: p.line, Message: fmt.Sprintf("variable %s is not set%s", p.name, includedFrom)})
+			}
+			out.WriteString(value)
+			continue
+		}
+
+		component := g.templates[p.name]
+		componentVars := maps.Clone(component.vars)
+		maps.Copy(componentVars, vars)
+		maps.Copy(componentVars, p.args)
+		content, componentProblems := g.render(p.name, component, componentVars, builtins, strict, fmt.Sprintf(" (included from %s:%d)", path, p.line))
+		problems = append(problems, componentProblems...)
+		out.Write(content)
+	}
+	out.Write(source[last:])
+	return out.Bytes(), problems
+}
+
+// IncludingFiles returns the given paths and all files that include one of them, directly or through other components.
+func (g *TemplateGraph) IncludingFiles(paths []string) []string {
+	seen := make(map[string]bool)
+	queue := slices.Clone(paths)
+	var result []string
+	for len(queue) > 0 {
+		path := queue[0]
+		queue = queue[1:]
+		if seen[path] {
+			continue
+		}
+		seen[path] = true
+		result = append(result, path)
+		queue = append(queue, g.includedBy[path]...)
+	}
+	return result
+}
+
+// Includes returns the number of includes of all files.
+func (g *TemplateGraph) Includes() int {
+	n := 0
+	for _, includes := range g.includes {
+		n += len(includes)
+	}
+	return n
+}
@@ -0,0 +1,123 @@
+package utils
+
+import (
+	"slices"
+	"testing"
+)
+
+func problemStrings(problems []TemplateProblem) []string {
+	var out []string
+	for _, problem := range problems {
+		out = append(out, problem.String())
+	}
+	return out
+}
+
+func TestTemplateProblems(t *testing.T) {
+	tests := []struct {
+		name    string
+		sources map[string]string
+		want    []string
+	}{
+		{
+			name: "cycle",
+			sources: map[string]string{
+				"a.html": "{%b.html%}",
+				"b.html": "<p>b</p>\n{%a.html%}",
+			},
+			want: []string{"b.html:2: cyclic include: a.html -> b.html -> a.html"},
+		},
+		{
+			name: "file that includes itself",
+			sources: map[string]string{
+				"a.html": "{%a.html%}",
+			},
+			want: []string{"a.html:1: cyclic include: a.html -> a.html"},
+		},
+		{
+			name: "missing component",
+			sources: map[string]string{
+				"index.html":        "<body>\n{%comps/header.html%}\n{%comps/nav.html%}\n</body>",
+				"comps/header.html": "<header></header>",
+			},
+			want: []string{"index.html:3: component comps/nav.html not found"},
+		},
+		{
+			name: "component that is no text file",
+			sources: map[string]string{
+				"index.html":    "{%pics/logo.png%}",
+				"pics/logo.png": "\xff\xfe",
+			},
+			want: []string{"index.html:1: component pics/logo.png is no text file"},
+		},
+		{
+			name: "invalid arguments",
+			sources: map[string]string{
+				"index.html":        "{%comps/header.html title%}",
+				"comps/header.html": "<h1>{%title%}</h1>",
+			},
+			want: []string{"index.html:1: invalid arguments in comps/header.html title, use name=\"value\""},
+		},
+		{
+			name: "no problems",
+			sources: map[string]string{
+				"index.html":        "{%comps/header.html title=\"Home\"%}",
+				"comps/header.html": "<h1>{%title%}</h1>",
+			},
+			want: nil,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			sources := make(map[string][]byte)
+			for path, content := range tt.sources {
+				sources[path] = []byte(content)
+			}
+			got := problemStrings(NewTemplateGraph(sources).Problems())
+			if !slices.Equal(got, tt.want) {
+				t.Errorf("Problems() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestTemplateRender(t *testing.T) {
+	sources := map[string][]byte{
+		"index.html":        []byte("---\ntitle: Home\n---\n{%comps/header.html%}"),
+		"guide.html":        []byte("{%comps/header.html title=\"Guide\"%}"),
+		"plain.html":        []byte("{%comps/header.html%}"),
+		"missing.html":      []byte("<p>\n{%comps/footer.html%}</p>"),
+		"comps/header.html": []byte("---\ntitle: Default\n---\n<h1>{%title%} - {%Appname%}</h1>"),
+		"comps/footer.html": []byte("<footer>\n{%owner%}</footer>"),
+	}
+	builtins := map[string]string{"Appname": "go4lage"}
+	graph := NewTemplateGraph(sources)
+
+	tests := []struct {
+		path         string
+		want         string
+		wantProblems []string
+	}{
+		{path: "index.html", want: "<h1>Home - go4lage</h1>"},
+		{path: "guide.html", want: "<h1>Guide - go4lage</h1>"},
+		{path: "plain.html", want: "<h1>Default - go4lage</h1>"},
+		{
+			path:         "missing.html",
+			want:         "<p>\n<footer>\n</footer></p>",
+			wantProblems: []string{"comps/footer.html:2: variable owner is not set (included from missing.html:2)"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.path, func(t *testing.T) {
+			got, problems := graph.Render(tt.path, builtins)
+			if string(got) != tt.want {
+				t.Errorf("Render(%s) = %q, want %q", tt.path, got, tt.want)
+			}
+			if gotProblems := problemStrings(problems); !slices.Equal(gotProblems, tt.wantProblems) {
+				t.Errorf("Render(%s) problems = %q, want %q", tt.path, gotProblems, tt.wantProblems)
+			}
+		})
+	}
+}
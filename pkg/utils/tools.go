@@ -2,10 +2,16 @@ package utils
 
 import (
 	"context"
+	"crypto/hmac"
 	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
 	"encoding/hex"
 	"encoding/json"
+	"errors"
 	"log"
+	"maps"
+	"net"
 	"net/http"
 	"os"
 	"path/filepath"
@@ -15,7 +21,6 @@ import (
 	pgxpool "github.com/jackc/pgx/v5/pgxpool"
 
 	settings "github.com/karl1b/go4lage/pkg/settings"
-	"golang.org/x/crypto/bcrypt"
 )
 
 func IsValidEmail(email string) bool {
@@ -33,27 +38,62 @@ func GenerateTokenHex(nBytes int) (string, error) {
 	return token, nil
 }
 
-func HashPassword(password string) (string, error) {
+// HashToken returns the hex encoded SHA-256 digest of a token.
+// Use it for tokens that are stored in the db, so a db dump does not contain usable tokens.
+func HashToken(token string) string {
+	sum := sha256.Sum256([]byte(token))
+	return hex.EncodeToString(sum[:])
+}
 
-	hashedPassword, err := bcrypt.GenerateFromPassword([]byte(password), bcrypt.DefaultCost)
-	if err != nil {
-		return "", err
+var ErrNoSecretKey = errors.New("SECRET_KEY is not set")
+var ErrInvalidSignature = errors.New("invalid signature")
+
+// SignToken appends an HMAC of the payload with SECRET_KEY, so the payload can not be changed or made up.
+// The payload is not encrypted, it can be read by everyone who has the token.
+func SignToken(payload []byte) (string, error) {
+	if settings.Settings.SecretKey == "" {
+		return "", ErrNoSecretKey
 	}
-	return string(hashedPassword), nil
+	mac := hmac.New(sha256.New, []byte(settings.Settings.SecretKey))
+	mac.Write(payload)
+	return base64.RawURLEncoding.EncodeToString(payload) + "." + base64.RawURLEncoding.EncodeToString(mac.Sum(nil)), nil
 }
 
-func CompareHashAndPassword(hash, password string) error {
-
-	err := bcrypt.CompareHashAndPassword([]byte(hash), []byte(password))
+// VerifyToken checks the signature of a token made by SignToken and returns its payload.
+func VerifyToken(token string) ([]byte, error) {
+	if settings.Settings.SecretKey == "" {
+		return nil, ErrNoSecretKey
+	}
+	encodedPayload, encodedSignature, found := strings.Cut(token, ".")
+	if !found {
+		return nil, ErrInvalidSignature
+	}
+	payload, err := base64.RawURLEncoding.DecodeString(encodedPayload)
 	if err != nil {
-		return err
+		return nil, ErrInvalidSignature
 	}
-	return nil
+	signature, err := base64.RawURLEncoding.DecodeString(encodedSignature)
+	if err != nil {
+		return nil, ErrInvalidSignature
+	}
+	mac := hmac.New(sha256.New, []byte(settings.Settings.SecretKey))
+	mac.Write(payload)
+	if !hmac.Equal(signature, mac.Sum(nil)) {
+		return nil, ErrInvalidSignature
+	}
+	return payload, nil
 }
 
+// UnusablePassword is stored for users that can not login with a password yet, like invited users.
+// It is no hash, so no password matches it.
+const UnusablePassword = "!"
+
+// ErrorResponse is the body of an error. Respond with an APIError instead of building it.
 type ErrorResponse struct {
-	Detail string `json:"detail"`
-	Error  string `json:"error"`
+	Code    string `json:"code,omitempty"`
+	Detail  string `json:"detail"`
+	Details any    `json:"details,omitempty"`
+	Error   string `json:"error"`
 }
 
 type ToastResponse struct {
@@ -78,7 +118,9 @@ func SetUp() (*pgxpool.Pool, func()) {
 	}
 }
 
-func FileCacheInit(baseDir string, baseUrl string, apiUrl string, cache *map[string][]byte) error {
+// ReadFiles reads all files below baseDir. The keys are the paths without baseDir, like "comps/header.html".
+func ReadFiles(baseDir string) (map[string][]byte, error) {
+	sources := make(map[string][]byte)
 
 	err := filepath.Walk(baseDir, func(path string, info os.FileInfo, err error) error {
 		if err != nil {
@@ -89,199 +131,193 @@ func FileCacheInit(baseDir string, baseUrl string, apiUrl string, cache *map[str
 			if err != nil {
 				return err
 			}
-			npath := strings.Join(strings.Split(path, "/")[1:], "/")
-
-			(*cache)[npath] = data
+			sources[rootPath(path)] = data
 		}
 		return nil
 	})
+	return sources, err
+}
 
-	if err != nil {
-		log.Fatalf("Failed to load static files: %v", err)
-	}
+// Strips the base dir of a path below it.
+func rootPath(path string) string {
+	return strings.Join(strings.Split(filepath.ToSlash(path), "/")[1:], "/")
+}
 
-	// Replaces the Baseurl
-	for path, file := range *cache {
-		if strings.HasSuffix(path, ".html") || strings.HasSuffix(path, ".js") {
-			baseUrl = strings.TrimSpace(baseUrl)
-			replacedContent := strings.ReplaceAll(string(file), "{%Baseurl%}", baseUrl)
-			extensions := []string{".png", ".jpg", ".js", ".css"}
-			replacedContent = ApplyCacheBuster(replacedContent, extensions) // With this cache buster in place you can cache agressively.
-			(*cache)[path] = []byte(replacedContent)
-		}
+// BuildFiles renders the given paths from their sources into the cache. Files that are not given stay as they are.
+// If a template has problems, it returns a TemplateError and builds nothing. Bust the rendered files before serving them.
+func BuildFiles(sources map[string][]byte, paths []string, baseUrl string, apiUrl string, cache *map[string][]byte) error {
+
+	graph := NewTemplateGraph(sources)
+	if problems := graph.Problems(); len(problems) > 0 {
+		return TemplateError(problems)
 	}
 
-	// Replaces the Apiurl
-	for path, file := range *cache {
-		if strings.HasSuffix(path, ".html") || strings.HasSuffix(path, ".js") {
-			apiUrl = strings.TrimSpace(apiUrl)
+	builtins := TemplateBuiltins(baseUrl, apiUrl, settings.Settings.AppName)
+	built := make(map[string][]byte, len(paths))
+	var problems []TemplateProblem
 
-			replacedContent := strings.ReplaceAll(string(file), "{%Apiurl%}", apiUrl)
-			extensions := []string{".png", ".jpg", ".js", ".css"}
-			replacedContent = ApplyCacheBuster(replacedContent, extensions) // With this cache buster in place you can cache agressively.
-			(*cache)[path] = []byte(replacedContent)
-		}
+	for _, path := range paths {
+		content, renderProblems := graph.Render(path, builtins)
+		problems = append(problems, renderProblems...)
+		built[path] = content
 	}
-	re := regexp.MustCompile(`\{%([^%}])*%\}`)
 
-	// Defines the non dynamic placeholder {%%}
+	if len(problems) > 0 {
+		sortProblems(problems)
+		return TemplateError(problems)
+	}
+	maps.Copy(*cache, built)
+	return nil
+}
 
-	regexFound := false
-	for range 10 {
+// References to files with these extensions get a cache buster.
+var cacheBusterRegex = regexp.MustCompile(`["']([^"'\s]+\.(?:png|jpg|jpeg|gif|svg|webp|ico|js|css))["']`)
 
-		// Perform the placeholder replacement for each file
-		for path, content := range *cache {
-			updatedContent, replaced := replacePlaceholders(string(content), re, cache)
-			if replaced {
-				(*cache)[path] = []byte(updatedContent)
-			}
-			if re.Match((*cache)[path]) {
-				regexFound = true
-			}
+// ContentHash returns the hash of a file, which is its cache buster and ETag.
+func ContentHash(data []byte) string {
+	sum := sha256.Sum256(data)
+	return hex.EncodeToString(sum[:8])
+}
+
+// BustFiles returns the files with a cache buster in every reference of .html and .js files to another of the files,
+// like "css/style.css" -> "css/style--3f2a9c01b7d4e5f6.css". The buster is the hash of the referenced file,
+// so the URL only changes if the file does. References to other hosts and unknown files stay as they are.
+// The path variants are the folders absolute references are looked up in, like serveFiles does.
+func BustFiles(files map[string][]byte, baseUrl string, pathVariants []string) map[string][]byte {
+	busted := make(map[string][]byte, len(files))
+
+	// References are busted before the file that references them is hashed, so a changed script
+	// changes the buster of every page that loads it, even through other scripts.
+	const (
+		visiting = 1
+		done     = 2
+	)
+	state := make(map[string]int)
+
+	var bust func(path string) []byte
+	bust = func(path string) []byte {
+		switch state[path] {
+		case done:
+			return busted[path]
+		case visiting:
+			return files[path] // The files reference each other, so this one is hashed without its busters.
 		}
+		state[path] = visiting
 
-		if !regexFound {
-			return nil
+		content := files[path]
+		if strings.HasSuffix(path, ".html") || strings.HasSuffix(path, ".js") {
+			content = cacheBusterRegex.ReplaceAllFunc(content, func(match []byte) []byte {
+				reference := string(match[1 : len(match)-1])
+				key, ok := resolveReference(path, reference, files, baseUrl, pathVariants)
+				if !ok {
+					return match
+				}
+				ext := filepath.Ext(reference)
+				reference = reference[:len(reference)-len(ext)] + "--" + ContentHash(bust(key)) + ext
+				return []byte(string(match[0]) + reference + string(match[len(match)-1]))
+			})
 		}
-		regexFound = false
 
+		busted[path] = content
+		state[path] = done
+		return content
 	}
-	var badpaths []string
 
-	for path := range *cache {
-		if re.Match((*cache)[path]) {
-			badpaths = append(badpaths, path)
-		}
+	for path := range files {
+		bust(path)
 	}
-
-	log.Fatal("Do you have a cyclic component?: ", badpaths)
-	return nil
+	return busted
 }
 
-// Replaces file paths in the content with paths that include a cache buster string.
-func ApplyCacheBuster(content string, extensions []string) string {
-	for _, ext := range extensions {
-		// This regex will match file references like src="file.js", href="file.css", etc.
-		// It looks for extensions that are followed by a quote character
-		re := regexp.MustCompile(`(["'])([^"']+` + regexp.QuoteMeta(ext) + `)(["'])`)
-
-		buster := "--" + addCacheBuster()
-
-		content = re.ReplaceAllStringFunc(content, func(match string) string {
-			// Extract the quote, path, and closing quote
-			parts := re.FindStringSubmatch(match)
-			if len(parts) >= 4 {
-				openQuote := parts[1]
-				path := parts[2]
-				closeQuote := parts[3]
-
-				// Insert the cache buster before the extension
-				extPos := strings.LastIndex(path, ext)
-				if extPos != -1 {
-					return openQuote + path[:extPos] + buster + ext + closeQuote
-				}
-			}
-			return match
-		})
+// Finds the file a reference in the file at path points to.
+func resolveReference(path string, reference string, files map[string][]byte, baseUrl string, pathVariants []string) (string, bool) {
+	if baseUrl = strings.TrimSuffix(strings.TrimSpace(baseUrl), "/"); baseUrl != "" && strings.HasPrefix(reference, baseUrl+"/") {
+		reference = strings.TrimPrefix(reference, baseUrl)
+	}
+	if strings.Contains(reference, "://") || strings.HasPrefix(reference, "//") {
+		return "", false
 	}
-	return content
+
+	if !strings.HasPrefix(reference, "/") {
+		key := filepath.ToSlash(filepath.Join(filepath.Dir(path), reference))
+		if _, ok := files[key]; ok {
+			return key, true
+		}
+		reference = "/" + reference
+	}
+	for _, variant := range pathVariants {
+		key := (variant + filepath.ToSlash(filepath.Clean(reference)))[1:]
+		if _, ok := files[key]; ok {
+			return key, true
+		}
+	}
+	return "", false
 }
 
-// Adds -- and random stuff to the filename
-func addCacheBuster() string {
-	randomBytes := make([]byte, 3) // 6 bytes generate 12 hex characters
-	if _, err := rand.Read(randomBytes); err != nil {
-		// Handle the error by returning it
-		log.Fatal(err)
+// CacheBuster returns the buster of a requested path, like "3f2a9c01b7d4e5f6" of "css/style--3f2a9c01b7d4e5f6.css".
+// Only a content hash after the last "--" is a buster, so "img/my--icon.svg" has none.
+func CacheBuster(path string) string {
+	index := strings.LastIndex(path, "--")
+	if index == -1 {
+		return ""
 	}
-	randomStr := hex.EncodeToString(randomBytes)
-	return "--" + randomStr
+	buster := strings.TrimSuffix(path[index+2:], filepath.Ext(path))
+	if _, err := hex.DecodeString(buster); err != nil || len(buster) != 16 {
+		return ""
+	}
+	return buster
 }
 
-// cacheReader processes a file path by removing characters after '--' and ensuring it has an appropriate extension.
+// cacheReader processes a file path by removing the cache buster and ensuring it has an appropriate extension.
 func CacheReader(path string) (string, error) {
-	index := strings.Index(path, "--")
-	if index != -1 {
-		ext := filepath.Ext(path)
-		if ext == "" {
-			ext = ".html" // Default to .html if there is no file extension
-		}
-		// Remove characters after '--' and ensure the path has an extension.
-		path = path[:index] + ext
-	} else {
-		// If '--' is not found, check if an extension is present; add .html if not.
-		if filepath.Ext(path) == "" {
-			path += ".html"
-		}
+	if CacheBuster(path) != "" {
+		// Remove the buster after the last '--', the extension stays.
+		path = path[:strings.LastIndex(path, "--")] + filepath.Ext(path)
+	}
+	// Add .html if there is no file extension.
+	if filepath.Ext(path) == "" {
+		path += ".html"
 	}
 	return path, nil
 }
 
-func replacePlaceholders(content string, re *regexp.Regexp, cache *map[string][]byte) (string, bool) {
-	replacementMade := false
-
-	result := re.ReplaceAllStringFunc(content, func(match string) string {
-		// Trim the surrounding {% and %} from the match
-		trimSpecific := func(match string) string {
-			prefix := "{%"
-			suffix := "%}"
-			if strings.HasPrefix(match, prefix) && strings.HasSuffix(match, suffix) {
-				return match[len(prefix) : len(match)-len(suffix)]
-			}
-			return match
-		}
-
-		key := trimSpecific(match)
-
-		// Check if the key exists in the cache
-		lookKey, err := CacheReader(key)
-		if err != nil {
-			log.Fatalf("Error CacheReader: %v", err)
-		}
-
-		if replacement, exists := (*cache)[lookKey]; exists {
-			if re.Match(replacement) {
-				// If the replacement still contains a regex match,
-				// return the original match without marking as replaced
-				return match
-			} else {
-				replacementMade = true
-				return string(replacement)
-			}
-		}
-
-		// If the key doesn't exist in the cache, return the original match
-		return match
-	})
-
-	return result, replacementMade
+// ClientIP is the IP address of the request without the port.
+// Throttles key on it, so a client does not get a new entry for every connection.
+func ClientIP(r *http.Request) string {
+	host, _, err := net.SplitHostPort(r.RemoteAddr)
+	if err != nil {
+		return r.RemoteAddr // RealIP sets it without a port.
+	}
+	return host
 }
 
+// RespondWithJSON sends the payload with status 200. Use RespondWithError for errors.
+// A plain ErrorResponse is still sent as a 400.
 func RespondWithJSON(w http.ResponseWriter, payload interface{}) {
-	var dat []byte
-	statuscode := 200
-	w.Header().Add("Content-Type", "application/json")
-
-	if errorResp, ok := payload.(*ErrorResponse); ok {
-		statuscode = 400
-		if !settings.Settings.Debug {
-			cleanedPayload := ErrorResponse{
-				Detail: errorResp.Detail,
-				Error:  "",
-			}
-			dat, _ = json.Marshal(cleanedPayload)
-
-			w.WriteHeader(statuscode)
-			w.Write(dat)
-			return
-
-		}
-
+	switch errorResp := payload.(type) {
+	case ErrorResponse:
+		RespondWithError(w, BadRequest(errorResp.Code, errorResp.Detail, errorFromString(errorResp.Error)))
+		return
+	case *ErrorResponse:
+		RespondWithError(w, BadRequest(errorResp.Code, errorResp.Detail, errorFromString(errorResp.Error)))
+		return
 	}
 
-	dat, _ = json.Marshal(payload)
+	RespondWithStatus(w, http.StatusOK, payload)
+}
+
+// RespondWithStatus sends the payload as JSON with another status than 200, e.g. a report of what was not valid.
+func RespondWithStatus(w http.ResponseWriter, status int, payload any) {
+	dat, _ := json.Marshal(payload)
 
-	w.WriteHeader(statuscode)
+	w.Header().Add("Content-Type", "application/json")
+	w.WriteHeader(status)
 	w.Write(dat)
+}
 
+func errorFromString(s string) error {
+	if s == "" {
+		return nil
+	}
+	return errors.New(s)
 }
@@ -0,0 +1,66 @@
+package utils
+
+import "testing"
+
+func TestCacheBuster(t *testing.T) {
+	tests := []struct {
+		path       string
+		wantBuster string
+		wantPath   string
+	}{
+		{"css/style.css", "", "css/style.css"},
+		{"css/style--3f2a9c01b7d4e5f6.css", "3f2a9c01b7d4e5f6", "css/style.css"},
+		{"about", "", "about.html"},
+		{"about--3f2a9c01b7d4e5f6", "3f2a9c01b7d4e5f6", "about.html"},
+		{"img/my--icon.svg", "", "img/my--icon.svg"},
+		{"img/my--icon--3f2a9c01b7d4e5f6.svg", "3f2a9c01b7d4e5f6", "img/my--icon.svg"},
+		{"img/icon--3f2a9c01.svg", "", "img/icon--3f2a9c01.svg"},
+		{"img/icon--3f2a9c01b7d4e5fx.svg", "", "img/icon--3f2a9c01b7d4e5fx.svg"},
+		{"my--dir/style.css", "", "my--dir/style.css"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.path, func(t *testing.T) {
+			if got := CacheBuster(tt.path); got != tt.wantBuster {
+				t.Errorf("CacheBuster(%q) = %q, want %q", tt.path, got, tt.wantBuster)
+			}
+			got, err := CacheReader(tt.path)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if got != tt.wantPath {
+				t.Errorf("CacheReader(%q) = %q, want %q", tt.path, got, tt.wantPath)
+			}
+		})
+	}
+}
+
+func TestBustFilesResolves(t *testing.T) {
+	files := map[string][]byte{
+		"index.html":       []byte(`<img src="img/my--icon.svg"><script src="/js/app.js"></script>`),
+		"img/my--icon.svg": []byte("<svg></svg>"),
+		"js/app.js":        []byte(`const logo = "../img/my--icon.svg"`),
+	}
+	busted := BustFiles(files, "", []string{""})
+
+	icon := ContentHash(busted["img/my--icon.svg"])
+	app := ContentHash(busted["js/app.js"])
+	want := map[string]string{
+		"index.html": `<img src="img/my--icon--` + icon + `.svg"><script src="/js/app--` + app + `.js"></script>`,
+		"js/app.js":  `const logo = "../img/my--icon--` + icon + `.svg"`,
+	}
+	for path, content := range want {
+		if string(busted[path]) != content {
+			t.Errorf("BustFiles %s = %s, want %s", path, busted[path], content)
+		}
+	}
+
+	// The busted URL of a file with -- in its name leads back to that file.
+	url := "img/my--icon--" + icon + ".svg"
+	if got, _ := CacheReader(url); got != "img/my--icon.svg" {
+		t.Errorf("CacheReader(%q) = %q, want img/my--icon.svg", url, got)
+	}
+	if got := CacheBuster(url); got != icon {
+		t.Errorf("CacheBuster(%q) = %q, want %q", url, got, icon)
+	}
+}
@@ -0,0 +1,89 @@
+package utils
+
+import (
+	"bytes"
+	"context"
+	"image/png"
+	"strings"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5/pgtype"
+	settings "github.com/karl1b/go4lage/pkg/settings"
+	"github.com/karl1b/go4lage/pkg/sql/db"
+	"github.com/pquerna/otp"
+	"github.com/pquerna/otp/totp"
+)
+
+// NewTOTPKey generates a new TOTP secret for the account and renders its QR code as PNG.
+func NewTOTPKey(accountName string) (*otp.Key, []byte, error) {
+	key, err := totp.Generate(totp.GenerateOpts{
+		Issuer:      settings.Settings.Baseurl,
+		AccountName: accountName,
+	})
+	if err != nil {
+		return nil, nil, err
+	}
+
+	// Convert TOTP key into a PNG
+	var buf bytes.Buffer
+	img, err := key.Image(200, 200)
+	if err != nil {
+		return nil, nil, err
+	}
+	err = png.Encode(&buf, img)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return key, buf.Bytes(), nil
+}
+
+// TwofactorMandatory tells if the user has to use 2FA.
+// It is mandatory for superusers if SUPERUSER_2FA is set and for all members of an organization that requires it.
+func TwofactorMandatory(user db.User, organization db.Organization) bool {
+	if user.IsSuperuser.Bool && settings.Settings.Superuser2FA {
+		return true
+	}
+	return organization.RequireTwofactor
+}
+
+const RecoveryCodeCount = 10
+
+// NewRecoveryCodes replaces all recovery codes of the user with new ones.
+// Only the digests are stored, the returned plain codes have to be shown to the user right away.
+// Call it with the queries of a transaction, so the old codes are only gone if all new ones are stored.
+func NewRecoveryCodes(queries *db.Queries, userID pgtype.UUID) ([]string, error) {
+	err := queries.RecoveryCodeDeleteByUserId(context.Background(), userID)
+	if err != nil {
+		return nil, err
+	}
+
+	var codes []string
+	for range RecoveryCodeCount {
+		raw, err := GenerateTokenHex(8)
+		if err != nil {
+			return nil, err
+		}
+		code := raw[0:4] + "-" + raw[4:8] + "-" + raw[8:12] + "-" + raw[12:16]
+
+		_, err = queries.RecoveryCodeCreate(context.Background(), db.RecoveryCodeCreateParams{
+			ID:       pgtype.UUID{Bytes: uuid.New(), Valid: true},
+			UserID:   userID,
+			CodeHash: HashRecoveryCode(code),
+		})
+		if err != nil {
+			return nil, err
+		}
+		codes = append(codes, code)
+	}
+
+	return codes, nil
+}
+
+// HashRecoveryCode returns the digest of a recovery code. Case, spaces and dashes do not matter.
+func HashRecoveryCode(code string) string {
+	code = strings.ToLower(code)
+	code = strings.ReplaceAll(code, "-", "")
+	code = strings.ReplaceAll(code, " ", "")
+	return HashToken(code)
+}
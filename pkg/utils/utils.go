@@ -7,6 +7,7 @@ import (
 type Info struct{}
 type InfoKey struct {
 	User         db.User
+	Session      db.Session // The session of the request token.
 	Organization db.Organization
 	Groups       []string
 	Permissions  []string
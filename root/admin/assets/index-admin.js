@@ -169,11 +169,7 @@ Error generating stack: `+c.message+`
  *
  * This source code is licensed under the ISC license.
  * See the LICENSE file in the root directory of this source tree.
-<<<<<<< HEAD
- */const Fa=Ye("X",[["path",{d:"M18 6 6 18",key:"1bl5f8"}],["path",{d:"m6 6 12 12",key:"d8bk6v"}]]),Tm={light:{"--color-brand":"#3c8dcf","--color-brand-secondary":"#3b93d1","--color-text-primary":"#333","--color-text-secondary":"#2c3e50","--color-text-muted":"#667788","--color-text-inverse":"#ffffff","--color-surface-primary":"#e4e4e4","--color-surface-secondary":"#d8d8d8","--color-surface-tertiary":"#dddddd","--color-surface-inverse":"#2c3e50","--color-accent-primary":"#3c8dcf","--color-accent-secondary":"#3b93d1","--color-success":"#16a34a","--color-warning":"#ca8a04","--color-error":"#dc2626","--color-info":"#3b93d1","--color-border":"#dddddd","--color-border-muted":"#e8e8e8","--color-interactive":"#4f7fff","--color-interactive-hover":"#2449a3","--color-interactive-active":"#1c3879","--color-interactive-disabled":"#a3b3c6","--gradient-brand":"linear-gradient(to right, var(--color-brand), var(--color-brand-secondary))","--gradient-surface":"linear-gradient(to bottom, var(--color-surface-primary), var(--color-surface-secondary))"},dark:{"--color-brand":"#2c5dcd","--color-brand-secondary":"#3b93d1","--color-text-primary":"#f4f4f4","--color-text-secondary":"#d1d8e0","--color-text-muted":"#9ba9b9","--color-text-inverse":"#2c3e50","--color-surface-primary":"#1a1f2c","--color-surface-secondary":"#141922","--color-surface-tertiary":"#252d3b","--color-surface-inverse":"#f4f4f4","--color-accent-primary":"#4077e4","--color-accent-secondary":"#5ca3e0","--color-success":"#15803d","--color-warning":"#a16207","--color-error":"#b91c1c","--color-info":"#0369a1","--color-border":"#2d3443","--color-border-muted":"#1f242f","--color-interactive":"#2c5dcd","--color-interactive-hover":"#5485ed","--color-interactive-active":"#6693f5","--color-interactive-disabled":"#4a5568","--gradient-brand":"linear-gradient(to right, var(--color-brand), var(--color-brand-secondary))","--gradient-surface":"linear-gradient(to bottom, var(--color-surface-primary), var(--color-surface-secondary))"}},Gd=C.createContext(void 0),_m=({children:o})=>{const[r,s]=C.useState(()=>localStorage.getItem("theme")||"dark");return C.useEffect(()=>{const a=document.documentElement,u=Tm[r];Object.entries(u).forEach(([d,h])=>{a.style.setProperty(d,h)}),localStorage.setItem("theme",r)},[r]),f.jsx(Gd.Provider,{value:{theme:r,setTheme:s},children:o})},Im=()=>{const o=C.useContext(Gd);if(o===void 0)throw new Error("useTheme must be used within a ThemeProvider");return o},Zd=()=>{const{theme:o,setTheme:r}=Im();return f.jsxs("button",{onClick:()=>r(o==="light"?"dark":"light"),className:"w-full flex items-center justify-between p-0 bg-transparent border-none text-text-primary hover:text-accent-primary transition-colors duration-200 cursor-pointer","aria-label":`Switch to ${o==="light"?"dark":"light"} mode`,children:[f.jsx("span",{className:"text-sm font-medium mr-2",children:"Theme"}),f.jsxs("div",{className:"flex items-center gap-2",children:[f.jsx("span",{className:"text-xs text-text-secondary capitalize",children:o}),f.jsx("div",{className:"w-6 h-6 rounded-full bg-surface-tertiary flex items-center justify-center",children:o==="light"?f.jsx(Om,{className:"w-4 h-4 text-accent-primary",strokeWidth:2}):f.jsx(zm,{className:"w-4 h-4 text-accent-primary",strokeWidth:2})})]})]})},Dm=(o,r,s,a)=>{const u=[s,{code:r,...a||{}}];if(o?.services?.logger?.forward)return o.services.logger.forward(u,"warn","react-i18next::",!0);bn(u[0])&&(u[0]=`react-i18next:: ${u[0]}`),o?.services?.logger?.warn?o.services.logger.warn(...u):console?.warn&&console.warn(...u)},Jc={},za=(o,r,s,a)=>{bn(s)&&Jc[s]||(bn(s)&&(Jc[s]=new Date),Dm(o,r,s,a))},Qd=(o,r)=>()=>{if(o.isInitialized)r();else{const s=()=>{setTimeout(()=>{o.off("initialized",s)},0),r()};o.on("initialized",s)}},Ta=(o,r,s)=>{o.loadNamespaces(r,Qd(o,s))},qc=(o,r,s,a)=>{if(bn(s)&&(s=[s]),o.options.preload&&o.options.preload.indexOf(r)>-1)return Ta(o,s,a);s.forEach(u=>{o.options.ns.indexOf(u)<0&&o.options.ns.push(u)}),o.loadLanguages(r,Qd(o,a))},Mm=(o,r,s={})=>!r.languages||!r.languages.length?(za(r,"NO_LANGUAGES","i18n.languages were undefined or empty",{languages:r.languages}),!0):r.hasLoadedNamespace(o,{lng:s.lng,precheck:(a,u)=>{if(s.bindI18n?.indexOf("languageChanging")>-1&&a.services.backendConnector.backend&&a.isLanguageChangingTo&&!u(a.isLanguageChangingTo,o))return!1}}),bn=o=>typeof o=="string",Rm=o=>typeof o=="object"&&o!==null,Fm=/&(?:amp|#38|lt|#60|gt|#62|apos|#39|quot|#34|nbsp|#160|copy|#169|reg|#174|hellip|#8230|#x2F|#47);/g,$m={"&amp;":"&","&#38;":"&","&lt;":"<","&#60;":"<","&gt;":">","&#62;":">","&apos;":"'","&#39;":"'","&quot;":'"',"&#34;":'"',"&nbsp;":" ","&#160;":" ","&copy;":"©","&#169;":"©","&reg;":"®","&#174;":"®","&hellip;":"…","&#8230;":"…","&#x2F;":"/","&#47;":"/"},Am=o=>$m[o],Um=o=>o.replace(Fm,Am);let _a={bindI18n:"languageChanged",bindI18nStore:"",transEmptyNodeValue:"",transSupportBasicHtmlNodes:!0,transWrapTextNodes:"",transKeepBasicHtmlNodesFor:["br","strong","i","p"],useSuspense:!0,unescape:Um};const Wm=(o={})=>{_a={..._a,...o}},Bm=()=>_a;let Kd;const Hm=o=>{Kd=o},Vm=()=>Kd,Ym={type:"3rdParty",init(o){Wm(o.options.react),Hm(o)}},Gm=C.createContext();class Zm{constructor(){this.usedNamespaces={}}addUsedNamespaces(r){r.forEach(s=>{this.usedNamespaces[s]||(this.usedNamespaces[s]=!0)})}getUsedNamespaces(){return Object.keys(this.usedNamespaces)}}const Qm=(o,r)=>{const s=C.useRef();return C.useEffect(()=>{s.current=o},[o,r]),s.current},Xd=(o,r,s,a)=>o.getFixedT(r,s,a),Km=(o,r,s,a)=>C.useCallback(Xd(o,r,s,a),[o,r,s,a]),Ae=(o,r={})=>{const{i18n:s}=r,{i18n:a,defaultNS:u}=C.useContext(Gm)||{},d=s||a||Vm();if(d&&!d.reportNamespaces&&(d.reportNamespaces=new Zm),!d){za(d,"NO_I18NEXT_INSTANCE","useTranslation: You will need to pass in an i18next instance by using initReactI18next");const Q=(J,ee)=>bn(ee)?ee:Rm(ee)&&bn(ee.defaultValue)?ee.defaultValue:Array.isArray(J)?J[J.length-1]:J,U=[Q,{},!1];return U.t=Q,U.i18n={},U.ready=!1,U}d.options.react?.wait&&za(d,"DEPRECATED_OPTION","useTranslation: It seems you are still using the old wait option, you may migrate to the new useSuspense behaviour.");const h={...Bm(),...d.options.react,...r},{useSuspense:m,keyPrefix:g}=h;let y=u||d.options?.defaultNS;y=bn(y)?[y]:y||["translation"],d.reportNamespaces.addUsedNamespaces?.(y);const k=(d.isInitialized||d.initializedStoreOnce)&&y.every(Q=>Mm(Q,d,h)),x=Km(d,r.lng||null,h.nsMode==="fallback"?y:y[0],g),L=()=>x,I=()=>Xd(d,r.lng||null,h.nsMode==="fallback"?y:y[0],g),[E,z]=C.useState(L);let T=y.join();r.lng&&(T=`${r.lng}${T}`);const A=Qm(T),H=C.useRef(!0);C.useEffect(()=>{const{bindI18n:Q,bindI18nStore:U}=h;H.current=!0,!k&&!m&&(r.lng?qc(d,r.lng,y,()=>{H.current&&z(I)}):Ta(d,y,()=>{H.current&&z(I)})),k&&A&&A!==T&&H.current&&z(I);const J=()=>{H.current&&z(I)};return Q&&d?.on(Q,J),U&&d?.store.on(U,J),()=>{H.current=!1,d&&Q?.split(" ").forEach(ee=>d.off(ee,J)),U&&d&&U.split(" ").forEach(ee=>d.store.off(ee,J))}},[d,T]),C.useEffect(()=>{H.current&&k&&z(L)},[d,g,k]);const Y=[E,d,k];if(Y.t=E,Y.i18n=d,Y.ready=k,k||!k&&!m)return Y;throw new Promise(Q=>{r.lng?qc(d,r.lng,y,()=>Q()):Ta(d,y,()=>Q())})};function Fe({kind:o="primary",size:r="md",children:s,className:a="",disabled:u=!1,...d}){const h="inline-flex items-center justify-center rounded-lg transition-all duration-200 focus:outline-none focus:ring-2 focus:ring-offset-2 disabled:opacity-50 disabled:cursor-not-allowed",m={sm:"px-2 py-1.5 text-sm font-normal",md:"px-3 py-2.5 text-base font-medium",lg:"px-4 py-3 text-lg font-semibold"},g={primary:"bg-brand hover:bg-brand-secondary text-text-primary focus:ring-brand",secondary:"bg-surface-secondary hover:bg-surface-tertiary text-text-primary border border-border-default focus:ring-brand",danger:"bg-error hover:bg-error/90 text-text-primary focus:ring-error",ghost:"bg-transparent hover:bg-surface-secondary text-text-primary focus:ring-brand"};return f.jsx("button",{className:`${h} ${m[r]} ${g[o]} ${a}`,disabled:u,...d,children:s})}class Xm{apiUrl;constructor(){const r="{%Apiurl%}";r.slice(2,-2).trim()==="Apiurl"?this.apiUrl="http://127.0.0.1:8080/adminapi":this.apiUrl=r+"/adminapi"}async fetchWithToken({url:r,options:s,token:a,toastHeader:u,setToast:d}){if(!a&&d){d({show:!0,success:!1,header:"Token is missing",text:"Try login and out again"});return}const h=new Headers(s.headers);h.append("Authorization","Token "+a);const m=await fetch(r,{...s,headers:h});if(!m.ok){d&&d({show:!0,success:!1,header:"Error outside go4lage",text:`${m.status}`});return}const g=m.headers.get("content-type");if(g&&g.includes("application/json")){const y=await m.json();if(y==null){d&&d({show:!0,success:!1,header:"Error",text:"Received empty response"});return}if(y?.error&&d){d({show:!0,success:!1,header:u||"Toastheader missing",text:`${y.detail} ${y.error}`});return}if((y?.text||y.header)&&d){console.log("inside correct toat"),d({show:!0,success:!0,header:u||"Toastheader missing",text:`${y.header} ${y.text}`});return}return y}return m}async dashboardinfo(){return await(await fetch(this.apiUrl+"/dashboardinfo",{method:"GET"})).json()}async allusers(r){return await this.fetchWithToken({url:`${this.apiUrl}/allusers`,options:{method:"GET"},token:r,toastHeader:null,setToast:null})||null}async logout(r){return await this.fetchWithToken({url:`${this.apiUrl}/logout`,options:{method:"GET"},token:r,toastHeader:null,setToast:null})}async editGroupPermissions(r,s,a,u){await this.fetchWithToken({url:`${this.apiUrl}/editgrouppermissions`,options:{method:"POST",headers:{"Content-Type":"application/json",Id:s},body:JSON.stringify(a)},token:r,toastHeader:"Edit Group Permissions",setToast:u})}async createoneuser(r,s,a){await this.fetchWithToken({url:`${this.apiUrl}/oneuser`,options:{method:"POST",headers:{"Content-Type":"application/json"},body:JSON.stringify(s)},token:r,toastHeader:"Create User",setToast:a})}async oneuser(r,s){return await this.fetchWithToken({url:`${this.apiUrl}/oneuser`,options:{method:"GET",headers:{Id:s}},token:r,toastHeader:null,setToast:null})}async editoneuserGroups(r,s,a,u){await this.fetchWithToken({url:`${this.apiUrl}/editusergroups`,options:{method:"POST",headers:{"Content-Type":"application/json",Id:s},body:JSON.stringify(a)},token:r,toastHeader:"Edit User",setToast:u})}async editoneuserPermissions(r,s,a,u){await this.fetchWithToken({url:`${this.apiUrl}/edituserpermissions`,options:{method:"POST",headers:{"Content-Type":"application/json",Id:s},body:JSON.stringify(a)},token:r,toastHeader:"Edit User",setToast:u})}async editoneuser(r,s,a,u){await this.fetchWithToken({url:`${this.apiUrl}/oneuser`,options:{method:"PUT",headers:{"Content-Type":"application/json",Id:s},body:JSON.stringify(a)},token:r,toastHeader:"Edit User",setToast:u})}async getGroups(r){return await this.fetchWithToken({url:`${this.apiUrl}/getgroups`,options:{method:"GET"},token:r,toastHeader:null,setToast:null})}async getBackups(r){return await this.fetchWithToken({url:`${this.apiUrl}/getbackups`,options:{method:"GET"},token:r,toastHeader:null,setToast:null})}async getLogs(r,s){return await this.fetchWithToken({url:`${this.apiUrl}/getlogs${s}`,options:{method:"GET"},token:r,toastHeader:null,setToast:null})}async getErrorLogs(r){return await this.fetchWithToken({url:`${this.apiUrl}/geterrorlogs`,options:{method:"GET"},token:r,toastHeader:null,setToast:null})}async createBackup(r,s){await this.fetchWithToken({url:`${this.apiUrl}/createbackup`,options:{method:"GET",headers:{"Content-Type":"application/json"}},token:r,toastHeader:"Create Backup",setToast:s})}async downloadBackup(r,s){return await(await this.fetchWithToken({url:`${this.apiUrl}/downloadbackup`,options:{method:"GET",headers:{Id:s}},token:r,toastHeader:null,setToast:null}))?.blob()||null}async deletebackup(r,s){return await this.fetchWithToken({url:`${this.apiUrl}/deletebackup`,options:{method:"DELETE",headers:{Id:s}},token:r,toastHeader:null,setToast:null}),null}async getPermissions(r){return await this.fetchWithToken({url:`${this.apiUrl}/getpermissions`,options:{method:"GET"},token:r,toastHeader:null,setToast:null})}async getGroupById(r,s){return await this.fetchWithToken({url:`${this.apiUrl}/getgroup`,options:{method:"GET",headers:{Id:s}},token:r,toastHeader:null,setToast:null})}async getPermissionById(r,s){return await this.fetchWithToken({url:`${this.apiUrl}/getpermission`,options:{method:"GET",headers:{Id:s}},token:r,toastHeader:null,setToast:null})}async getPermissionForGroup(r,s){return await this.fetchWithToken({url:`${this.apiUrl}/getpermissionsforgroup`,options:{method:"GET",headers:{Id:s}},token:r,toastHeader:null,setToast:null})}async login(r,s,a,u){const d=await fetch(this.apiUrl+"/login",{method:"POST",headers:{"Content-Type":"application/json"},body:JSON.stringify({email:r,password:s,tfa:a})});if(!d.ok)return u({show:!0,success:!1,header:"Login",text:"Login failed"}),null;const h=await d.json();return h.error?(u({show:!0,success:!1,header:"Login",text:`${h.detail} ${h.error}`}),null):(u({show:!0,success:!0,header:"Login",text:"Login successful!"}),h)}async deletePermission(r,s,a){await this.fetchWithToken({url:`${this.apiUrl}/deletepermission`,options:{method:"DELETE",headers:{Id:s}},token:r,toastHeader:"Delete Permission",setToast:a})}async deleteUser(r,s,a){await this.fetchWithToken({url:`${this.apiUrl}/deleteuser`,options:{method:"DELETE",headers:{Id:s}},token:r,toastHeader:"Delete User",setToast:a})}async createGroup(r,s,a){await this.fetchWithToken({url:`${this.apiUrl}/creategroup`,options:{method:"PUT",body:JSON.stringify({name:s})},token:r,toastHeader:"Create group",setToast:a})}async createPermission(r,s,a){await this.fetchWithToken({url:`${this.apiUrl}/createpermission`,options:{method:"PUT",body:JSON.stringify({name:s})},token:r,toastHeader:"Create Permission",setToast:a})}async deleteGroup(r,s,a){await this.fetchWithToken({url:`${this.apiUrl}/deletegroup`,options:{method:"DELETE",headers:{Id:s}},token:r,toastHeader:"Delete Group",setToast:a})}async downloadCSVtemplate(r){return await(await this.fetchWithToken({url:`${this.apiUrl}/downloadcsvtemplate`,options:{method:"GET"},token:r,toastHeader:null,setToast:null}))?.blob()||null}async bulkCreateUsers(r,s,a){const u=await this.fetchWithToken({url:`${this.apiUrl}/bulkcreateusers`,options:{method:"POST",body:s},token:r,toastHeader:"Bulk Create Users",setToast:a});a({show:!0,success:!0,header:"Bulk Create Users",text:"Users created successfully!"}),console.log("Users created successfully:",u)}async newfeedback(r,s,a){await this.fetchWithToken({url:`${this.apiUrl}/newfeedback`,options:{method:"POST",headers:{"Content-Type":"application/json"},body:JSON.stringify(s)},token:r,toastHeader:"Feedback send",setToast:a})}async getMsg(r,s){const a=s?"allfeedback":"getuserspecificfeedback";return await this.fetchWithToken({url:`${this.apiUrl}/${a}`,options:{method:"GET"},token:r,toastHeader:null,setToast:null})||[]}async updateFeedBack(r,s,a,u){const d=a?"updatefeedbackstaff":"updatefeedbackuser";return await this.fetchWithToken({url:`${this.apiUrl}/${d}`,options:{method:"POST",headers:{"Content-Type":"application/json"},body:JSON.stringify(s)},token:r,toastHeader:"Feedback send",setToast:u})}async createOrganization(r,s,a){await this.fetchWithToken({url:`${this.apiUrl}/createorganization`,options:{method:"POST",body:JSON.stringify(s)},token:r,toastHeader:"Create Organization",setToast:a})}async editOneOrganization(r,s,a,u){await this.fetchWithToken({url:`${this.apiUrl}/editoneorganization`,options:{method:"PUT",headers:{"Content-Type":"application/json",Id:s},body:JSON.stringify(a)},token:r,toastHeader:"Edit Organization",setToast:u})}async deleteOrganization(r,s,a){await this.fetchWithToken({url:`${this.apiUrl}/deleteorganization`,options:{method:"DELETE",headers:{Id:s}},token:r,toastHeader:"Delete Organization",setToast:a})}async allOrganizations(r){return await this.fetchWithToken({url:`${this.apiUrl}/allorganizations`,options:{method:"GET"},token:r,toastHeader:null,setToast:null})}async oneOrganization(r,s){return await this.fetchWithToken({url:`${this.apiUrl}/oneorganization`,options:{method:"GET",headers:{Id:s}},token:r,toastHeader:null,setToast:null})}}const Pe=new Xm;function Jm(){const{t:o}=Ae(),{userData:r,setUserData:s}=C.useContext(et);async function a(){if(r.token)try{await Pe.logout(r.token)}catch(u){console.log(u)}finally{s({email:null,token:null,is_organizationadmin:!1,is_superuser:!1})}}return f.jsx(f.Fragment,{children:f.jsx(Fe,{onClick:a,kind:"secondary",children:o("Logout")})})}const $a="data:image/svg+xml;base64,PD94bWwgdmVyc2lvbj0iMS4wIiBlbmNvZGluZz0iVVRGLTgiIHN0YW5kYWxvbmU9Im5vIj8+CjwhLS0gQ3JlYXRlZCB3aXRoIElua3NjYXBlIChodHRwOi8vd3d3Lmlua3NjYXBlLm9yZy8pIC0tPgoKPHN2ZwogICB3aWR0aD0iNjYuMjE1MjYzbW0iCiAgIGhlaWdodD0iNjIuNzQ2MzcybW0iCiAgIHZpZXdCb3g9IjAgMCA2Ni4yMTUyNjMgNjIuNzQ2MzcyIgogICB2ZXJzaW9uPSIxLjEiCiAgIGlkPSJzdmcxIgogICB4bWxucz0iaHR0cDovL3d3dy53My5vcmcvMjAwMC9zdmciCiAgIHhtbG5zOnN2Zz0iaHR0cDovL3d3dy53My5vcmcvMjAwMC9zdmciPgogIDxkZWZzCiAgICAgaWQ9ImRlZnMxIiAvPgogIDxnCiAgICAgaWQ9ImxheWVyMSIKICAgICB0cmFuc2Zvcm09InRyYW5zbGF0ZSgtNzYuOTUxNjMyLC02LjA3MTMwNDYpIj4KICAgIDx0ZXh0CiAgICAgICB4bWw6c3BhY2U9InByZXNlcnZlIgogICAgICAgc3R5bGU9ImZvbnQtc3R5bGU6bm9ybWFsO2ZvbnQtdmFyaWFudDpub3JtYWw7Zm9udC13ZWlnaHQ6NjAwO2ZvbnQtc3RyZXRjaDpub3JtYWw7Zm9udC1zaXplOjQ0LjUyMzJweDtmb250LWZhbWlseTpGcmVlU2FuczstaW5rc2NhcGUtZm9udC1zcGVjaWZpY2F0aW9uOidGcmVlU2FucywgU2VtaS1Cb2xkJztmb250LXZhcmlhbnQtbGlnYXR1cmVzOm5vcm1hbDtmb250LXZhcmlhbnQtY2Fwczpub3JtYWw7Zm9udC12YXJpYW50LW51bWVyaWM6bm9ybWFsO2ZvbnQtdmFyaWFudC1lYXN0LWFzaWFuOm5vcm1hbDtmaWxsOiNmZmZmZmY7ZmlsbC1vcGFjaXR5OjE7c3Ryb2tlOiMwMDAwMDA7c3Ryb2tlLXdpZHRoOjM7c3Ryb2tlLWRhc2hhcnJheTpub25lO3BhaW50LW9yZGVyOm1hcmtlcnMgc3Ryb2tlIGZpbGwiCiAgICAgICB4PSI3MC4yMTU0MzEiCiAgICAgICB5PSI0MS4zMzA4OTQiCiAgICAgICBpZD0idGV4dDEiCiAgICAgICB0cmFuc2Zvcm09InNjYWxlKDEuMDg5NjYxMSwwLjkxNzcxNjUzKSI+PHRzcGFuCiAgICAgICAgIHN0eWxlPSJmb250LXN0eWxlOm5vcm1hbDtmb250LXZhcmlhbnQ6bm9ybWFsO2ZvbnQtd2VpZ2h0OjYwMDtmb250LXN0cmV0Y2g6bm9ybWFsO2ZvbnQtc2l6ZTo0NC41MjMycHg7Zm9udC1mYW1pbHk6RnJlZVNhbnM7LWlua3NjYXBlLWZvbnQtc3BlY2lmaWNhdGlvbjonRnJlZVNhbnMsIFNlbWktQm9sZCc7Zm9udC12YXJpYW50LWxpZ2F0dXJlczpub3JtYWw7Zm9udC12YXJpYW50LWNhcHM6bm9ybWFsO2ZvbnQtdmFyaWFudC1udW1lcmljOm5vcm1hbDtmb250LXZhcmlhbnQtZWFzdC1hc2lhbjpub3JtYWw7ZmlsbDojZmZmZmZmO2ZpbGwtb3BhY2l0eToxO3N0cm9rZS13aWR0aDozO3N0cm9rZS1kYXNoYXJyYXk6bm9uZSIKICAgICAgICAgeD0iNzAuMjE1NDMxIgogICAgICAgICB5PSI0MS4zMzA4OTQiCiAgICAgICAgIGlkPSJ0c3BhbjIiPkdvPC90c3Bhbj48L3RleHQ+CiAgICA8dGV4dAogICAgICAgeG1sOnNwYWNlPSJwcmVzZXJ2ZSIKICAgICAgIHN0eWxlPSJmb250LXN0eWxlOml0YWxpYztmb250LXNpemU6MjUuNHB4O2ZvbnQtZmFtaWx5OkZyZWVTYW5zOy1pbmtzY2FwZS1mb250LXNwZWNpZmljYXRpb246J0ZyZWVTYW5zIEl0YWxpYyc7ZmlsbDojZmZmZmZmO2ZpbGwtb3BhY2l0eToxO3N0cm9rZTojMDAwMDAwO3N0cm9rZS13aWR0aDoyO3BhaW50LW9yZGVyOm1hcmtlcnMgc3Ryb2tlIGZpbGwiCiAgICAgICB4PSI4MC44MTc1ODEiCiAgICAgICB5PSI2Mi4yODA0NzYiCiAgICAgICBpZD0idGV4dDMiPjx0c3BhbgogICAgICAgICBpZD0idHNwYW4zIgogICAgICAgICBzdHlsZT0iZm9udC1zdHlsZTpub3JtYWw7Zm9udC12YXJpYW50Om5vcm1hbDtmb250LXdlaWdodDpub3JtYWw7Zm9udC1zdHJldGNoOm5vcm1hbDtmb250LXNpemU6MjUuNHB4O2ZvbnQtZmFtaWx5OkZyZWVTYW5zOy1pbmtzY2FwZS1mb250LXNwZWNpZmljYXRpb246J0ZyZWVTYW5zLCBOb3JtYWwnO2ZvbnQtdmFyaWFudC1saWdhdHVyZXM6bm9ybWFsO2ZvbnQtdmFyaWFudC1jYXBzOm5vcm1hbDtmb250LXZhcmlhbnQtbnVtZXJpYzpub3JtYWw7Zm9udC12YXJpYW50LWVhc3QtYXNpYW46bm9ybWFsO2ZpbGw6I2ZmZmZmZjtmaWxsLW9wYWNpdHk6MTtzdHJva2Utd2lkdGg6MiIKICAgICAgICAgeD0iODAuODE3NTgxIgogICAgICAgICB5PSI2Mi4yODA0NzYiPjRsYWdlPC90c3Bhbj48L3RleHQ+CiAgPC9nPgo8L3N2Zz4K";function ed(){const o=_t();return f.jsx("div",{className:"cursor-pointer flex items-center justify-center transition-transform hover:scale-105 active:scale-95 h-10 overflow-hidden",onClick:()=>o("/"),children:f.jsx("img",{src:$a,alt:"Go4lage Logo",className:"h-full w-auto max-w-20 object-contain",style:{maxHeight:"40px"}})})}const qm=({className:o})=>{const{i18n:r,t:s}=Ae(),a=[{code:"en",name:s("language.english"),flag:"🇺🇸"},{code:"de",name:s("language.german"),flag:"🇩🇪"}],u=d=>{const h=d.target.value;r.changeLanguage(h)};return f.jsxs("div",{className:`w-full relative ${o}`,children:[f.jsx("select",{value:r.language,onChange:u,className:"w-full appearance-none bg-surface-tertiary border border-border-default rounded-lg px-3 py-2 pr-8 text-sm text-text-primary focus:outline-none focus:ring-2 focus:ring-accent-primary focus:border-transparent cursor-pointer hover:bg-surface-secondary transition-colors",children:a.map(d=>f.jsxs("option",{value:d.code,className:"bg-surface-primary text-text-primary",children:[d.flag," ",d.name]},d.code))}),f.jsx("div",{className:"absolute right-2 top-1/2 -translate-y-1/2 pointer-events-none",children:f.jsx("svg",{className:"w-4 h-4 text-text-secondary",fill:"none",stroke:"currentColor",viewBox:"0 0 24 24",children:f.jsx("path",{strokeLinecap:"round",strokeLinejoin:"round",strokeWidth:2,d:"M19 9l-7 7-7-7"})})})]})},eg=({isSidebarExpanded:o,setIsMobileOpen:r})=>{const{userData:s,setToast:a}=C.useContext(et),u=_t(),[d,h]=C.useState(!1),[m,g]=C.useState(!1),[y,k]=C.useState(!1),[x,L]=C.useState(""),[I,E]=C.useState(""),z=C.useRef(null),T=C.useRef(null),{t:A}=Ae();C.useEffect(()=>{function J(ee){z.current&&!z.current.contains(ee.target)&&h(!1),T.current&&!T.current.contains(ee.target)&&(g(!1),k(!1),L(""),E(""))}return document.addEventListener("mousedown",J),()=>document.removeEventListener("mousedown",J)},[]);const H=()=>{if(!x.trim()||!I.trim()){a({show:!0,success:!1,header:A("validationError"),text:A("pleaseFillBothBehaviorFields")});return}a({show:!0,success:!0,header:A("messageSent"),text:A("feedbackMessageSentSuccessfully")}),L(""),E(""),k(!1),g(!1);async function J(){try{Pe.newfeedback(s.token,{behaviour_is:x,behaviour_should:I,full_url:window.location.toString(),chat:null,id:"",is_solved:!1,created_at:"",updated_at:""},a)}catch(ee){console.log(ee)}}J()},Y=()=>{g(!1),u("/mymessages")},Q=()=>{k(!0)},U=()=>{k(!1),L(""),E("")};return f.jsx("header",{className:`
-=======
- */const $c=et("X",[["path",{d:"M18 6 6 18",key:"1bl5f8"}],["path",{d:"m6 6 12 12",key:"d8bk6v"}]]),Kv={light:{"--color-brand":"#3c8dcf","--color-brand-secondary":"#3b93d1","--color-text-primary":"#333","--color-text-secondary":"#2c3e50","--color-text-muted":"#667788","--color-text-inverse":"#ffffff","--color-surface-primary":"#e4e4e4","--color-surface-secondary":"#d8d8d8","--color-surface-tertiary":"#dddddd","--color-surface-inverse":"#2c3e50","--color-accent-primary":"#3c8dcf","--color-accent-secondary":"#3b93d1","--color-success":"#16a34a","--color-warning":"#ca8a04","--color-error":"#dc2626","--color-info":"#3b93d1","--color-border":"#dddddd","--color-border-muted":"#e8e8e8","--color-interactive":"#4f7fff","--color-interactive-hover":"#2449a3","--color-interactive-active":"#1c3879","--color-interactive-disabled":"#a3b3c6","--gradient-brand":"linear-gradient(to right, var(--color-brand), var(--color-brand-secondary))","--gradient-surface":"linear-gradient(to bottom, var(--color-surface-primary), var(--color-surface-secondary))"},dark:{"--color-brand":"#2c5dcd","--color-brand-secondary":"#3b93d1","--color-text-primary":"#f4f4f4","--color-text-secondary":"#d1d8e0","--color-text-muted":"#9ba9b9","--color-text-inverse":"#2c3e50","--color-surface-primary":"#1a1f2c","--color-surface-secondary":"#141922","--color-surface-tertiary":"#252d3b","--color-surface-inverse":"#f4f4f4","--color-accent-primary":"#4077e4","--color-accent-secondary":"#5ca3e0","--color-success":"#15803d","--color-warning":"#a16207","--color-error":"#b91c1c","--color-info":"#0369a1","--color-border":"#2d3443","--color-border-muted":"#1f242f","--color-interactive":"#2c5dcd","--color-interactive-hover":"#5485ed","--color-interactive-active":"#6693f5","--color-interactive-disabled":"#4a5568","--gradient-brand":"linear-gradient(to right, var(--color-brand), var(--color-brand-secondary))","--gradient-surface":"linear-gradient(to bottom, var(--color-surface-primary), var(--color-surface-secondary))"}},_m=E.createContext(void 0),Xv=({children:n})=>{const[e,t]=E.useState(()=>localStorage.getItem("theme")||"dark");return E.useEffect(()=>{const r=document.documentElement,o=Kv[e];Object.entries(o).forEach(([a,c])=>{r.style.setProperty(a,c)}),localStorage.setItem("theme",e)},[e]),y.jsx(_m.Provider,{value:{theme:e,setTheme:t},children:n})},Zv=()=>{const n=E.useContext(_m);if(n===void 0)throw new Error("useTheme must be used within a ThemeProvider");return n},Cm=()=>{const{theme:n,setTheme:e}=Zv();return y.jsxs("button",{onClick:()=>e(n==="light"?"dark":"light"),className:"w-full flex items-center justify-between p-0 bg-transparent border-none text-text-primary hover:text-accent-primary transition-colors duration-200 cursor-pointer","aria-label":`Switch to ${n==="light"?"dark":"light"} mode`,children:[y.jsx("span",{className:"text-sm font-medium mr-2",children:"Theme"}),y.jsxs("div",{className:"flex items-center gap-2",children:[y.jsx("span",{className:"text-xs text-text-secondary capitalize",children:n}),y.jsx("div",{className:"w-6 h-6 rounded-full bg-surface-tertiary flex items-center justify-center",children:n==="light"?y.jsx(Hv,{className:"w-4 h-4 text-accent-primary",strokeWidth:2}):y.jsx(Gv,{className:"w-4 h-4 text-accent-primary",strokeWidth:2})})]})]})};function je({kind:n="primary",size:e="md",children:t,className:r="",disabled:o=!1,...a}){const c="inline-flex items-center justify-center rounded-lg transition-all duration-200 focus:outline-none focus:ring-2 focus:ring-offset-2 disabled:opacity-50 disabled:cursor-not-allowed",u={sm:"px-2 py-1.5 text-sm font-normal",md:"px-3 py-2.5 text-base font-medium",lg:"px-4 py-3 text-lg font-semibold"},f={primary:"bg-brand hover:bg-brand-secondary text-text-primary focus:ring-brand",secondary:"bg-surface-secondary hover:bg-surface-tertiary text-text-primary border border-border-default focus:ring-brand",danger:"bg-error hover:bg-error/90 text-text-primary focus:ring-error",ghost:"bg-transparent hover:bg-surface-secondary text-text-primary focus:ring-brand"};return y.jsx("button",{className:`${c} ${u[e]} ${f[n]} ${r}`,disabled:o,...a,children:t})}class Qv{apiUrl;constructor(){const e="{%Apiurl%}";e.slice(2,-2).trim()==="Apiurl"?this.apiUrl="http://127.0.0.1:8080/adminapi":this.apiUrl=e+"/adminapi"}async fetchWithToken({url:e,options:t,token:r,toastHeader:o,setToast:a}){if(!r&&a){a({show:!0,success:!1,header:"Token is missing",text:"Try login and out again"});return}const c=new Headers(t.headers);c.append("Authorization","Token "+r);const u=await fetch(e,{...t,headers:c});if(!u.ok){a&&a({show:!0,success:!1,header:"Error outside go4lage",text:`${u.status}`});return}const f=u.headers.get("content-type");if(f&&f.includes("application/json")){const h=await u.json();if(h==null){a&&a({show:!0,success:!1,header:"Error",text:"Received empty response"});return}if(h?.error&&a){a({show:!0,success:!1,header:o||"Toastheader missing",text:`${h.detail} ${h.error}`});return}if((h?.text||h.header)&&a){console.log("inside correct toat"),a({show:!0,success:!0,header:o||"Toastheader missing",text:`${h.header} ${h.text}`});return}return h}return u}async dashboardinfo(){return await(await fetch(this.apiUrl+"/dashboardinfo",{method:"GET"})).json()}async allusers(e){return await this.fetchWithToken({url:`${this.apiUrl}/allusers`,options:{method:"GET"},token:e,toastHeader:null,setToast:null})||null}async logout(e){return await this.fetchWithToken({url:`${this.apiUrl}/logout`,options:{method:"GET"},token:e,toastHeader:null,setToast:null})}async editGroupPermissions(e,t,r,o){await this.fetchWithToken({url:`${this.apiUrl}/editgrouppermissions`,options:{method:"POST",headers:{"Content-Type":"application/json",Id:t},body:JSON.stringify(r)},token:e,toastHeader:"Edit Group Permissions",setToast:o})}async createoneuser(e,t,r){await this.fetchWithToken({url:`${this.apiUrl}/oneuser`,options:{method:"POST",headers:{"Content-Type":"application/json"},body:JSON.stringify(t)},token:e,toastHeader:"Create User",setToast:r})}async oneuser(e,t){return await this.fetchWithToken({url:`${this.apiUrl}/oneuser`,options:{method:"GET",headers:{Id:t}},token:e,toastHeader:null,setToast:null})}async editoneuserGroups(e,t,r,o){await this.fetchWithToken({url:`${this.apiUrl}/editusergroups`,options:{method:"POST",headers:{"Content-Type":"application/json",Id:t},body:JSON.stringify(r)},token:e,toastHeader:"Edit User",setToast:o})}async editoneuserPermissions(e,t,r,o){await this.fetchWithToken({url:`${this.apiUrl}/edituserpermissions`,options:{method:"POST",headers:{"Content-Type":"application/json",Id:t},body:JSON.stringify(r)},token:e,toastHeader:"Edit User",setToast:o})}async editoneuser(e,t,r,o){await this.fetchWithToken({url:`${this.apiUrl}/oneuser`,options:{method:"PUT",headers:{"Content-Type":"application/json",Id:t},body:JSON.stringify(r)},token:e,toastHeader:"Edit User",setToast:o})}async getGroups(e){return await this.fetchWithToken({url:`${this.apiUrl}/getgroups`,options:{method:"GET"},token:e,toastHeader:null,setToast:null})}async getBackups(e){return await this.fetchWithToken({url:`${this.apiUrl}/getbackups`,options:{method:"GET"},token:e,toastHeader:null,setToast:null})}async getLogs(e,t){return await this.fetchWithToken({url:`${this.apiUrl}/getlogs${t}`,options:{method:"GET"},token:e,toastHeader:null,setToast:null})}async getErrorLogs(e){return await this.fetchWithToken({url:`${this.apiUrl}/geterrorlogs`,options:{method:"GET"},token:e,toastHeader:null,setToast:null})}async createBackup(e,t){await this.fetchWithToken({url:`${this.apiUrl}/createbackup`,options:{method:"GET",headers:{"Content-Type":"application/json"}},token:e,toastHeader:"Create Backup",setToast:t})}async downloadBackup(e,t){return await(await this.fetchWithToken({url:`${this.apiUrl}/downloadbackup`,options:{method:"GET",headers:{Id:t}},token:e,toastHeader:null,setToast:null}))?.blob()||null}async deletebackup(e,t){return await this.fetchWithToken({url:`${this.apiUrl}/deletebackup`,options:{method:"DELETE",headers:{Id:t}},token:e,toastHeader:null,setToast:null}),null}async getPermissions(e){return await this.fetchWithToken({url:`${this.apiUrl}/getpermissions`,options:{method:"GET"},token:e,toastHeader:null,setToast:null})}async getGroupById(e,t){return await this.fetchWithToken({url:`${this.apiUrl}/getgroup`,options:{method:"GET",headers:{Id:t}},token:e,toastHeader:null,setToast:null})}async getPermissionById(e,t){return await this.fetchWithToken({url:`${this.apiUrl}/getpermission`,options:{method:"GET",headers:{Id:t}},token:e,toastHeader:null,setToast:null})}async getPermissionForGroup(e,t){return await this.fetchWithToken({url:`${this.apiUrl}/getpermissionsforgroup`,options:{method:"GET",headers:{Id:t}},token:e,toastHeader:null,setToast:null})}async login(e,t,r,o){const a=await fetch(this.apiUrl+"/login",{method:"POST",headers:{"Content-Type":"application/json"},body:JSON.stringify({email:e,password:t,tfa:r})});if(!a.ok)return o({show:!0,success:!1,header:"Login",text:"Login failed"}),null;const c=await a.json();return c.error?(o({show:!0,success:!1,header:"Login",text:`${c.detail} ${c.error}`}),null):(o({show:!0,success:!0,header:"Login",text:"Login successful!"}),c)}async deletePermission(e,t,r){await this.fetchWithToken({url:`${this.apiUrl}/deletepermission`,options:{method:"DELETE",headers:{Id:t}},token:e,toastHeader:"Delete Permission",setToast:r})}async deleteUser(e,t,r){await this.fetchWithToken({url:`${this.apiUrl}/deleteuser`,options:{method:"DELETE",headers:{Id:t}},token:e,toastHeader:"Delete User",setToast:r})}async createGroup(e,t,r){await this.fetchWithToken({url:`${this.apiUrl}/creategroup`,options:{method:"PUT",body:JSON.stringify({name:t})},token:e,toastHeader:"Create group",setToast:r})}async createPermission(e,t,r){await this.fetchWithToken({url:`${this.apiUrl}/createpermission`,options:{method:"PUT",body:JSON.stringify({name:t})},token:e,toastHeader:"Create Permission",setToast:r})}async deleteGroup(e,t,r){await this.fetchWithToken({url:`${this.apiUrl}/deletegroup`,options:{method:"DELETE",headers:{Id:t}},token:e,toastHeader:"Delete Group",setToast:r})}async downloadCSVtemplate(e){return await(await this.fetchWithToken({url:`${this.apiUrl}/downloadcsvtemplate`,options:{method:"GET"},token:e,toastHeader:null,setToast:null}))?.blob()||null}async bulkCreateUsers(e,t,r){const o=await this.fetchWithToken({url:`${this.apiUrl}/bulkcreateusers`,options:{method:"POST",body:t},token:e,toastHeader:"Bulk Create Users",setToast:r});r({show:!0,success:!0,header:"Bulk Create Users",text:"Users created successfully!"}),console.log("Users created successfully:",o)}async newfeedback(e,t,r){await this.fetchWithToken({url:`${this.apiUrl}/newfeedback`,options:{method:"POST",headers:{"Content-Type":"application/json"},body:JSON.stringify(t)},token:e,toastHeader:"Feedback send",setToast:r})}async getMsg(e,t){const r=t?"allfeedback":"getuserspecificfeedback";return await this.fetchWithToken({url:`${this.apiUrl}/${r}`,options:{method:"GET"},token:e,toastHeader:null,setToast:null})||[]}async updateFeedBack(e,t,r,o){const a=r?"updatefeedbackstaff":"updatefeedbackuser";return await this.fetchWithToken({url:`${this.apiUrl}/${a}`,options:{method:"POST",headers:{"Content-Type":"application/json"},body:JSON.stringify(t)},token:e,toastHeader:"Feedback send",setToast:o})}}const xe=new Qv;function Jv(){const{userData:n,setUserData:e}=E.useContext(Qe);async function t(){if(n.token)try{await xe.logout(n.token)}catch(r){console.log(r)}finally{e({email:null,token:null})}}return y.jsx(y.Fragment,{children:y.jsx(je,{onClick:t,kind:"secondary",children:"Logout"})})}const aa="data:image/svg+xml;base64,PD94bWwgdmVyc2lvbj0iMS4wIiBlbmNvZGluZz0iVVRGLTgiIHN0YW5kYWxvbmU9Im5vIj8+CjwhLS0gQ3JlYXRlZCB3aXRoIElua3NjYXBlIChodHRwOi8vd3d3Lmlua3NjYXBlLm9yZy8pIC0tPgoKPHN2ZwogICB3aWR0aD0iNjYuMjE1MjYzbW0iCiAgIGhlaWdodD0iNjIuNzQ2MzcybW0iCiAgIHZpZXdCb3g9IjAgMCA2Ni4yMTUyNjMgNjIuNzQ2MzcyIgogICB2ZXJzaW9uPSIxLjEiCiAgIGlkPSJzdmcxIgogICB4bWxucz0iaHR0cDovL3d3dy53My5vcmcvMjAwMC9zdmciCiAgIHhtbG5zOnN2Zz0iaHR0cDovL3d3dy53My5vcmcvMjAwMC9zdmciPgogIDxkZWZzCiAgICAgaWQ9ImRlZnMxIiAvPgogIDxnCiAgICAgaWQ9ImxheWVyMSIKICAgICB0cmFuc2Zvcm09InRyYW5zbGF0ZSgtNzYuOTUxNjMyLC02LjA3MTMwNDYpIj4KICAgIDx0ZXh0CiAgICAgICB4bWw6c3BhY2U9InByZXNlcnZlIgogICAgICAgc3R5bGU9ImZvbnQtc3R5bGU6bm9ybWFsO2ZvbnQtdmFyaWFudDpub3JtYWw7Zm9udC13ZWlnaHQ6NjAwO2ZvbnQtc3RyZXRjaDpub3JtYWw7Zm9udC1zaXplOjQ0LjUyMzJweDtmb250LWZhbWlseTpGcmVlU2FuczstaW5rc2NhcGUtZm9udC1zcGVjaWZpY2F0aW9uOidGcmVlU2FucywgU2VtaS1Cb2xkJztmb250LXZhcmlhbnQtbGlnYXR1cmVzOm5vcm1hbDtmb250LXZhcmlhbnQtY2Fwczpub3JtYWw7Zm9udC12YXJpYW50LW51bWVyaWM6bm9ybWFsO2ZvbnQtdmFyaWFudC1lYXN0LWFzaWFuOm5vcm1hbDtmaWxsOiNmZmZmZmY7ZmlsbC1vcGFjaXR5OjE7c3Ryb2tlOiMwMDAwMDA7c3Ryb2tlLXdpZHRoOjM7c3Ryb2tlLWRhc2hhcnJheTpub25lO3BhaW50LW9yZGVyOm1hcmtlcnMgc3Ryb2tlIGZpbGwiCiAgICAgICB4PSI3MC4yMTU0MzEiCiAgICAgICB5PSI0MS4zMzA4OTQiCiAgICAgICBpZD0idGV4dDEiCiAgICAgICB0cmFuc2Zvcm09InNjYWxlKDEuMDg5NjYxMSwwLjkxNzcxNjUzKSI+PHRzcGFuCiAgICAgICAgIHN0eWxlPSJmb250LXN0eWxlOm5vcm1hbDtmb250LXZhcmlhbnQ6bm9ybWFsO2ZvbnQtd2VpZ2h0OjYwMDtmb250LXN0cmV0Y2g6bm9ybWFsO2ZvbnQtc2l6ZTo0NC41MjMycHg7Zm9udC1mYW1pbHk6RnJlZVNhbnM7LWlua3NjYXBlLWZvbnQtc3BlY2lmaWNhdGlvbjonRnJlZVNhbnMsIFNlbWktQm9sZCc7Zm9udC12YXJpYW50LWxpZ2F0dXJlczpub3JtYWw7Zm9udC12YXJpYW50LWNhcHM6bm9ybWFsO2ZvbnQtdmFyaWFudC1udW1lcmljOm5vcm1hbDtmb250LXZhcmlhbnQtZWFzdC1hc2lhbjpub3JtYWw7ZmlsbDojZmZmZmZmO2ZpbGwtb3BhY2l0eToxO3N0cm9rZS13aWR0aDozO3N0cm9rZS1kYXNoYXJyYXk6bm9uZSIKICAgICAgICAgeD0iNzAuMjE1NDMxIgogICAgICAgICB5PSI0MS4zMzA4OTQiCiAgICAgICAgIGlkPSJ0c3BhbjIiPkdvPC90c3Bhbj48L3RleHQ+CiAgICA8dGV4dAogICAgICAgeG1sOnNwYWNlPSJwcmVzZXJ2ZSIKICAgICAgIHN0eWxlPSJmb250LXN0eWxlOml0YWxpYztmb250LXNpemU6MjUuNHB4O2ZvbnQtZmFtaWx5OkZyZWVTYW5zOy1pbmtzY2FwZS1mb250LXNwZWNpZmljYXRpb246J0ZyZWVTYW5zIEl0YWxpYyc7ZmlsbDojZmZmZmZmO2ZpbGwtb3BhY2l0eToxO3N0cm9rZTojMDAwMDAwO3N0cm9rZS13aWR0aDoyO3BhaW50LW9yZGVyOm1hcmtlcnMgc3Ryb2tlIGZpbGwiCiAgICAgICB4PSI4MC44MTc1ODEiCiAgICAgICB5PSI2Mi4yODA0NzYiCiAgICAgICBpZD0idGV4dDMiPjx0c3BhbgogICAgICAgICBpZD0idHNwYW4zIgogICAgICAgICBzdHlsZT0iZm9udC1zdHlsZTpub3JtYWw7Zm9udC12YXJpYW50Om5vcm1hbDtmb250LXdlaWdodDpub3JtYWw7Zm9udC1zdHJldGNoOm5vcm1hbDtmb250LXNpemU6MjUuNHB4O2ZvbnQtZmFtaWx5OkZyZWVTYW5zOy1pbmtzY2FwZS1mb250LXNwZWNpZmljYXRpb246J0ZyZWVTYW5zLCBOb3JtYWwnO2ZvbnQtdmFyaWFudC1saWdhdHVyZXM6bm9ybWFsO2ZvbnQtdmFyaWFudC1jYXBzOm5vcm1hbDtmb250LXZhcmlhbnQtbnVtZXJpYzpub3JtYWw7Zm9udC12YXJpYW50LWVhc3QtYXNpYW46bm9ybWFsO2ZpbGw6I2ZmZmZmZjtmaWxsLW9wYWNpdHk6MTtzdHJva2Utd2lkdGg6MiIKICAgICAgICAgeD0iODAuODE3NTgxIgogICAgICAgICB5PSI2Mi4yODA0NzYiPjRsYWdlPC90c3Bhbj48L3RleHQ+CiAgPC9nPgo8L3N2Zz4K";function yh(){const n=tn();return y.jsx("div",{className:"cursor-pointer flex items-center justify-center transition-transform hover:scale-105 active:scale-95 h-10 overflow-hidden",onClick:()=>n("/"),children:y.jsx("img",{src:aa,alt:"Go4lage Logo",className:"h-full w-auto max-w-20 object-contain",style:{maxHeight:"40px"}})})}const qv=(n,e,t,r)=>{const o=[t,{code:e,...r||{}}];if(n?.services?.logger?.forward)return n.services.logger.forward(o,"warn","react-i18next::",!0);ls(o[0])&&(o[0]=`react-i18next:: ${o[0]}`),n?.services?.logger?.warn?n.services.logger.warn(...o):console?.warn&&console.warn(...o)},xh={},jc=(n,e,t,r)=>{ls(t)&&xh[t]||(ls(t)&&(xh[t]=new Date),qv(n,e,t,r))},Nm=(n,e)=>()=>{if(n.isInitialized)e();else{const t=()=>{setTimeout(()=>{n.off("initialized",t)},0),e()};n.on("initialized",t)}},Pc=(n,e,t)=>{n.loadNamespaces(e,Nm(n,t))},vh=(n,e,t,r)=>{if(ls(t)&&(t=[t]),n.options.preload&&n.options.preload.indexOf(e)>-1)return Pc(n,t,r);t.forEach(o=>{n.options.ns.indexOf(o)<0&&n.options.ns.push(o)}),n.loadLanguages(e,Nm(n,r))},e0=(n,e,t={})=>!e.languages||!e.languages.length?(jc(e,"NO_LANGUAGES","i18n.languages were undefined or empty",{languages:e.languages}),!0):e.hasLoadedNamespace(n,{lng:t.lng,precheck:(r,o)=>{if(t.bindI18n?.indexOf("languageChanging")>-1&&r.services.backendConnector.backend&&r.isLanguageChangingTo&&!o(r.isLanguageChangingTo,n))return!1}}),ls=n=>typeof n=="string",t0=n=>typeof n=="object"&&n!==null,n0=/&(?:amp|#38|lt|#60|gt|#62|apos|#39|quot|#34|nbsp|#160|copy|#169|reg|#174|hellip|#8230|#x2F|#47);/g,s0={"&amp;":"&","&#38;":"&","&lt;":"<","&#60;":"<","&gt;":">","&#62;":">","&apos;":"'","&#39;":"'","&quot;":'"',"&#34;":'"',"&nbsp;":" ","&#160;":" ","&copy;":"©","&#169;":"©","&reg;":"®","&#174;":"®","&hellip;":"…","&#8230;":"…","&#x2F;":"/","&#47;":"/"},r0=n=>s0[n],i0=n=>n.replace(n0,r0);let Ec={bindI18n:"languageChanged",bindI18nStore:"",transEmptyNodeValue:"",transSupportBasicHtmlNodes:!0,transWrapTextNodes:"",transKeepBasicHtmlNodesFor:["br","strong","i","p"],useSuspense:!0,unescape:i0};const o0=(n={})=>{Ec={...Ec,...n}},a0=()=>Ec;let jm;const l0=n=>{jm=n},c0=()=>jm,u0={type:"3rdParty",init(n){o0(n.options.react),l0(n)}},d0=E.createContext();class f0{constructor(){this.usedNamespaces={}}addUsedNamespaces(e){e.forEach(t=>{this.usedNamespaces[t]||(this.usedNamespaces[t]=!0)})}getUsedNamespaces(){return Object.keys(this.usedNamespaces)}}const h0=(n,e)=>{const t=E.useRef();return E.useEffect(()=>{t.current=n},[n,e]),t.current},Pm=(n,e,t,r)=>n.getFixedT(e,t,r),p0=(n,e,t,r)=>E.useCallback(Pm(n,e,t,r),[n,e,t,r]),la=(n,e={})=>{const{i18n:t}=e,{i18n:r,defaultNS:o}=E.useContext(d0)||{},a=t||r||c0();if(a&&!a.reportNamespaces&&(a.reportNamespaces=new f0),!a){jc(a,"NO_I18NEXT_INSTANCE","useTranslation: You will need to pass in an i18next instance by using initReactI18next");const B=($,D)=>ls(D)?D:t0(D)&&ls(D.defaultValue)?D.defaultValue:Array.isArray($)?$[$.length-1]:$,M=[B,{},!1];return M.t=B,M.i18n={},M.ready=!1,M}a.options.react?.wait&&jc(a,"DEPRECATED_OPTION","useTranslation: It seems you are still using the old wait option, you may migrate to the new useSuspense behaviour.");const c={...a0(),...a.options.react,...e},{useSuspense:u,keyPrefix:f}=c;let h=o||a.options?.defaultNS;h=ls(h)?[h]:h||["translation"],a.reportNamespaces.addUsedNamespaces?.(h);const g=(a.isInitialized||a.initializedStoreOnce)&&h.every(B=>e0(B,a,c)),x=p0(a,e.lng||null,c.nsMode==="fallback"?h:h[0],f),w=()=>x,b=()=>Pm(a,e.lng||null,c.nsMode==="fallback"?h:h[0],f),[S,_]=E.useState(w);let k=h.join();e.lng&&(k=`${e.lng}${k}`);const O=h0(k),R=E.useRef(!0);E.useEffect(()=>{const{bindI18n:B,bindI18nStore:M}=c;R.current=!0,!g&&!u&&(e.lng?vh(a,e.lng,h,()=>{R.current&&_(b)}):Pc(a,h,()=>{R.current&&_(b)})),g&&O&&O!==k&&R.current&&_(b);const $=()=>{R.current&&_(b)};return B&&a?.on(B,$),M&&a?.store.on(M,$),()=>{R.current=!1,a&&B?.split(" ").forEach(D=>a.off(D,$)),M&&a&&M.split(" ").forEach(D=>a.store.off(D,$))}},[a,k]),E.useEffect(()=>{R.current&&g&&_(w)},[a,f,g]);const A=[S,a,g];if(A.t=S,A.i18n=a,A.ready=g,g||!g&&!u)return A;throw new Promise(B=>{e.lng?vh(a,e.lng,h,()=>B()):Pc(a,h,()=>B())})},m0=({className:n})=>{const{i18n:e}=la(),t=[{code:"en",name:"English",flag:"🇺🇸"},{code:"de",name:"Deutsch",flag:"🇩🇪"}],r=o=>{const a=o.target.value;e.changeLanguage(a)};return y.jsxs("div",{className:`w-full relative ${n}`,children:[y.jsx("select",{value:e.language,onChange:r,className:"w-full appearance-none bg-surface-tertiary border border-border-default rounded-lg px-3 py-2 pr-8 text-sm text-text-primary focus:outline-none focus:ring-2 focus:ring-accent-primary focus:border-transparent cursor-pointer hover:bg-surface-secondary transition-colors",children:t.map(o=>y.jsxs("option",{value:o.code,className:"bg-surface-primary text-text-primary",children:[o.flag," ",o.name]},o.code))}),y.jsx("div",{className:"absolute right-2 top-1/2 -translate-y-1/2 pointer-events-none",children:y.jsx("svg",{className:"w-4 h-4 text-text-secondary",fill:"none",stroke:"currentColor",viewBox:"0 0 24 24",children:y.jsx("path",{strokeLinecap:"round",strokeLinejoin:"round",strokeWidth:2,d:"M19 9l-7 7-7-7"})})})]})},g0=({isSidebarExpanded:n,setIsMobileOpen:e})=>{const{userData:t,setToast:r}=E.useContext(Qe),o=tn(),[a,c]=E.useState(!1),[u,f]=E.useState(!1),[h,g]=E.useState(!1),[x,w]=E.useState(""),[b,S]=E.useState(""),_=E.useRef(null),k=E.useRef(null),{t:O}=la();E.useEffect(()=>{function $(D){_.current&&!_.current.contains(D.target)&&c(!1),k.current&&!k.current.contains(D.target)&&(f(!1),g(!1),w(""),S(""))}return document.addEventListener("mousedown",$),()=>document.removeEventListener("mousedown",$)},[]);const R=()=>{if(!x.trim()||!b.trim()){r({show:!0,success:!1,header:"Validation Error",text:"Please fill in both behavior fields before sending."});return}r({show:!0,success:!0,header:"Message Sent",text:"Your feedback message has been sent successfully!"}),w(""),S(""),g(!1),f(!1);async function $(){try{xe.newfeedback(t.token,{behaviour_is:x,behaviour_should:b,full_url:window.location.toString(),chat:null,id:"",is_solved:!1,created_at:"",updated_at:""},r)}catch(D){console.log(D)}}$()},A=()=>{f(!1),o("/mymessages")},B=()=>{g(!0)},M=()=>{g(!1),w(""),S("")};return y.jsx("header",{className:`
->>>>>>> 3a234504ea1fb5b289a516755099f4c562f10bb3
+ */const Fa=Ye("X",[["path",{d:"M18 6 6 18",key:"1bl5f8"}],["path",{d:"m6 6 12 12",key:"d8bk6v"}]]),Tm={light:{"--color-brand":"#3c8dcf","--color-brand-secondary":"#3b93d1","--color-text-primary":"#333","--color-text-secondary":"#2c3e50","--color-text-muted":"#667788","--color-text-inverse":"#ffffff","--color-surface-primary":"#e4e4e4","--color-surface-secondary":"#d8d8d8","--color-surface-tertiary":"#dddddd","--color-surface-inverse":"#2c3e50","--color-accent-primary":"#3c8dcf","--color-accent-secondary":"#3b93d1","--color-success":"#16a34a","--color-warning":"#ca8a04","--color-error":"#dc2626","--color-info":"#3b93d1","--color-border":"#dddddd","--color-border-muted":"#e8e8e8","--color-interactive":"#4f7fff","--color-interactive-hover":"#2449a3","--color-interactive-active":"#1c3879","--color-interactive-disabled":"#a3b3c6","--gradient-brand":"linear-gradient(to right, var(--color-brand), var(--color-brand-secondary))","--gradient-surface":"linear-gradient(to bottom, var(--color-surface-primary), var(--color-surface-secondary))"},dark:{"--color-brand":"#2c5dcd","--color-brand-secondary":"#3b93d1","--color-text-primary":"#f4f4f4","--color-text-secondary":"#d1d8e0","--color-text-muted":"#9ba9b9","--color-text-inverse":"#2c3e50","--color-surface-primary":"#1a1f2c","--color-surface-secondary":"#141922","--color-surface-tertiary":"#252d3b","--color-surface-inverse":"#f4f4f4","--color-accent-primary":"#4077e4","--color-accent-secondary":"#5ca3e0","--color-success":"#15803d","--color-warning":"#a16207","--color-error":"#b91c1c","--color-info":"#0369a1","--color-border":"#2d3443","--color-border-muted":"#1f242f","--color-interactive":"#2c5dcd","--color-interactive-hover":"#5485ed","--color-interactive-active":"#6693f5","--color-interactive-disabled":"#4a5568","--gradient-brand":"linear-gradient(to right, var(--color-brand), var(--color-brand-secondary))","--gradient-surface":"linear-gradient(to bottom, var(--color-surface-primary), var(--color-surface-secondary))"}},Gd=C.createContext(void 0),_m=({children:o})=>{const[r,s]=C.useState(()=>localStorage.getItem("theme")||"dark");return C.useEffect(()=>{const a=document.documentElement,u=Tm[r];Object.entries(u).forEach(([d,h])=>{a.style.setProperty(d,h)}),localStorage.setItem("theme",r)},[r]),f.jsx(Gd.Provider,{value:{theme:r,setTheme:s},children:o})},Im=()=>{const o=C.useContext(Gd);if(o===void 0)throw new Error("useTheme must be used within a ThemeProvider");return o},Zd=()=>{const{theme:o,setTheme:r}=Im();return f.jsxs("button",{onClick:()=>r(o==="light"?"dark":"light"),className:"w-full flex items-center justify-between p-0 bg-transparent border-none text-text-primary hover:text-accent-primary transition-colors duration-200 cursor-pointer","aria-label":`Switch to ${o==="light"?"dark":"light"} mode`,children:[f.jsx("span",{className:"text-sm font-medium mr-2",children:"Theme"}),f.jsxs("div",{className:"flex items-center gap-2",children:[f.jsx("span",{className:"text-xs text-text-secondary capitalize",children:o}),f.jsx("div",{className:"w-6 h-6 rounded-full bg-surface-tertiary flex items-center justify-center",children:o==="light"?f.jsx(Om,{className:"w-4 h-4 text-accent-primary",strokeWidth:2}):f.jsx(zm,{className:"w-4 h-4 text-accent-primary",strokeWidth:2})})]})]})},Dm=(o,r,s,a)=>{const u=[s,{code:r,...a||{}}];if(o?.services?.logger?.forward)return o.services.logger.forward(u,"warn","react-i18next::",!0);bn(u[0])&&(u[0]=`react-i18next:: ${u[0]}`),o?.services?.logger?.warn?o.services.logger.warn(...u):console?.warn&&console.warn(...u)},Jc={},za=(o,r,s,a)=>{bn(s)&&Jc[s]||(bn(s)&&(Jc[s]=new Date),Dm(o,r,s,a))},Qd=(o,r)=>()=>{if(o.isInitialized)r();else{const s=()=>{setTimeout(()=>{o.off("initialized",s)},0),r()};o.on("initialized",s)}},Ta=(o,r,s)=>{o.loadNamespaces(r,Qd(o,s))},qc=(o,r,s,a)=>{if(bn(s)&&(s=[s]),o.options.preload&&o.options.preload.indexOf(r)>-1)return Ta(o,s,a);s.forEach(u=>{o.options.ns.indexOf(u)<0&&o.options.ns.push(u)}),o.loadLanguages(r,Qd(o,a))},Mm=(o,r,s={})=>!r.languages||!r.languages.length?(za(r,"NO_LANGUAGES","i18n.languages were undefined or empty",{languages:r.languages}),!0):r.hasLoadedNamespace(o,{lng:s.lng,precheck:(a,u)=>{if(s.bindI18n?.indexOf("languageChanging")>-1&&a.services.backendConnector.backend&&a.isLanguageChangingTo&&!u(a.isLanguageChangingTo,o))return!1}}),bn=o=>typeof o=="string",Rm=o=>typeof o=="object"&&o!==null,Fm=/&(?:amp|#38|lt|#60|gt|#62|apos|#39|quot|#34|nbsp|#160|copy|#169|reg|#174|hellip|#8230|#x2F|#47);/g,$m={"&amp;":"&","&#38;":"&","&lt;":"<","&#60;":"<","&gt;":">","&#62;":">","&apos;":"'","&#39;":"'","&quot;":'"',"&#34;":'"',"&nbsp;":" ","&#160;":" ","&copy;":"©","&#169;":"©","&reg;":"®","&#174;":"®","&hellip;":"…","&#8230;":"…","&#x2F;":"/","&#47;":"/"},Am=o=>$m[o],Um=o=>o.replace(Fm,Am);let _a={bindI18n:"languageChanged",bindI18nStore:"",transEmptyNodeValue:"",transSupportBasicHtmlNodes:!0,transWrapTextNodes:"",transKeepBasicHtmlNodesFor:["br","strong","i","p"],useSuspense:!0,unescape:Um};const Wm=(o={})=>{_a={..._a,...o}},Bm=()=>_a;let Kd;const Hm=o=>{Kd=o},Vm=()=>Kd,Ym={type:"3rdParty",init(o){Wm(o.options.react),Hm(o)}},Gm=C.createContext();class Zm{constructor(){this.usedNamespaces={}}addUsedNamespaces(r){r.forEach(s=>{this.usedNamespaces[s]||(this.usedNamespaces[s]=!0)})}getUsedNamespaces(){return Object.keys(this.usedNamespaces)}}const Qm=(o,r)=>{const s=C.useRef();return C.useEffect(()=>{s.current=o},[o,r]),s.current},Xd=(o,r,s,a)=>o.getFixedT(r,s,a),Km=(o,r,s,a)=>C.useCallback(Xd(o,r,s,a),[o,r,s,a]),Ae=(o,r={})=>{const{i18n:s}=r,{i18n:a,defaultNS:u}=C.useContext(Gm)||{},d=s||a||Vm();if(d&&!d.reportNamespaces&&(d.reportNamespaces=new Zm),!d){za(d,"NO_I18NEXT_INSTANCE","useTranslation: You will need to pass in an i18next instance by using initReactI18next");const Q=(J,ee)=>bn(ee)?ee:Rm(ee)&&bn(ee.defaultValue)?ee.defaultValue:Array.isArray(J)?J[J.length-1]:J,U=[Q,{},!1];return U.t=Q,U.i18n={},U.ready=!1,U}d.options.react?.wait&&za(d,"DEPRECATED_OPTION","useTranslation: It seems you are still using the old wait option, you may migrate to the new useSuspense behaviour.");const h={...Bm(),...d.options.react,...r},{useSuspense:m,keyPrefix:g}=h;let y=u||d.options?.defaultNS;y=bn(y)?[y]:y||["translation"],d.reportNamespaces.addUsedNamespaces?.(y);const k=(d.isInitialized||d.initializedStoreOnce)&&y.every(Q=>Mm(Q,d,h)),x=Km(d,r.lng||null,h.nsMode==="fallback"?y:y[0],g),L=()=>x,I=()=>Xd(d,r.lng||null,h.nsMode==="fallback"?y:y[0],g),[E,z]=C.useState(L);let T=y.join();r.lng&&(T=`${r.lng}${T}`);const A=Qm(T),H=C.useRef(!0);C.useEffect(()=>{const{bindI18n:Q,bindI18nStore:U}=h;H.current=!0,!k&&!m&&(r.lng?qc(d,r.lng,y,()=>{H.current&&z(I)}):Ta(d,y,()=>{H.current&&z(I)})),k&&A&&A!==T&&H.current&&z(I);const J=()=>{H.current&&z(I)};return Q&&d?.on(Q,J),U&&d?.store.on(U,J),()=>{H.current=!1,d&&Q?.split(" ").forEach(ee=>d.off(ee,J)),U&&d&&U.split(" ").forEach(ee=>d.store.off(ee,J))}},[d,T]),C.useEffect(()=>{H.current&&k&&z(L)},[d,g,k]);const Y=[E,d,k];if(Y.t=E,Y.i18n=d,Y.ready=k,k||!k&&!m)return Y;throw new Promise(Q=>{r.lng?qc(d,r.lng,y,()=>Q()):Ta(d,y,()=>Q())})};function Fe({kind:o="primary",size:r="md",children:s,className:a="",disabled:u=!1,...d}){const h="inline-flex items-center justify-center rounded-lg transition-all duration-200 focus:outline-none focus:ring-2 focus:ring-offset-2 disabled:opacity-50 disabled:cursor-not-allowed",m={sm:"px-2 py-1.5 text-sm font-normal",md:"px-3 py-2.5 text-base font-medium",lg:"px-4 py-3 text-lg font-semibold"},g={primary:"bg-brand hover:bg-brand-secondary text-text-primary focus:ring-brand",secondary:"bg-surface-secondary hover:bg-surface-tertiary text-text-primary border border-border-default focus:ring-brand",danger:"bg-error hover:bg-error/90 text-text-primary focus:ring-error",ghost:"bg-transparent hover:bg-surface-secondary text-text-primary focus:ring-brand"};return f.jsx("button",{className:`${h} ${m[r]} ${g[o]} ${a}`,disabled:u,...d,children:s})}async function Zq1(o){const r=o.headers.get("content-type");if(!r||!r.includes("application/json"))return null;try{return await o.json()}catch{return null}}function Zq2(o){return o.error?`${o.detail} ${o.error}`:o.detail}class Xm{apiUrl;onSessionRefresh=null;refreshing=null;constructor(){const r="{%Apiurl%}";r.slice(2,-2).trim()==="Apiurl"?this.apiUrl="http://127.0.0.1:8080/adminapi":this.apiUrl=r+"/adminapi"}async fetchWithToken({url:r,options:s,token:a,toastHeader:u,setToast:d}){if(!a&&d){d({show:!0,success:!1,header:"Token is missing",text:"Try login and out again"});return}const h=new Headers(s.headers);h.set("Authorization","Token "+a);let m=await fetch(r,{...s,headers:h});if(m.status===401&&a){const y=await this.refreshToken(a);y&&(h.set("Authorization","Token "+y),m=await fetch(r,{...s,headers:h}))}if(!m.ok){if(d){const y=await Zq1(m);d({show:!0,success:!1,header:y?u||"Toastheader missing":"Error outside go4lage",text:y?Zq2(y):`${m.status}`})}return}const g=m.headers.get("content-type");if(g&&g.includes("application/json")){const y=await m.json();if(y==null){d&&d({show:!0,success:!1,header:"Error",text:"Received empty response"});return}if(y?.error&&d){d({show:!0,success:!1,header:u||"Toastheader missing",text:`${y.detail} ${y.error}`});return}if((y?.text||y.header)&&d){console.log("inside correct toat"),d({show:!0,success:!0,header:u||"Toastheader missing",text:`${y.header} ${y.text}`});return}return y}return m}async refreshToken(r){const s=sessionStorage.getItem("userData");if(!s)return null;const a=JSON.parse(s);return a.token&&a.token!==r?a.token:a.refresh_token?(this.refreshing||(this.refreshing=this.refreshSession(a).finally(()=>{this.refreshing=null})),this.refreshing):null}async refreshSession(r){try{const s=await fetch(this.apiUrl+"/refresh",{method:"POST",headers:{"Content-Type":"application/json"},body:JSON.stringify({refresh_token:r.refresh_token})});if(!s.ok)return null;const a=await s.json();return sessionStorage.setItem("userData",JSON.stringify({...r,token:a.token,refresh_token:a.refresh_token})),this.onSessionRefresh&&this.onSessionRefresh(a.token,a.refresh_token),a.token}catch{return null}}async dashboardinfo(){return await(await fetch(this.apiUrl+"/dashboardinfo",{method:"GET"})).json()}async allusers(r){const s=[];for(let a=1;;a++){const u=await this.fetchWithToken({url:`${this.apiUrl}/allusers?page=${a}&page_size=200`,options:{method:"GET"},token:r,toastHeader:null,setToast:null});if(!u)return null;if(s.push(...u.users),u.users.length===0||s.length>=u.total)return s}}async logout(r){return await this.fetchWithToken({url:`${this.apiUrl}/logout`,options:{method:"GET"},token:r,toastHeader:null,setToast:null})}async editGroupPermissions(r,s,a,u){await this.fetchWithToken({url:`${this.apiUrl}/editgrouppermissions`,options:{method:"POST",headers:{"Content-Type":"application/json",Id:s},body:JSON.stringify(a)},token:r,toastHeader:"Edit Group Permissions",setToast:u})}async createoneuser(r,s,a){await this.fetchWithToken({url:`${this.apiUrl}/oneuser`,options:{method:"POST",headers:{"Content-Type":"application/json"},body:JSON.stringify(s)},token:r,toastHeader:"Create User",setToast:a})}async oneuser(r,s){return await this.fetchWithToken({url:`${this.apiUrl}/oneuser`,options:{method:"GET",headers:{Id:s}},token:r,toastHeader:null,setToast:null})}async editoneuserGroups(r,s,a,u){await this.fetchWithToken({url:`${this.apiUrl}/editusergroups`,options:{method:"POST",headers:{"Content-Type":"application/json",Id:s},body:JSON.stringify(a)},token:r,toastHeader:"Edit User",setToast:u})}async editoneuserPermissions(r,s,a,u){await this.fetchWithToken({url:`${this.apiUrl}/edituserpermissions`,options:{method:"POST",headers:{"Content-Type":"application/json",Id:s},body:JSON.stringify(a)},token:r,toastHeader:"Edit User",setToast:u})}async editoneuser(r,s,a,u){await this.fetchWithToken({url:`${this.apiUrl}/oneuser`,options:{method:"PUT",headers:{"Content-Type":"application/json",Id:s},body:JSON.stringify(a)},token:r,toastHeader:"Edit User",setToast:u})}async getGroups(r){return await this.fetchWithToken({url:`${this.apiUrl}/getgroups`,options:{method:"GET"},token:r,toastHeader:null,setToast:null})}async getBackups(r){return await this.fetchWithToken({url:`${this.apiUrl}/getbackups`,options:{method:"GET"},token:r,toastHeader:null,setToast:null})}async getLogs(r,s){return await this.fetchWithToken({url:`${this.apiUrl}/getlogs${s}`,options:{method:"GET"},token:r,toastHeader:null,setToast:null})}async getErrorLogs(r){return await this.fetchWithToken({url:`${this.apiUrl}/geterrorlogs`,options:{method:"GET"},token:r,toastHeader:null,setToast:null})}async createBackup(r,s){await this.fetchWithToken({url:`${this.apiUrl}/createbackup`,options:{method:"GET",headers:{"Content-Type":"application/json"}},token:r,toastHeader:"Create Backup",setToast:s})}async downloadBackup(r,s){return await(await this.fetchWithToken({url:`${this.apiUrl}/downloadbackup`,options:{method:"GET",headers:{Id:s}},token:r,toastHeader:null,setToast:null}))?.blob()||null}async deletebackup(r,s){return await this.fetchWithToken({url:`${this.apiUrl}/deletebackup`,options:{method:"DELETE",headers:{Id:s}},token:r,toastHeader:null,setToast:null}),null}async getPermissions(r){return await this.fetchWithToken({url:`${this.apiUrl}/getpermissions`,options:{method:"GET"},token:r,toastHeader:null,setToast:null})}async getGroupById(r,s){return await this.fetchWithToken({url:`${this.apiUrl}/getgroup`,options:{method:"GET",headers:{Id:s}},token:r,toastHeader:null,setToast:null})}async getPermissionById(r,s){return await this.fetchWithToken({url:`${this.apiUrl}/getpermission`,options:{method:"GET",headers:{Id:s}},token:r,toastHeader:null,setToast:null})}async getPermissionForGroup(r,s){return await this.fetchWithToken({url:`${this.apiUrl}/getpermissionsforgroup`,options:{method:"GET",headers:{Id:s}},token:r,toastHeader:null,setToast:null})}async login(r,s,a,u){const d=await fetch(this.apiUrl+"/login",{method:"POST",headers:{"Content-Type":"application/json"},body:JSON.stringify({email:r,password:s,twofakey:a})});if(!d.ok){const g=await Zq1(d);return u({show:!0,success:!1,header:"Login",text:g?Zq2(g):"Login failed"}),null}const h=await d.json();return h.error?(u({show:!0,success:!1,header:"Login",text:`${h.detail} ${h.error}`}),null):h.twofa_required?h:(u({show:!0,success:!0,header:"Login",text:"Login successful!"}),h)}async deletePermission(r,s,a){await this.fetchWithToken({url:`${this.apiUrl}/deletepermission`,options:{method:"DELETE",headers:{Id:s}},token:r,toastHeader:"Delete Permission",setToast:a})}async deleteUser(r,s,a){await this.fetchWithToken({url:`${this.apiUrl}/deleteuser`,options:{method:"DELETE",headers:{Id:s}},token:r,toastHeader:"Delete User",setToast:a})}async createGroup(r,s,a){await this.fetchWithToken({url:`${this.apiUrl}/creategroup`,options:{method:"PUT",body:JSON.stringify({name:s})},token:r,toastHeader:"Create group",setToast:a})}async createPermission(r,s,a){await this.fetchWithToken({url:`${this.apiUrl}/createpermission`,options:{method:"PUT",body:JSON.stringify({name:s})},token:r,toastHeader:"Create Permission",setToast:a})}async deleteGroup(r,s,a){await this.fetchWithToken({url:`${this.apiUrl}/deletegroup`,options:{method:"DELETE",headers:{Id:s}},token:r,toastHeader:"Delete Group",setToast:a})}async downloadCSVtemplate(r){return await(await this.fetchWithToken({url:`${this.apiUrl}/downloadcsvtemplate`,options:{method:"GET"},token:r,toastHeader:null,setToast:null}))?.blob()||null}async bulkCreateUsers(r,s,a){const u=await this.fetchWithToken({url:`${this.apiUrl}/bulkcreateusers`,options:{method:"POST",body:s},token:r,toastHeader:"Bulk Create Users",setToast:a});a({show:!0,success:!0,header:"Bulk Create Users",text:"Users created successfully!"}),console.log("Users created successfully:",u)}async newfeedback(r,s,a){await this.fetchWithToken({url:`${this.apiUrl}/newfeedback`,options:{method:"POST",headers:{"Content-Type":"application/json"},body:JSON.stringify(s)},token:r,toastHeader:"Feedback send",setToast:a})}async getMsg(r,s){const a=s?"allfeedback":"getuserspecificfeedback";return await this.fetchWithToken({url:`${this.apiUrl}/${a}`,options:{method:"GET"},token:r,toastHeader:null,setToast:null})||[]}async updateFeedBack(r,s,a,u){const d=a?"updatefeedbackstaff":"updatefeedbackuser";return await this.fetchWithToken({url:`${this.apiUrl}/${d}`,options:{method:"POST",headers:{"Content-Type":"application/json"},body:JSON.stringify(s)},token:r,toastHeader:"Feedback send",setToast:u})}async createOrganization(r,s,a){await this.fetchWithToken({url:`${this.apiUrl}/createorganization`,options:{method:"POST",body:JSON.stringify(s)},token:r,toastHeader:"Create Organization",setToast:a})}async editOneOrganization(r,s,a,u){await this.fetchWithToken({url:`${this.apiUrl}/editoneorganization`,options:{method:"PUT",headers:{"Content-Type":"application/json",Id:s},body:JSON.stringify(a)},token:r,toastHeader:"Edit Organization",setToast:u})}async deleteOrganization(r,s,a){await this.fetchWithToken({url:`${this.apiUrl}/deleteorganization`,options:{method:"DELETE",headers:{Id:s}},token:r,toastHeader:"Delete Organization",setToast:a})}async allOrganizations(r){return await this.fetchWithToken({url:`${this.apiUrl}/allorganizations`,options:{method:"GET"},token:r,toastHeader:null,setToast:null})}async oneOrganization(r,s){return await this.fetchWithToken({url:`${this.apiUrl}/oneorganization`,options:{method:"GET",headers:{Id:s}},token:r,toastHeader:null,setToast:null})}}const Pe=new Xm;function Jm(){const{t:o}=Ae(),{userData:r,setUserData:s}=C.useContext(et);async function a(){if(r.token)try{await Pe.logout(r.token)}catch(u){console.log(u)}finally{s({email:null,token:null,is_organizationadmin:!1,is_superuser:!1})}}return f.jsx(f.Fragment,{children:f.jsx(Fe,{onClick:a,kind:"secondary",children:o("Logout")})})}const $a="data:image/svg+xml;base64,PD94bWwgdmVyc2lvbj0iMS4wIiBlbmNvZGluZz0iVVRGLTgiIHN0YW5kYWxvbmU9Im5vIj8+CjwhLS0gQ3JlYXRlZCB3aXRoIElua3NjYXBlIChodHRwOi8vd3d3Lmlua3NjYXBlLm9yZy8pIC0tPgoKPHN2ZwogICB3aWR0aD0iNjYuMjE1MjYzbW0iCiAgIGhlaWdodD0iNjIuNzQ2MzcybW0iCiAgIHZpZXdCb3g9IjAgMCA2Ni4yMTUyNjMgNjIuNzQ2MzcyIgogICB2ZXJzaW9uPSIxLjEiCiAgIGlkPSJzdmcxIgogICB4bWxucz0iaHR0cDovL3d3dy53My5vcmcvMjAwMC9zdmciCiAgIHhtbG5zOnN2Zz0iaHR0cDovL3d3dy53My5vcmcvMjAwMC9zdmciPgogIDxkZWZzCiAgICAgaWQ9ImRlZnMxIiAvPgogIDxnCiAgICAgaWQ9ImxheWVyMSIKICAgICB0cmFuc2Zvcm09InRyYW5zbGF0ZSgtNzYuOTUxNjMyLC02LjA3MTMwNDYpIj4KICAgIDx0ZXh0CiAgICAgICB4bWw6c3BhY2U9InByZXNlcnZlIgogICAgICAgc3R5bGU9ImZvbnQtc3R5bGU6bm9ybWFsO2ZvbnQtdmFyaWFudDpub3JtYWw7Zm9udC13ZWlnaHQ6NjAwO2ZvbnQtc3RyZXRjaDpub3JtYWw7Zm9udC1zaXplOjQ0LjUyMzJweDtmb250LWZhbWlseTpGcmVlU2FuczstaW5rc2NhcGUtZm9udC1zcGVjaWZpY2F0aW9uOidGcmVlU2FucywgU2VtaS1Cb2xkJztmb250LXZhcmlhbnQtbGlnYXR1cmVzOm5vcm1hbDtmb250LXZhcmlhbnQtY2Fwczpub3JtYWw7Zm9udC12YXJpYW50LW51bWVyaWM6bm9ybWFsO2ZvbnQtdmFyaWFudC1lYXN0LWFzaWFuOm5vcm1hbDtmaWxsOiNmZmZmZmY7ZmlsbC1vcGFjaXR5OjE7c3Ryb2tlOiMwMDAwMDA7c3Ryb2tlLXdpZHRoOjM7c3Ryb2tlLWRhc2hhcnJheTpub25lO3BhaW50LW9yZGVyOm1hcmtlcnMgc3Ryb2tlIGZpbGwiCiAgICAgICB4PSI3MC4yMTU0MzEiCiAgICAgICB5PSI0MS4zMzA4OTQiCiAgICAgICBpZD0idGV4dDEiCiAgICAgICB0cmFuc2Zvcm09InNjYWxlKDEuMDg5NjYxMSwwLjkxNzcxNjUzKSI+PHRzcGFuCiAgICAgICAgIHN0eWxlPSJmb250LXN0eWxlOm5vcm1hbDtmb250LXZhcmlhbnQ6bm9ybWFsO2ZvbnQtd2VpZ2h0OjYwMDtmb250LXN0cmV0Y2g6bm9ybWFsO2ZvbnQtc2l6ZTo0NC41MjMycHg7Zm9udC1mYW1pbHk6RnJlZVNhbnM7LWlua3NjYXBlLWZvbnQtc3BlY2lmaWNhdGlvbjonRnJlZVNhbnMsIFNlbWktQm9sZCc7Zm9udC12YXJpYW50LWxpZ2F0dXJlczpub3JtYWw7Zm9udC12YXJpYW50LWNhcHM6bm9ybWFsO2ZvbnQtdmFyaWFudC1udW1lcmljOm5vcm1hbDtmb250LXZhcmlhbnQtZWFzdC1hc2lhbjpub3JtYWw7ZmlsbDojZmZmZmZmO2ZpbGwtb3BhY2l0eToxO3N0cm9rZS13aWR0aDozO3N0cm9rZS1kYXNoYXJyYXk6bm9uZSIKICAgICAgICAgeD0iNzAuMjE1NDMxIgogICAgICAgICB5PSI0MS4zMzA4OTQiCiAgICAgICAgIGlkPSJ0c3BhbjIiPkdvPC90c3Bhbj48L3RleHQ+CiAgICA8dGV4dAogICAgICAgeG1sOnNwYWNlPSJwcmVzZXJ2ZSIKICAgICAgIHN0eWxlPSJmb250LXN0eWxlOml0YWxpYztmb250LXNpemU6MjUuNHB4O2ZvbnQtZmFtaWx5OkZyZWVTYW5zOy1pbmtzY2FwZS1mb250LXNwZWNpZmljYXRpb246J0ZyZWVTYW5zIEl0YWxpYyc7ZmlsbDojZmZmZmZmO2ZpbGwtb3BhY2l0eToxO3N0cm9rZTojMDAwMDAwO3N0cm9rZS13aWR0aDoyO3BhaW50LW9yZGVyOm1hcmtlcnMgc3Ryb2tlIGZpbGwiCiAgICAgICB4PSI4MC44MTc1ODEiCiAgICAgICB5PSI2Mi4yODA0NzYiCiAgICAgICBpZD0idGV4dDMiPjx0c3BhbgogICAgICAgICBpZD0idHNwYW4zIgogICAgICAgICBzdHlsZT0iZm9udC1zdHlsZTpub3JtYWw7Zm9udC12YXJpYW50Om5vcm1hbDtmb250LXdlaWdodDpub3JtYWw7Zm9udC1zdHJldGNoOm5vcm1hbDtmb250LXNpemU6MjUuNHB4O2ZvbnQtZmFtaWx5OkZyZWVTYW5zOy1pbmtzY2FwZS1mb250LXNwZWNpZmljYXRpb246J0ZyZWVTYW5zLCBOb3JtYWwnO2ZvbnQtdmFyaWFudC1saWdhdHVyZXM6bm9ybWFsO2ZvbnQtdmFyaWFudC1jYXBzOm5vcm1hbDtmb250LXZhcmlhbnQtbnVtZXJpYzpub3JtYWw7Zm9udC12YXJpYW50LWVhc3QtYXNpYW46bm9ybWFsO2ZpbGw6I2ZmZmZmZjtmaWxsLW9wYWNpdHk6MTtzdHJva2Utd2lkdGg6MiIKICAgICAgICAgeD0iODAuODE3NTgxIgogICAgICAgICB5PSI2Mi4yODA0NzYiPjRsYWdlPC90c3Bhbj48L3RleHQ+CiAgPC9nPgo8L3N2Zz4K";function ed(){const o=_t();return f.jsx("div",{className:"cursor-pointer flex items-center justify-center transition-transform hover:scale-105 active:scale-95 h-10 overflow-hidden",onClick:()=>o("/"),children:f.jsx("img",{src:$a,alt:"Go4lage Logo",className:"h-full w-auto max-w-20 object-contain",style:{maxHeight:"40px"}})})}const qm=({className:o})=>{const{i18n:r,t:s}=Ae(),a=[{code:"en",name:s("language.english"),flag:"🇺🇸"},{code:"de",name:s("language.german"),flag:"🇩🇪"}],u=d=>{const h=d.target.value;r.changeLanguage(h)};return f.jsxs("div",{className:`w-full relative ${o}`,children:[f.jsx("select",{value:r.language,onChange:u,className:"w-full appearance-none bg-surface-tertiary border border-border-default rounded-lg px-3 py-2 pr-8 text-sm text-text-primary focus:outline-none focus:ring-2 focus:ring-accent-primary focus:border-transparent cursor-pointer hover:bg-surface-secondary transition-colors",children:a.map(d=>f.jsxs("option",{value:d.code,className:"bg-surface-primary text-text-primary",children:[d.flag," ",d.name]},d.code))}),f.jsx("div",{className:"absolute right-2 top-1/2 -translate-y-1/2 pointer-events-none",children:f.jsx("svg",{className:"w-4 h-4 text-text-secondary",fill:"none",stroke:"currentColor",viewBox:"0 0 24 24",children:f.jsx("path",{strokeLinecap:"round",strokeLinejoin:"round",strokeWidth:2,d:"M19 9l-7 7-7-7"})})})]})},eg=({isSidebarExpanded:o,setIsMobileOpen:r})=>{const{userData:s,setToast:a}=C.useContext(et),u=_t(),[d,h]=C.useState(!1),[m,g]=C.useState(!1),[y,k]=C.useState(!1),[x,L]=C.useState(""),[I,E]=C.useState(""),z=C.useRef(null),T=C.useRef(null),{t:A}=Ae();C.useEffect(()=>{function J(ee){z.current&&!z.current.contains(ee.target)&&h(!1),T.current&&!T.current.contains(ee.target)&&(g(!1),k(!1),L(""),E(""))}return document.addEventListener("mousedown",J),()=>document.removeEventListener("mousedown",J)},[]);const H=()=>{if(!x.trim()||!I.trim()){a({show:!0,success:!1,header:A("validationError"),text:A("pleaseFillBothBehaviorFields")});return}a({show:!0,success:!0,header:A("messageSent"),text:A("feedbackMessageSentSuccessfully")}),L(""),E(""),k(!1),g(!1);async function J(){try{Pe.newfeedback(s.token,{behaviour_is:x,behaviour_should:I,full_url:window.location.toString(),chat:null,id:"",is_solved:!1,created_at:"",updated_at:""},a)}catch(ee){console.log(ee)}}J()},Y=()=>{g(!1),u("/mymessages")},Q=()=>{k(!0)},U=()=>{k(!1),L(""),E("")};return f.jsx("header",{className:`
         fixed top-0 right-0 h-16 z-30
         bg-surface-primary border-b border-default
         transition-all duration-300 ease-in-out
@@ -199,25 +195,22 @@ Error generating stack: `+c.message+`
               text-text-secondary hover:text-text-primary
               transition-all duration-200
               ml-0
-<<<<<<< HEAD
-            `,"aria-label":d(o?"CollapseSidebar":"ExpandSidebar"),children:o?f.jsx(Sm,{className:"w-4 h-4"}):f.jsx(km,{className:"w-4 h-4"})}),f.jsx("button",{onClick:()=>a(!1),className:"lg:hidden flex items-center justify-center w-8 h-8 rounded-lg bg-surface-secondary hover:bg-surface-tertiary text-text-primary transition-all duration-200",children:f.jsx(Fa,{className:"w-4 h-4"})})]}),o&&f.jsx(f.Fragment,{children:f.jsx("nav",{className:"flex-1 overflow-y-auto p-4",children:f.jsx(tg,{})})})]})]})},Jd="/assets/goopher-admin.svg";function rg(){const{setUserData:o,setToast:r}=C.useContext(et),s=_t(),[a,u]=C.useState(!1),[d,h]=C.useState(""),[m,g]=C.useState(""),[y,k]=C.useState(""),{t:x}=Ae();C.useEffect(()=>{async function I(){try{const E=await Pe.dashboardinfo();u(E.tfa)}catch{u(!1)}}I()},[]);async function L(I){I.preventDefault();const E=await Pe.login(d,m,y,r);E&&E.token&&(o({email:E.email,token:E.token,is_superuser:E.is_superuser,is_organizationadmin:E.is_organizationadmin,organization_id:E.organization_id,organization_name:E.organization_name}),sessionStorage.setItem("userData",JSON.stringify({email:E.email,token:E.token,is_superuser:E.is_superuser,is_organizationadmin:E.is_organizationadmin,organization_id:E.organization_id,organization_name:E.organization_name})),s("/"))}return f.jsx("div",{className:"min-h-screen flex items-center justify-center bg-surface-secondary",children:f.jsxs("div",{className:"bg-surface-primary rounded-xl shadow-xl w-full max-w-lg transition-all duration-300 hover:shadow-2xl",children:[f.jsxs("div",{className:"p-8 border-b border-border-default",children:[f.jsxs("div",{className:"flex items-center justify-between mb-2",children:[f.jsxs("div",{className:"flex items-center gap-4",children:[f.jsx("img",{src:$a,className:"w-20 h-20",alt:"Logo"}),f.jsx("img",{src:Jd,className:"w-20 h-20",alt:"Goopher"})]}),f.jsx("div",{className:"flex",children:f.jsx(Zd,{})})]}),f.jsx("h1",{className:"text-2xl font-bold text-text-primary",children:x("adminDashboard")}),f.jsx("p",{className:"text-text-muted mt-2",children:x("SignInToContinue")})]}),f.jsxs("form",{onSubmit:L,className:"p-8 space-y-6",children:[f.jsxs("div",{className:"space-y-4",children:[f.jsxs("div",{children:[f.jsx("label",{className:"block text-sm font-medium text-text-primary mb-2",children:x("emailAddress")}),f.jsx("input",{type:"email",value:d,onChange:I=>h(I.target.value),className:`w-full px-4 py-2 rounded-lg border border-border-default bg-surface-tertiary 
-=======
-            `,"aria-label":n?"Collapse sidebar":"Expand sidebar",children:n?y.jsx(Fv,{className:"w-4 h-4"}):y.jsx(Av,{className:"w-4 h-4"})}),y.jsx("button",{onClick:()=>r(!1),className:"lg:hidden flex items-center justify-center w-8 h-8 rounded-lg bg-surface-secondary hover:bg-surface-tertiary text-text-primary transition-all duration-200",children:y.jsx($c,{className:"w-4 h-4"})})]}),n&&y.jsx(y.Fragment,{children:y.jsx("nav",{className:"flex-1 overflow-y-auto p-4",children:y.jsx(y0,{})})})]})]})},Em="/assets/goopher-admin.svg";function v0(){const{setUserData:n,setToast:e}=E.useContext(Qe),t=tn(),[r,o]=E.useState(!1),[a,c]=E.useState(""),[u,f]=E.useState(""),[h,g]=E.useState("");E.useEffect(()=>{async function w(){try{const b=await xe.dashboardinfo();o(b.tfa)}catch{o(!1)}}w()},[]);async function x(w){w.preventDefault();const b=await xe.login(a,u,h,e);b&&b.token&&(n({email:b.email,token:b.token}),sessionStorage.setItem("userData",JSON.stringify({email:b.email,token:b.token})),t("/"))}return y.jsx("div",{className:"min-h-screen flex items-center justify-center bg-surface-secondary bg-gradient-surface",children:y.jsxs("div",{className:"bg-surface-primary rounded-xl shadow-xl w-full max-w-lg transition-all duration-300 hover:shadow-2xl",children:[y.jsxs("div",{className:"p-8 border-b border-border-default",children:[y.jsxs("div",{className:"flex items-center justify-between mb-2",children:[y.jsxs("div",{className:"flex items-center gap-4",children:[y.jsx("img",{src:aa,className:"w-20 h-20",alt:"Logo"}),y.jsx("img",{src:Em,className:"w-20 h-20",alt:"Goopher"})]}),y.jsx("div",{className:"flex",children:y.jsx(Cm,{})})]}),y.jsx("h1",{className:"text-2xl font-bold text-text-primary",children:"Admin Dashboard"}),y.jsx("p",{className:"text-text-muted mt-2",children:"Please sign in to continue"})]}),y.jsxs("form",{onSubmit:x,className:"p-8 space-y-6",children:[y.jsxs("div",{className:"space-y-4",children:[y.jsxs("div",{children:[y.jsx("label",{className:"block text-sm font-medium text-text-primary mb-2",children:"Email Address"}),y.jsx("input",{type:"email",value:a,onChange:w=>c(w.target.value),className:`w-full px-4 py-2 rounded-lg border border-border-default bg-surface-tertiary 
->>>>>>> 3a234504ea1fb5b289a516755099f4c562f10bb3
+            `,"aria-label":d(o?"CollapseSidebar":"ExpandSidebar"),children:o?f.jsx(Sm,{className:"w-4 h-4"}):f.jsx(km,{className:"w-4 h-4"})}),f.jsx("button",{onClick:()=>a(!1),className:"lg:hidden flex items-center justify-center w-8 h-8 rounded-lg bg-surface-secondary hover:bg-surface-tertiary text-text-primary transition-all duration-200",children:f.jsx(Fa,{className:"w-4 h-4"})})]}),o&&f.jsx(f.Fragment,{children:f.jsx("nav",{className:"flex-1 overflow-y-auto p-4",children:f.jsx(tg,{})})})]})]})},Jd="/assets/goopher-admin.svg";function rg(){const{setUserData:o,setToast:r}=C.useContext(et),s=_t(),[a,u]=C.useState(!1),[d,h]=C.useState(""),[m,g]=C.useState(""),[y,k]=C.useState(""),[S,w]=C.useState(null),[T,N]=C.useState(null),{t:x}=Ae();C.useEffect(()=>{async function I(){try{const E=await Pe.dashboardinfo();u(E.tfa)}catch{u(!1)}}I()},[]);function j(I){o({email:I.email,token:I.token,refresh_token:I.refresh_token,is_superuser:I.is_superuser,is_organizationadmin:I.is_organizationadmin,organization_id:I.organization_id,organization_name:I.organization_name}),sessionStorage.setItem("userData",JSON.stringify({email:I.email,token:I.token,refresh_token:I.refresh_token,is_superuser:I.is_superuser,is_organizationadmin:I.is_organizationadmin,organization_id:I.organization_id,organization_name:I.organization_name})),s("/")}async function L(I){I.preventDefault();const E=await Pe.login(d,m,y,r);if(E){if(E.twofa_required){u(!0),w(E.twofa_enrollment??null),k("");return}E.token&&(E.recovery_codes&&E.recovery_codes.length>0?N(E):j(E))}}if(T)return f.jsx("div",{className:"min-h-screen flex items-center justify-center bg-surface-secondary",children:f.jsxs("div",{className:"bg-surface-primary rounded-xl shadow-xl w-full max-w-lg p-8 space-y-6",children:[f.jsx("h1",{className:"text-2xl font-bold text-text-primary",children:x("recoveryCodesTitle")}),f.jsx("p",{className:"text-text-muted",children:x("recoveryCodesHint")}),f.jsx("ul",{className:"bg-surface-tertiary rounded-lg p-4 font-mono text-text-primary text-center",children:T.recovery_codes?.map(I=>f.jsx("li",{children:I},I))}),f.jsx(Fe,{kind:"primary",onClick:()=>j(T),className:`w-full flex justify-center py-3 rounded-lg bg-interactive-default hover:bg-interactive-hover 
+                      text-text-inverse font-medium transition-colors duration-200`,children:x("continue")})]})});return f.jsx("div",{className:"min-h-screen flex items-center justify-center bg-surface-secondary",children:f.jsxs("div",{className:"bg-surface-primary rounded-xl shadow-xl w-full max-w-lg transition-all duration-300 hover:shadow-2xl",children:[f.jsxs("div",{className:"p-8 border-b border-border-default",children:[f.jsxs("div",{className:"flex items-center justify-between mb-2",children:[f.jsxs("div",{className:"flex items-center gap-4",children:[f.jsx("img",{src:$a,className:"w-20 h-20",alt:"Logo"}),f.jsx("img",{src:Jd,className:"w-20 h-20",alt:"Goopher"})]}),f.jsx("div",{className:"flex",children:f.jsx(Zd,{})})]}),f.jsx("h1",{className:"text-2xl font-bold text-text-primary",children:x("adminDashboard")}),f.jsx("p",{className:"text-text-muted mt-2",children:x("SignInToContinue")})]}),f.jsxs("form",{onSubmit:L,className:"p-8 space-y-6",children:[f.jsxs("div",{className:"space-y-4",children:[f.jsxs("div",{children:[f.jsx("label",{className:"block text-sm font-medium text-text-primary mb-2",children:x("emailAddress")}),f.jsx("input",{type:"email",value:d,onChange:I=>h(I.target.value),className:`w-full px-4 py-2 rounded-lg border border-border-default bg-surface-tertiary 
                           text-text-primary placeholder-text-muted
                           focus:outline-none focus:ring-2 focus:ring-interactive-default focus:border-transparent
                           transition-colors duration-200`,placeholder:x("enterYourEmail")})]}),f.jsxs("div",{children:[f.jsx("label",{className:"block text-sm font-medium text-text-primary mb-2",children:x("password")}),f.jsx("input",{type:"password",value:m,onChange:I=>g(I.target.value),className:`w-full px-4 py-2 rounded-lg border border-border-default bg-surface-tertiary 
                           text-text-primary placeholder-text-muted
                           focus:outline-none focus:ring-2 focus:ring-interactive-default focus:border-transparent
-                          transition-colors duration-200`,placeholder:x("enterYourPassword")})]}),a&&f.jsxs("div",{children:[f.jsx("label",{className:"block text-sm font-medium text-text-primary mb-2",children:x("tfaCode")}),f.jsx("input",{type:"text",value:y,onChange:I=>k(I.target.value),className:`w-full px-4 py-2 rounded-lg border border-border-default bg-surface-tertiary 
+                          transition-colors duration-200`,placeholder:x("enterYourPassword")})]}),S&&f.jsxs("div",{className:"bg-surface-tertiary rounded-lg p-4 text-center",children:[f.jsx("p",{className:"text-sm font-medium text-text-primary",children:x("twofaEnrollTitle")}),f.jsx("p",{className:"text-sm text-text-muted mt-2",children:x("twofaEnrollHint")}),f.jsx("img",{src:`data:image/png;base64,${S.qr_png}`,className:"mx-auto mt-4",alt:"QR code"}),f.jsx("p",{className:"text-xs text-text-muted mt-4",children:x("twofaSecret")}),f.jsx("p",{className:"font-mono text-sm text-text-primary break-all",children:S.secret})]}),a&&f.jsxs("div",{children:[f.jsx("label",{className:"block text-sm font-medium text-text-primary mb-2",children:x("tfaCode")}),f.jsx("input",{type:"text",value:y,onChange:I=>k(I.target.value),className:`w-full px-4 py-2 rounded-lg border border-border-default bg-surface-tertiary 
                             text-text-primary placeholder-text-muted
                             focus:outline-none focus:ring-2 focus:ring-interactive-default focus:border-transparent
-                            transition-colors duration-200`,placeholder:x("enterSixDigitCode"),maxLength:6})]})]}),f.jsx("div",{className:"pt-4",children:f.jsx(Fe,{kind:"primary",type:"submit",className:`w-full flex justify-center py-3 rounded-lg bg-interactive-default hover:bg-interactive-hover 
-                        text-text-inverse font-medium transition-colors duration-200`,children:x("signIn")})})]})]})})}function sg(){return f.jsxs("div",{className:"flex flex-col",children:[f.jsxs("div",{className:"flex justify-center mt-10",children:[f.jsx("div",{className:"m-0 ",children:f.jsx("img",{src:$a,width:"150px"})}),f.jsx("div",{className:"m-0 ",children:f.jsx("img",{src:Jd,width:"150px"})})]}),f.jsx("h1",{className:"text-center text-text-primary text-lg mt-4",children:"Welcome home, admin"})]})}function hi({label:o,checked:r,onChange:s}){return f.jsxs("label",{className:"flex items-center space-x-3 m-4",children:[f.jsx("input",{type:"checkbox",checked:r,onChange:a=>s(a.target.checked),className:"form-checkbox h-5 w-5 accent-brand"}),f.jsx("span",{className:"text-text-primary",children:o})]})}const ig=({password:o,setPassword:r})=>{const s=C.useRef(null),a=u=>{const d=u.target.selectionStart,h=u.target.value,m=o.length;if(h.length<m)r(o.slice(0,-1));else if(h.length>m){const g=h.charAt(d-1);r(o+g)}setTimeout(()=>{s.current&&s.current.setSelectionRange(d,d)},0)};return f.jsx("div",{className:"relative mb-4",children:f.jsx("input",{ref:s,type:"text",autoCapitalize:"off",autoComplete:"off",autoCorrect:"off",value:"•".repeat(o?.length),onChange:a,className:"block w-full rounded-md border border-border-default py-2 px-4 text-text-primary bg-surface-primary focus:outline-none focus:ring-2 focus:ring-interactive-default focus:border-transparent",placeholder:"Password"})})};var qd={color:void 0,size:void 0,className:void 0,style:void 0,attr:void 0},td=Wt.createContext&&Wt.createContext(qd),og=["attr","size","title"];function ag(o,r){if(o==null)return{};var s=lg(o,r),a,u;if(Object.getOwnPropertySymbols){var d=Object.getOwnPropertySymbols(o);for(u=0;u<d.length;u++)a=d[u],!(r.indexOf(a)>=0)&&Object.prototype.propertyIsEnumerable.call(o,a)&&(s[a]=o[a])}return s}function lg(o,r){if(o==null)return{};var s={};for(var a in o)if(Object.prototype.hasOwnProperty.call(o,a)){if(r.indexOf(a)>=0)continue;s[a]=o[a]}return s}function mi(){return mi=Object.assign?Object.assign.bind():function(o){for(var r=1;r<arguments.length;r++){var s=arguments[r];for(var a in s)Object.prototype.hasOwnProperty.call(s,a)&&(o[a]=s[a])}return o},mi.apply(this,arguments)}function nd(o,r){var s=Object.keys(o);if(Object.getOwnPropertySymbols){var a=Object.getOwnPropertySymbols(o);r&&(a=a.filter(function(u){return Object.getOwnPropertyDescriptor(o,u).enumerable})),s.push.apply(s,a)}return s}function gi(o){for(var r=1;r<arguments.length;r++){var s=arguments[r]!=null?arguments[r]:{};r%2?nd(Object(s),!0).forEach(function(a){ug(o,a,s[a])}):Object.getOwnPropertyDescriptors?Object.defineProperties(o,Object.getOwnPropertyDescriptors(s)):nd(Object(s)).forEach(function(a){Object.defineProperty(o,a,Object.getOwnPropertyDescriptor(s,a))})}return o}function ug(o,r,s){return r=cg(r),r in o?Object.defineProperty(o,r,{value:s,enumerable:!0,configurable:!0,writable:!0}):o[r]=s,o}function cg(o){var r=dg(o,"string");return typeof r=="symbol"?r:r+""}function dg(o,r){if(typeof o!="object"||!o)return o;var s=o[Symbol.toPrimitive];if(s!==void 0){var a=s.call(o,r);if(typeof a!="object")return a;throw new TypeError("@@toPrimitive must return a primitive value.")}return(r==="string"?String:Number)(o)}function ef(o){return o&&o.map((r,s)=>Wt.createElement(r.tag,gi({key:s},r.attr),ef(r.child)))}function ts(o){return r=>Wt.createElement(fg,mi({attr:gi({},o.attr)},r),ef(o.child))}function fg(o){var r=s=>{var{attr:a,size:u,title:d}=o,h=ag(o,og),m=u||s.size||"1em",g;return s.className&&(g=s.className),o.className&&(g=(g?g+" ":"")+o.className),Wt.createElement("svg",mi({stroke:"currentColor",fill:"currentColor",strokeWidth:"0"},s.attr,a,h,{className:g,style:gi(gi({color:o.color||s.color},s.style),o.style),height:m,width:m,xmlns:"http://www.w3.org/2000/svg"}),d&&Wt.createElement("title",null,d),o.children)};return td!==void 0?Wt.createElement(td.Consumer,null,s=>r(s)):r(qd)}function hg(o){return ts({attr:{viewBox:"0 0 448 512"},child:[{tag:"path",attr:{d:"M436 480h-20V24c0-13.255-10.745-24-24-24H56C42.745 0 32 10.745 32 24v456H12c-6.627 0-12 5.373-12 12v20h448v-20c0-6.627-5.373-12-12-12zM128 76c0-6.627 5.373-12 12-12h40c6.627 0 12 5.373 12 12v40c0 6.627-5.373 12-12 12h-40c-6.627 0-12-5.373-12-12V76zm0 96c0-6.627 5.373-12 12-12h40c6.627 0 12 5.373 12 12v40c0 6.627-5.373 12-12 12h-40c-6.627 0-12-5.373-12-12v-40zm52 148h-40c-6.627 0-12-5.373-12-12v-40c0-6.627 5.373-12 12-12h40c6.627 0 12 5.373 12 12v40c0 6.627-5.373 12-12 12zm76 160h-64v-84c0-6.627 5.373-12 12-12h40c6.627 0 12 5.373 12 12v84zm64-172c0 6.627-5.373 12-12 12h-40c-6.627 0-12-5.373-12-12v-40c0-6.627 5.373-12 12-12h40c6.627 0 12 5.373 12 12v40zm0-96c0 6.627-5.373 12-12 12h-40c-6.627 0-12-5.373-12-12v-40c0-6.627 5.373-12 12-12h40c6.627 0 12 5.373 12 12v40zm0-96c0 6.627-5.373 12-12 12h-40c-6.627 0-12-5.373-12-12V76c0-6.627 5.373-12 12-12h40c6.627 0 12 5.373 12 12v40z"},child:[]}]})(o)}function pg(o){return ts({attr:{viewBox:"0 0 448 512"},child:[{tag:"path",attr:{d:"M0 464c0 26.5 21.5 48 48 48h352c26.5 0 48-21.5 48-48V192H0v272zm320-196c0-6.6 5.4-12 12-12h40c6.6 0 12 5.4 12 12v40c0 6.6-5.4 12-12 12h-40c-6.6 0-12-5.4-12-12v-40zm0 128c0-6.6 5.4-12 12-12h40c6.6 0 12 5.4 12 12v40c0 6.6-5.4 12-12 12h-40c-6.6 0-12-5.4-12-12v-40zM192 268c0-6.6 5.4-12 12-12h40c6.6 0 12 5.4 12 12v40c0 6.6-5.4 12-12 12h-40c-6.6 0-12-5.4-12-12v-40zm0 128c0-6.6 5.4-12 12-12h40c6.6 0 12 5.4 12 12v40c0 6.6-5.4 12-12 12h-40c-6.6 0-12-5.4-12-12v-40zM64 268c0-6.6 5.4-12 12-12h40c6.6 0 12 5.4 12 12v40c0 6.6-5.4 12-12 12H76c-6.6 0-12-5.4-12-12v-40zm0 128c0-6.6 5.4-12 12-12h40c6.6 0 12 5.4 12 12v40c0 6.6-5.4 12-12 12H76c-6.6 0-12-5.4-12-12v-40zM400 64h-48V16c0-8.8-7.2-16-16-16h-32c-8.8 0-16 7.2-16 16v48H160V16c0-8.8-7.2-16-16-16h-32c-8.8 0-16 7.2-16 16v48H48C21.5 64 0 85.5 0 112v48h448v-48c0-26.5-21.5-48-48-48z"},child:[]}]})(o)}function mg(o){return ts({attr:{viewBox:"0 0 576 512"},child:[{tag:"path",attr:{d:"M402.6 83.2l90.2 90.2c3.8 3.8 3.8 10 0 13.8L274.4 405.6l-92.8 10.3c-12.4 1.4-22.9-9.1-21.5-21.5l10.3-92.8L388.8 83.2c3.8-3.8 10-3.8 13.8 0zm162-22.9l-48.8-48.8c-15.2-15.2-39.9-15.2-55.2 0l-35.4 35.4c-3.8 3.8-3.8 10 0 13.8l90.2 90.2c3.8 3.8 10 3.8 13.8 0l35.4-35.4c15.2-15.3 15.2-40 0-55.2zM384 346.2V448H64V128h229.8c3.2 0 6.2-1.3 8.5-3.5l40-40c7.6-7.6 2.2-20.5-8.5-20.5H48C21.5 64 0 85.5 0 112v352c0 26.5 21.5 48 48 48h352c26.5 0 48-21.5 48-48V306.2c0-10.7-12.9-16-20.5-8.5l-40 40c-2.2 2.3-3.5 5.3-3.5 8.5z"},child:[]}]})(o)}function gg(o){return ts({attr:{viewBox:"0 0 512 512"},child:[{tag:"path",attr:{d:"M502.3 190.8c3.9-3.1 9.7-.2 9.7 4.7V400c0 26.5-21.5 48-48 48H48c-26.5 0-48-21.5-48-48V195.6c0-5 5.7-7.8 9.7-4.7 22.4 17.4 52.1 39.5 154.1 113.6 21.1 15.4 56.7 47.8 92.2 47.6 35.7.3 72-32.8 92.3-47.6 102-74.1 131.6-96.3 154-113.7zM256 320c23.2.4 56.6-29.2 73.4-41.4 132.7-96.3 142.8-104.7 173.4-128.7 5.8-4.5 9.2-11.5 9.2-18.9v-19c0-26.5-21.5-48-48-48H48C21.5 64 0 85.5 0 112v19c0 7.4 3.4 14.3 9.2 18.9 30.6 23.9 40.7 32.4 173.4 128.7 16.8 12.2 50.2 41.8 73.4 41.4z"},child:[]}]})(o)}function rd(o){return ts({attr:{viewBox:"0 0 512 512"},child:[{tag:"path",attr:{d:"M256 8C119.043 8 8 119.083 8 256c0 136.997 111.043 248 248 248s248-111.003 248-248C504 119.083 392.957 8 256 8zm0 110c23.196 0 42 18.804 42 42s-18.804 42-42 42-42-18.804-42-42 18.804-42 42-42zm56 254c0 6.627-5.373 12-12 12h-88c-6.627 0-12-5.373-12-12v-24c0-6.627 5.373-12 12-12h12v-64h-12c-6.627 0-12-5.373-12-12v-24c0-6.627 5.373-12 12-12h64c6.627 0 12 5.373 12 12v100h12c6.627 0 12 5.373 12 12v24z"},child:[]}]})(o)}function tf({headText:o,userId:r,email:s,setEmail:a,password:u,setPassword:d,username:h,setUserName:m,first_name:g,setFirst_name:y,last_name:k,setLast_name:x,isActive:L,setIsActive:I,isSuperuser:E,setIsSuperuser:z,groups:T,setGroups:A,permissions:H,setPermissions:Y,organizationId:Q,setOrganizationId:U,organizations:J,handleSubmit:ee}){const{t:re}=Ae(),{userData:fe,setToast:de}=C.useContext(et),[Ce,Se]=C.useState(!1),[M,q]=C.useState(!1);function he(D,S){const P=[...T];P[D].checked=S,A(P)}function se(D,S){const P=[...H];P[D].checked=S,Y(P)}function le(){Pe.deleteUser(fe.token,r,de)}const B=!E,_=B&&!Q;return f.jsxs("div",{className:"bg-surface-primary rounded-lg border border-border-default p-6",children:[f.jsx("h1",{className:"text-2xl font-semibold text-text-primary mb-6",children:o}),f.jsxs("div",{className:"space-y-6",children:[f.jsxs("div",{className:"grid grid-cols-1 md:grid-cols-2 lg:grid-cols-3 gap-4",children:[f.jsxs("div",{children:[f.jsx("label",{className:"block text-sm font-medium text-text-secondary mb-2",children:re("Email")}),f.jsx("input",{type:"email",value:s,onChange:D=>a(D.target.value),className:"w-full rounded-lg border border-border-default px-4 py-2 text-text-primary bg-surface-secondary focus:outline-none focus:ring-2 focus:ring-brand focus:border-transparent",placeholder:re("EmailPlaceholder")})]}),f.jsxs("div",{children:[f.jsx("label",{className:"block text-sm font-medium text-text-secondary mb-2",children:re("Password")}),f.jsx(ig,{password:u,setPassword:d})]}),f.jsxs("div",{children:[f.jsx("label",{className:"block text-sm font-medium text-text-secondary mb-2",children:re("Username")}),f.jsx("input",{type:"text",value:h,onChange:D=>m(D.target.value),className:"w-full rounded-lg border border-border-default px-4 py-2 text-text-primary bg-surface-secondary focus:outline-none focus:ring-2 focus:ring-brand focus:border-transparent",placeholder:re("UsernamePlaceholder")})]}),f.jsxs("div",{children:[f.jsx("label",{className:"block text-sm font-medium text-text-secondary mb-2",children:re("FirstName")}),f.jsx("input",{type:"text",value:g,onChange:D=>y(D.target.value),className:"w-full rounded-lg border border-border-default px-4 py-2 text-text-primary bg-surface-secondary focus:outline-none focus:ring-2 focus:ring-brand focus:border-transparent",placeholder:re("FirstNamePlaceholder")})]}),f.jsxs("div",{children:[f.jsx("label",{className:"block text-sm font-medium text-text-secondary mb-2",children:re("LastName")}),f.jsx("input",{type:"text",value:k,onChange:D=>x(D.target.value),className:"w-full rounded-lg border border-border-default px-4 py-2 text-text-primary bg-surface-secondary focus:outline-none focus:ring-2 focus:ring-brand focus:border-transparent",placeholder:re("LastNamePlaceholder")})]})]}),f.jsxs("div",{children:[f.jsxs("label",{className:"block text-sm font-medium text-text-secondary mb-2",children:[re("Organization")," ",B&&f.jsx("span",{className:"text-red-500",children:"*"})]}),f.jsxs("select",{value:Q||"",onChange:D=>U(D.target.value||null),className:`w-full rounded-lg border ${_?"border-red-500":"border-border-default"} px-4 py-2 text-text-primary bg-surface-secondary focus:outline-none focus:ring-2 focus:ring-brand focus:border-transparent`,disabled:E,children:[f.jsx("option",{value:"",children:re("NoOrganization")}),J.map(D=>f.jsx("option",{value:D.id||"",children:D.organization_name},D.id))]}),_&&f.jsx("p",{className:"mt-1 text-sm text-red-500",children:re("OrganizationRequiredWarning")}),E&&f.jsx("p",{className:"mt-1 text-sm text-text-muted",children:re("OrganizationOptionalForSuperusers")})]}),f.jsxs("div",{className:"flex gap-6 p-4 bg-surface-secondary rounded-lg",children:[f.jsx(hi,{label:re("IsActive"),checked:L,onChange:()=>I(!L)}),f.jsx(hi,{label:re("IsSuperuser"),checked:E,onChange:()=>{z(!E)}})]}),f.jsxs("div",{children:[f.jsxs("div",{className:"flex items-center gap-2 mb-4",children:[f.jsx("h2",{className:"text-lg font-medium text-text-primary",children:re("Groups")}),f.jsx(rd,{className:"w-5 h-5 text-accent-primary cursor-pointer hover:text-accent-secondary",onClick:()=>Se(!Ce)})]}),Ce&&f.jsxs("div",{className:"mb-4 p-4 bg-info/10 rounded-lg text-text-primary",children:[f.jsx("p",{className:"mb-2",children:re("GroupDescription1")}),f.jsx("p",{children:re("GroupDescription2")})]}),f.jsx("div",{className:"grid grid-cols-1 sm:grid-cols-2 lg:grid-cols-3 gap-3 p-4 bg-surface-secondary rounded-lg max-h-48 overflow-y-auto",children:T.map((D,S)=>f.jsx(hi,{label:D.name,checked:D.checked,onChange:P=>he(S,P)},D.id))})]}),f.jsxs("div",{children:[f.jsxs("div",{className:"flex items-center gap-2 mb-4",children:[f.jsx("h2",{className:"text-lg font-medium text-text-primary",children:re("PurePermissions")}),f.jsx(rd,{className:"w-5 h-5 text-accent-primary cursor-pointer hover:text-accent-secondary",onClick:()=>q(!M)})]}),M&&f.jsxs("div",{className:"mb-4 p-4 bg-info/10 rounded-lg text-text-primary",children:[f.jsx("p",{className:"mb-2",children:re("PurePermissionsDescription1")}),f.jsx("p",{children:re("PurePermissionsDescription2")})]}),f.jsx("div",{className:"grid grid-cols-1 sm:grid-cols-2 lg:grid-cols-3 gap-3 p-4 bg-surface-secondary rounded-lg max-h-48 overflow-y-auto",children:H.map((D,S)=>f.jsx(hi,{label:D.name,checked:D.checked,onChange:P=>se(S,P)},D.id))})]}),f.jsxs("div",{className:"flex gap-4 pt-4",children:[f.jsx(Fe,{onClick:ee,kind:"primary",children:re(o==="Create user"?"CreateUser":"SaveChanges")}),o!=="Create user"&&f.jsx(Fe,{onClick:le,kind:"danger",children:re("DeleteUser")})]})]})]})}function yg(){const{userData:o,setToast:r}=C.useContext(et),{t:s}=Ae(),[a,u]=C.useState(""),[d,h]=C.useState(""),[m,g]=C.useState(""),[y,k]=C.useState(""),[x,L]=C.useState(""),[I,E]=C.useState(!0),[z,T]=C.useState(!1),[A,H]=C.useState([]),[Y,Q]=C.useState([]),[U,J]=C.useState(null),[ee,re]=C.useState([]);C.useEffect(()=>{async function de(){const M=await Pe.getGroups(o.token);H(M||[])}de();async function Ce(){const M=await Pe.getPermissions(o.token);Q(M||[])}Ce();async function Se(){const M=await Pe.allOrganizations(o.token);re(M||[])}Se()},[o.token]);function fe(){if(!z&&!U){r({header:s("validationError"),text:s("usersNotSuperuserMustBelongToOrganization"),success:!1,show:!0});return}const de=A.filter(M=>M.checked).map(M=>M.name).join("|"),Ce=Y.filter(M=>M.checked).map(M=>M.name).join("|"),Se={email:a,password:d,first_name:y,last_name:x,username:m||a,is_active:I,is_superuser:z,groups:de,permissions:Ce,organization_id:U};Pe.createoneuser(o.token,Se,r)}return f.jsx(tf,{userId:"",headText:s("createUser"),email:a,setEmail:u,password:d,setPassword:h,username:m,setUserName:g,first_name:y,setFirst_name:k,last_name:x,setLast_name:L,isActive:I,setIsActive:E,isSuperuser:z,setIsSuperuser:T,groups:A,setGroups:H,permissions:Y,setPermissions:Q,organizationId:U,setOrganizationId:J,organizations:ee,handleSubmit:fe})}function vg({setShowData:o,availableGroups:r,availableOrganizations:s,allUsers:a}){const{t:u}=Ae(),[d,h]=gm(),[m,g]=C.useState(d.get("username")||""),[y,k]=C.useState(d.get("first_name")||""),[x,L]=C.useState(d.get("last_name")||""),[I,E]=C.useState(d.get("email")||""),[z,T]=C.useState(d.get("is_active")!=="false"),[A,H]=C.useState(d.get("is_superuser")==="true"),[Y,Q]=C.useState(d.get("created_at")||""),[U,J]=C.useState(d.get("last_login")||""),[ee,re]=C.useState(d.get("group")||""),[fe,de]=C.useState(d.get("organization")||"");C.useEffect(()=>{const M=new URLSearchParams;m&&M.set("username",m),y&&M.set("first_name",y),x&&M.set("last_name",x),I&&M.set("email",I),z||M.set("is_active","false"),A&&M.set("is_superuser","true"),Y&&M.set("created_at",Y),U&&M.set("last_login",U),ee&&M.set("group",ee),fe&&M.set("organization",fe),h(M,{replace:!0})},[m,y,x,I,z,A,Y,U,ee,fe,h]);function Ce(){try{let M=a;m&&(M=M.filter(q=>q.username.toLowerCase().includes(m.toLowerCase()))),y&&(M=M.filter(q=>q.first_name.toLowerCase().includes(y.toLowerCase()))),x&&(M=M.filter(q=>q.last_name.toLowerCase().includes(x.toLowerCase()))),I&&(M=M.filter(q=>q.email.toLowerCase().includes(I.toLowerCase()))),M=M.filter(q=>q.is_active===z),M=M.filter(q=>q.is_superuser===A),ee&&(M=M.filter(q=>q.groups.split("|").includes(ee))),fe&&(fe==="none"?M=M.filter(q=>!q.organization):M=M.filter(q=>q.organization?.id===fe)),Y&&(M=[...M].sort((q,he)=>Y==="asc"?new Date(q.created_at).getTime()-new Date(he.created_at).getTime():new Date(he.created_at).getTime()-new Date(q.created_at).getTime())),U&&(M=[...M].sort((q,he)=>U==="asc"?new Date(q.last_login).getTime()-new Date(he.last_login).getTime():new Date(he.last_login).getTime()-new Date(q.last_login).getTime())),o(M)}catch(M){console.log(M)}}function Se(){g(""),k(""),L(""),E(""),T(!0),H(!1),re(""),de(""),Q(""),J(""),o(a)}return C.useEffect(()=>{Ce()},[Y,U,ee,fe,z,A]),C.useEffect(()=>{a.length>0&&Ce()},[a]),f.jsxs("div",{className:"p-6 space-y-4 bg-surface-secondary border-b border-border-default",children:[f.jsxs("div",{className:"grid grid-cols-1 sm:grid-cols-2 lg:grid-cols-4 gap-3",children:[f.jsx("input",{type:"text",placeholder:u("Username"),value:m,onChange:M=>g(M.target.value),className:"px-4 py-2 rounded-lg bg-surface-primary text-text-primary border-2 border-border-default placeholder-text-muted focus:outline-none focus:ring-2 focus:ring-blue-500 focus:border-transparent"}),f.jsx("input",{type:"text",placeholder:u("FirstName"),value:y,onChange:M=>k(M.target.value),className:"px-4 py-2 rounded-lg bg-surface-primary text-text-primary border-2 border-border-default placeholder-text-muted focus:outline-none focus:ring-2 focus:ring-blue-500 focus:border-transparent"}),f.jsx("input",{type:"text",placeholder:u("LastName"),value:x,onChange:M=>L(M.target.value),className:"px-4 py-2 rounded-lg bg-surface-primary text-text-primary border-2 border-border-default placeholder-text-muted focus:outline-none focus:ring-2 focus:ring-blue-500 focus:border-transparent"}),f.jsx("input",{type:"email",placeholder:u("Email"),value:I,onChange:M=>E(M.target.value),className:"px-4 py-2 rounded-lg bg-surface-primary text-text-primary border-2 border-border-default placeholder-text-muted focus:outline-none focus:ring-2 focus:ring-blue-500 focus:border-transparent"})]}),f.jsxs("div",{className:"grid grid-cols-1 sm:grid-cols-2 lg:grid-cols-3 xl:grid-cols-6 gap-3",children:[f.jsxs("div",{className:"flex flex-col justify-center gap-2 px-3 py-2 bg-surface-primary rounded-lg border border-border-default",children:[f.jsxs("label",{className:"flex items-center text-sm text-text-primary",children:[f.jsx("input",{type:"checkbox",checked:z,onChange:M=>T(M.target.checked),className:"mr-2 accent-blue-600 w-4 h-4"}),f.jsx("span",{className:"whitespace-nowrap",children:u("IsActive")})]}),f.jsxs("label",{className:"flex items-center text-sm text-text-primary",children:[f.jsx("input",{type:"checkbox",checked:A,onChange:M=>H(M.target.checked),className:"mr-2 accent-blue-600 w-4 h-4"}),f.jsx("span",{className:"whitespace-nowrap",children:u("IsSuperuser")})]})]}),f.jsxs("select",{value:ee,onChange:M=>re(M.target.value),className:"px-4 py-2 rounded-lg bg-surface-primary text-text-primary border-2 border-border-default focus:outline-none focus:ring-2 focus:ring-blue-500 focus:border-transparent",children:[f.jsx("option",{value:"",children:u("AllGroups")}),r.map(M=>f.jsx("option",{value:M.name,children:M.name},M.id))]}),f.jsxs("select",{value:fe,onChange:M=>de(M.target.value),className:"px-4 py-2 rounded-lg bg-surface-primary text-text-primary border-2 border-border-default focus:outline-none focus:ring-2 focus:ring-blue-500 focus:border-transparent",children:[f.jsx("option",{value:"",children:u("AllOrganizations")}),f.jsx("option",{value:"none",children:u("NoOrganization")}),s.map(M=>f.jsx("option",{value:M.id||"",children:M.organization_name},M.id))]}),f.jsxs("div",{className:"flex items-center gap-2 px-3 py-2 bg-surface-primary rounded-lg border border-border-default",children:[f.jsx("label",{className:"text-sm text-text-primary whitespace-nowrap",children:u("Created")}),f.jsx("button",{onClick:()=>{Q(Y==="asc"?"desc":"asc")},className:"ml-auto px-3 py-1 rounded border-2 border-border-default bg-surface-secondary hover:bg-surface-tertiary text-text-primary transition-colors",title:Y==="asc"?"Oldest first":"Newest first",children:Y==="asc"?"↑":"↓"})]}),f.jsxs("div",{className:"flex items-center gap-2 px-3 py-2 bg-surface-primary rounded-lg border border-border-default",children:[f.jsx("label",{className:"text-sm text-text-primary whitespace-nowrap",children:u("LastLogin")}),f.jsx("button",{onClick:()=>{J(U==="asc"?"desc":"asc")},className:"ml-auto px-3 py-1 rounded border-2 border-border-default bg-surface-secondary hover:bg-surface-tertiary text-text-primary transition-colors",title:U==="asc"?"Oldest first":"Newest first",children:U==="asc"?"↑":"↓"})]}),f.jsxs("div",{className:"flex gap-2 sm:col-span-2 lg:col-span-3 xl:col-span-1",children:[f.jsx(Fe,{kind:"primary",onClick:Ce,className:"flex-1 bg-blue-600 hover:bg-blue-700 text-white",children:u("Search")}),f.jsx(Fe,{kind:"secondary",onClick:Se,className:"flex-1 bg-surface-secondary hover:bg-surface-tertiary text-text-primary border-2 border-border-default",children:u("ShowAll")})]})]})]})}function jt(o){const r=Object.prototype.toString.call(o);return o instanceof Date||typeof o=="object"&&r==="[object Date]"?new o.constructor(+o):typeof o=="number"||r==="[object Number]"||typeof o=="string"||r==="[object String]"?new Date(o):new Date(NaN)}function En(o,r){return o instanceof Date?new o.constructor(r):new Date(r)}const nf=6048e5,xg=864e5;let wg={};function Ci(){return wg}function Jr(o,r){const s=Ci(),a=r?.weekStartsOn??r?.locale?.options?.weekStartsOn??s.weekStartsOn??s.locale?.options?.weekStartsOn??0,u=jt(o),d=u.getDay(),h=(d<a?7:0)+d-a;return u.setDate(u.getDate()-h),u.setHours(0,0,0,0),u}function yi(o){return Jr(o,{weekStartsOn:1})}function rf(o){const r=jt(o),s=r.getFullYear(),a=En(o,0);a.setFullYear(s+1,0,4),a.setHours(0,0,0,0);const u=yi(a),d=En(o,0);d.setFullYear(s,0,4),d.setHours(0,0,0,0);const h=yi(d);return r.getTime()>=u.getTime()?s+1:r.getTime()>=h.getTime()?s:s-1}function sd(o){const r=jt(o);return r.setHours(0,0,0,0),r}function id(o){const r=jt(o),s=new Date(Date.UTC(r.getFullYear(),r.getMonth(),r.getDate(),r.getHours(),r.getMinutes(),r.getSeconds(),r.getMilliseconds()));return s.setUTCFullYear(r.getFullYear()),+o-+s}function Sg(o,r){const s=sd(o),a=sd(r),u=+s-id(s),d=+a-id(a);return Math.round((u-d)/xg)}function kg(o){const r=rf(o),s=En(o,0);return s.setFullYear(r,0,4),s.setHours(0,0,0,0),yi(s)}function Ng(o){return o instanceof Date||typeof o=="object"&&Object.prototype.toString.call(o)==="[object Date]"}function Cg(o){if(!Ng(o)&&typeof o!="number")return!1;const r=jt(o);return!isNaN(Number(r))}function jg(o){const r=jt(o),s=En(o,0);return s.setFullYear(r.getFullYear(),0,1),s.setHours(0,0,0,0),s}const bg={lessThanXSeconds:{one:"less than a second",other:"less than {{count}} seconds"},xSeconds:{one:"1 second",other:"{{count}} seconds"},halfAMinute:"half a minute",lessThanXMinutes:{one:"less than a minute",other:"less than {{count}} minutes"},xMinutes:{one:"1 minute",other:"{{count}} minutes"},aboutXHours:{one:"about 1 hour",other:"about {{count}} hours"},xHours:{one:"1 hour",other:"{{count}} hours"},xDays:{one:"1 day",other:"{{count}} days"},aboutXWeeks:{one:"about 1 week",other:"about {{count}} weeks"},xWeeks:{one:"1 week",other:"{{count}} weeks"},aboutXMonths:{one:"about 1 month",other:"about {{count}} months"},xMonths:{one:"1 month",other:"{{count}} months"},aboutXYears:{one:"about 1 year",other:"about {{count}} years"},xYears:{one:"1 year",other:"{{count}} years"},overXYears:{one:"over 1 year",other:"over {{count}} years"},almostXYears:{one:"almost 1 year",other:"almost {{count}} years"}},Eg=(o,r,s)=>{let a;const u=bg[o];return typeof u=="string"?a=u:r===1?a=u.one:a=u.other.replace("{{count}}",r.toString()),s?.addSuffix?s.comparison&&s.comparison>0?"in "+a:a+" ago":a};function ba(o){return(r={})=>{const s=r.width?String(r.width):o.defaultWidth;return o.formats[s]||o.formats[o.defaultWidth]}}const Og={full:"EEEE, MMMM do, y",long:"MMMM do, y",medium:"MMM d, y",short:"MM/dd/yyyy"},Pg={full:"h:mm:ss a zzzz",long:"h:mm:ss a z",medium:"h:mm:ss a",short:"h:mm a"},Lg={full:"{{date}} 'at' {{time}}",long:"{{date}} 'at' {{time}}",medium:"{{date}}, {{time}}",short:"{{date}}, {{time}}"},zg={date:ba({formats:Og,defaultWidth:"full"}),time:ba({formats:Pg,defaultWidth:"full"}),dateTime:ba({formats:Lg,defaultWidth:"full"})},Tg={lastWeek:"'last' eeee 'at' p",yesterday:"'yesterday at' p",today:"'today at' p",tomorrow:"'tomorrow at' p",nextWeek:"eeee 'at' p",other:"P"},_g=(o,r,s,a)=>Tg[o];function Yr(o){return(r,s)=>{const a=s?.context?String(s.context):"standalone";let u;if(a==="formatting"&&o.formattingValues){const h=o.defaultFormattingWidth||o.defaultWidth,m=s?.width?String(s.width):h;u=o.formattingValues[m]||o.formattingValues[h]}else{const h=o.defaultWidth,m=s?.width?String(s.width):o.defaultWidth;u=o.values[m]||o.values[h]}const d=o.argumentCallback?o.argumentCallback(r):r;return u[d]}}const Ig={narrow:["B","A"],abbreviated:["BC","AD"],wide:["Before Christ","Anno Domini"]},Dg={narrow:["1","2","3","4"],abbreviated:["Q1","Q2","Q3","Q4"],wide:["1st quarter","2nd quarter","3rd quarter","4th quarter"]},Mg={narrow:["J","F","M","A","M","J","J","A","S","O","N","D"],abbreviated:["Jan","Feb","Mar","Apr","May","Jun","Jul","Aug","Sep","Oct","Nov","Dec"],wide:["January","February","March","April","May","June","July","August","September","October","November","December"]},Rg={narrow:["S","M","T","W","T","F","S"],short:["Su","Mo","Tu","We","Th","Fr","Sa"],abbreviated:["Sun","Mon","Tue","Wed","Thu","Fri","Sat"],wide:["Sunday","Monday","Tuesday","Wednesday","Thursday","Friday","Saturday"]},Fg={narrow:{am:"a",pm:"p",midnight:"mi",noon:"n",morning:"morning",afternoon:"afternoon",evening:"evening",night:"night"},abbreviated:{am:"AM",pm:"PM",midnight:"midnight",noon:"noon",morning:"morning",afternoon:"afternoon",evening:"evening",night:"night"},wide:{am:"a.m.",pm:"p.m.",midnight:"midnight",noon:"noon",morning:"morning",afternoon:"afternoon",evening:"evening",night:"night"}},$g={narrow:{am:"a",pm:"p",midnight:"mi",noon:"n",morning:"in the morning",afternoon:"in the afternoon",evening:"in the evening",night:"at night"},abbreviated:{am:"AM",pm:"PM",midnight:"midnight",noon:"noon",morning:"in the morning",afternoon:"in the afternoon",evening:"in the evening",night:"at night"},wide:{am:"a.m.",pm:"p.m.",midnight:"midnight",noon:"noon",morning:"in the morning",afternoon:"in the afternoon",evening:"in the evening",night:"at night"}},Ag=(o,r)=>{const s=Number(o),a=s%100;if(a>20||a<10)switch(a%10){case 1:return s+"st";case 2:return s+"nd";case 3:return s+"rd"}return s+"th"},Ug={ordinalNumber:Ag,era:Yr({values:Ig,defaultWidth:"wide"}),quarter:Yr({values:Dg,defaultWidth:"wide",argumentCallback:o=>o-1}),month:Yr({values:Mg,defaultWidth:"wide"}),day:Yr({values:Rg,defaultWidth:"wide"}),dayPeriod:Yr({values:Fg,defaultWidth:"wide",formattingValues:$g,defaultFormattingWidth:"wide"})};function Gr(o){return(r,s={})=>{const a=s.width,u=a&&o.matchPatterns[a]||o.matchPatterns[o.defaultMatchWidth],d=r.match(u);if(!d)return null;const h=d[0],m=a&&o.parsePatterns[a]||o.parsePatterns[o.defaultParseWidth],g=Array.isArray(m)?Bg(m,x=>x.test(h)):Wg(m,x=>x.test(h));let y;y=o.valueCallback?o.valueCallback(g):g,y=s.valueCallback?s.valueCallback(y):y;const k=r.slice(h.length);return{value:y,rest:k}}}function Wg(o,r){for(const s in o)if(Object.prototype.hasOwnProperty.call(o,s)&&r(o[s]))return s}function Bg(o,r){for(let s=0;s<o.length;s++)if(r(o[s]))return s}function Hg(o){return(r,s={})=>{const a=r.match(o.matchPattern);if(!a)return null;const u=a[0],d=r.match(o.parsePattern);if(!d)return null;let h=o.valueCallback?o.valueCallback(d[0]):d[0];h=s.valueCallback?s.valueCallback(h):h;const m=r.slice(u.length);return{value:h,rest:m}}}const Vg=/^(\d+)(th|st|nd|rd)?/i,Yg=/\d+/i,Gg={narrow:/^(b|a)/i,abbreviated:/^(b\.?\s?c\.?|b\.?\s?c\.?\s?e\.?|a\.?\s?d\.?|c\.?\s?e\.?)/i,wide:/^(before christ|before common era|anno domini|common era)/i},Zg={any:[/^b/i,/^(a|c)/i]},Qg={narrow:/^[1234]/i,abbreviated:/^q[1234]/i,wide:/^[1234](th|st|nd|rd)? quarter/i},Kg={any:[/1/i,/2/i,/3/i,/4/i]},Xg={narrow:/^[jfmasond]/i,abbreviated:/^(jan|feb|mar|apr|may|jun|jul|aug|sep|oct|nov|dec)/i,wide:/^(january|february|march|april|may|june|july|august|september|october|november|december)/i},Jg={narrow:[/^j/i,/^f/i,/^m/i,/^a/i,/^m/i,/^j/i,/^j/i,/^a/i,/^s/i,/^o/i,/^n/i,/^d/i],any:[/^ja/i,/^f/i,/^mar/i,/^ap/i,/^may/i,/^jun/i,/^jul/i,/^au/i,/^s/i,/^o/i,/^n/i,/^d/i]},qg={narrow:/^[smtwf]/i,short:/^(su|mo|tu|we|th|fr|sa)/i,abbreviated:/^(sun|mon|tue|wed|thu|fri|sat)/i,wide:/^(sunday|monday|tuesday|wednesday|thursday|friday|saturday)/i},ey={narrow:[/^s/i,/^m/i,/^t/i,/^w/i,/^t/i,/^f/i,/^s/i],any:[/^su/i,/^m/i,/^tu/i,/^w/i,/^th/i,/^f/i,/^sa/i]},ty={narrow:/^(a|p|mi|n|(in the|at) (morning|afternoon|evening|night))/i,any:/^([ap]\.?\s?m\.?|midnight|noon|(in the|at) (morning|afternoon|evening|night))/i},ny={any:{am:/^a/i,pm:/^p/i,midnight:/^mi/i,noon:/^no/i,morning:/morning/i,afternoon:/afternoon/i,evening:/evening/i,night:/night/i}},ry={ordinalNumber:Hg({matchPattern:Vg,parsePattern:Yg,valueCallback:o=>parseInt(o,10)}),era:Gr({matchPatterns:Gg,defaultMatchWidth:"wide",parsePatterns:Zg,defaultParseWidth:"any"}),quarter:Gr({matchPatterns:Qg,defaultMatchWidth:"wide",parsePatterns:Kg,defaultParseWidth:"any",valueCallback:o=>o+1}),month:Gr({matchPatterns:Xg,defaultMatchWidth:"wide",parsePatterns:Jg,defaultParseWidth:"any"}),day:Gr({matchPatterns:qg,defaultMatchWidth:"wide",parsePatterns:ey,defaultParseWidth:"any"}),dayPeriod:Gr({matchPatterns:ty,defaultMatchWidth:"any",parsePatterns:ny,defaultParseWidth:"any"})},sy={code:"en-US",formatDistance:Eg,formatLong:zg,formatRelative:_g,localize:Ug,match:ry,options:{weekStartsOn:0,firstWeekContainsDate:1}};function iy(o){const r=jt(o);return Sg(r,jg(r))+1}function oy(o){const r=jt(o),s=+yi(r)-+kg(r);return Math.round(s/nf)+1}function sf(o,r){const s=jt(o),a=s.getFullYear(),u=Ci(),d=r?.firstWeekContainsDate??r?.locale?.options?.firstWeekContainsDate??u.firstWeekContainsDate??u.locale?.options?.firstWeekContainsDate??1,h=En(o,0);h.setFullYear(a+1,0,d),h.setHours(0,0,0,0);const m=Jr(h,r),g=En(o,0);g.setFullYear(a,0,d),g.setHours(0,0,0,0);const y=Jr(g,r);return s.getTime()>=m.getTime()?a+1:s.getTime()>=y.getTime()?a:a-1}function ay(o,r){const s=Ci(),a=r?.firstWeekContainsDate??r?.locale?.options?.firstWeekContainsDate??s.firstWeekContainsDate??s.locale?.options?.firstWeekContainsDate??1,u=sf(o,r),d=En(o,0);return d.setFullYear(u,0,a),d.setHours(0,0,0,0),Jr(d,r)}function ly(o,r){const s=jt(o),a=+Jr(s,r)-+ay(s,r);return Math.round(a/nf)+1}function we(o,r){const s=o<0?"-":"",a=Math.abs(o).toString().padStart(r,"0");return s+a}const un={y(o,r){const s=o.getFullYear(),a=s>0?s:1-s;return we(r==="yy"?a%100:a,r.length)},M(o,r){const s=o.getMonth();return r==="M"?String(s+1):we(s+1,2)},d(o,r){return we(o.getDate(),r.length)},a(o,r){const s=o.getHours()/12>=1?"pm":"am";switch(r){case"a":case"aa":return s.toUpperCase();case"aaa":return s;case"aaaaa":return s[0];case"aaaa":default:return s==="am"?"a.m.":"p.m."}},h(o,r){return we(o.getHours()%12||12,r.length)},H(o,r){return we(o.getHours(),r.length)},m(o,r){return we(o.getMinutes(),r.length)},s(o,r){return we(o.getSeconds(),r.length)},S(o,r){const s=r.length,a=o.getMilliseconds(),u=Math.trunc(a*Math.pow(10,s-3));return we(u,r.length)}},er={midnight:"midnight",noon:"noon",morning:"morning",afternoon:"afternoon",evening:"evening",night:"night"},od={G:function(o,r,s){const a=o.getFullYear()>0?1:0;switch(r){case"G":case"GG":case"GGG":return s.era(a,{width:"abbreviated"});case"GGGGG":return s.era(a,{width:"narrow"});case"GGGG":default:return s.era(a,{width:"wide"})}},y:function(o,r,s){if(r==="yo"){const a=o.getFullYear(),u=a>0?a:1-a;return s.ordinalNumber(u,{unit:"year"})}return un.y(o,r)},Y:function(o,r,s,a){const u=sf(o,a),d=u>0?u:1-u;if(r==="YY"){const h=d%100;return we(h,2)}return r==="Yo"?s.ordinalNumber(d,{unit:"year"}):we(d,r.length)},R:function(o,r){const s=rf(o);return we(s,r.length)},u:function(o,r){const s=o.getFullYear();return we(s,r.length)},Q:function(o,r,s){const a=Math.ceil((o.getMonth()+1)/3);switch(r){case"Q":return String(a);case"QQ":return we(a,2);case"Qo":return s.ordinalNumber(a,{unit:"quarter"});case"QQQ":return s.quarter(a,{width:"abbreviated",context:"formatting"});case"QQQQQ":return s.quarter(a,{width:"narrow",context:"formatting"});case"QQQQ":default:return s.quarter(a,{width:"wide",context:"formatting"})}},q:function(o,r,s){const a=Math.ceil((o.getMonth()+1)/3);switch(r){case"q":return String(a);case"qq":return we(a,2);case"qo":return s.ordinalNumber(a,{unit:"quarter"});case"qqq":return s.quarter(a,{width:"abbreviated",context:"standalone"});case"qqqqq":return s.quarter(a,{width:"narrow",context:"standalone"});case"qqqq":default:return s.quarter(a,{width:"wide",context:"standalone"})}},M:function(o,r,s){const a=o.getMonth();switch(r){case"M":case"MM":return un.M(o,r);case"Mo":return s.ordinalNumber(a+1,{unit:"month"});case"MMM":return s.month(a,{width:"abbreviated",context:"formatting"});case"MMMMM":return s.month(a,{width:"narrow",context:"formatting"});case"MMMM":default:return s.month(a,{width:"wide",context:"formatting"})}},L:function(o,r,s){const a=o.getMonth();switch(r){case"L":return String(a+1);case"LL":return we(a+1,2);case"Lo":return s.ordinalNumber(a+1,{unit:"month"});case"LLL":return s.month(a,{width:"abbreviated",context:"standalone"});case"LLLLL":return s.month(a,{width:"narrow",context:"standalone"});case"LLLL":default:return s.month(a,{width:"wide",context:"standalone"})}},w:function(o,r,s,a){const u=ly(o,a);return r==="wo"?s.ordinalNumber(u,{unit:"week"}):we(u,r.length)},I:function(o,r,s){const a=oy(o);return r==="Io"?s.ordinalNumber(a,{unit:"week"}):we(a,r.length)},d:function(o,r,s){return r==="do"?s.ordinalNumber(o.getDate(),{unit:"date"}):un.d(o,r)},D:function(o,r,s){const a=iy(o);return r==="Do"?s.ordinalNumber(a,{unit:"dayOfYear"}):we(a,r.length)},E:function(o,r,s){const a=o.getDay();switch(r){case"E":case"EE":case"EEE":return s.day(a,{width:"abbreviated",context:"formatting"});case"EEEEE":return s.day(a,{width:"narrow",context:"formatting"});case"EEEEEE":return s.day(a,{width:"short",context:"formatting"});case"EEEE":default:return s.day(a,{width:"wide",context:"formatting"})}},e:function(o,r,s,a){const u=o.getDay(),d=(u-a.weekStartsOn+8)%7||7;switch(r){case"e":return String(d);case"ee":return we(d,2);case"eo":return s.ordinalNumber(d,{unit:"day"});case"eee":return s.day(u,{width:"abbreviated",context:"formatting"});case"eeeee":return s.day(u,{width:"narrow",context:"formatting"});case"eeeeee":return s.day(u,{width:"short",context:"formatting"});case"eeee":default:return s.day(u,{width:"wide",context:"formatting"})}},c:function(o,r,s,a){const u=o.getDay(),d=(u-a.weekStartsOn+8)%7||7;switch(r){case"c":return String(d);case"cc":return we(d,r.length);case"co":return s.ordinalNumber(d,{unit:"day"});case"ccc":return s.day(u,{width:"abbreviated",context:"standalone"});case"ccccc":return s.day(u,{width:"narrow",context:"standalone"});case"cccccc":return s.day(u,{width:"short",context:"standalone"});case"cccc":default:return s.day(u,{width:"wide",context:"standalone"})}},i:function(o,r,s){const a=o.getDay(),u=a===0?7:a;switch(r){case"i":return String(u);case"ii":return we(u,r.length);case"io":return s.ordinalNumber(u,{unit:"day"});case"iii":return s.day(a,{width:"abbreviated",context:"formatting"});case"iiiii":return s.day(a,{width:"narrow",context:"formatting"});case"iiiiii":return s.day(a,{width:"short",context:"formatting"});case"iiii":default:return s.day(a,{width:"wide",context:"formatting"})}},a:function(o,r,s){const u=o.getHours()/12>=1?"pm":"am";switch(r){case"a":case"aa":return s.dayPeriod(u,{width:"abbreviated",context:"formatting"});case"aaa":return s.dayPeriod(u,{width:"abbreviated",context:"formatting"}).toLowerCase();case"aaaaa":return s.dayPeriod(u,{width:"narrow",context:"formatting"});case"aaaa":default:return s.dayPeriod(u,{width:"wide",context:"formatting"})}},b:function(o,r,s){const a=o.getHours();let u;switch(a===12?u=er.noon:a===0?u=er.midnight:u=a/12>=1?"pm":"am",r){case"b":case"bb":return s.dayPeriod(u,{width:"abbreviated",context:"formatting"});case"bbb":return s.dayPeriod(u,{width:"abbreviated",context:"formatting"}).toLowerCase();case"bbbbb":return s.dayPeriod(u,{width:"narrow",context:"formatting"});case"bbbb":default:return s.dayPeriod(u,{width:"wide",context:"formatting"})}},B:function(o,r,s){const a=o.getHours();let u;switch(a>=17?u=er.evening:a>=12?u=er.afternoon:a>=4?u=er.morning:u=er.night,r){case"B":case"BB":case"BBB":return s.dayPeriod(u,{width:"abbreviated",context:"formatting"});case"BBBBB":return s.dayPeriod(u,{width:"narrow",context:"formatting"});case"BBBB":default:return s.dayPeriod(u,{width:"wide",context:"formatting"})}},h:function(o,r,s){if(r==="ho"){let a=o.getHours()%12;return a===0&&(a=12),s.ordinalNumber(a,{unit:"hour"})}return un.h(o,r)},H:function(o,r,s){return r==="Ho"?s.ordinalNumber(o.getHours(),{unit:"hour"}):un.H(o,r)},K:function(o,r,s){const a=o.getHours()%12;return r==="Ko"?s.ordinalNumber(a,{unit:"hour"}):we(a,r.length)},k:function(o,r,s){let a=o.getHours();return a===0&&(a=24),r==="ko"?s.ordinalNumber(a,{unit:"hour"}):we(a,r.length)},m:function(o,r,s){return r==="mo"?s.ordinalNumber(o.getMinutes(),{unit:"minute"}):un.m(o,r)},s:function(o,r,s){return r==="so"?s.ordinalNumber(o.getSeconds(),{unit:"second"}):un.s(o,r)},S:function(o,r){return un.S(o,r)},X:function(o,r,s){const a=o.getTimezoneOffset();if(a===0)return"Z";switch(r){case"X":return ld(a);case"XXXX":case"XX":return Cn(a);case"XXXXX":case"XXX":default:return Cn(a,":")}},x:function(o,r,s){const a=o.getTimezoneOffset();switch(r){case"x":return ld(a);case"xxxx":case"xx":return Cn(a);case"xxxxx":case"xxx":default:return Cn(a,":")}},O:function(o,r,s){const a=o.getTimezoneOffset();switch(r){case"O":case"OO":case"OOO":return"GMT"+ad(a,":");case"OOOO":default:return"GMT"+Cn(a,":")}},z:function(o,r,s){const a=o.getTimezoneOffset();switch(r){case"z":case"zz":case"zzz":return"GMT"+ad(a,":");case"zzzz":default:return"GMT"+Cn(a,":")}},t:function(o,r,s){const a=Math.trunc(o.getTime()/1e3);return we(a,r.length)},T:function(o,r,s){const a=o.getTime();return we(a,r.length)}};function ad(o,r=""){const s=o>0?"-":"+",a=Math.abs(o),u=Math.trunc(a/60),d=a%60;return d===0?s+String(u):s+String(u)+r+we(d,2)}function ld(o,r){return o%60===0?(o>0?"-":"+")+we(Math.abs(o)/60,2):Cn(o,r)}function Cn(o,r=""){const s=o>0?"-":"+",a=Math.abs(o),u=we(Math.trunc(a/60),2),d=we(a%60,2);return s+u+r+d}const ud=(o,r)=>{switch(o){case"P":return r.date({width:"short"});case"PP":return r.date({width:"medium"});case"PPP":return r.date({width:"long"});case"PPPP":default:return r.date({width:"full"})}},of=(o,r)=>{switch(o){case"p":return r.time({width:"short"});case"pp":return r.time({width:"medium"});case"ppp":return r.time({width:"long"});case"pppp":default:return r.time({width:"full"})}},uy=(o,r)=>{const s=o.match(/(P+)(p+)?/)||[],a=s[1],u=s[2];if(!u)return ud(o,r);let d;switch(a){case"P":d=r.dateTime({width:"short"});break;case"PP":d=r.dateTime({width:"medium"});break;case"PPP":d=r.dateTime({width:"long"});break;case"PPPP":default:d=r.dateTime({width:"full"});break}return d.replace("{{date}}",ud(a,r)).replace("{{time}}",of(u,r))},cy={p:of,P:uy},dy=/^D+$/,fy=/^Y+$/,hy=["D","DD","YY","YYYY"];function py(o){return dy.test(o)}function my(o){return fy.test(o)}function gy(o,r,s){const a=yy(o,r,s);if(console.warn(a),hy.includes(o))throw new RangeError(a)}function yy(o,r,s){const a=o[0]==="Y"?"years":"days of the month";return`Use \`${o.toLowerCase()}\` instead of \`${o}\` (in \`${r}\`) for formatting ${a} to the input \`${s}\`; see: https://github.com/date-fns/date-fns/blob/master/docs/unicodeTokens.md`}const vy=/[yYQqMLwIdDecihHKkms]o|(\w)\1*|''|'(''|[^'])+('|$)|./g,xy=/P+p+|P+|p+|''|'(''|[^'])+('|$)|./g,wy=/^'([^]*?)'?$/,Sy=/''/g,ky=/[a-zA-Z]/;function cd(o,r,s){const a=Ci(),u=a.locale??sy,d=a.firstWeekContainsDate??a.locale?.options?.firstWeekContainsDate??1,h=a.weekStartsOn??a.locale?.options?.weekStartsOn??0,m=jt(o);if(!Cg(m))throw new RangeError("Invalid time value");let g=r.match(xy).map(k=>{const x=k[0];if(x==="p"||x==="P"){const L=cy[x];return L(k,u.formatLong)}return k}).join("").match(vy).map(k=>{if(k==="''")return{isToken:!1,value:"'"};const x=k[0];if(x==="'")return{isToken:!1,value:Ny(k)};if(od[x])return{isToken:!0,value:k};if(x.match(ky))throw new RangeError("Format string contains an unescaped latin alphabet character `"+x+"`");return{isToken:!1,value:k}});u.localize.preprocessor&&(g=u.localize.preprocessor(m,g));const y={firstWeekContainsDate:d,weekStartsOn:h,locale:u};return g.map(k=>{if(!k.isToken)return k.value;const x=k.value;(my(x)||py(x))&&gy(x,r,String(o));const L=od[x[0]];return L(m,x,u.localize,y)}).join("")}function Ny(o){const r=o.match(wy);return r?r[1].replace(Sy,"'"):o}function Cy({user:o}){const{t:r}=Ae(),s=_t(),a=cd(new Date(o.created_at),"dd.MM.yyyy HH:mm"),u=cd(new Date(o.last_login),"dd.MM.yyyy HH:mm");return f.jsx("div",{className:"flex justify-center cursor-pointer text-text-primary mb-4",children:f.jsxs("div",{className:"bg-surface-secondary p-4 rounded-lg border border-border-default shadow-lg hover:bg-surface-tertiary transition-colors w-full",onClick:()=>s(`/manageuser/${o.id}`),children:[f.jsxs("div",{className:"flex flex-col sm:flex-row sm:items-center sm:justify-between gap-3 mb-4",children:[f.jsxs("p",{className:"text-xl font-bold text-text-primary",children:[o.first_name," ",o.last_name]}),o.organization&&f.jsx("span",{className:"px-3 py-1 text-sm bg-blue-100 text-blue-800 rounded-full w-fit",children:o.organization.organization_name})]}),f.jsxs("div",{className:"grid grid-cols-1 sm:grid-cols-2 lg:grid-cols-3 xl:grid-cols-4 2xl:grid-cols-6 gap-4",children:[f.jsxs("div",{className:"min-w-0",children:[f.jsx("label",{className:"block text-xs font-medium text-text-secondary mb-1",children:r("Username")}),f.jsx("p",{className:"text-sm text-text-primary truncate",title:o.username,children:o.username})]}),f.jsxs("div",{className:"min-w-0",children:[f.jsx("label",{className:"block text-xs font-medium text-text-secondary mb-1",children:r("Email")}),f.jsx("p",{className:"text-sm text-text-primary truncate",title:o.email,children:o.email})]}),f.jsxs("div",{className:"min-w-0",children:[f.jsx("label",{className:"block text-xs font-medium text-text-secondary mb-1",children:r("Status")}),f.jsxs("div",{className:"flex gap-2 flex-wrap",children:[o.is_active?f.jsx("span",{className:"inline-block px-2 py-0.5 text-xs bg-green-100 text-green-800 rounded whitespace-nowrap",children:r("Active")}):f.jsx("span",{className:"inline-block px-2 py-0.5 text-xs bg-gray-100 text-gray-800 rounded whitespace-nowrap",children:r("Inactive")}),o.is_superuser&&f.jsx("span",{className:"inline-block px-2 py-0.5 text-xs bg-purple-100 text-purple-800 rounded whitespace-nowrap",children:r("Super")})]})]}),f.jsxs("div",{className:"min-w-0",children:[f.jsx("label",{className:"block text-xs font-medium text-text-secondary mb-1",children:r("Created")}),f.jsx("p",{className:"text-sm text-text-primary whitespace-nowrap",children:a})]}),f.jsxs("div",{className:"min-w-0",children:[f.jsx("label",{className:"block text-xs font-medium text-text-secondary mb-1",children:r("LastLogin")}),f.jsx("p",{className:"text-sm text-text-primary whitespace-nowrap",children:u})]}),f.jsxs("div",{className:"min-w-0 sm:col-span-2 lg:col-span-1",children:[f.jsx("label",{className:"block text-xs font-medium text-text-secondary mb-1",children:r("Groups")}),f.jsx("p",{className:"text-sm text-text-primary truncate",title:o.groups?.replace(/\|/g,", "),children:o.groups?.replace(/\|/g,", ")||r("None")})]})]})]})})}function jy({showData:o}){const[r,s]=C.useState([]),a=C.useRef(null),u=20;return C.useEffect(()=>{s(o.slice(0,u))},[o]),C.useEffect(()=>{const d=new IntersectionObserver(h=>{if(h[0].isIntersecting&&r.length<o.length){const g=o.slice(r.length,r.length+u);s(y=>[...y,...g])}},{root:null,rootMargin:"20px",threshold:.1});return a.current&&d.observe(a.current),()=>{a.current&&d.unobserve(a.current)}},[r,o]),f.jsxs("div",{className:"w-full space-y-0",children:[r.map(d=>f.jsx(Cy,{user:d},d.id)),r.length<o.length&&f.jsx("div",{ref:a,className:"w-full p-4 flex justify-center",children:f.jsx("div",{className:"animate-spin h-8 w-8 border-4 border-blue-500 rounded-full border-t-transparent"})})]})}function by(){const{userData:o}=C.useContext(et),{t:r}=Ae(),s=_t(),[a,u]=C.useState([]),[d,h]=C.useState([]),[m,g]=C.useState([]),[y,k]=C.useState([]);return C.useEffect(()=>{async function x(){if(!(!o||!o.token))try{const E=await Pe.allusers(o.token);if(!E)return;u(E),h(E)}catch(E){E instanceof Error?(console.error("Error fetching users:",E),E.name==="BulkError"&&console.error("Detailed BulkError:",E)):console.log(E)}}async function L(){try{const E=await Pe.getGroups(o.token);g(E||[])}catch(E){console.error("Error fetching groups:",E)}}async function I(){try{const E=await Pe.allOrganizations(o.token);k(E||[])}catch(E){console.error("Error fetching organizations:",E)}}x(),L(),I()},[o]),f.jsx("div",{className:"space-y-6",children:f.jsxs("div",{className:"bg-surface-primary rounded-lg border border-border-default shadow-sm",children:[f.jsxs("div",{className:"flex flex-col sm:flex-row sm:items-center sm:justify-between gap-4 p-6 border-b border-border-default",children:[f.jsx("h1",{className:"text-2xl font-semibold text-text-primary",children:r("users")}),f.jsxs("button",{onClick:()=>s("/createuser"),className:"inline-flex items-center justify-center gap-2 px-4 py-2 bg-blue-600 hover:bg-blue-700 text-white font-medium rounded-lg transition-colors duration-200 shadow-sm hover:shadow-md whitespace-nowrap",children:[f.jsx("svg",{className:"w-5 h-5",fill:"none",stroke:"currentColor",viewBox:"0 0 24 24",children:f.jsx("path",{strokeLinecap:"round",strokeLinejoin:"round",strokeWidth:2,d:"M12 4v16m8-8H4"})}),r("createNewUser")]})]}),f.jsx(vg,{setShowData:h,availableGroups:m,availableOrganizations:y,allUsers:a}),f.jsx("div",{className:"p-6 pt-0",children:d.length>0?f.jsx(jy,{showData:d}):f.jsx("div",{className:"text-center py-12 text-text-secondary",children:r("noUsersFound")})})]})})}function Ey(){const{userData:o,setToast:r}=C.useContext(et),{id:s}=Ad(),a=s??"",{t:u}=Ae(),[d,h]=C.useState(""),[m,g]=C.useState(""),[y,k]=C.useState(""),[x,L]=C.useState(""),[I,E]=C.useState(""),[z,T]=C.useState(!0),[A,H]=C.useState(!1),[Y,Q]=C.useState([]),[U,J]=C.useState([]),[ee,re]=C.useState(null),[fe,de]=C.useState([]);C.useEffect(()=>{async function Se(){const se=await Pe.getGroups(o.token);Q(se||[])}Se();async function M(){const se=await Pe.getPermissions(o.token);J(se||[])}M();async function q(){const se=await Pe.allOrganizations(o.token);de(se||[])}q();async function he(){if(console.log(a),a==="")return;const se=await Pe.oneuser(o.token,a);if(se){h(se.email),k(se.username),L(se.first_name),E(se.last_name),T(se.is_active),H(se.is_superuser),re(se.organization?.id||null);const le=se.groups?se.groups.split("|"):[];Q(_=>_.map(D=>({...D,checked:le.includes(D.name)})));const B=se.permissions?se.permissions.split("|"):[];J(_=>_.map(D=>({...D,checked:B.includes(D.name)})))}}he()},[o,a]);function Ce(){if(!A&&!ee){r({header:u("validationError"),text:u("usersNotSuperuserMustBelongToOrganization"),success:!1,show:!0});return}const Se=Y.filter(he=>he.checked).map(he=>he.name).join("|"),M=U.filter(he=>he.checked).map(he=>he.name).join("|"),q={password:m,email:d,first_name:x,last_name:I,username:y||d,is_active:z,is_superuser:A,groups:Se,permissions:M,organization_id:ee};Pe.editoneuser(o.token,a,q,r)}return f.jsx(tf,{headText:u("manageUser"),userId:a,email:d,setEmail:h,password:m,setPassword:g,username:y,setUserName:k,first_name:x,setFirst_name:L,last_name:I,setLast_name:E,isActive:z,setIsActive:T,isSuperuser:A,setIsSuperuser:H,groups:Y,setGroups:Q,permissions:U,setPermissions:J,organizationId:ee,setOrganizationId:re,organizations:fe,handleSubmit:Ce})}function Oy(){const{toast:o,setToast:r}=C.useContext(et);return C.useEffect(()=>{if(o.show){const s=setTimeout(()=>{r({...o,show:!1})},5e3);return()=>clearTimeout(s)}},[o,r]),f.jsx(f.Fragment,{children:o.show&&f.jsxs("div",{className:`
+                            transition-colors duration-200`,placeholder:x("enterSixDigitCode"),autoComplete:"one-time-code"})]})]}),f.jsx("div",{className:"pt-4",children:f.jsx(Fe,{kind:"primary",type:"submit",className:`w-full flex justify-center py-3 rounded-lg bg-interactive-default hover:bg-interactive-hover 
+                        text-text-inverse font-medium transition-colors duration-200`,children:x("signIn")})}),f.jsx("div",{className:"text-center",children:f.jsx("a",{href:"/resetpassword",className:"text-sm text-text-muted hover:text-text-primary",children:x("forgotPassword")})})]})]})})}function sg(){return f.jsxs("div",{className:"flex flex-col",children:[f.jsxs("div",{className:"flex justify-center mt-10",children:[f.jsx("div",{className:"m-0 ",children:f.jsx("img",{src:$a,width:"150px"})}),f.jsx("div",{className:"m-0 ",children:f.jsx("img",{src:Jd,width:"150px"})})]}),f.jsx("h1",{className:"text-center text-text-primary text-lg mt-4",children:"Welcome home, admin"})]})}function hi({label:o,checked:r,onChange:s}){return f.jsxs("label",{className:"flex items-center space-x-3 m-4",children:[f.jsx("input",{type:"checkbox",checked:r,onChange:a=>s(a.target.checked),className:"form-checkbox h-5 w-5 accent-brand"}),f.jsx("span",{className:"text-text-primary",children:o})]})}const ig=({password:o,setPassword:r})=>{const s=C.useRef(null),a=u=>{const d=u.target.selectionStart,h=u.target.value,m=o.length;if(h.length<m)r(o.slice(0,-1));else if(h.length>m){const g=h.charAt(d-1);r(o+g)}setTimeout(()=>{s.current&&s.current.setSelectionRange(d,d)},0)};return f.jsx("div",{className:"relative mb-4",children:f.jsx("input",{ref:s,type:"text",autoCapitalize:"off",autoComplete:"off",autoCorrect:"off",value:"•".repeat(o?.length),onChange:a,className:"block w-full rounded-md border border-border-default py-2 px-4 text-text-primary bg-surface-primary focus:outline-none focus:ring-2 focus:ring-interactive-default focus:border-transparent",placeholder:"Password"})})};var qd={color:void 0,size:void 0,className:void 0,style:void 0,attr:void 0},td=Wt.createContext&&Wt.createContext(qd),og=["attr","size","title"];function ag(o,r){if(o==null)return{};var s=lg(o,r),a,u;if(Object.getOwnPropertySymbols){var d=Object.getOwnPropertySymbols(o);for(u=0;u<d.length;u++)a=d[u],!(r.indexOf(a)>=0)&&Object.prototype.propertyIsEnumerable.call(o,a)&&(s[a]=o[a])}return s}function lg(o,r){if(o==null)return{};var s={};for(var a in o)if(Object.prototype.hasOwnProperty.call(o,a)){if(r.indexOf(a)>=0)continue;s[a]=o[a]}return s}function mi(){return mi=Object.assign?Object.assign.bind():function(o){for(var r=1;r<arguments.length;r++){var s=arguments[r];for(var a in s)Object.prototype.hasOwnProperty.call(s,a)&&(o[a]=s[a])}return o},mi.apply(this,arguments)}function nd(o,r){var s=Object.keys(o);if(Object.getOwnPropertySymbols){var a=Object.getOwnPropertySymbols(o);r&&(a=a.filter(function(u){return Object.getOwnPropertyDescriptor(o,u).enumerable})),s.push.apply(s,a)}return s}function gi(o){for(var r=1;r<arguments.length;r++){var s=arguments[r]!=null?arguments[r]:{};r%2?nd(Object(s),!0).forEach(function(a){ug(o,a,s[a])}):Object.getOwnPropertyDescriptors?Object.defineProperties(o,Object.getOwnPropertyDescriptors(s)):nd(Object(s)).forEach(function(a){Object.defineProperty(o,a,Object.getOwnPropertyDescriptor(s,a))})}return o}function ug(o,r,s){return r=cg(r),r in o?Object.defineProperty(o,r,{value:s,enumerable:!0,configurable:!0,writable:!0}):o[r]=s,o}function cg(o){var r=dg(o,"string");return typeof r=="symbol"?r:r+""}function dg(o,r){if(typeof o!="object"||!o)return o;var s=o[Symbol.toPrimitive];if(s!==void 0){var a=s.call(o,r);if(typeof a!="object")return a;throw new TypeError("@@toPrimitive must return a primitive value.")}return(r==="string"?String:Number)(o)}function ef(o){return o&&o.map((r,s)=>Wt.createElement(r.tag,gi({key:s},r.attr),ef(r.child)))}function ts(o){return r=>Wt.createElement(fg,mi({attr:gi({},o.attr)},r),ef(o.child))}function fg(o){var r=s=>{var{attr:a,size:u,title:d}=o,h=ag(o,og),m=u||s.size||"1em",g;return s.className&&(g=s.className),o.className&&(g=(g?g+" ":"")+o.className),Wt.createElement("svg",mi({stroke:"currentColor",fill:"currentColor",strokeWidth:"0"},s.attr,a,h,{className:g,style:gi(gi({color:o.color||s.color},s.style),o.style),height:m,width:m,xmlns:"http://www.w3.org/2000/svg"}),d&&Wt.createElement("title",null,d),o.children)};return td!==void 0?Wt.createElement(td.Consumer,null,s=>r(s)):r(qd)}function hg(o){return ts({attr:{viewBox:"0 0 448 512"},child:[{tag:"path",attr:{d:"M436 480h-20V24c0-13.255-10.745-24-24-24H56C42.745 0 32 10.745 32 24v456H12c-6.627 0-12 5.373-12 12v20h448v-20c0-6.627-5.373-12-12-12zM128 76c0-6.627 5.373-12 12-12h40c6.627 0 12 5.373 12 12v40c0 6.627-5.373 12-12 12h-40c-6.627 0-12-5.373-12-12V76zm0 96c0-6.627 5.373-12 12-12h40c6.627 0 12 5.373 12 12v40c0 6.627-5.373 12-12 12h-40c-6.627 0-12-5.373-12-12v-40zm52 148h-40c-6.627 0-12-5.373-12-12v-40c0-6.627 5.373-12 12-12h40c6.627 0 12 5.373 12 12v40c0 6.627-5.373 12-12 12zm76 160h-64v-84c0-6.627 5.373-12 12-12h40c6.627 0 12 5.373 12 12v84zm64-172c0 6.627-5.373 12-12 12h-40c-6.627 0-12-5.373-12-12v-40c0-6.627 5.373-12 12-12h40c6.627 0 12 5.373 12 12v40zm0-96c0 6.627-5.373 12-12 12h-40c-6.627 0-12-5.373-12-12v-40c0-6.627 5.373-12 12-12h40c6.627 0 12 5.373 12 12v40zm0-96c0 6.627-5.373 12-12 12h-40c-6.627 0-12-5.373-12-12V76c0-6.627 5.373-12 12-12h40c6.627 0 12 5.373 12 12v40z"},child:[]}]})(o)}function pg(o){return ts({attr:{viewBox:"0 0 448 512"},child:[{tag:"path",attr:{d:"M0 464c0 26.5 21.5 48 48 48h352c26.5 0 48-21.5 48-48V192H0v272zm320-196c0-6.6 5.4-12 12-12h40c6.6 0 12 5.4 12 12v40c0 6.6-5.4 12-12 12h-40c-6.6 0-12-5.4-12-12v-40zm0 128c0-6.6 5.4-12 12-12h40c6.6 0 12 5.4 12 12v40c0 6.6-5.4 12-12 12h-40c-6.6 0-12-5.4-12-12v-40zM192 268c0-6.6 5.4-12 12-12h40c6.6 0 12 5.4 12 12v40c0 6.6-5.4 12-12 12h-40c-6.6 0-12-5.4-12-12v-40zm0 128c0-6.6 5.4-12 12-12h40c6.6 0 12 5.4 12 12v40c0 6.6-5.4 12-12 12h-40c-6.6 0-12-5.4-12-12v-40zM64 268c0-6.6 5.4-12 12-12h40c6.6 0 12 5.4 12 12v40c0 6.6-5.4 12-12 12H76c-6.6 0-12-5.4-12-12v-40zm0 128c0-6.6 5.4-12 12-12h40c6.6 0 12 5.4 12 12v40c0 6.6-5.4 12-12 12H76c-6.6 0-12-5.4-12-12v-40zM400 64h-48V16c0-8.8-7.2-16-16-16h-32c-8.8 0-16 7.2-16 16v48H160V16c0-8.8-7.2-16-16-16h-32c-8.8 0-16 7.2-16 16v48H48C21.5 64 0 85.5 0 112v48h448v-48c0-26.5-21.5-48-48-48z"},child:[]}]})(o)}function mg(o){return ts({attr:{viewBox:"0 0 576 512"},child:[{tag:"path",attr:{d:"M402.6 83.2l90.2 90.2c3.8 3.8 3.8 10 0 13.8L274.4 405.6l-92.8 10.3c-12.4 1.4-22.9-9.1-21.5-21.5l10.3-92.8L388.8 83.2c3.8-3.8 10-3.8 13.8 0zm162-22.9l-48.8-48.8c-15.2-15.2-39.9-15.2-55.2 0l-35.4 35.4c-3.8 3.8-3.8 10 0 13.8l90.2 90.2c3.8 3.8 10 3.8 13.8 0l35.4-35.4c15.2-15.3 15.2-40 0-55.2zM384 346.2V448H64V128h229.8c3.2 0 6.2-1.3 8.5-3.5l40-40c7.6-7.6 2.2-20.5-8.5-20.5H48C21.5 64 0 85.5 0 112v352c0 26.5 21.5 48 48 48h352c26.5 0 48-21.5 48-48V306.2c0-10.7-12.9-16-20.5-8.5l-40 40c-2.2 2.3-3.5 5.3-3.5 8.5z"},child:[]}]})(o)}function gg(o){return ts({attr:{viewBox:"0 0 512 512"},child:[{tag:"path",attr:{d:"M502.3 190.8c3.9-3.1 9.7-.2 9.7 4.7V400c0 26.5-21.5 48-48 48H48c-26.5 0-48-21.5-48-48V195.6c0-5 5.7-7.8 9.7-4.7 22.4 17.4 52.1 39.5 154.1 113.6 21.1 15.4 56.7 47.8 92.2 47.6 35.7.3 72-32.8 92.3-47.6 102-74.1 131.6-96.3 154-113.7zM256 320c23.2.4 56.6-29.2 73.4-41.4 132.7-96.3 142.8-104.7 173.4-128.7 5.8-4.5 9.2-11.5 9.2-18.9v-19c0-26.5-21.5-48-48-48H48C21.5 64 0 85.5 0 112v19c0 7.4 3.4 14.3 9.2 18.9 30.6 23.9 40.7 32.4 173.4 128.7 16.8 12.2 50.2 41.8 73.4 41.4z"},child:[]}]})(o)}function rd(o){return ts({attr:{viewBox:"0 0 512 512"},child:[{tag:"path",attr:{d:"M256 8C119.043 8 8 119.083 8 256c0 136.997 111.043 248 248 248s248-111.003 248-248C504 119.083 392.957 8 256 8zm0 110c23.196 0 42 18.804 42 42s-18.804 42-42 42-42-18.804-42-42 18.804-42 42-42zm56 254c0 6.627-5.373 12-12 12h-88c-6.627 0-12-5.373-12-12v-24c0-6.627 5.373-12 12-12h12v-64h-12c-6.627 0-12-5.373-12-12v-24c0-6.627 5.373-12 12-12h64c6.627 0 12 5.373 12 12v100h12c6.627 0 12 5.373 12 12v24z"},child:[]}]})(o)}function tf({headText:o,userId:r,email:s,setEmail:a,password:u,setPassword:d,username:h,setUserName:m,first_name:g,setFirst_name:y,last_name:k,setLast_name:x,isActive:L,setIsActive:I,isSuperuser:E,setIsSuperuser:z,groups:T,setGroups:A,permissions:H,setPermissions:Y,organizationId:Q,setOrganizationId:U,organizations:J,handleSubmit:ee}){const{t:re}=Ae(),{userData:fe,setToast:de}=C.useContext(et),[Ce,Se]=C.useState(!1),[M,q]=C.useState(!1);function he(D,S){const P=[...T];P[D].checked=S,A(P)}function se(D,S){const P=[...H];P[D].checked=S,Y(P)}function le(){Pe.deleteUser(fe.token,r,de)}const B=!E,_=B&&!Q;return f.jsxs("div",{className:"bg-surface-primary rounded-lg border border-border-default p-6",children:[f.jsx("h1",{className:"text-2xl font-semibold text-text-primary mb-6",children:o}),f.jsxs("div",{className:"space-y-6",children:[f.jsxs("div",{className:"grid grid-cols-1 md:grid-cols-2 lg:grid-cols-3 gap-4",children:[f.jsxs("div",{children:[f.jsx("label",{className:"block text-sm font-medium text-text-secondary mb-2",children:re("Email")}),f.jsx("input",{type:"email",value:s,onChange:D=>a(D.target.value),className:"w-full rounded-lg border border-border-default px-4 py-2 text-text-primary bg-surface-secondary focus:outline-none focus:ring-2 focus:ring-brand focus:border-transparent",placeholder:re("EmailPlaceholder")})]}),f.jsxs("div",{children:[f.jsx("label",{className:"block text-sm font-medium text-text-secondary mb-2",children:re("Password")}),f.jsx(ig,{password:u,setPassword:d})]}),f.jsxs("div",{children:[f.jsx("label",{className:"block text-sm font-medium text-text-secondary mb-2",children:re("Username")}),f.jsx("input",{type:"text",value:h,onChange:D=>m(D.target.value),className:"w-full rounded-lg border border-border-default px-4 py-2 text-text-primary bg-surface-secondary focus:outline-none focus:ring-2 focus:ring-brand focus:border-transparent",placeholder:re("UsernamePlaceholder")})]}),f.jsxs("div",{children:[f.jsx("label",{className:"block text-sm font-medium text-text-secondary mb-2",children:re("FirstName")}),f.jsx("input",{type:"text",value:g,onChange:D=>y(D.target.value),className:"w-full rounded-lg border border-border-default px-4 py-2 text-text-primary bg-surface-secondary focus:outline-none focus:ring-2 focus:ring-brand focus:border-transparent",placeholder:re("FirstNamePlaceholder")})]}),f.jsxs("div",{children:[f.jsx("label",{className:"block text-sm font-medium text-text-secondary mb-2",children:re("LastName")}),f.jsx("input",{type:"text",value:k,onChange:D=>x(D.target.value),className:"w-full rounded-lg border border-border-default px-4 py-2 text-text-primary bg-surface-secondary focus:outline-none focus:ring-2 focus:ring-brand focus:border-transparent",placeholder:re("LastNamePlaceholder")})]})]}),f.jsxs("div",{children:[f.jsxs("label",{className:"block text-sm font-medium text-text-secondary mb-2",children:[re("Organization")," ",B&&f.jsx("span",{className:"text-red-500",children:"*"})]}),f.jsxs("select",{value:Q||"",onChange:D=>U(D.target.value||null),className:`w-full rounded-lg border ${_?"border-red-500":"border-border-default"} px-4 py-2 text-text-primary bg-surface-secondary focus:outline-none focus:ring-2 focus:ring-brand focus:border-transparent`,disabled:E,children:[f.jsx("option",{value:"",children:re("NoOrganization")}),J.map(D=>f.jsx("option",{value:D.id||"",children:D.organization_name},D.id))]}),_&&f.jsx("p",{className:"mt-1 text-sm text-red-500",children:re("OrganizationRequiredWarning")}),E&&f.jsx("p",{className:"mt-1 text-sm text-text-muted",children:re("OrganizationOptionalForSuperusers")})]}),f.jsxs("div",{className:"flex gap-6 p-4 bg-surface-secondary rounded-lg",children:[f.jsx(hi,{label:re("IsActive"),checked:L,onChange:()=>I(!L)}),f.jsx(hi,{label:re("IsSuperuser"),checked:E,onChange:()=>{z(!E)}})]}),f.jsxs("div",{children:[f.jsxs("div",{className:"flex items-center gap-2 mb-4",children:[f.jsx("h2",{className:"text-lg font-medium text-text-primary",children:re("Groups")}),f.jsx(rd,{className:"w-5 h-5 text-accent-primary cursor-pointer hover:text-accent-secondary",onClick:()=>Se(!Ce)})]}),Ce&&f.jsxs("div",{className:"mb-4 p-4 bg-info/10 rounded-lg text-text-primary",children:[f.jsx("p",{className:"mb-2",children:re("GroupDescription1")}),f.jsx("p",{children:re("GroupDescription2")})]}),f.jsx("div",{className:"grid grid-cols-1 sm:grid-cols-2 lg:grid-cols-3 gap-3 p-4 bg-surface-secondary rounded-lg max-h-48 overflow-y-auto",children:T.map((D,S)=>f.jsx(hi,{label:D.name,checked:D.checked,onChange:P=>he(S,P)},D.id))})]}),f.jsxs("div",{children:[f.jsxs("div",{className:"flex items-center gap-2 mb-4",children:[f.jsx("h2",{className:"text-lg font-medium text-text-primary",children:re("PurePermissions")}),f.jsx(rd,{className:"w-5 h-5 text-accent-primary cursor-pointer hover:text-accent-secondary",onClick:()=>q(!M)})]}),M&&f.jsxs("div",{className:"mb-4 p-4 bg-info/10 rounded-lg text-text-primary",children:[f.jsx("p",{className:"mb-2",children:re("PurePermissionsDescription1")}),f.jsx("p",{children:re("PurePermissionsDescription2")})]}),f.jsx("div",{className:"grid grid-cols-1 sm:grid-cols-2 lg:grid-cols-3 gap-3 p-4 bg-surface-secondary rounded-lg max-h-48 overflow-y-auto",children:H.map((D,S)=>f.jsx(hi,{label:D.name,checked:D.checked,onChange:P=>se(S,P)},D.id))})]}),f.jsxs("div",{className:"flex gap-4 pt-4",children:[f.jsx(Fe,{onClick:ee,kind:"primary",children:re(o==="Create user"?"CreateUser":"SaveChanges")}),o!=="Create user"&&f.jsx(Fe,{onClick:le,kind:"danger",children:re("DeleteUser")})]})]})]})}function yg(){const{userData:o,setToast:r}=C.useContext(et),{t:s}=Ae(),[a,u]=C.useState(""),[d,h]=C.useState(""),[m,g]=C.useState(""),[y,k]=C.useState(""),[x,L]=C.useState(""),[I,E]=C.useState(!0),[z,T]=C.useState(!1),[A,H]=C.useState([]),[Y,Q]=C.useState([]),[U,J]=C.useState(null),[ee,re]=C.useState([]);C.useEffect(()=>{async function de(){const M=await Pe.getGroups(o.token);H(M||[])}de();async function Ce(){const M=await Pe.getPermissions(o.token);Q(M||[])}Ce();async function Se(){const M=await Pe.allOrganizations(o.token);re(M||[])}Se()},[o.token]);function fe(){if(!z&&!U){r({header:s("validationError"),text:s("usersNotSuperuserMustBelongToOrganization"),success:!1,show:!0});return}const de=A.filter(M=>M.checked).map(M=>M.name).join("|"),Ce=Y.filter(M=>M.checked).map(M=>M.name).join("|"),Se={email:a,password:d,first_name:y,last_name:x,username:m||a,is_active:I,is_superuser:z,groups:de,permissions:Ce,organization_id:U};Pe.createoneuser(o.token,Se,r)}return f.jsx(tf,{userId:"",headText:s("createUser"),email:a,setEmail:u,password:d,setPassword:h,username:m,setUserName:g,first_name:y,setFirst_name:k,last_name:x,setLast_name:L,isActive:I,setIsActive:E,isSuperuser:z,setIsSuperuser:T,groups:A,setGroups:H,permissions:Y,setPermissions:Q,organizationId:U,setOrganizationId:J,organizations:ee,handleSubmit:fe})}function vg({setShowData:o,availableGroups:r,availableOrganizations:s,allUsers:a}){const{t:u}=Ae(),[d,h]=gm(),[m,g]=C.useState(d.get("username")||""),[y,k]=C.useState(d.get("first_name")||""),[x,L]=C.useState(d.get("last_name")||""),[I,E]=C.useState(d.get("email")||""),[z,T]=C.useState(d.get("is_active")!=="false"),[A,H]=C.useState(d.get("is_superuser")==="true"),[Y,Q]=C.useState(d.get("created_at")||""),[U,J]=C.useState(d.get("last_login")||""),[ee,re]=C.useState(d.get("group")||""),[fe,de]=C.useState(d.get("organization")||"");C.useEffect(()=>{const M=new URLSearchParams;m&&M.set("username",m),y&&M.set("first_name",y),x&&M.set("last_name",x),I&&M.set("email",I),z||M.set("is_active","false"),A&&M.set("is_superuser","true"),Y&&M.set("created_at",Y),U&&M.set("last_login",U),ee&&M.set("group",ee),fe&&M.set("organization",fe),h(M,{replace:!0})},[m,y,x,I,z,A,Y,U,ee,fe,h]);function Ce(){try{let M=a;m&&(M=M.filter(q=>q.username.toLowerCase().includes(m.toLowerCase()))),y&&(M=M.filter(q=>q.first_name.toLowerCase().includes(y.toLowerCase()))),x&&(M=M.filter(q=>q.last_name.toLowerCase().includes(x.toLowerCase()))),I&&(M=M.filter(q=>q.email.toLowerCase().includes(I.toLowerCase()))),M=M.filter(q=>q.is_active===z),M=M.filter(q=>q.is_superuser===A),ee&&(M=M.filter(q=>q.groups.split("|").includes(ee))),fe&&(fe==="none"?M=M.filter(q=>!q.organization):M=M.filter(q=>q.organization?.id===fe)),Y&&(M=[...M].sort((q,he)=>Y==="asc"?new Date(q.created_at).getTime()-new Date(he.created_at).getTime():new Date(he.created_at).getTime()-new Date(q.created_at).getTime())),U&&(M=[...M].sort((q,he)=>U==="asc"?new Date(q.last_login).getTime()-new Date(he.last_login).getTime():new Date(he.last_login).getTime()-new Date(q.last_login).getTime())),o(M)}catch(M){console.log(M)}}function Se(){g(""),k(""),L(""),E(""),T(!0),H(!1),re(""),de(""),Q(""),J(""),o(a)}return C.useEffect(()=>{Ce()},[Y,U,ee,fe,z,A]),C.useEffect(()=>{a.length>0&&Ce()},[a]),f.jsxs("div",{className:"p-6 space-y-4 bg-surface-secondary border-b border-border-default",children:[f.jsxs("div",{className:"grid grid-cols-1 sm:grid-cols-2 lg:grid-cols-4 gap-3",children:[f.jsx("input",{type:"text",placeholder:u("Username"),value:m,onChange:M=>g(M.target.value),className:"px-4 py-2 rounded-lg bg-surface-primary text-text-primary border-2 border-border-default placeholder-text-muted focus:outline-none focus:ring-2 focus:ring-blue-500 focus:border-transparent"}),f.jsx("input",{type:"text",placeholder:u("FirstName"),value:y,onChange:M=>k(M.target.value),className:"px-4 py-2 rounded-lg bg-surface-primary text-text-primary border-2 border-border-default placeholder-text-muted focus:outline-none focus:ring-2 focus:ring-blue-500 focus:border-transparent"}),f.jsx("input",{type:"text",placeholder:u("LastName"),value:x,onChange:M=>L(M.target.value),className:"px-4 py-2 rounded-lg bg-surface-primary text-text-primary border-2 border-border-default placeholder-text-muted focus:outline-none focus:ring-2 focus:ring-blue-500 focus:border-transparent"}),f.jsx("input",{type:"email",placeholder:u("Email"),value:I,onChange:M=>E(M.target.value),className:"px-4 py-2 rounded-lg bg-surface-primary text-text-primary border-2 border-border-default placeholder-text-muted focus:outline-none focus:ring-2 focus:ring-blue-500 focus:border-transparent"})]}),f.jsxs("div",{className:"grid grid-cols-1 sm:grid-cols-2 lg:grid-cols-3 xl:grid-cols-6 gap-3",children:[f.jsxs("div",{className:"flex flex-col justify-center gap-2 px-3 py-2 bg-surface-primary rounded-lg border border-border-default",children:[f.jsxs("label",{className:"flex items-center text-sm text-text-primary",children:[f.jsx("input",{type:"checkbox",checked:z,onChange:M=>T(M.target.checked),className:"mr-2 accent-blue-600 w-4 h-4"}),f.jsx("span",{className:"whitespace-nowrap",children:u("IsActive")})]}),f.jsxs("label",{className:"flex items-center text-sm text-text-primary",children:[f.jsx("input",{type:"checkbox",checked:A,onChange:M=>H(M.target.checked),className:"mr-2 accent-blue-600 w-4 h-4"}),f.jsx("span",{className:"whitespace-nowrap",children:u("IsSuperuser")})]})]}),f.jsxs("select",{value:ee,onChange:M=>re(M.target.value),className:"px-4 py-2 rounded-lg bg-surface-primary text-text-primary border-2 border-border-default focus:outline-none focus:ring-2 focus:ring-blue-500 focus:border-transparent",children:[f.jsx("option",{value:"",children:u("AllGroups")}),r.map(M=>f.jsx("option",{value:M.name,children:M.name},M.id))]}),f.jsxs("select",{value:fe,onChange:M=>de(M.target.value),className:"px-4 py-2 rounded-lg bg-surface-primary text-text-primary border-2 border-border-default focus:outline-none focus:ring-2 focus:ring-blue-500 focus:border-transparent",children:[f.jsx("option",{value:"",children:u("AllOrganizations")}),f.jsx("option",{value:"none",children:u("NoOrganization")}),s.map(M=>f.jsx("option",{value:M.id||"",children:M.organization_name},M.id))]}),f.jsxs("div",{className:"flex items-center gap-2 px-3 py-2 bg-surface-primary rounded-lg border border-border-default",children:[f.jsx("label",{className:"text-sm text-text-primary whitespace-nowrap",children:u("Created")}),f.jsx("button",{onClick:()=>{Q(Y==="asc"?"desc":"asc")},className:"ml-auto px-3 py-1 rounded border-2 border-border-default bg-surface-secondary hover:bg-surface-tertiary text-text-primary transition-colors",title:Y==="asc"?"Oldest first":"Newest first",children:Y==="asc"?"↑":"↓"})]}),f.jsxs("div",{className:"flex items-center gap-2 px-3 py-2 bg-surface-primary rounded-lg border border-border-default",children:[f.jsx("label",{className:"text-sm text-text-primary whitespace-nowrap",children:u("LastLogin")}),f.jsx("button",{onClick:()=>{J(U==="asc"?"desc":"asc")},className:"ml-auto px-3 py-1 rounded border-2 border-border-default bg-surface-secondary hover:bg-surface-tertiary text-text-primary transition-colors",title:U==="asc"?"Oldest first":"Newest first",children:U==="asc"?"↑":"↓"})]}),f.jsxs("div",{className:"flex gap-2 sm:col-span-2 lg:col-span-3 xl:col-span-1",children:[f.jsx(Fe,{kind:"primary",onClick:Ce,className:"flex-1 bg-blue-600 hover:bg-blue-700 text-white",children:u("Search")}),f.jsx(Fe,{kind:"secondary",onClick:Se,className:"flex-1 bg-surface-secondary hover:bg-surface-tertiary text-text-primary border-2 border-border-default",children:u("ShowAll")})]})]})]})}function jt(o){const r=Object.prototype.toString.call(o);return o instanceof Date||typeof o=="object"&&r==="[object Date]"?new o.constructor(+o):typeof o=="number"||r==="[object Number]"||typeof o=="string"||r==="[object String]"?new Date(o):new Date(NaN)}function En(o,r){return o instanceof Date?new o.constructor(r):new Date(r)}const nf=6048e5,xg=864e5;let wg={};function Ci(){return wg}function Jr(o,r){const s=Ci(),a=r?.weekStartsOn??r?.locale?.options?.weekStartsOn??s.weekStartsOn??s.locale?.options?.weekStartsOn??0,u=jt(o),d=u.getDay(),h=(d<a?7:0)+d-a;return u.setDate(u.getDate()-h),u.setHours(0,0,0,0),u}function yi(o){return Jr(o,{weekStartsOn:1})}function rf(o){const r=jt(o),s=r.getFullYear(),a=En(o,0);a.setFullYear(s+1,0,4),a.setHours(0,0,0,0);const u=yi(a),d=En(o,0);d.setFullYear(s,0,4),d.setHours(0,0,0,0);const h=yi(d);return r.getTime()>=u.getTime()?s+1:r.getTime()>=h.getTime()?s:s-1}function sd(o){const r=jt(o);return r.setHours(0,0,0,0),r}function id(o){const r=jt(o),s=new Date(Date.UTC(r.getFullYear(),r.getMonth(),r.getDate(),r.getHours(),r.getMinutes(),r.getSeconds(),r.getMilliseconds()));return s.setUTCFullYear(r.getFullYear()),+o-+s}function Sg(o,r){const s=sd(o),a=sd(r),u=+s-id(s),d=+a-id(a);return Math.round((u-d)/xg)}function kg(o){const r=rf(o),s=En(o,0);return s.setFullYear(r,0,4),s.setHours(0,0,0,0),yi(s)}function Ng(o){return o instanceof Date||typeof o=="object"&&Object.prototype.toString.call(o)==="[object Date]"}function Cg(o){if(!Ng(o)&&typeof o!="number")return!1;const r=jt(o);return!isNaN(Number(r))}function jg(o){const r=jt(o),s=En(o,0);return s.setFullYear(r.getFullYear(),0,1),s.setHours(0,0,0,0),s}const bg={lessThanXSeconds:{one:"less than a second",other:"less than {{count}} seconds"},xSeconds:{one:"1 second",other:"{{count}} seconds"},halfAMinute:"half a minute",lessThanXMinutes:{one:"less than a minute",other:"less than {{count}} minutes"},xMinutes:{one:"1 minute",other:"{{count}} minutes"},aboutXHours:{one:"about 1 hour",other:"about {{count}} hours"},xHours:{one:"1 hour",other:"{{count}} hours"},xDays:{one:"1 day",other:"{{count}} days"},aboutXWeeks:{one:"about 1 week",other:"about {{count}} weeks"},xWeeks:{one:"1 week",other:"{{count}} weeks"},aboutXMonths:{one:"about 1 month",other:"about {{count}} months"},xMonths:{one:"1 month",other:"{{count}} months"},aboutXYears:{one:"about 1 year",other:"about {{count}} years"},xYears:{one:"1 year",other:"{{count}} years"},overXYears:{one:"over 1 year",other:"over {{count}} years"},almostXYears:{one:"almost 1 year",other:"almost {{count}} years"}},Eg=(o,r,s)=>{let a;const u=bg[o];return typeof u=="string"?a=u:r===1?a=u.one:a=u.other.replace("{{count}}",r.toString()),s?.addSuffix?s.comparison&&s.comparison>0?"in "+a:a+" ago":a};function ba(o){return(r={})=>{const s=r.width?String(r.width):o.defaultWidth;return o.formats[s]||o.formats[o.defaultWidth]}}const Og={full:"EEEE, MMMM do, y",long:"MMMM do, y",medium:"MMM d, y",short:"MM/dd/yyyy"},Pg={full:"h:mm:ss a zzzz",long:"h:mm:ss a z",medium:"h:mm:ss a",short:"h:mm a"},Lg={full:"{{date}} 'at' {{time}}",long:"{{date}} 'at' {{time}}",medium:"{{date}}, {{time}}",short:"{{date}}, {{time}}"},zg={date:ba({formats:Og,defaultWidth:"full"}),time:ba({formats:Pg,defaultWidth:"full"}),dateTime:ba({formats:Lg,defaultWidth:"full"})},Tg={lastWeek:"'last' eeee 'at' p",yesterday:"'yesterday at' p",today:"'today at' p",tomorrow:"'tomorrow at' p",nextWeek:"eeee 'at' p",other:"P"},_g=(o,r,s,a)=>Tg[o];function Yr(o){return(r,s)=>{const a=s?.context?String(s.context):"standalone";let u;if(a==="formatting"&&o.formattingValues){const h=o.defaultFormattingWidth||o.defaultWidth,m=s?.width?String(s.width):h;u=o.formattingValues[m]||o.formattingValues[h]}else{const h=o.defaultWidth,m=s?.width?String(s.width):o.defaultWidth;u=o.values[m]||o.values[h]}const d=o.argumentCallback?o.argumentCallback(r):r;return u[d]}}const Ig={narrow:["B","A"],abbreviated:["BC","AD"],wide:["Before Christ","Anno Domini"]},Dg={narrow:["1","2","3","4"],abbreviated:["Q1","Q2","Q3","Q4"],wide:["1st quarter","2nd quarter","3rd quarter","4th quarter"]},Mg={narrow:["J","F","M","A","M","J","J","A","S","O","N","D"],abbreviated:["Jan","Feb","Mar","Apr","May","Jun","Jul","Aug","Sep","Oct","Nov","Dec"],wide:["January","February","March","April","May","June","July","August","September","October","November","December"]},Rg={narrow:["S","M","T","W","T","F","S"],short:["Su","Mo","Tu","We","Th","Fr","Sa"],abbreviated:["Sun","Mon","Tue","Wed","Thu","Fri","Sat"],wide:["Sunday","Monday","Tuesday","Wednesday","Thursday","Friday","Saturday"]},Fg={narrow:{am:"a",pm:"p",midnight:"mi",noon:"n",morning:"morning",afternoon:"afternoon",evening:"evening",night:"night"},abbreviated:{am:"AM",pm:"PM",midnight:"midnight",noon:"noon",morning:"morning",afternoon:"afternoon",evening:"evening",night:"night"},wide:{am:"a.m.",pm:"p.m.",midnight:"midnight",noon:"noon",morning:"morning",afternoon:"afternoon",evening:"evening",night:"night"}},$g={narrow:{am:"a",pm:"p",midnight:"mi",noon:"n",morning:"in the morning",afternoon:"in the afternoon",evening:"in the evening",night:"at night"},abbreviated:{am:"AM",pm:"PM",midnight:"midnight",noon:"noon",morning:"in the morning",afternoon:"in the afternoon",evening:"in the evening",night:"at night"},wide:{am:"a.m.",pm:"p.m.",midnight:"midnight",noon:"noon",morning:"in the morning",afternoon:"in the afternoon",evening:"in the evening",night:"at night"}},Ag=(o,r)=>{const s=Number(o),a=s%100;if(a>20||a<10)switch(a%10){case 1:return s+"st";case 2:return s+"nd";case 3:return s+"rd"}return s+"th"},Ug={ordinalNumber:Ag,era:Yr({values:Ig,defaultWidth:"wide"}),quarter:Yr({values:Dg,defaultWidth:"wide",argumentCallback:o=>o-1}),month:Yr({values:Mg,defaultWidth:"wide"}),day:Yr({values:Rg,defaultWidth:"wide"}),dayPeriod:Yr({values:Fg,defaultWidth:"wide",formattingValues:$g,defaultFormattingWidth:"wide"})};function Gr(o){return(r,s={})=>{const a=s.width,u=a&&o.matchPatterns[a]||o.matchPatterns[o.defaultMatchWidth],d=r.match(u);if(!d)return null;const h=d[0],m=a&&o.parsePatterns[a]||o.parsePatterns[o.defaultParseWidth],g=Array.isArray(m)?Bg(m,x=>x.test(h)):Wg(m,x=>x.test(h));let y;y=o.valueCallback?o.valueCallback(g):g,y=s.valueCallback?s.valueCallback(y):y;const k=r.slice(h.length);return{value:y,rest:k}}}function Wg(o,r){for(const s in o)if(Object.prototype.hasOwnProperty.call(o,s)&&r(o[s]))return s}function Bg(o,r){for(let s=0;s<o.length;s++)if(r(o[s]))return s}function Hg(o){return(r,s={})=>{const a=r.match(o.matchPattern);if(!a)return null;const u=a[0],d=r.match(o.parsePattern);if(!d)return null;let h=o.valueCallback?o.valueCallback(d[0]):d[0];h=s.valueCallback?s.valueCallback(h):h;const m=r.slice(u.length);return{value:h,rest:m}}}const Vg=/^(\d+)(th|st|nd|rd)?/i,Yg=/\d+/i,Gg={narrow:/^(b|a)/i,abbreviated:/^(b\.?\s?c\.?|b\.?\s?c\.?\s?e\.?|a\.?\s?d\.?|c\.?\s?e\.?)/i,wide:/^(before christ|before common era|anno domini|common era)/i},Zg={any:[/^b/i,/^(a|c)/i]},Qg={narrow:/^[1234]/i,abbreviated:/^q[1234]/i,wide:/^[1234](th|st|nd|rd)? quarter/i},Kg={any:[/1/i,/2/i,/3/i,/4/i]},Xg={narrow:/^[jfmasond]/i,abbreviated:/^(jan|feb|mar|apr|may|jun|jul|aug|sep|oct|nov|dec)/i,wide:/^(january|february|march|april|may|june|july|august|september|october|november|december)/i},Jg={narrow:[/^j/i,/^f/i,/^m/i,/^a/i,/^m/i,/^j/i,/^j/i,/^a/i,/^s/i,/^o/i,/^n/i,/^d/i],any:[/^ja/i,/^f/i,/^mar/i,/^ap/i,/^may/i,/^jun/i,/^jul/i,/^au/i,/^s/i,/^o/i,/^n/i,/^d/i]},qg={narrow:/^[smtwf]/i,short:/^(su|mo|tu|we|th|fr|sa)/i,abbreviated:/^(sun|mon|tue|wed|thu|fri|sat)/i,wide:/^(sunday|monday|tuesday|wednesday|thursday|friday|saturday)/i},ey={narrow:[/^s/i,/^m/i,/^t/i,/^w/i,/^t/i,/^f/i,/^s/i],any:[/^su/i,/^m/i,/^tu/i,/^w/i,/^th/i,/^f/i,/^sa/i]},ty={narrow:/^(a|p|mi|n|(in the|at) (morning|afternoon|evening|night))/i,any:/^([ap]\.?\s?m\.?|midnight|noon|(in the|at) (morning|afternoon|evening|night))/i},ny={any:{am:/^a/i,pm:/^p/i,midnight:/^mi/i,noon:/^no/i,morning:/morning/i,afternoon:/afternoon/i,evening:/evening/i,night:/night/i}},ry={ordinalNumber:Hg({matchPattern:Vg,parsePattern:Yg,valueCallback:o=>parseInt(o,10)}),era:Gr({matchPatterns:Gg,defaultMatchWidth:"wide",parsePatterns:Zg,defaultParseWidth:"any"}),quarter:Gr({matchPatterns:Qg,defaultMatchWidth:"wide",parsePatterns:Kg,defaultParseWidth:"any",valueCallback:o=>o+1}),month:Gr({matchPatterns:Xg,defaultMatchWidth:"wide",parsePatterns:Jg,defaultParseWidth:"any"}),day:Gr({matchPatterns:qg,defaultMatchWidth:"wide",parsePatterns:ey,defaultParseWidth:"any"}),dayPeriod:Gr({matchPatterns:ty,defaultMatchWidth:"any",parsePatterns:ny,defaultParseWidth:"any"})},sy={code:"en-US",formatDistance:Eg,formatLong:zg,formatRelative:_g,localize:Ug,match:ry,options:{weekStartsOn:0,firstWeekContainsDate:1}};function iy(o){const r=jt(o);return Sg(r,jg(r))+1}function oy(o){const r=jt(o),s=+yi(r)-+kg(r);return Math.round(s/nf)+1}function sf(o,r){const s=jt(o),a=s.getFullYear(),u=Ci(),d=r?.firstWeekContainsDate??r?.locale?.options?.firstWeekContainsDate??u.firstWeekContainsDate??u.locale?.options?.firstWeekContainsDate??1,h=En(o,0);h.setFullYear(a+1,0,d),h.setHours(0,0,0,0);const m=Jr(h,r),g=En(o,0);g.setFullYear(a,0,d),g.setHours(0,0,0,0);const y=Jr(g,r);return s.getTime()>=m.getTime()?a+1:s.getTime()>=y.getTime()?a:a-1}function ay(o,r){const s=Ci(),a=r?.firstWeekContainsDate??r?.locale?.options?.firstWeekContainsDate??s.firstWeekContainsDate??s.locale?.options?.firstWeekContainsDate??1,u=sf(o,r),d=En(o,0);return d.setFullYear(u,0,a),d.setHours(0,0,0,0),Jr(d,r)}function ly(o,r){const s=jt(o),a=+Jr(s,r)-+ay(s,r);return Math.round(a/nf)+1}function we(o,r){const s=o<0?"-":"",a=Math.abs(o).toString().padStart(r,"0");return s+a}const un={y(o,r){const s=o.getFullYear(),a=s>0?s:1-s;return we(r==="yy"?a%100:a,r.length)},M(o,r){const s=o.getMonth();return r==="M"?String(s+1):we(s+1,2)},d(o,r){return we(o.getDate(),r.length)},a(o,r){const s=o.getHours()/12>=1?"pm":"am";switch(r){case"a":case"aa":return s.toUpperCase();case"aaa":return s;case"aaaaa":return s[0];case"aaaa":default:return s==="am"?"a.m.":"p.m."}},h(o,r){return we(o.getHours()%12||12,r.length)},H(o,r){return we(o.getHours(),r.length)},m(o,r){return we(o.getMinutes(),r.length)},s(o,r){return we(o.getSeconds(),r.length)},S(o,r){const s=r.length,a=o.getMilliseconds(),u=Math.trunc(a*Math.pow(10,s-3));return we(u,r.length)}},er={midnight:"midnight",noon:"noon",morning:"morning",afternoon:"afternoon",evening:"evening",night:"night"},od={G:function(o,r,s){const a=o.getFullYear()>0?1:0;switch(r){case"G":case"GG":case"GGG":return s.era(a,{width:"abbreviated"});case"GGGGG":return s.era(a,{width:"narrow"});case"GGGG":default:return s.era(a,{width:"wide"})}},y:function(o,r,s){if(r==="yo"){const a=o.getFullYear(),u=a>0?a:1-a;return s.ordinalNumber(u,{unit:"year"})}return un.y(o,r)},Y:function(o,r,s,a){const u=sf(o,a),d=u>0?u:1-u;if(r==="YY"){const h=d%100;return we(h,2)}return r==="Yo"?s.ordinalNumber(d,{unit:"year"}):we(d,r.length)},R:function(o,r){const s=rf(o);return we(s,r.length)},u:function(o,r){const s=o.getFullYear();return we(s,r.length)},Q:function(o,r,s){const a=Math.ceil((o.getMonth()+1)/3);switch(r){case"Q":return String(a);case"QQ":return we(a,2);case"Qo":return s.ordinalNumber(a,{unit:"quarter"});case"QQQ":return s.quarter(a,{width:"abbreviated",context:"formatting"});case"QQQQQ":return s.quarter(a,{width:"narrow",context:"formatting"});case"QQQQ":default:return s.quarter(a,{width:"wide",context:"formatting"})}},q:function(o,r,s){const a=Math.ceil((o.getMonth()+1)/3);switch(r){case"q":return String(a);case"qq":return we(a,2);case"qo":return s.ordinalNumber(a,{unit:"quarter"});case"qqq":return s.quarter(a,{width:"abbreviated",context:"standalone"});case"qqqqq":return s.quarter(a,{width:"narrow",context:"standalone"});case"qqqq":default:return s.quarter(a,{width:"wide",context:"standalone"})}},M:function(o,r,s){const a=o.getMonth();switch(r){case"M":case"MM":return un.M(o,r);case"Mo":return s.ordinalNumber(a+1,{unit:"month"});case"MMM":return s.month(a,{width:"abbreviated",context:"formatting"});case"MMMMM":return s.month(a,{width:"narrow",context:"formatting"});case"MMMM":default:return s.month(a,{width:"wide",context:"formatting"})}},L:function(o,r,s){const a=o.getMonth();switch(r){case"L":return String(a+1);case"LL":return we(a+1,2);case"Lo":return s.ordinalNumber(a+1,{unit:"month"});case"LLL":return s.month(a,{width:"abbreviated",context:"standalone"});case"LLLLL":return s.month(a,{width:"narrow",context:"standalone"});case"LLLL":default:return s.month(a,{width:"wide",context:"standalone"})}},w:function(o,r,s,a){const u=ly(o,a);return r==="wo"?s.ordinalNumber(u,{unit:"week"}):we(u,r.length)},I:function(o,r,s){const a=oy(o);return r==="Io"?s.ordinalNumber(a,{unit:"week"}):we(a,r.length)},d:function(o,r,s){return r==="do"?s.ordinalNumber(o.getDate(),{unit:"date"}):un.d(o,r)},D:function(o,r,s){const a=iy(o);return r==="Do"?s.ordinalNumber(a,{unit:"dayOfYear"}):we(a,r.length)},E:function(o,r,s){const a=o.getDay();switch(r){case"E":case"EE":case"EEE":return s.day(a,{width:"abbreviated",context:"formatting"});case"EEEEE":return s.day(a,{width:"narrow",context:"formatting"});case"EEEEEE":return s.day(a,{width:"short",context:"formatting"});case"EEEE":default:return s.day(a,{width:"wide",context:"formatting"})}},e:function(o,r,s,a){const u=o.getDay(),d=(u-a.weekStartsOn+8)%7||7;switch(r){case"e":return String(d);case"ee":return we(d,2);case"eo":return s.ordinalNumber(d,{unit:"day"});case"eee":return s.day(u,{width:"abbreviated",context:"formatting"});case"eeeee":return s.day(u,{width:"narrow",context:"formatting"});case"eeeeee":return s.day(u,{width:"short",context:"formatting"});case"eeee":default:return s.day(u,{width:"wide",context:"formatting"})}},c:function(o,r,s,a){const u=o.getDay(),d=(u-a.weekStartsOn+8)%7||7;switch(r){case"c":return String(d);case"cc":return we(d,r.length);case"co":return s.ordinalNumber(d,{unit:"day"});case"ccc":return s.day(u,{width:"abbreviated",context:"standalone"});case"ccccc":return s.day(u,{width:"narrow",context:"standalone"});case"cccccc":return s.day(u,{width:"short",context:"standalone"});case"cccc":default:return s.day(u,{width:"wide",context:"standalone"})}},i:function(o,r,s){const a=o.getDay(),u=a===0?7:a;switch(r){case"i":return String(u);case"ii":return we(u,r.length);case"io":return s.ordinalNumber(u,{unit:"day"});case"iii":return s.day(a,{width:"abbreviated",context:"formatting"});case"iiiii":return s.day(a,{width:"narrow",context:"formatting"});case"iiiiii":return s.day(a,{width:"short",context:"formatting"});case"iiii":default:return s.day(a,{width:"wide",context:"formatting"})}},a:function(o,r,s){const u=o.getHours()/12>=1?"pm":"am";switch(r){case"a":case"aa":return s.dayPeriod(u,{width:"abbreviated",context:"formatting"});case"aaa":return s.dayPeriod(u,{width:"abbreviated",context:"formatting"}).toLowerCase();case"aaaaa":return s.dayPeriod(u,{width:"narrow",context:"formatting"});case"aaaa":default:return s.dayPeriod(u,{width:"wide",context:"formatting"})}},b:function(o,r,s){const a=o.getHours();let u;switch(a===12?u=er.noon:a===0?u=er.midnight:u=a/12>=1?"pm":"am",r){case"b":case"bb":return s.dayPeriod(u,{width:"abbreviated",context:"formatting"});case"bbb":return s.dayPeriod(u,{width:"abbreviated",context:"formatting"}).toLowerCase();case"bbbbb":return s.dayPeriod(u,{width:"narrow",context:"formatting"});case"bbbb":default:return s.dayPeriod(u,{width:"wide",context:"formatting"})}},B:function(o,r,s){const a=o.getHours();let u;switch(a>=17?u=er.evening:a>=12?u=er.afternoon:a>=4?u=er.morning:u=er.night,r){case"B":case"BB":case"BBB":return s.dayPeriod(u,{width:"abbreviated",context:"formatting"});case"BBBBB":return s.dayPeriod(u,{width:"narrow",context:"formatting"});case"BBBB":default:return s.dayPeriod(u,{width:"wide",context:"formatting"})}},h:function(o,r,s){if(r==="ho"){let a=o.getHours()%12;return a===0&&(a=12),s.ordinalNumber(a,{unit:"hour"})}return un.h(o,r)},H:function(o,r,s){return r==="Ho"?s.ordinalNumber(o.getHours(),{unit:"hour"}):un.H(o,r)},K:function(o,r,s){const a=o.getHours()%12;return r==="Ko"?s.ordinalNumber(a,{unit:"hour"}):we(a,r.length)},k:function(o,r,s){let a=o.getHours();return a===0&&(a=24),r==="ko"?s.ordinalNumber(a,{unit:"hour"}):we(a,r.length)},m:function(o,r,s){return r==="mo"?s.ordinalNumber(o.getMinutes(),{unit:"minute"}):un.m(o,r)},s:function(o,r,s){return r==="so"?s.ordinalNumber(o.getSeconds(),{unit:"second"}):un.s(o,r)},S:function(o,r){return un.S(o,r)},X:function(o,r,s){const a=o.getTimezoneOffset();if(a===0)return"Z";switch(r){case"X":return ld(a);case"XXXX":case"XX":return Cn(a);case"XXXXX":case"XXX":default:return Cn(a,":")}},x:function(o,r,s){const a=o.getTimezoneOffset();switch(r){case"x":return ld(a);case"xxxx":case"xx":return Cn(a);case"xxxxx":case"xxx":default:return Cn(a,":")}},O:function(o,r,s){const a=o.getTimezoneOffset();switch(r){case"O":case"OO":case"OOO":return"GMT"+ad(a,":");case"OOOO":default:return"GMT"+Cn(a,":")}},z:function(o,r,s){const a=o.getTimezoneOffset();switch(r){case"z":case"zz":case"zzz":return"GMT"+ad(a,":");case"zzzz":default:return"GMT"+Cn(a,":")}},t:function(o,r,s){const a=Math.trunc(o.getTime()/1e3);return we(a,r.length)},T:function(o,r,s){const a=o.getTime();return we(a,r.length)}};function ad(o,r=""){const s=o>0?"-":"+",a=Math.abs(o),u=Math.trunc(a/60),d=a%60;return d===0?s+String(u):s+String(u)+r+we(d,2)}function ld(o,r){return o%60===0?(o>0?"-":"+")+we(Math.abs(o)/60,2):Cn(o,r)}function Cn(o,r=""){const s=o>0?"-":"+",a=Math.abs(o),u=we(Math.trunc(a/60),2),d=we(a%60,2);return s+u+r+d}const ud=(o,r)=>{switch(o){case"P":return r.date({width:"short"});case"PP":return r.date({width:"medium"});case"PPP":return r.date({width:"long"});case"PPPP":default:return r.date({width:"full"})}},of=(o,r)=>{switch(o){case"p":return r.time({width:"short"});case"pp":return r.time({width:"medium"});case"ppp":return r.time({width:"long"});case"pppp":default:return r.time({width:"full"})}},uy=(o,r)=>{const s=o.match(/(P+)(p+)?/)||[],a=s[1],u=s[2];if(!u)return ud(o,r);let d;switch(a){case"P":d=r.dateTime({width:"short"});break;case"PP":d=r.dateTime({width:"medium"});break;case"PPP":d=r.dateTime({width:"long"});break;case"PPPP":default:d=r.dateTime({width:"full"});break}return d.replace("{{date}}",ud(a,r)).replace("{{time}}",of(u,r))},cy={p:of,P:uy},dy=/^D+$/,fy=/^Y+$/,hy=["D","DD","YY","YYYY"];function py(o){return dy.test(o)}function my(o){return fy.test(o)}function gy(o,r,s){const a=yy(o,r,s);if(console.warn(a),hy.includes(o))throw new RangeError(a)}function yy(o,r,s){const a=o[0]==="Y"?"years":"days of the month";return`Use \`${o.toLowerCase()}\` instead of \`${o}\` (in \`${r}\`) for formatting ${a} to the input \`${s}\`; see: https://github.com/date-fns/date-fns/blob/master/docs/unicodeTokens.md`}const vy=/[yYQqMLwIdDecihHKkms]o|(\w)\1*|''|'(''|[^'])+('|$)|./g,xy=/P+p+|P+|p+|''|'(''|[^'])+('|$)|./g,wy=/^'([^]*?)'?$/,Sy=/''/g,ky=/[a-zA-Z]/;function cd(o,r,s){const a=Ci(),u=a.locale??sy,d=a.firstWeekContainsDate??a.locale?.options?.firstWeekContainsDate??1,h=a.weekStartsOn??a.locale?.options?.weekStartsOn??0,m=jt(o);if(!Cg(m))throw new RangeError("Invalid time value");let g=r.match(xy).map(k=>{const x=k[0];if(x==="p"||x==="P"){const L=cy[x];return L(k,u.formatLong)}return k}).join("").match(vy).map(k=>{if(k==="''")return{isToken:!1,value:"'"};const x=k[0];if(x==="'")return{isToken:!1,value:Ny(k)};if(od[x])return{isToken:!0,value:k};if(x.match(ky))throw new RangeError("Format string contains an unescaped latin alphabet character `"+x+"`");return{isToken:!1,value:k}});u.localize.preprocessor&&(g=u.localize.preprocessor(m,g));const y={firstWeekContainsDate:d,weekStartsOn:h,locale:u};return g.map(k=>{if(!k.isToken)return k.value;const x=k.value;(my(x)||py(x))&&gy(x,r,String(o));const L=od[x[0]];return L(m,x,u.localize,y)}).join("")}function Ny(o){const r=o.match(wy);return r?r[1].replace(Sy,"'"):o}function Cy({user:o}){const{t:r}=Ae(),s=_t(),a=cd(new Date(o.created_at),"dd.MM.yyyy HH:mm"),u=cd(new Date(o.last_login),"dd.MM.yyyy HH:mm");return f.jsx("div",{className:"flex justify-center cursor-pointer text-text-primary mb-4",children:f.jsxs("div",{className:"bg-surface-secondary p-4 rounded-lg border border-border-default shadow-lg hover:bg-surface-tertiary transition-colors w-full",onClick:()=>s(`/manageuser/${o.id}`),children:[f.jsxs("div",{className:"flex flex-col sm:flex-row sm:items-center sm:justify-between gap-3 mb-4",children:[f.jsxs("p",{className:"text-xl font-bold text-text-primary",children:[o.first_name," ",o.last_name]}),o.organization&&f.jsx("span",{className:"px-3 py-1 text-sm bg-blue-100 text-blue-800 rounded-full w-fit",children:o.organization.organization_name})]}),f.jsxs("div",{className:"grid grid-cols-1 sm:grid-cols-2 lg:grid-cols-3 xl:grid-cols-4 2xl:grid-cols-6 gap-4",children:[f.jsxs("div",{className:"min-w-0",children:[f.jsx("label",{className:"block text-xs font-medium text-text-secondary mb-1",children:r("Username")}),f.jsx("p",{className:"text-sm text-text-primary truncate",title:o.username,children:o.username})]}),f.jsxs("div",{className:"min-w-0",children:[f.jsx("label",{className:"block text-xs font-medium text-text-secondary mb-1",children:r("Email")}),f.jsx("p",{className:"text-sm text-text-primary truncate",title:o.email,children:o.email})]}),f.jsxs("div",{className:"min-w-0",children:[f.jsx("label",{className:"block text-xs font-medium text-text-secondary mb-1",children:r("Status")}),f.jsxs("div",{className:"flex gap-2 flex-wrap",children:[o.is_active?f.jsx("span",{className:"inline-block px-2 py-0.5 text-xs bg-green-100 text-green-800 rounded whitespace-nowrap",children:r("Active")}):f.jsx("span",{className:"inline-block px-2 py-0.5 text-xs bg-gray-100 text-gray-800 rounded whitespace-nowrap",children:r("Inactive")}),o.is_superuser&&f.jsx("span",{className:"inline-block px-2 py-0.5 text-xs bg-purple-100 text-purple-800 rounded whitespace-nowrap",children:r("Super")})]})]}),f.jsxs("div",{className:"min-w-0",children:[f.jsx("label",{className:"block text-xs font-medium text-text-secondary mb-1",children:r("Created")}),f.jsx("p",{className:"text-sm text-text-primary whitespace-nowrap",children:a})]}),f.jsxs("div",{className:"min-w-0",children:[f.jsx("label",{className:"block text-xs font-medium text-text-secondary mb-1",children:r("LastLogin")}),f.jsx("p",{className:"text-sm text-text-primary whitespace-nowrap",children:u})]}),f.jsxs("div",{className:"min-w-0 sm:col-span-2 lg:col-span-1",children:[f.jsx("label",{className:"block text-xs font-medium text-text-secondary mb-1",children:r("Groups")}),f.jsx("p",{className:"text-sm text-text-primary truncate",title:o.groups?.replace(/\|/g,", "),children:o.groups?.replace(/\|/g,", ")||r("None")})]})]})]})})}function jy({showData:o}){const[r,s]=C.useState([]),a=C.useRef(null),u=20;return C.useEffect(()=>{s(o.slice(0,u))},[o]),C.useEffect(()=>{const d=new IntersectionObserver(h=>{if(h[0].isIntersecting&&r.length<o.length){const g=o.slice(r.length,r.length+u);s(y=>[...y,...g])}},{root:null,rootMargin:"20px",threshold:.1});return a.current&&d.observe(a.current),()=>{a.current&&d.unobserve(a.current)}},[r,o]),f.jsxs("div",{className:"w-full space-y-0",children:[r.map(d=>f.jsx(Cy,{user:d},d.id)),r.length<o.length&&f.jsx("div",{ref:a,className:"w-full p-4 flex justify-center",children:f.jsx("div",{className:"animate-spin h-8 w-8 border-4 border-blue-500 rounded-full border-t-transparent"})})]})}function by(){const{userData:o}=C.useContext(et),{t:r}=Ae(),s=_t(),[a,u]=C.useState([]),[d,h]=C.useState([]),[m,g]=C.useState([]),[y,k]=C.useState([]);return C.useEffect(()=>{async function x(){if(!(!o||!o.token))try{const E=await Pe.allusers(o.token);if(!E)return;u(E),h(E)}catch(E){E instanceof Error?(console.error("Error fetching users:",E),E.name==="BulkError"&&console.error("Detailed BulkError:",E)):console.log(E)}}async function L(){try{const E=await Pe.getGroups(o.token);g(E||[])}catch(E){console.error("Error fetching groups:",E)}}async function I(){try{const E=await Pe.allOrganizations(o.token);k(E||[])}catch(E){console.error("Error fetching organizations:",E)}}x(),L(),I()},[o]),f.jsx("div",{className:"space-y-6",children:f.jsxs("div",{className:"bg-surface-primary rounded-lg border border-border-default shadow-sm",children:[f.jsxs("div",{className:"flex flex-col sm:flex-row sm:items-center sm:justify-between gap-4 p-6 border-b border-border-default",children:[f.jsx("h1",{className:"text-2xl font-semibold text-text-primary",children:r("users")}),f.jsxs("button",{onClick:()=>s("/createuser"),className:"inline-flex items-center justify-center gap-2 px-4 py-2 bg-blue-600 hover:bg-blue-700 text-white font-medium rounded-lg transition-colors duration-200 shadow-sm hover:shadow-md whitespace-nowrap",children:[f.jsx("svg",{className:"w-5 h-5",fill:"none",stroke:"currentColor",viewBox:"0 0 24 24",children:f.jsx("path",{strokeLinecap:"round",strokeLinejoin:"round",strokeWidth:2,d:"M12 4v16m8-8H4"})}),r("createNewUser")]})]}),f.jsx(vg,{setShowData:h,availableGroups:m,availableOrganizations:y,allUsers:a}),f.jsx("div",{className:"p-6 pt-0",children:d.length>0?f.jsx(jy,{showData:d}):f.jsx("div",{className:"text-center py-12 text-text-secondary",children:r("noUsersFound")})})]})})}function Ey(){const{userData:o,setToast:r}=C.useContext(et),{id:s}=Ad(),a=s??"",{t:u}=Ae(),[d,h]=C.useState(""),[m,g]=C.useState(""),[y,k]=C.useState(""),[x,L]=C.useState(""),[I,E]=C.useState(""),[z,T]=C.useState(!0),[A,H]=C.useState(!1),[Y,Q]=C.useState([]),[U,J]=C.useState([]),[ee,re]=C.useState(null),[fe,de]=C.useState([]);C.useEffect(()=>{async function Se(){const se=await Pe.getGroups(o.token);Q(se||[])}Se();async function M(){const se=await Pe.getPermissions(o.token);J(se||[])}M();async function q(){const se=await Pe.allOrganizations(o.token);de(se||[])}q();async function he(){if(console.log(a),a==="")return;const se=await Pe.oneuser(o.token,a);if(se){h(se.email),k(se.username),L(se.first_name),E(se.last_name),T(se.is_active),H(se.is_superuser),re(se.organization?.id||null);const le=se.groups?se.groups.split("|"):[];Q(_=>_.map(D=>({...D,checked:le.includes(D.name)})));const B=se.permissions?se.permissions.split("|"):[];J(_=>_.map(D=>({...D,checked:B.includes(D.name)})))}}he()},[o,a]);function Ce(){if(!A&&!ee){r({header:u("validationError"),text:u("usersNotSuperuserMustBelongToOrganization"),success:!1,show:!0});return}const Se=Y.filter(he=>he.checked).map(he=>he.name).join("|"),M=U.filter(he=>he.checked).map(he=>he.name).join("|"),q={password:m,email:d,first_name:x,last_name:I,username:y||d,is_active:z,is_superuser:A,groups:Se,permissions:M,organization_id:ee};Pe.editoneuser(o.token,a,q,r)}return f.jsx(tf,{headText:u("manageUser"),userId:a,email:d,setEmail:h,password:m,setPassword:g,username:y,setUserName:k,first_name:x,setFirst_name:L,last_name:I,setLast_name:E,isActive:z,setIsActive:T,isSuperuser:A,setIsSuperuser:H,groups:Y,setGroups:Q,permissions:U,setPermissions:J,organizationId:ee,setOrganizationId:re,organizations:fe,handleSubmit:Ce})}function Oy(){const{toast:o,setToast:r}=C.useContext(et);return C.useEffect(()=>{if(o.show){const s=setTimeout(()=>{r({...o,show:!1})},5e3);return()=>clearTimeout(s)}},[o,r]),f.jsx(f.Fragment,{children:o.show&&f.jsxs("div",{className:`
             fixed top-20 right-20 
             border-2 border-border-default 
             p-6 rounded-lg shadow-lg 
             ${o.success?"bg-success":"bg-error"} 
             transition-opacity duration-300 ease-in-out
             animate-[pulse_0.5s_ease-in-out]
-          `,children:[f.jsx("div",{className:"font-bold text-lg text-text-primary",children:o.header}),f.jsx("div",{className:"mt-1 text-text-primary",children:o.text})]})})}function dd({isAdmin:o}){const[r,s]=C.useState([]),[a,u]=C.useState(null),[d,h]=C.useState(""),[m,g]=C.useState("all"),[y,k]=C.useState(!1),[x,L]=C.useState(0),[I,E]=C.useState(""),[z,T]=C.useState(!1),[A,H]=C.useState(!1),{userData:Y,setToast:Q}=C.useContext(et),{t:U}=Ae();C.useEffect(()=>{async function _(){if(Y.token)try{const D=await Pe.getMsg(Y.token,o);s(D)}catch(D){console.error("Error fetching messages:",D)}}_()},[Y.token,x]);async function J(){if(!(!Y.token||!a||!I.trim())){T(!0);try{const _=await Pe.updateFeedBack(Y.token,{message:I.trim(),id:a.id,is_solved:o?A:null},o,Q);E(""),H(!1),L(x+1),u(_)}catch(_){console.log(_)}finally{T(!1)}}}const ee=_=>{_.key==="Enter"&&!_.shiftKey&&(_.preventDefault(),J())},re=_=>{u(_),k(!0),H(_.is_solved||!1)},fe=()=>{k(!1),u(null),E(""),H(!1)},de=r.filter(_=>{const D=_.behaviour_is.toLowerCase().includes(d.toLowerCase())||_.behaviour_should.toLowerCase().includes(d.toLowerCase())||_.full_url.toLowerCase().includes(d.toLowerCase()),S=m==="all"||m==="solved"&&_.is_solved||m==="unsolved"&&!_.is_solved;return D&&S}),Ce=_=>_?f.jsx(Nm,{className:"w-4 h-4 text-success"}):f.jsx("div",{className:"w-2 h-2 bg-accent-primary rounded-full"}),Se=_=>U(_?"solved":"open"),M=_=>{try{const D=new Date(_),P=Math.floor((new Date().getTime()-D.getTime())/(1e3*60*60));if(P<1)return U("justNow");if(P<24)return U("hoursAgo",{hours:P});if(P<48)return U("oneDayAgo");{const ie=Math.floor(P/24);return U("daysAgo",{days:ie})}}catch{return _}},q=_=>{try{return new Date(_).toLocaleTimeString([],{hour:"2-digit",minute:"2-digit"})}catch{return _}},he=_=>_.behaviour_is.substring(0,50)+(_.behaviour_is.length>50?"...":""),se=_=>`${U("current")}: ${_.behaviour_is.substring(0,100)}${_.behaviour_is.length>100?"...":""}`,le=_=>{if(!_)return[];try{return JSON.parse(_)}catch(D){return console.error("Error parsing chat JSON:",D),[]}},B=_=>{const D=le(_);if(D.length===0)return U("noMessagesYet");const S=D[D.length-1];return`${S.from}: ${S.message.substring(0,50)}${S.message.length>50?"...":""}`};return f.jsxs("div",{className:"max-w-7xl mx-auto p-6",children:[f.jsx("div",{className:"bg-surface-primary rounded-lg border border-border-default p-6 mb-6",children:f.jsxs("div",{className:"flex items-center justify-between",children:[f.jsxs("div",{children:[f.jsx("h1",{className:"text-2xl font-semibold text-text-primary mb-2",children:U("myFeedback")}),f.jsx("p",{className:"text-text-secondary",children:U("viewAndManageFeedback")})]}),f.jsx("div",{className:"flex items-center gap-2",children:f.jsx("span",{className:"text-sm text-text-secondary",children:U("feedbackItemsCount",{filtered:de.length,total:r.length})})})]})}),f.jsxs("div",{className:"bg-surface-primary rounded-lg border border-border-default",children:[f.jsx("div",{className:"p-4 border-b border-border-default",children:f.jsxs("div",{className:"flex flex-col sm:flex-row gap-3",children:[f.jsxs("div",{className:"relative flex-1",children:[f.jsx(Lm,{className:"absolute left-3 top-1/2 transform -translate-y-1/2 w-4 h-4 text-text-muted"}),f.jsx("input",{type:"text",value:d,onChange:_=>h(_.target.value),className:"w-full pl-10 pr-4 py-2 rounded-lg border border-border-default bg-surface-secondary text-text-primary focus:outline-none focus:ring-2 focus:ring-brand focus:border-transparent",placeholder:U("searchFeedbackPlaceholder")})]}),f.jsxs("div",{className:"flex gap-2",children:[f.jsxs("select",{value:m,onChange:_=>g(_.target.value),className:"px-3 py-2 rounded-lg border border-border-default bg-surface-secondary text-text-primary focus:outline-none focus:ring-2 focus:ring-brand focus:border-transparent",children:[f.jsx("option",{value:"all",children:U("allFeedback")}),f.jsx("option",{value:"unsolved",children:U("open")}),f.jsx("option",{value:"solved",children:U("solved")})]}),f.jsxs(Fe,{kind:"secondary",className:"flex items-center gap-2",children:[f.jsx(jm,{className:"w-4 h-4"}),U("filter")]})]})]})}),f.jsx("div",{className:"divide-y divide-border-default",children:de.length>0?de.map(_=>f.jsx("div",{onClick:()=>re(_),className:`p-4 cursor-pointer hover:bg-surface-secondary transition-colors border-l-4 ${_.is_solved?"border-l-success":"border-l-warning"}`,children:f.jsxs("div",{className:"flex items-start justify-between gap-3",children:[f.jsxs("div",{className:"flex items-start gap-3 flex-1 min-w-0",children:[f.jsxs("div",{className:"flex items-center gap-2 mt-1",children:[Ce(_.is_solved),f.jsx(sr,{className:"w-4 h-4 text-accent-primary"})]}),f.jsxs("div",{className:"flex-1 min-w-0",children:[f.jsx("h3",{className:"text-sm font-medium truncate text-text-primary",children:he(_)}),f.jsx("p",{className:"text-sm text-text-muted mt-1 line-clamp-2",children:se(_)}),_.chat&&f.jsx("p",{className:"text-xs text-text-muted mt-1 italic",children:B(_.chat)})]})]}),f.jsx("div",{className:"text-xs text-text-muted whitespace-nowrap",children:M(_.created_at)})]})},_.id)):f.jsxs("div",{className:"p-8 text-center",children:[f.jsx(sr,{className:"w-12 h-12 text-text-muted mx-auto mb-3"}),f.jsx("p",{className:"text-text-secondary",children:U("noFeedbackFound")})]})})]}),y&&a&&f.jsx("div",{className:"fixed inset-0 z-50 flex items-center justify-center p-4 bg-black bg-opacity-50",children:f.jsxs("div",{className:"bg-surface-primary rounded-lg border border-border-default w-full max-w-4xl max-h-[90vh] overflow-hidden flex flex-col",children:[f.jsx("div",{className:"p-6 border-b border-border-default",children:f.jsxs("div",{className:"flex items-center justify-between",children:[f.jsxs("div",{className:"flex items-center gap-3",children:[f.jsxs("div",{className:"flex items-center gap-2",children:[Ce(a.is_solved),f.jsx(sr,{className:"w-5 h-5 text-accent-primary"})]}),f.jsxs("div",{children:[f.jsx("h2",{className:"text-xl font-semibold text-text-primary",children:U("feedbackDetails")}),f.jsxs("p",{className:"text-sm text-text-secondary",children:[Se(a.is_solved)," •"," ",U("created")," ",M(a.created_at)]})]})]}),f.jsx("div",{className:"flex items-center gap-2",children:f.jsxs(Fe,{kind:"secondary",className:"flex items-center gap-2",onClick:fe,children:[f.jsx(Fa,{className:"w-4 h-4"}),U("close")]})})]})}),f.jsxs("div",{className:"flex-1 overflow-hidden flex",children:[f.jsx("div",{className:"w-1/2 p-6 border-r border-border-default overflow-y-auto",children:f.jsxs("div",{className:"space-y-6",children:[f.jsxs("div",{children:[f.jsxs("h3",{className:"text-sm font-medium text-text-primary mb-3 flex items-center gap-2",children:[f.jsx("div",{className:"w-1 h-4 bg-accent-primary rounded"}),U("currentBehavior")]}),f.jsx("div",{className:"bg-surface-secondary p-4 rounded-lg",children:f.jsx("p",{className:"text-sm text-text-secondary leading-relaxed",children:a.behaviour_is})})]}),f.jsxs("div",{children:[f.jsxs("h3",{className:"text-sm font-medium text-text-primary mb-3 flex items-center gap-2",children:[f.jsx("div",{className:"w-1 h-4 bg-success rounded"}),U("expectedBehavior")]}),f.jsx("div",{className:"bg-surface-secondary p-4 rounded-lg",children:f.jsx("p",{className:"text-sm text-text-secondary leading-relaxed",children:a.behaviour_should})})]}),f.jsxs("div",{children:[f.jsxs("h3",{className:"text-sm font-medium text-text-primary mb-3 flex items-center gap-2",children:[f.jsx("div",{className:"w-1 h-4 bg-warning rounded"}),U("url")]}),f.jsx("div",{className:"bg-surface-secondary p-4 rounded-lg",children:f.jsx("p",{className:"text-sm text-text-secondary break-all font-mono",children:a.full_url})})]})]})}),f.jsxs("div",{className:"w-1/2 flex flex-col",children:[f.jsx("div",{className:"p-4 border-b border-border-default",children:f.jsxs("h3",{className:"text-sm font-medium text-text-primary flex items-center gap-2",children:[f.jsx(Pm,{className:"w-4 h-4"}),U("chatHistory")]})}),f.jsx("div",{className:"flex-1 overflow-y-auto p-4 space-y-4",children:le(a.chat).length>0?le(a.chat).map((_,D)=>{const S=_.from.split(" ")[0],P=S===Y.email;return f.jsx("div",{className:`flex gap-3 ${P?"justify-end":"justify-start"}`,children:f.jsxs("div",{className:`max-w-[80%] ${P?"order-2":"order-1"}`,children:[f.jsx("div",{className:`p-3 rounded-lg shadow-sm ${P?"bg-brand text-text-primary rounded-br-sm":"bg-surface-secondary text-text-secondary rounded-bl-sm"}`,children:f.jsx("p",{className:"text-sm leading-relaxed whitespace-pre-wrap",children:_.message})}),f.jsxs("div",{className:`flex items-center gap-1 mt-1 text-xs text-text-muted ${P?"justify-end":"justify-start"}`,children:[f.jsxs("div",{className:"flex items-center gap-1",children:[f.jsx(Yd,{className:"w-3 h-3"}),f.jsx("span",{className:"font-medium",children:P?U("you"):S})]}),f.jsxs("div",{className:"flex items-center gap-1 ml-2",children:[f.jsx(Cm,{className:"w-3 h-3"}),f.jsx("span",{children:q(_.timestamp)})]})]})]})},D)}):f.jsxs("div",{className:"flex flex-col items-center justify-center h-full text-center",children:[f.jsx(sr,{className:"w-12 h-12 text-text-muted mb-3"}),f.jsx("p",{className:"text-text-secondary",children:U("noMessagesYet")}),f.jsx("p",{className:"text-text-muted text-sm",children:U("startConversationBelow")})]})}),f.jsxs("div",{className:"p-4 border-t border-border-default",children:[o&&!a.is_solved&&f.jsx("div",{className:"mb-3",children:f.jsxs("label",{className:"flex items-center gap-2 text-sm text-text-primary",children:[f.jsx("input",{type:"checkbox",checked:A,onChange:_=>H(_.target.checked),className:"w-4 h-4 text-brand bg-surface-secondary border-border-default rounded focus:ring-brand focus:ring-2"}),f.jsx("span",{children:U("markAsSolved")})]})}),f.jsxs("div",{className:"flex gap-2",children:[f.jsx("textarea",{value:I,onChange:_=>E(_.target.value),onKeyPress:ee,placeholder:U("typeYourMessage"),className:"flex-1 px-3 py-2 rounded-lg border border-border-default bg-surface-secondary text-text-primary focus:outline-none focus:ring-2 focus:ring-brand focus:border-transparent resize-none",rows:2,disabled:z}),f.jsxs(Fe,{kind:"primary",onClick:J,disabled:!I.trim()||z,className:"flex items-center gap-2 px-4 py-2 self-end",children:[f.jsx(Vd,{className:"w-4 h-4"}),U(z?"sending":"send")]})]}),f.jsx("p",{className:"text-xs text-text-muted mt-2",children:U("enterToSendShiftEnterNewLine")})]})]})]})]})})]})}function af({headText:o,organizationId:r,organizationName:s,setOrganizationName:a,email:u,setEmail:d,activeUntil:h,setActiveUntil:m,handleSubmit:g}){const{t:y}=Ae(),{userData:k,setToast:x}=C.useContext(et);function L(){Pe.deleteOrganization(k.token,r,x)}function I(A){if(!A)return"";const H=new Date(A);return isNaN(H.getTime())?"":H.toISOString().split("T")[0]}const E=new Date().toISOString().split("T")[0],z=h&&new Date(h)>new Date,T=h?Math.ceil((new Date(h).getTime()-new Date().getTime())/(1e3*60*60*24)):0;return f.jsxs("div",{className:"bg-surface-primary rounded-lg border border-border-default p-6",children:[f.jsx("h1",{className:"text-2xl font-semibold text-text-primary mb-6",children:o}),f.jsxs("div",{className:"space-y-6",children:[f.jsxs("div",{className:"grid grid-cols-1 md:grid-cols-2 lg:grid-cols-3 gap-4",children:[f.jsxs("div",{children:[f.jsxs("label",{className:"block text-sm font-medium text-text-secondary mb-2",children:[y("OrganizationName")," ",f.jsx("span",{className:"text-red-500",children:"*"})]}),f.jsx("input",{type:"text",value:s,onChange:A=>a(A.target.value),className:"w-full rounded-lg border border-border-default px-4 py-2 text-text-primary bg-surface-secondary focus:outline-none focus:ring-2 focus:ring-blue-600 focus:border-transparent",placeholder:y("OrganizationNamePlaceholder"),required:!0})]}),f.jsxs("div",{children:[f.jsxs("label",{className:"block text-sm font-medium text-text-secondary mb-2",children:[y("Email")," ",f.jsx("span",{className:"text-red-500",children:"*"})]}),f.jsx("input",{type:"email",value:u,onChange:A=>d(A.target.value),className:"w-full rounded-lg border border-border-default px-4 py-2 text-text-primary bg-surface-secondary focus:outline-none focus:ring-2 focus:ring-blue-600 focus:border-transparent",placeholder:y("OrganizationEmailPlaceholder"),required:!0})]}),f.jsxs("div",{children:[f.jsxs("label",{className:"block text-sm font-medium text-text-secondary mb-2",children:[y("ActiveUntil")," ",f.jsx("span",{className:"text-red-500",children:"*"})]}),f.jsx("input",{type:"date",value:I(h),onChange:A=>m(A.target.value),min:E,className:"w-full rounded-lg border border-border-default px-4 py-2 text-text-primary bg-surface-secondary focus:outline-none focus:ring-2 focus:ring-blue-600 focus:border-transparent",required:!0}),f.jsx("p",{className:"mt-1 text-xs text-text-muted",children:y("ActiveUntilDescription")})]})]}),r&&h&&f.jsxs("div",{className:"p-4 bg-surface-secondary rounded-lg border border-border-default",children:[f.jsx("h3",{className:"text-sm font-medium text-text-secondary mb-3",children:y("Status")}),f.jsxs("div",{className:"flex flex-col sm:flex-row sm:items-center gap-3",children:[f.jsxs("div",{className:`flex items-center gap-2 px-3 py-1.5 rounded-full w-fit ${z?"bg-green-100 text-green-700":"bg-red-100 text-red-700"}`,children:[f.jsx("div",{className:`w-2 h-2 rounded-full ${z?"bg-green-500":"bg-red-500"}`}),f.jsx("span",{className:"text-sm font-medium",children:y(z?"Active":"Expired")})]}),z&&T>0&&f.jsx("div",{className:`px-3 py-1.5 rounded-full text-sm font-medium w-fit ${T<=30?"bg-orange-100 text-orange-700":"bg-blue-100 text-blue-700"}`,children:T<=30?y("ExpiresInDaysWarning",{days:T}):y("ExpiresInDays",{days:T})})]})]}),k.is_superuser&&f.jsxs("div",{className:"flex gap-4 pt-4",children:[f.jsx(Fe,{onClick:g,kind:"primary",children:y(o==="Create Organization"?"CreateOrganization":"SaveChanges")}),o!=="Create Organization"&&f.jsx(Fe,{onClick:L,kind:"danger",children:y("DeleteOrganization")})]}),!k.is_superuser&&f.jsx("div",{className:"p-4 bg-yellow-50 border border-yellow-200 rounded-lg",children:f.jsx("p",{className:"text-sm text-yellow-800",children:y("OnlySuperusersCanModifyOrganizations")})})]})]})}function Py(){const{userData:o,setToast:r}=C.useContext(et),{t:s}=Ae(),{id:a}=Ad(),u=a??"",[d,h]=C.useState(""),[m,g]=C.useState(""),[y,k]=C.useState("");C.useEffect(()=>{async function L(){if(u==="")return;const I=await Pe.oneOrganization(o.token,u);I&&(h(I.organization_name),g(I.email),k(I.active_until))}L()},[o,u]);function x(){const L={organization_name:d,email:m,active_until:y};Pe.editOneOrganization(o.token,u,L,r)}return f.jsx(af,{headText:s("manageOrganization"),organizationId:u,organizationName:d,setOrganizationName:h,email:m,setEmail:g,activeUntil:y,setActiveUntil:k,handleSubmit:x})}function Ly(){const{userData:o,setToast:r}=C.useContext(et),{t:s}=Ae(),[a,u]=C.useState(""),[d,h]=C.useState(""),[m,g]=C.useState("2999-01-01");function y(){const k={organization_name:a,email:d,active_until:m};Pe.createOrganization(o.token,k,r)}return f.jsx(af,{headText:s("createOrganization"),organizationId:"",organizationName:a,setOrganizationName:u,email:d,setEmail:h,activeUntil:m,setActiveUntil:g,handleSubmit:y})}function zy({setShowData:o,allOrganizations:r}){const{t:s}=Ae(),[a,u]=C.useState(""),[d,h]=C.useState(""),[m,g]=C.useState("all"),[y,k]=C.useState("");function x(){try{let z=r;if(a&&(z=z.filter(T=>T.organization_name.toLowerCase().includes(a.toLowerCase()))),d&&(z=z.filter(T=>T.email.toLowerCase().includes(d.toLowerCase()))),m!=="all"){const T=new Date;z=z.filter(A=>{const H=new Date(A.active_until)>T;return m==="active"?H:!H})}y&&(z=z.sort((T,A)=>{const H=new Date(T.active_until).getTime(),Y=new Date(A.active_until).getTime();return y==="asc"?H-Y:Y-H})),o(z)}catch(z){console.error("Error filtering organizations:",z)}}function L(){o(r),u(""),h(""),g("all"),k("")}C.useEffect(()=>{x()},[m,y]),C.useEffect(()=>{r.length>0&&x()},[r]);const E=(()=>{const z=new Date;z.setDate(z.getDate()+30);const T=new Date;return r.filter(A=>{const H=new Date(A.active_until);return H>T&&H<=z}).length})();return f.jsxs("div",{className:"p-6 space-y-4 bg-surface-secondary border-b border-border-default",children:[E>0&&f.jsxs("div",{className:"bg-orange-50 border-l-4 border-orange-500 rounded-lg p-4 flex items-start gap-3",children:[f.jsx("svg",{className:"w-5 h-5 text-orange-600 mt-0.5 flex-shrink-0",fill:"currentColor",viewBox:"0 0 20 20",children:f.jsx("path",{fillRule:"evenodd",d:"M8.257 3.099c.765-1.36 2.722-1.36 3.486 0l5.58 9.92c.75 1.334-.213 2.98-1.742 2.98H4.42c-1.53 0-2.493-1.646-1.743-2.98l5.58-9.92zM11 13a1 1 0 11-2 0 1 1 0 012 0zm-1-8a1 1 0 00-1 1v3a1 1 0 002 0V6a1 1 0 00-1-1z",clipRule:"evenodd"})}),f.jsx("div",{className:"flex-1",children:f.jsxs("p",{className:"text-sm font-medium text-orange-800",children:[E," ",E===1?s("OrganizationExpiringWithin30Days"):s("OrganizationsExpiringWithin30Days",{count:E})]})})]}),f.jsxs("div",{className:"grid grid-cols-1 sm:grid-cols-2 lg:grid-cols-4 gap-3",children:[f.jsx("input",{type:"text",placeholder:s("OrganizationName"),value:a,onChange:z=>u(z.target.value),onKeyPress:z=>z.key==="Enter"&&x(),className:"px-4 py-2 rounded-lg bg-surface-primary text-text-primary border-2 border-border-default placeholder-text-muted focus:outline-none focus:ring-2 focus:ring-blue-600 focus:border-transparent"}),f.jsx("input",{type:"email",placeholder:s("Email"),value:d,onChange:z=>h(z.target.value),onKeyPress:z=>z.key==="Enter"&&x(),className:"px-4 py-2 rounded-lg bg-surface-primary text-text-primary border-2 border-border-default placeholder-text-muted focus:outline-none focus:ring-2 focus:ring-blue-600 focus:border-transparent"}),f.jsxs("select",{value:m,onChange:z=>g(z.target.value),className:"px-4 py-2 rounded-lg bg-surface-primary text-text-primary border-2 border-border-default focus:outline-none focus:ring-2 focus:ring-blue-600 focus:border-transparent",children:[f.jsx("option",{value:"all",children:s("AllStatus")}),f.jsx("option",{value:"active",children:s("Active")}),f.jsx("option",{value:"expired",children:s("Expired")})]}),f.jsxs("div",{className:"flex gap-2",children:[f.jsx(Fe,{kind:"primary",onClick:x,className:"flex-1 bg-blue-600 hover:bg-blue-700 text-white",children:s("Search")}),f.jsx(Fe,{kind:"secondary",onClick:L,className:"flex-1 bg-surface-secondary hover:bg-surface-tertiary text-text-primary border-2 border-border-default",children:s("Clear")})]})]}),f.jsx("div",{className:"flex flex-col sm:flex-row sm:items-center sm:justify-between gap-3",children:f.jsxs("div",{className:"flex items-center gap-2",children:[f.jsxs("label",{className:"text-sm font-medium text-text-primary whitespace-nowrap",children:[s("SortByExpiry"),":"]}),f.jsxs("div",{className:"flex gap-2",children:[f.jsx("button",{onClick:()=>k(y==="asc"?"":"asc"),className:`px-3 py-1.5 rounded-lg text-sm font-medium transition-all duration-200 ${y==="asc"?"bg-blue-600 text-white shadow-sm":"bg-surface-primary hover:bg-surface-tertiary text-text-primary border border-border-default"}`,title:s("ExpiringSoonFirst"),children:f.jsxs("span",{className:"flex items-center gap-1",children:[f.jsx("svg",{className:"w-4 h-4",fill:"none",stroke:"currentColor",viewBox:"0 0 24 24",children:f.jsx("path",{strokeLinecap:"round",strokeLinejoin:"round",strokeWidth:2,d:"M5 10l7-7m0 0l7 7m-7-7v18"})}),s("Soon")]})}),f.jsx("button",{onClick:()=>k(y==="desc"?"":"desc"),className:`px-3 py-1.5 rounded-lg text-sm font-medium transition-all duration-200 ${y==="desc"?"bg-blue-600 text-white shadow-sm":"bg-surface-primary hover:bg-surface-tertiary text-text-primary border border-border-default"}`,title:s("ExpiringLaterFirst"),children:f.jsxs("span",{className:"flex items-center gap-1",children:[f.jsx("svg",{className:"w-4 h-4",fill:"none",stroke:"currentColor",viewBox:"0 0 24 24",children:f.jsx("path",{strokeLinecap:"round",strokeLinejoin:"round",strokeWidth:2,d:"M19 14l-7 7m0 0l-7-7m7 7V3"})}),s("Later")]})})]})]})})]})}function Ty({organization:o}){const{t:r}=Ae(),s=_t(),a=new Date(o.active_until)>new Date,u=Math.ceil((new Date(o.active_until).getTime()-new Date().getTime())/(1e3*60*60*24)),d=h=>new Date(h).toLocaleDateString("en-US",{year:"numeric",month:"short",day:"numeric"});return f.jsx("div",{className:"bg-surface-secondary border border-border-default rounded-lg p-4 mb-3 hover:shadow-md transition-all duration-200 cursor-pointer",onClick:()=>s(`/manageorganization/${o.id}`),children:f.jsxs("div",{className:"flex flex-col lg:flex-row lg:items-start lg:justify-between gap-4",children:[f.jsxs("div",{className:"flex-1 min-w-0",children:[f.jsxs("div",{className:"flex items-start gap-3 mb-3",children:[f.jsx(hg,{className:"text-blue-600 w-5 h-5 mt-0.5 flex-shrink-0"}),f.jsxs("div",{className:"flex-1 min-w-0",children:[f.jsx("h3",{className:"text-lg font-semibold text-text-primary truncate mb-1",title:o.organization_name,children:o.organization_name}),f.jsxs("div",{className:"flex items-center gap-2 flex-wrap",children:[f.jsxs("div",{className:`flex items-center gap-1.5 px-2 py-1 rounded-full text-xs font-medium ${a?"bg-green-100 text-green-700":"bg-red-100 text-red-700"}`,children:[f.jsx("div",{className:`w-1.5 h-1.5 rounded-full ${a?"bg-green-500":"bg-red-500"}`}),r(a?"Active":"Expired")]}),a&&u<=30&&f.jsx("span",{className:"px-2 py-1 text-xs font-medium text-orange-700 bg-orange-100 rounded-full",children:r("ExpiresInDaysWarning",{days:u})})]})]})]}),f.jsxs("div",{className:"grid grid-cols-1 md:grid-cols-2 gap-3 pl-8",children:[f.jsxs("div",{className:"flex items-center gap-2 text-text-secondary min-w-0",children:[f.jsx(gg,{className:"w-4 h-4 flex-shrink-0"}),f.jsx("span",{className:"text-sm truncate",title:o.email,children:o.email})]}),f.jsxs("div",{className:"flex items-center gap-2 text-text-secondary",children:[f.jsx(pg,{className:"w-4 h-4 flex-shrink-0"}),f.jsxs("div",{className:"text-sm",children:[f.jsxs("span",{className:"text-text-muted",children:[r("ActiveUntilLabel"),":"," "]}),f.jsx("span",{className:"font-medium text-text-primary whitespace-nowrap",children:d(o.active_until)})]})]})]})]}),f.jsx("button",{onClick:h=>{h.stopPropagation(),s(`/manageorganization/${o.id}`)},className:"self-start p-2 text-text-secondary hover:text-blue-600 hover:bg-blue-50 rounded-lg transition-colors duration-200 flex-shrink-0","aria-label":r("EditOrganization"),children:f.jsx(mg,{className:"w-5 h-5"})})]})})}function _y({showData:o}){const[r,s]=C.useState([]),a=C.useRef(null),u=20;return C.useEffect(()=>{s(o.slice(0,u))},[o]),C.useEffect(()=>{const d=new IntersectionObserver(h=>{if(h[0].isIntersecting&&r.length<o.length){const g=o.slice(r.length,r.length+u);s(y=>[...y,...g])}},{root:null,rootMargin:"20px",threshold:.1});return a.current&&d.observe(a.current),()=>{a.current&&d.unobserve(a.current)}},[r,o]),f.jsxs("div",{className:"w-full space-y-0",children:[r.map((d,h)=>f.jsx(Ty,{organization:d},d.id||`${d.organization_name}-${h}`)),r.length<o.length&&f.jsx("div",{ref:a,className:"w-full p-4 flex justify-center",children:f.jsx("div",{className:"animate-spin h-8 w-8 border-4 border-blue-500 rounded-full border-t-transparent"})})]})}function Iy(){const{userData:o}=C.useContext(et),{t:r}=Ae(),s=_t(),[a,u]=C.useState([]),[d,h]=C.useState([]);return C.useEffect(()=>{async function m(){if(!(!o||!o.token))try{const g=await Pe.allOrganizations(o.token);if(!g)return;u(g),h(g)}catch(g){g instanceof Error?console.error("Error fetching organizations:",g):console.log(g)}}m()},[o]),f.jsx("div",{className:"space-y-6",children:f.jsxs("div",{className:"bg-surface-primary rounded-lg border border-border-default shadow-sm",children:[f.jsxs("div",{className:"flex flex-col sm:flex-row sm:items-center sm:justify-between gap-4 p-6 border-b border-border-default",children:[f.jsx("h1",{className:"text-2xl font-semibold text-text-primary",children:r("organizations")}),f.jsxs("button",{onClick:()=>s("/createorganization"),className:"inline-flex items-center justify-center gap-2 px-4 py-2 bg-blue-600 hover:bg-blue-700 text-white font-medium rounded-lg transition-colors duration-200 shadow-sm hover:shadow-md whitespace-nowrap",children:[f.jsx("svg",{className:"w-5 h-5",fill:"none",stroke:"currentColor",viewBox:"0 0 24 24",children:f.jsx("path",{strokeLinecap:"round",strokeLinejoin:"round",strokeWidth:2,d:"M12 4v16m8-8H4"})}),r("createNewOrganization")]})]}),f.jsx(zy,{setShowData:h,allOrganizations:a}),f.jsx("div",{className:"p-6 pt-0",children:d.length>0?f.jsx(_y,{showData:d}):f.jsx("div",{className:"text-center py-12 text-text-secondary",children:r("noOrganizationsFound")})})]})})}const et=C.createContext({userData:{email:null,token:null,is_superuser:!1,is_organizationadmin:!1},toast:{show:!1,success:!0,text:"",header:""},setUserData:()=>{},setToast:()=>{}});function Dy(){const[o,r]=C.useState(My()),[s,a]=C.useState({show:!1,success:!0,header:"",text:""}),[u,d]=C.useState(!0),[h,m]=C.useState(!1);return f.jsx(f.Fragment,{children:f.jsx(_m,{children:f.jsx(mm,{children:f.jsx(et.Provider,{value:{userData:o,setUserData:r,toast:s,setToast:a},children:f.jsxs("div",{className:"bg-surface-secondary min-h-screen",children:[f.jsx(Oy,{}),o.email?f.jsxs("div",{className:"flex h-screen overflow-hidden",children:[f.jsx(ng,{isExpanded:u,setIsExpanded:d,isMobileOpen:h,setIsMobileOpen:m}),f.jsxs("div",{className:"flex-1 flex flex-col",children:[f.jsx(eg,{isSidebarExpanded:u,setIsMobileOpen:m}),f.jsx("main",{className:"flex overflow-y-auto pt-16 justify-center",children:f.jsx("div",{className:u?"flex lg:pl-[288px]":"flex md:pl-20 lg:pl-14",children:f.jsxs(dm,{children:[f.jsx(zt,{path:"/",element:f.jsx(sg,{})}),f.jsx(zt,{path:"/createuser",element:f.jsx(yg,{})}),f.jsx(zt,{path:"/showusers",element:f.jsx(by,{})}),f.jsx(zt,{path:"/manageuser/:id",element:f.jsx(Ey,{})}),f.jsx(zt,{path:"/createorganization",element:f.jsx(Ly,{})}),f.jsx(zt,{path:"/showorganizations",element:f.jsx(Iy,{})}),f.jsx(zt,{path:"/manageorganization/:id",element:f.jsx(Py,{})}),f.jsx(zt,{path:"/mymessages",element:f.jsx(dd,{isAdmin:!1})}),f.jsx(zt,{path:"/adminmessages",element:f.jsx(dd,{isAdmin:!0})})]})})})]})]}):f.jsx(rg,{})]})})})})})}function My(){const o=sessionStorage.getItem("userData");return o?JSON.parse(o):{email:null,token:null,is_superuser:!1,is_organizationadmin:!1}}const ae=o=>typeof o=="string",Zr=()=>{let o,r;const s=new Promise((a,u)=>{o=a,r=u});return s.resolve=o,s.reject=r,s},fd=o=>o==null?"":""+o,Ry=(o,r,s)=>{o.forEach(a=>{r[a]&&(s[a]=r[a])})},Fy=/###/g,hd=o=>o&&o.indexOf("###")>-1?o.replace(Fy,"."):o,pd=o=>!o||ae(o),Qr=(o,r,s)=>{const a=ae(r)?r.split("."):r;let u=0;for(;u<a.length-1;){if(pd(o))return{};const d=hd(a[u]);!o[d]&&s&&(o[d]=new s),Object.prototype.hasOwnProperty.call(o,d)?o=o[d]:o={},++u}return pd(o)?{}:{obj:o,k:hd(a[u])}},md=(o,r,s)=>{const{obj:a,k:u}=Qr(o,r,Object);if(a!==void 0||r.length===1){a[u]=s;return}let d=r[r.length-1],h=r.slice(0,r.length-1),m=Qr(o,h,Object);for(;m.obj===void 0&&h.length;)d=`${h[h.length-1]}.${d}`,h=h.slice(0,h.length-1),m=Qr(o,h,Object),m?.obj&&typeof m.obj[`${m.k}.${d}`]<"u"&&(m.obj=void 0);m.obj[`${m.k}.${d}`]=s},$y=(o,r,s,a)=>{const{obj:u,k:d}=Qr(o,r,Object);u[d]=u[d]||[],u[d].push(s)},vi=(o,r)=>{const{obj:s,k:a}=Qr(o,r);if(s&&Object.prototype.hasOwnProperty.call(s,a))return s[a]},Ay=(o,r,s)=>{const a=vi(o,s);return a!==void 0?a:vi(r,s)},lf=(o,r,s)=>{for(const a in r)a!=="__proto__"&&a!=="constructor"&&(a in o?ae(o[a])||o[a]instanceof String||ae(r[a])||r[a]instanceof String?s&&(o[a]=r[a]):lf(o[a],r[a],s):o[a]=r[a]);return o},tr=o=>o.replace(/[\-\[\]\/\{\}\(\)\*\+\?\.\\\^\$\|]/g,"\\$&");var Uy={"&":"&amp;","<":"&lt;",">":"&gt;",'"':"&quot;","'":"&#39;","/":"&#x2F;"};const Wy=o=>ae(o)?o.replace(/[&<>"'\/]/g,r=>Uy[r]):o;class By{constructor(r){this.capacity=r,this.regExpMap=new Map,this.regExpQueue=[]}getRegExp(r){const s=this.regExpMap.get(r);if(s!==void 0)return s;const a=new RegExp(r);return this.regExpQueue.length===this.capacity&&this.regExpMap.delete(this.regExpQueue.shift()),this.regExpMap.set(r,a),this.regExpQueue.push(r),a}}const Hy=[" ",",","?","!",";"],Vy=new By(20),Yy=(o,r,s)=>{r=r||"",s=s||"";const a=Hy.filter(h=>r.indexOf(h)<0&&s.indexOf(h)<0);if(a.length===0)return!0;const u=Vy.getRegExp(`(${a.map(h=>h==="?"?"\\?":h).join("|")})`);let d=!u.test(o);if(!d){const h=o.indexOf(s);h>0&&!u.test(o.substring(0,h))&&(d=!0)}return d},Ia=(o,r,s=".")=>{if(!o)return;if(o[r])return Object.prototype.hasOwnProperty.call(o,r)?o[r]:void 0;const a=r.split(s);let u=o;for(let d=0;d<a.length;){if(!u||typeof u!="object")return;let h,m="";for(let g=d;g<a.length;++g)if(g!==d&&(m+=s),m+=a[g],h=u[m],h!==void 0){if(["string","number","boolean"].indexOf(typeof h)>-1&&g<a.length-1)continue;d+=g-d+1;break}u=h}return u},qr=o=>o?.replace("_","-"),Gy={type:"logger",log(o){this.output("log",o)},warn(o){this.output("warn",o)},error(o){this.output("error",o)},output(o,r){console?.[o]?.apply?.(console,r)}};class xi{constructor(r,s={}){this.init(r,s)}init(r,s={}){this.prefix=s.prefix||"i18next:",this.logger=r||Gy,this.options=s,this.debug=s.debug}log(...r){return this.forward(r,"log","",!0)}warn(...r){return this.forward(r,"warn","",!0)}error(...r){return this.forward(r,"error","")}deprecate(...r){return this.forward(r,"warn","WARNING DEPRECATED: ",!0)}forward(r,s,a,u){return u&&!this.debug?null:(ae(r[0])&&(r[0]=`${a}${this.prefix} ${r[0]}`),this.logger[s](r))}create(r){return new xi(this.logger,{prefix:`${this.prefix}:${r}:`,...this.options})}clone(r){return r=r||this.options,r.prefix=r.prefix||this.prefix,new xi(this.logger,r)}}var Tt=new xi;class ji{constructor(){this.observers={}}on(r,s){return r.split(" ").forEach(a=>{this.observers[a]||(this.observers[a]=new Map);const u=this.observers[a].get(s)||0;this.observers[a].set(s,u+1)}),this}off(r,s){if(this.observers[r]){if(!s){delete this.observers[r];return}this.observers[r].delete(s)}}emit(r,...s){this.observers[r]&&Array.from(this.observers[r].entries()).forEach(([u,d])=>{for(let h=0;h<d;h++)u(...s)}),this.observers["*"]&&Array.from(this.observers["*"].entries()).forEach(([u,d])=>{for(let h=0;h<d;h++)u.apply(u,[r,...s])})}}class gd extends ji{constructor(r,s={ns:["translation"],defaultNS:"translation"}){super(),this.data=r||{},this.options=s,this.options.keySeparator===void 0&&(this.options.keySeparator="."),this.options.ignoreJSONStructure===void 0&&(this.options.ignoreJSONStructure=!0)}addNamespaces(r){this.options.ns.indexOf(r)<0&&this.options.ns.push(r)}removeNamespaces(r){const s=this.options.ns.indexOf(r);s>-1&&this.options.ns.splice(s,1)}getResource(r,s,a,u={}){const d=u.keySeparator!==void 0?u.keySeparator:this.options.keySeparator,h=u.ignoreJSONStructure!==void 0?u.ignoreJSONStructure:this.options.ignoreJSONStructure;let m;r.indexOf(".")>-1?m=r.split("."):(m=[r,s],a&&(Array.isArray(a)?m.push(...a):ae(a)&&d?m.push(...a.split(d)):m.push(a)));const g=vi(this.data,m);return!g&&!s&&!a&&r.indexOf(".")>-1&&(r=m[0],s=m[1],a=m.slice(2).join(".")),g||!h||!ae(a)?g:Ia(this.data?.[r]?.[s],a,d)}addResource(r,s,a,u,d={silent:!1}){const h=d.keySeparator!==void 0?d.keySeparator:this.options.keySeparator;let m=[r,s];a&&(m=m.concat(h?a.split(h):a)),r.indexOf(".")>-1&&(m=r.split("."),u=s,s=m[1]),this.addNamespaces(s),md(this.data,m,u),d.silent||this.emit("added",r,s,a,u)}addResources(r,s,a,u={silent:!1}){for(const d in a)(ae(a[d])||Array.isArray(a[d]))&&this.addResource(r,s,d,a[d],{silent:!0});u.silent||this.emit("added",r,s,a)}addResourceBundle(r,s,a,u,d,h={silent:!1,skipCopy:!1}){let m=[r,s];r.indexOf(".")>-1&&(m=r.split("."),u=a,a=s,s=m[1]),this.addNamespaces(s);let g=vi(this.data,m)||{};h.skipCopy||(a=JSON.parse(JSON.stringify(a))),u?lf(g,a,d):g={...g,...a},md(this.data,m,g),h.silent||this.emit("added",r,s,a)}removeResourceBundle(r,s){this.hasResourceBundle(r,s)&&delete this.data[r][s],this.removeNamespaces(s),this.emit("removed",r,s)}hasResourceBundle(r,s){return this.getResource(r,s)!==void 0}getResourceBundle(r,s){return s||(s=this.options.defaultNS),this.getResource(r,s)}getDataByLanguage(r){return this.data[r]}hasLanguageSomeTranslations(r){const s=this.getDataByLanguage(r);return!!(s&&Object.keys(s)||[]).find(u=>s[u]&&Object.keys(s[u]).length>0)}toJSON(){return this.data}}var uf={processors:{},addPostProcessor(o){this.processors[o.name]=o},handle(o,r,s,a,u){return o.forEach(d=>{r=this.processors[d]?.process(r,s,a,u)??r}),r}};const yd={},vd=o=>!ae(o)&&typeof o!="boolean"&&typeof o!="number";class wi extends ji{constructor(r,s={}){super(),Ry(["resourceStore","languageUtils","pluralResolver","interpolator","backendConnector","i18nFormat","utils"],r,this),this.options=s,this.options.keySeparator===void 0&&(this.options.keySeparator="."),this.logger=Tt.create("translator")}changeLanguage(r){r&&(this.language=r)}exists(r,s={interpolation:{}}){const a={...s};return r==null?!1:this.resolve(r,a)?.res!==void 0}extractFromKey(r,s){let a=s.nsSeparator!==void 0?s.nsSeparator:this.options.nsSeparator;a===void 0&&(a=":");const u=s.keySeparator!==void 0?s.keySeparator:this.options.keySeparator;let d=s.ns||this.options.defaultNS||[];const h=a&&r.indexOf(a)>-1,m=!this.options.userDefinedKeySeparator&&!s.keySeparator&&!this.options.userDefinedNsSeparator&&!s.nsSeparator&&!Yy(r,a,u);if(h&&!m){const g=r.match(this.interpolator.nestingRegexp);if(g&&g.length>0)return{key:r,namespaces:ae(d)?[d]:d};const y=r.split(a);(a!==u||a===u&&this.options.ns.indexOf(y[0])>-1)&&(d=y.shift()),r=y.join(u)}return{key:r,namespaces:ae(d)?[d]:d}}translate(r,s,a){let u=typeof s=="object"?{...s}:s;if(typeof u!="object"&&this.options.overloadTranslationOptionHandler&&(u=this.options.overloadTranslationOptionHandler(arguments)),typeof options=="object"&&(u={...u}),u||(u={}),r==null)return"";Array.isArray(r)||(r=[String(r)]);const d=u.returnDetails!==void 0?u.returnDetails:this.options.returnDetails,h=u.keySeparator!==void 0?u.keySeparator:this.options.keySeparator,{key:m,namespaces:g}=this.extractFromKey(r[r.length-1],u),y=g[g.length-1];let k=u.nsSeparator!==void 0?u.nsSeparator:this.options.nsSeparator;k===void 0&&(k=":");const x=u.lng||this.language,L=u.appendNamespaceToCIMode||this.options.appendNamespaceToCIMode;if(x?.toLowerCase()==="cimode")return L?d?{res:`${y}${k}${m}`,usedKey:m,exactUsedKey:m,usedLng:x,usedNS:y,usedParams:this.getUsedParamsDetails(u)}:`${y}${k}${m}`:d?{res:m,usedKey:m,exactUsedKey:m,usedLng:x,usedNS:y,usedParams:this.getUsedParamsDetails(u)}:m;const I=this.resolve(r,u);let E=I?.res;const z=I?.usedKey||m,T=I?.exactUsedKey||m,A=["[object Number]","[object Function]","[object RegExp]"],H=u.joinArrays!==void 0?u.joinArrays:this.options.joinArrays,Y=!this.i18nFormat||this.i18nFormat.handleAsObject,Q=u.count!==void 0&&!ae(u.count),U=wi.hasDefaultValue(u),J=Q?this.pluralResolver.getSuffix(x,u.count,u):"",ee=u.ordinal&&Q?this.pluralResolver.getSuffix(x,u.count,{ordinal:!1}):"",re=Q&&!u.ordinal&&u.count===0,fe=re&&u[`defaultValue${this.options.pluralSeparator}zero`]||u[`defaultValue${J}`]||u[`defaultValue${ee}`]||u.defaultValue;let de=E;Y&&!E&&U&&(de=fe);const Ce=vd(de),Se=Object.prototype.toString.apply(de);if(Y&&de&&Ce&&A.indexOf(Se)<0&&!(ae(H)&&Array.isArray(de))){if(!u.returnObjects&&!this.options.returnObjects){this.options.returnedObjectHandler||this.logger.warn("accessing an object - but returnObjects options is not enabled!");const M=this.options.returnedObjectHandler?this.options.returnedObjectHandler(z,de,{...u,ns:g}):`key '${m} (${this.language})' returned an object instead of string.`;return d?(I.res=M,I.usedParams=this.getUsedParamsDetails(u),I):M}if(h){const M=Array.isArray(de),q=M?[]:{},he=M?T:z;for(const se in de)if(Object.prototype.hasOwnProperty.call(de,se)){const le=`${he}${h}${se}`;U&&!E?q[se]=this.translate(le,{...u,defaultValue:vd(fe)?fe[se]:void 0,joinArrays:!1,ns:g}):q[se]=this.translate(le,{...u,joinArrays:!1,ns:g}),q[se]===le&&(q[se]=de[se])}E=q}}else if(Y&&ae(H)&&Array.isArray(E))E=E.join(H),E&&(E=this.extendTranslation(E,r,u,a));else{let M=!1,q=!1;!this.isValidLookup(E)&&U&&(M=!0,E=fe),this.isValidLookup(E)||(q=!0,E=m);const se=(u.missingKeyNoValueFallbackToKey||this.options.missingKeyNoValueFallbackToKey)&&q?void 0:E,le=U&&fe!==E&&this.options.updateMissing;if(q||M||le){if(this.logger.log(le?"updateKey":"missingKey",x,y,m,le?fe:E),h){const S=this.resolve(m,{...u,keySeparator:!1});S&&S.res&&this.logger.warn("Seems the loaded translations were in flat JSON format instead of nested. Either set keySeparator: false on init or make sure your translations are published in nested format.")}let B=[];const _=this.languageUtils.getFallbackCodes(this.options.fallbackLng,u.lng||this.language);if(this.options.saveMissingTo==="fallback"&&_&&_[0])for(let S=0;S<_.length;S++)B.push(_[S]);else this.options.saveMissingTo==="all"?B=this.languageUtils.toResolveHierarchy(u.lng||this.language):B.push(u.lng||this.language);const D=(S,P,ie)=>{const ue=U&&ie!==E?ie:se;this.options.missingKeyHandler?this.options.missingKeyHandler(S,y,P,ue,le,u):this.backendConnector?.saveMissing&&this.backendConnector.saveMissing(S,y,P,ue,le,u),this.emit("missingKey",S,y,P,E)};this.options.saveMissing&&(this.options.saveMissingPlurals&&Q?B.forEach(S=>{const P=this.pluralResolver.getSuffixes(S,u);re&&u[`defaultValue${this.options.pluralSeparator}zero`]&&P.indexOf(`${this.options.pluralSeparator}zero`)<0&&P.push(`${this.options.pluralSeparator}zero`),P.forEach(ie=>{D([S],m+ie,u[`defaultValue${ie}`]||fe)})}):D(B,m,fe))}E=this.extendTranslation(E,r,u,I,a),q&&E===m&&this.options.appendNamespaceToMissingKey&&(E=`${y}${k}${m}`),(q||M)&&this.options.parseMissingKeyHandler&&(E=this.options.parseMissingKeyHandler(this.options.appendNamespaceToMissingKey?`${y}${k}${m}`:m,M?E:void 0,u))}return d?(I.res=E,I.usedParams=this.getUsedParamsDetails(u),I):E}extendTranslation(r,s,a,u,d){if(this.i18nFormat?.parse)r=this.i18nFormat.parse(r,{...this.options.interpolation.defaultVariables,...a},a.lng||this.language||u.usedLng,u.usedNS,u.usedKey,{resolved:u});else if(!a.skipInterpolation){a.interpolation&&this.interpolator.init({...a,interpolation:{...this.options.interpolation,...a.interpolation}});const g=ae(r)&&(a?.interpolation?.skipOnVariables!==void 0?a.interpolation.skipOnVariables:this.options.interpolation.skipOnVariables);let y;if(g){const x=r.match(this.interpolator.nestingRegexp);y=x&&x.length}let k=a.replace&&!ae(a.replace)?a.replace:a;if(this.options.interpolation.defaultVariables&&(k={...this.options.interpolation.defaultVariables,...k}),r=this.interpolator.interpolate(r,k,a.lng||this.language||u.usedLng,a),g){const x=r.match(this.interpolator.nestingRegexp),L=x&&x.length;y<L&&(a.nest=!1)}!a.lng&&u&&u.res&&(a.lng=this.language||u.usedLng),a.nest!==!1&&(r=this.interpolator.nest(r,(...x)=>d?.[0]===x[0]&&!a.context?(this.logger.warn(`It seems you are nesting recursively key: ${x[0]} in key: ${s[0]}`),null):this.translate(...x,s),a)),a.interpolation&&this.interpolator.reset()}const h=a.postProcess||this.options.postProcess,m=ae(h)?[h]:h;return r!=null&&m?.length&&a.applyPostProcessor!==!1&&(r=uf.handle(m,r,s,this.options&&this.options.postProcessPassResolved?{i18nResolved:{...u,usedParams:this.getUsedParamsDetails(a)},...a}:a,this)),r}resolve(r,s={}){let a,u,d,h,m;return ae(r)&&(r=[r]),r.forEach(g=>{if(this.isValidLookup(a))return;const y=this.extractFromKey(g,s),k=y.key;u=k;let x=y.namespaces;this.options.fallbackNS&&(x=x.concat(this.options.fallbackNS));const L=s.count!==void 0&&!ae(s.count),I=L&&!s.ordinal&&s.count===0,E=s.context!==void 0&&(ae(s.context)||typeof s.context=="number")&&s.context!=="",z=s.lngs?s.lngs:this.languageUtils.toResolveHierarchy(s.lng||this.language,s.fallbackLng);x.forEach(T=>{this.isValidLookup(a)||(m=T,!yd[`${z[0]}-${T}`]&&this.utils?.hasLoadedNamespace&&!this.utils?.hasLoadedNamespace(m)&&(yd[`${z[0]}-${T}`]=!0,this.logger.warn(`key "${u}" for languages "${z.join(", ")}" won't get resolved as namespace "${m}" was not yet loaded`,"This means something IS WRONG in your setup. You access the t function before i18next.init / i18next.loadNamespace / i18next.changeLanguage was done. Wait for the callback or Promise to resolve before accessing it!!!")),z.forEach(A=>{if(this.isValidLookup(a))return;h=A;const H=[k];if(this.i18nFormat?.addLookupKeys)this.i18nFormat.addLookupKeys(H,k,A,T,s);else{let Q;L&&(Q=this.pluralResolver.getSuffix(A,s.count,s));const U=`${this.options.pluralSeparator}zero`,J=`${this.options.pluralSeparator}ordinal${this.options.pluralSeparator}`;if(L&&(H.push(k+Q),s.ordinal&&Q.indexOf(J)===0&&H.push(k+Q.replace(J,this.options.pluralSeparator)),I&&H.push(k+U)),E){const ee=`${k}${this.options.contextSeparator}${s.context}`;H.push(ee),L&&(H.push(ee+Q),s.ordinal&&Q.indexOf(J)===0&&H.push(ee+Q.replace(J,this.options.pluralSeparator)),I&&H.push(ee+U))}}let Y;for(;Y=H.pop();)this.isValidLookup(a)||(d=Y,a=this.getResource(A,T,Y,s))}))})}),{res:a,usedKey:u,exactUsedKey:d,usedLng:h,usedNS:m}}isValidLookup(r){return r!==void 0&&!(!this.options.returnNull&&r===null)&&!(!this.options.returnEmptyString&&r==="")}getResource(r,s,a,u={}){return this.i18nFormat?.getResource?this.i18nFormat.getResource(r,s,a,u):this.resourceStore.getResource(r,s,a,u)}getUsedParamsDetails(r={}){const s=["defaultValue","ordinal","context","replace","lng","lngs","fallbackLng","ns","keySeparator","nsSeparator","returnObjects","returnDetails","joinArrays","postProcess","interpolation"],a=r.replace&&!ae(r.replace);let u=a?r.replace:r;if(a&&typeof r.count<"u"&&(u.count=r.count),this.options.interpolation.defaultVariables&&(u={...this.options.interpolation.defaultVariables,...u}),!a){u={...u};for(const d of s)delete u[d]}return u}static hasDefaultValue(r){const s="defaultValue";for(const a in r)if(Object.prototype.hasOwnProperty.call(r,a)&&s===a.substring(0,s.length)&&r[a]!==void 0)return!0;return!1}}class xd{constructor(r){this.options=r,this.supportedLngs=this.options.supportedLngs||!1,this.logger=Tt.create("languageUtils")}getScriptPartFromCode(r){if(r=qr(r),!r||r.indexOf("-")<0)return null;const s=r.split("-");return s.length===2||(s.pop(),s[s.length-1].toLowerCase()==="x")?null:this.formatLanguageCode(s.join("-"))}getLanguagePartFromCode(r){if(r=qr(r),!r||r.indexOf("-")<0)return r;const s=r.split("-");return this.formatLanguageCode(s[0])}formatLanguageCode(r){if(ae(r)&&r.indexOf("-")>-1){let s;try{s=Intl.getCanonicalLocales(r)[0]}catch{}return s&&this.options.lowerCaseLng&&(s=s.toLowerCase()),s||(this.options.lowerCaseLng?r.toLowerCase():r)}return this.options.cleanCode||this.options.lowerCaseLng?r.toLowerCase():r}isSupportedCode(r){return(this.options.load==="languageOnly"||this.options.nonExplicitSupportedLngs)&&(r=this.getLanguagePartFromCode(r)),!this.supportedLngs||!this.supportedLngs.length||this.supportedLngs.indexOf(r)>-1}getBestMatchFromCodes(r){if(!r)return null;let s;return r.forEach(a=>{if(s)return;const u=this.formatLanguageCode(a);(!this.options.supportedLngs||this.isSupportedCode(u))&&(s=u)}),!s&&this.options.supportedLngs&&r.forEach(a=>{if(s)return;const u=this.getScriptPartFromCode(a);if(this.isSupportedCode(u))return s=u;const d=this.getLanguagePartFromCode(a);if(this.isSupportedCode(d))return s=d;s=this.options.supportedLngs.find(h=>{if(h===d)return h;if(!(h.indexOf("-")<0&&d.indexOf("-")<0)&&(h.indexOf("-")>0&&d.indexOf("-")<0&&h.substring(0,h.indexOf("-"))===d||h.indexOf(d)===0&&d.length>1))return h})}),s||(s=this.getFallbackCodes(this.options.fallbackLng)[0]),s}getFallbackCodes(r,s){if(!r)return[];if(typeof r=="function"&&(r=r(s)),ae(r)&&(r=[r]),Array.isArray(r))return r;if(!s)return r.default||[];let a=r[s];return a||(a=r[this.getScriptPartFromCode(s)]),a||(a=r[this.formatLanguageCode(s)]),a||(a=r[this.getLanguagePartFromCode(s)]),a||(a=r.default),a||[]}toResolveHierarchy(r,s){const a=this.getFallbackCodes((s===!1?[]:s)||this.options.fallbackLng||[],r),u=[],d=h=>{h&&(this.isSupportedCode(h)?u.push(h):this.logger.warn(`rejecting language code not found in supportedLngs: ${h}`))};return ae(r)&&(r.indexOf("-")>-1||r.indexOf("_")>-1)?(this.options.load!=="languageOnly"&&d(this.formatLanguageCode(r)),this.options.load!=="languageOnly"&&this.options.load!=="currentOnly"&&d(this.getScriptPartFromCode(r)),this.options.load!=="currentOnly"&&d(this.getLanguagePartFromCode(r))):ae(r)&&d(this.formatLanguageCode(r)),a.forEach(h=>{u.indexOf(h)<0&&d(this.formatLanguageCode(h))}),u}}const wd={zero:0,one:1,two:2,few:3,many:4,other:5},Sd={select:o=>o===1?"one":"other",resolvedOptions:()=>({pluralCategories:["one","other"]})};class Zy{constructor(r,s={}){this.languageUtils=r,this.options=s,this.logger=Tt.create("pluralResolver"),this.pluralRulesCache={}}addRule(r,s){this.rules[r]=s}clearCache(){this.pluralRulesCache={}}getRule(r,s={}){const a=qr(r==="dev"?"en":r),u=s.ordinal?"ordinal":"cardinal",d=JSON.stringify({cleanedCode:a,type:u});if(d in this.pluralRulesCache)return this.pluralRulesCache[d];let h;try{h=new Intl.PluralRules(a,{type:u})}catch{if(!Intl)return this.logger.error("No Intl support, please use an Intl polyfill!"),Sd;if(!r.match(/-|_/))return Sd;const g=this.languageUtils.getLanguagePartFromCode(r);h=this.getRule(g,s)}return this.pluralRulesCache[d]=h,h}needsPlural(r,s={}){let a=this.getRule(r,s);return a||(a=this.getRule("dev",s)),a?.resolvedOptions().pluralCategories.length>1}getPluralFormsOfKey(r,s,a={}){return this.getSuffixes(r,a).map(u=>`${s}${u}`)}getSuffixes(r,s={}){let a=this.getRule(r,s);return a||(a=this.getRule("dev",s)),a?a.resolvedOptions().pluralCategories.sort((u,d)=>wd[u]-wd[d]).map(u=>`${this.options.prepend}${s.ordinal?`ordinal${this.options.prepend}`:""}${u}`):[]}getSuffix(r,s,a={}){const u=this.getRule(r,a);return u?`${this.options.prepend}${a.ordinal?`ordinal${this.options.prepend}`:""}${u.select(s)}`:(this.logger.warn(`no plural rule found for: ${r}`),this.getSuffix("dev",s,a))}}const kd=(o,r,s,a=".",u=!0)=>{let d=Ay(o,r,s);return!d&&u&&ae(s)&&(d=Ia(o,s,a),d===void 0&&(d=Ia(r,s,a))),d},Ea=o=>o.replace(/\$/g,"$$$$");class Qy{constructor(r={}){this.logger=Tt.create("interpolator"),this.options=r,this.format=r?.interpolation?.format||(s=>s),this.init(r)}init(r={}){r.interpolation||(r.interpolation={escapeValue:!0});const{escape:s,escapeValue:a,useRawValueToEscape:u,prefix:d,prefixEscaped:h,suffix:m,suffixEscaped:g,formatSeparator:y,unescapeSuffix:k,unescapePrefix:x,nestingPrefix:L,nestingPrefixEscaped:I,nestingSuffix:E,nestingSuffixEscaped:z,nestingOptionsSeparator:T,maxReplaces:A,alwaysFormat:H}=r.interpolation;this.escape=s!==void 0?s:Wy,this.escapeValue=a!==void 0?a:!0,this.useRawValueToEscape=u!==void 0?u:!1,this.prefix=d?tr(d):h||"{{",this.suffix=m?tr(m):g||"}}",this.formatSeparator=y||",",this.unescapePrefix=k?"":x||"-",this.unescapeSuffix=this.unescapePrefix?"":k||"",this.nestingPrefix=L?tr(L):I||tr("$t("),this.nestingSuffix=E?tr(E):z||tr(")"),this.nestingOptionsSeparator=T||",",this.maxReplaces=A||1e3,this.alwaysFormat=H!==void 0?H:!1,this.resetRegExp()}reset(){this.options&&this.init(this.options)}resetRegExp(){const r=(s,a)=>s?.source===a?(s.lastIndex=0,s):new RegExp(a,"g");this.regexp=r(this.regexp,`${this.prefix}(.+?)${this.suffix}`),this.regexpUnescape=r(this.regexpUnescape,`${this.prefix}${this.unescapePrefix}(.+?)${this.unescapeSuffix}${this.suffix}`),this.nestingRegexp=r(this.nestingRegexp,`${this.nestingPrefix}(.+?)${this.nestingSuffix}`)}interpolate(r,s,a,u){let d,h,m;const g=this.options&&this.options.interpolation&&this.options.interpolation.defaultVariables||{},y=I=>{if(I.indexOf(this.formatSeparator)<0){const A=kd(s,g,I,this.options.keySeparator,this.options.ignoreJSONStructure);return this.alwaysFormat?this.format(A,void 0,a,{...u,...s,interpolationkey:I}):A}const E=I.split(this.formatSeparator),z=E.shift().trim(),T=E.join(this.formatSeparator).trim();return this.format(kd(s,g,z,this.options.keySeparator,this.options.ignoreJSONStructure),T,a,{...u,...s,interpolationkey:z})};this.resetRegExp();const k=u?.missingInterpolationHandler||this.options.missingInterpolationHandler,x=u?.interpolation?.skipOnVariables!==void 0?u.interpolation.skipOnVariables:this.options.interpolation.skipOnVariables;return[{regex:this.regexpUnescape,safeValue:I=>Ea(I)},{regex:this.regexp,safeValue:I=>this.escapeValue?Ea(this.escape(I)):Ea(I)}].forEach(I=>{for(m=0;d=I.regex.exec(r);){const E=d[1].trim();if(h=y(E),h===void 0)if(typeof k=="function"){const T=k(r,d,u);h=ae(T)?T:""}else if(u&&Object.prototype.hasOwnProperty.call(u,E))h="";else if(x){h=d[0];continue}else this.logger.warn(`missed to pass in variable ${E} for interpolating ${r}`),h="";else!ae(h)&&!this.useRawValueToEscape&&(h=fd(h));const z=I.safeValue(h);if(r=r.replace(d[0],z),x?(I.regex.lastIndex+=h.length,I.regex.lastIndex-=d[0].length):I.regex.lastIndex=0,m++,m>=this.maxReplaces)break}}),r}nest(r,s,a={}){let u,d,h;const m=(g,y)=>{const k=this.nestingOptionsSeparator;if(g.indexOf(k)<0)return g;const x=g.split(new RegExp(`${k}[ ]*{`));let L=`{${x[1]}`;g=x[0],L=this.interpolate(L,h);const I=L.match(/'/g),E=L.match(/"/g);((I?.length??0)%2===0&&!E||E.length%2!==0)&&(L=L.replace(/'/g,'"'));try{h=JSON.parse(L),y&&(h={...y,...h})}catch(z){return this.logger.warn(`failed parsing options string in nesting for key ${g}`,z),`${g}${k}${L}`}return h.defaultValue&&h.defaultValue.indexOf(this.prefix)>-1&&delete h.defaultValue,g};for(;u=this.nestingRegexp.exec(r);){let g=[];h={...a},h=h.replace&&!ae(h.replace)?h.replace:h,h.applyPostProcessor=!1,delete h.defaultValue;const y=/{.*}/.test(u[1])?u[1].lastIndexOf("}")+1:u[1].indexOf(this.formatSeparator);if(y!==-1&&(g=u[1].slice(y).split(this.formatSeparator).map(k=>k.trim()).filter(Boolean),u[1]=u[1].slice(0,y)),d=s(m.call(this,u[1].trim(),h),h),d&&u[0]===r&&!ae(d))return d;ae(d)||(d=fd(d)),d||(this.logger.warn(`missed to resolve ${u[1]} for nesting ${r}`),d=""),g.length&&(d=g.reduce((k,x)=>this.format(k,x,a.lng,{...a,interpolationkey:u[1].trim()}),d.trim())),r=r.replace(u[0],d),this.regexp.lastIndex=0}return r}}const Ky=o=>{let r=o.toLowerCase().trim();const s={};if(o.indexOf("(")>-1){const a=o.split("(");r=a[0].toLowerCase().trim();const u=a[1].substring(0,a[1].length-1);r==="currency"&&u.indexOf(":")<0?s.currency||(s.currency=u.trim()):r==="relativetime"&&u.indexOf(":")<0?s.range||(s.range=u.trim()):u.split(";").forEach(h=>{if(h){const[m,...g]=h.split(":"),y=g.join(":").trim().replace(/^'+|'+$/g,""),k=m.trim();s[k]||(s[k]=y),y==="false"&&(s[k]=!1),y==="true"&&(s[k]=!0),isNaN(y)||(s[k]=parseInt(y,10))}})}return{formatName:r,formatOptions:s}},Nd=o=>{const r={};return(s,a,u)=>{let d=u;u&&u.interpolationkey&&u.formatParams&&u.formatParams[u.interpolationkey]&&u[u.interpolationkey]&&(d={...d,[u.interpolationkey]:void 0});const h=a+JSON.stringify(d);let m=r[h];return m||(m=o(qr(a),u),r[h]=m),m(s)}},Xy=o=>(r,s,a)=>o(qr(s),a)(r);class Jy{constructor(r={}){this.logger=Tt.create("formatter"),this.options=r,this.init(r)}init(r,s={interpolation:{}}){this.formatSeparator=s.interpolation.formatSeparator||",";const a=s.cacheInBuiltFormats?Nd:Xy;this.formats={number:a((u,d)=>{const h=new Intl.NumberFormat(u,{...d});return m=>h.format(m)}),currency:a((u,d)=>{const h=new Intl.NumberFormat(u,{...d,style:"currency"});return m=>h.format(m)}),datetime:a((u,d)=>{const h=new Intl.DateTimeFormat(u,{...d});return m=>h.format(m)}),relativetime:a((u,d)=>{const h=new Intl.RelativeTimeFormat(u,{...d});return m=>h.format(m,d.range||"day")}),list:a((u,d)=>{const h=new Intl.ListFormat(u,{...d});return m=>h.format(m)})}}add(r,s){this.formats[r.toLowerCase().trim()]=s}addCached(r,s){this.formats[r.toLowerCase().trim()]=Nd(s)}format(r,s,a,u={}){const d=s.split(this.formatSeparator);if(d.length>1&&d[0].indexOf("(")>1&&d[0].indexOf(")")<0&&d.find(m=>m.indexOf(")")>-1)){const m=d.findIndex(g=>g.indexOf(")")>-1);d[0]=[d[0],...d.splice(1,m)].join(this.formatSeparator)}return d.reduce((m,g)=>{const{formatName:y,formatOptions:k}=Ky(g);if(this.formats[y]){let x=m;try{const L=u?.formatParams?.[u.interpolationkey]||{},I=L.locale||L.lng||u.locale||u.lng||a;x=this.formats[y](m,I,{...k,...u,...L})}catch(L){this.logger.warn(L)}return x}else this.logger.warn(`there was no format function for ${y}`);return m},r)}}const qy=(o,r)=>{o.pending[r]!==void 0&&(delete o.pending[r],o.pendingCount--)};class ev extends ji{constructor(r,s,a,u={}){super(),this.backend=r,this.store=s,this.services=a,this.languageUtils=a.languageUtils,this.options=u,this.logger=Tt.create("backendConnector"),this.waitingReads=[],this.maxParallelReads=u.maxParallelReads||10,this.readingCalls=0,this.maxRetries=u.maxRetries>=0?u.maxRetries:5,this.retryTimeout=u.retryTimeout>=1?u.retryTimeout:350,this.state={},this.queue=[],this.backend?.init?.(a,u.backend,u)}queueLoad(r,s,a,u){const d={},h={},m={},g={};return r.forEach(y=>{let k=!0;s.forEach(x=>{const L=`${y}|${x}`;!a.reload&&this.store.hasResourceBundle(y,x)?this.state[L]=2:this.state[L]<0||(this.state[L]===1?h[L]===void 0&&(h[L]=!0):(this.state[L]=1,k=!1,h[L]===void 0&&(h[L]=!0),d[L]===void 0&&(d[L]=!0),g[x]===void 0&&(g[x]=!0)))}),k||(m[y]=!0)}),(Object.keys(d).length||Object.keys(h).length)&&this.queue.push({pending:h,pendingCount:Object.keys(h).length,loaded:{},errors:[],callback:u}),{toLoad:Object.keys(d),pending:Object.keys(h),toLoadLanguages:Object.keys(m),toLoadNamespaces:Object.keys(g)}}loaded(r,s,a){const u=r.split("|"),d=u[0],h=u[1];s&&this.emit("failedLoading",d,h,s),!s&&a&&this.store.addResourceBundle(d,h,a,void 0,void 0,{skipCopy:!0}),this.state[r]=s?-1:2,s&&a&&(this.state[r]=0);const m={};this.queue.forEach(g=>{$y(g.loaded,[d],h),qy(g,r),s&&g.errors.push(s),g.pendingCount===0&&!g.done&&(Object.keys(g.loaded).forEach(y=>{m[y]||(m[y]={});const k=g.loaded[y];k.length&&k.forEach(x=>{m[y][x]===void 0&&(m[y][x]=!0)})}),g.done=!0,g.errors.length?g.callback(g.errors):g.callback())}),this.emit("loaded",m),this.queue=this.queue.filter(g=>!g.done)}read(r,s,a,u=0,d=this.retryTimeout,h){if(!r.length)return h(null,{});if(this.readingCalls>=this.maxParallelReads){this.waitingReads.push({lng:r,ns:s,fcName:a,tried:u,wait:d,callback:h});return}this.readingCalls++;const m=(y,k)=>{if(this.readingCalls--,this.waitingReads.length>0){const x=this.waitingReads.shift();this.read(x.lng,x.ns,x.fcName,x.tried,x.wait,x.callback)}if(y&&k&&u<this.maxRetries){setTimeout(()=>{this.read.call(this,r,s,a,u+1,d*2,h)},d);return}h(y,k)},g=this.backend[a].bind(this.backend);if(g.length===2){try{const y=g(r,s);y&&typeof y.then=="function"?y.then(k=>m(null,k)).catch(m):m(null,y)}catch(y){m(y)}return}return g(r,s,m)}prepareLoading(r,s,a={},u){if(!this.backend)return this.logger.warn("No backend was added via i18next.use. Will not load resources."),u&&u();ae(r)&&(r=this.languageUtils.toResolveHierarchy(r)),ae(s)&&(s=[s]);const d=this.queueLoad(r,s,a,u);if(!d.toLoad.length)return d.pending.length||u(),null;d.toLoad.forEach(h=>{this.loadOne(h)})}load(r,s,a){this.prepareLoading(r,s,{},a)}reload(r,s,a){this.prepareLoading(r,s,{reload:!0},a)}loadOne(r,s=""){const a=r.split("|"),u=a[0],d=a[1];this.read(u,d,"read",void 0,void 0,(h,m)=>{h&&this.logger.warn(`${s}loading namespace ${d} for language ${u} failed`,h),!h&&m&&this.logger.log(`${s}loaded namespace ${d} for language ${u}`,m),this.loaded(r,h,m)})}saveMissing(r,s,a,u,d,h={},m=()=>{}){if(this.services?.utils?.hasLoadedNamespace&&!this.services?.utils?.hasLoadedNamespace(s)){this.logger.warn(`did not save key "${a}" as the namespace "${s}" was not yet loaded`,"This means something IS WRONG in your setup. You access the t function before i18next.init / i18next.loadNamespace / i18next.changeLanguage was done. Wait for the callback or Promise to resolve before accessing it!!!");return}if(!(a==null||a==="")){if(this.backend?.create){const g={...h,isUpdate:d},y=this.backend.create.bind(this.backend);if(y.length<6)try{let k;y.length===5?k=y(r,s,a,u,g):k=y(r,s,a,u),k&&typeof k.then=="function"?k.then(x=>m(null,x)).catch(m):m(null,k)}catch(k){m(k)}else y(r,s,a,u,m,g)}!r||!r[0]||this.store.addResource(r[0],s,a,u)}}}const Cd=()=>({debug:!1,initAsync:!0,ns:["translation"],defaultNS:["translation"],fallbackLng:["dev"],fallbackNS:!1,supportedLngs:!1,nonExplicitSupportedLngs:!1,load:"all",preload:!1,simplifyPluralSuffix:!0,keySeparator:".",nsSeparator:":",pluralSeparator:"_",contextSeparator:"_",partialBundledLanguages:!1,saveMissing:!1,updateMissing:!1,saveMissingTo:"fallback",saveMissingPlurals:!0,missingKeyHandler:!1,missingInterpolationHandler:!1,postProcess:!1,postProcessPassResolved:!1,returnNull:!1,returnEmptyString:!0,returnObjects:!1,joinArrays:!1,returnedObjectHandler:!1,parseMissingKeyHandler:!1,appendNamespaceToMissingKey:!1,appendNamespaceToCIMode:!1,overloadTranslationOptionHandler:o=>{let r={};if(typeof o[1]=="object"&&(r=o[1]),ae(o[1])&&(r.defaultValue=o[1]),ae(o[2])&&(r.tDescription=o[2]),typeof o[2]=="object"||typeof o[3]=="object"){const s=o[3]||o[2];Object.keys(s).forEach(a=>{r[a]=s[a]})}return r},interpolation:{escapeValue:!0,format:o=>o,prefix:"{{",suffix:"}}",formatSeparator:",",unescapePrefix:"-",nestingPrefix:"$t(",nestingSuffix:")",nestingOptionsSeparator:",",maxReplaces:1e3,skipOnVariables:!0},cacheInBuiltFormats:!0}),jd=o=>(ae(o.ns)&&(o.ns=[o.ns]),ae(o.fallbackLng)&&(o.fallbackLng=[o.fallbackLng]),ae(o.fallbackNS)&&(o.fallbackNS=[o.fallbackNS]),o.supportedLngs?.indexOf?.("cimode")<0&&(o.supportedLngs=o.supportedLngs.concat(["cimode"])),typeof o.initImmediate=="boolean"&&(o.initAsync=o.initImmediate),o),pi=()=>{},tv=o=>{Object.getOwnPropertyNames(Object.getPrototypeOf(o)).forEach(s=>{typeof o[s]=="function"&&(o[s]=o[s].bind(o))})};class es extends ji{constructor(r={},s){if(super(),this.options=jd(r),this.services={},this.logger=Tt,this.modules={external:[]},tv(this),s&&!this.isInitialized&&!r.isClone){if(!this.options.initAsync)return this.init(r,s),this;setTimeout(()=>{this.init(r,s)},0)}}init(r={},s){this.isInitializing=!0,typeof r=="function"&&(s=r,r={}),r.defaultNS==null&&r.ns&&(ae(r.ns)?r.defaultNS=r.ns:r.ns.indexOf("translation")<0&&(r.defaultNS=r.ns[0]));const a=Cd();this.options={...a,...this.options,...jd(r)},this.options.interpolation={...a.interpolation,...this.options.interpolation},r.keySeparator!==void 0&&(this.options.userDefinedKeySeparator=r.keySeparator),r.nsSeparator!==void 0&&(this.options.userDefinedNsSeparator=r.nsSeparator);const u=y=>y?typeof y=="function"?new y:y:null;if(!this.options.isClone){this.modules.logger?Tt.init(u(this.modules.logger),this.options):Tt.init(null,this.options);let y;this.modules.formatter?y=this.modules.formatter:y=Jy;const k=new xd(this.options);this.store=new gd(this.options.resources,this.options);const x=this.services;x.logger=Tt,x.resourceStore=this.store,x.languageUtils=k,x.pluralResolver=new Zy(k,{prepend:this.options.pluralSeparator,simplifyPluralSuffix:this.options.simplifyPluralSuffix}),this.options.interpolation.format&&this.options.interpolation.format!==a.interpolation.format&&this.logger.warn("init: you are still using the legacy format function, please use the new approach: https://www.i18next.com/translation-function/formatting"),y&&(!this.options.interpolation.format||this.options.interpolation.format===a.interpolation.format)&&(x.formatter=u(y),x.formatter.init&&x.formatter.init(x,this.options),this.options.interpolation.format=x.formatter.format.bind(x.formatter)),x.interpolator=new Qy(this.options),x.utils={hasLoadedNamespace:this.hasLoadedNamespace.bind(this)},x.backendConnector=new ev(u(this.modules.backend),x.resourceStore,x,this.options),x.backendConnector.on("*",(I,...E)=>{this.emit(I,...E)}),this.modules.languageDetector&&(x.languageDetector=u(this.modules.languageDetector),x.languageDetector.init&&x.languageDetector.init(x,this.options.detection,this.options)),this.modules.i18nFormat&&(x.i18nFormat=u(this.modules.i18nFormat),x.i18nFormat.init&&x.i18nFormat.init(this)),this.translator=new wi(this.services,this.options),this.translator.on("*",(I,...E)=>{this.emit(I,...E)}),this.modules.external.forEach(I=>{I.init&&I.init(this)})}if(this.format=this.options.interpolation.format,s||(s=pi),this.options.fallbackLng&&!this.services.languageDetector&&!this.options.lng){const y=this.services.languageUtils.getFallbackCodes(this.options.fallbackLng);y.length>0&&y[0]!=="dev"&&(this.options.lng=y[0])}!this.services.languageDetector&&!this.options.lng&&this.logger.warn("init: no languageDetector is used and no lng is defined"),["getResource","hasResourceBundle","getResourceBundle","getDataByLanguage"].forEach(y=>{this[y]=(...k)=>this.store[y](...k)}),["addResource","addResources","addResourceBundle","removeResourceBundle"].forEach(y=>{this[y]=(...k)=>(this.store[y](...k),this)});const m=Zr(),g=()=>{const y=(k,x)=>{this.isInitializing=!1,this.isInitialized&&!this.initializedStoreOnce&&this.logger.warn("init: i18next is already initialized. You should call init just once!"),this.isInitialized=!0,this.options.isClone||this.logger.log("initialized",this.options),this.emit("initialized",this.options),m.resolve(x),s(k,x)};if(this.languages&&!this.isInitialized)return y(null,this.t.bind(this));this.changeLanguage(this.options.lng,y)};return this.options.resources||!this.options.initAsync?g():setTimeout(g,0),m}loadResources(r,s=pi){let a=s;const u=ae(r)?r:this.language;if(typeof r=="function"&&(a=r),!this.options.resources||this.options.partialBundledLanguages){if(u?.toLowerCase()==="cimode"&&(!this.options.preload||this.options.preload.length===0))return a();const d=[],h=m=>{if(!m||m==="cimode")return;this.services.languageUtils.toResolveHierarchy(m).forEach(y=>{y!=="cimode"&&d.indexOf(y)<0&&d.push(y)})};u?h(u):this.services.languageUtils.getFallbackCodes(this.options.fallbackLng).forEach(g=>h(g)),this.options.preload?.forEach?.(m=>h(m)),this.services.backendConnector.load(d,this.options.ns,m=>{!m&&!this.resolvedLanguage&&this.language&&this.setResolvedLanguage(this.language),a(m)})}else a(null)}reloadResources(r,s,a){const u=Zr();return typeof r=="function"&&(a=r,r=void 0),typeof s=="function"&&(a=s,s=void 0),r||(r=this.languages),s||(s=this.options.ns),a||(a=pi),this.services.backendConnector.reload(r,s,d=>{u.resolve(),a(d)}),u}use(r){if(!r)throw new Error("You are passing an undefined module! Please check the object you are passing to i18next.use()");if(!r.type)throw new Error("You are passing a wrong module! Please check the object you are passing to i18next.use()");return r.type==="backend"&&(this.modules.backend=r),(r.type==="logger"||r.log&&r.warn&&r.error)&&(this.modules.logger=r),r.type==="languageDetector"&&(this.modules.languageDetector=r),r.type==="i18nFormat"&&(this.modules.i18nFormat=r),r.type==="postProcessor"&&uf.addPostProcessor(r),r.type==="formatter"&&(this.modules.formatter=r),r.type==="3rdParty"&&this.modules.external.push(r),this}setResolvedLanguage(r){if(!(!r||!this.languages)&&!(["cimode","dev"].indexOf(r)>-1)){for(let s=0;s<this.languages.length;s++){const a=this.languages[s];if(!(["cimode","dev"].indexOf(a)>-1)&&this.store.hasLanguageSomeTranslations(a)){this.resolvedLanguage=a;break}}!this.resolvedLanguage&&this.languages.indexOf(r)<0&&this.store.hasLanguageSomeTranslations(r)&&(this.resolvedLanguage=r,this.languages.unshift(r))}}changeLanguage(r,s){this.isLanguageChangingTo=r;const a=Zr();this.emit("languageChanging",r);const u=m=>{this.language=m,this.languages=this.services.languageUtils.toResolveHierarchy(m),this.resolvedLanguage=void 0,this.setResolvedLanguage(m)},d=(m,g)=>{g?this.isLanguageChangingTo===r&&(u(g),this.translator.changeLanguage(g),this.isLanguageChangingTo=void 0,this.emit("languageChanged",g),this.logger.log("languageChanged",g)):this.isLanguageChangingTo=void 0,a.resolve((...y)=>this.t(...y)),s&&s(m,(...y)=>this.t(...y))},h=m=>{!r&&!m&&this.services.languageDetector&&(m=[]);const g=ae(m)?m:m&&m[0],y=this.store.hasLanguageSomeTranslations(g)?g:this.services.languageUtils.getBestMatchFromCodes(ae(m)?[m]:m);y&&(this.language||u(y),this.translator.language||this.translator.changeLanguage(y),this.services.languageDetector?.cacheUserLanguage?.(y)),this.loadResources(y,k=>{d(k,y)})};return!r&&this.services.languageDetector&&!this.services.languageDetector.async?h(this.services.languageDetector.detect()):!r&&this.services.languageDetector&&this.services.languageDetector.async?this.services.languageDetector.detect.length===0?this.services.languageDetector.detect().then(h):this.services.languageDetector.detect(h):h(r),a}getFixedT(r,s,a){const u=(d,h,...m)=>{let g;typeof h!="object"?g=this.options.overloadTranslationOptionHandler([d,h].concat(m)):g={...h},g.lng=g.lng||u.lng,g.lngs=g.lngs||u.lngs,g.ns=g.ns||u.ns,g.keyPrefix!==""&&(g.keyPrefix=g.keyPrefix||a||u.keyPrefix);const y=this.options.keySeparator||".";let k;return g.keyPrefix&&Array.isArray(d)?k=d.map(x=>`${g.keyPrefix}${y}${x}`):k=g.keyPrefix?`${g.keyPrefix}${y}${d}`:d,this.t(k,g)};return ae(r)?u.lng=r:u.lngs=r,u.ns=s,u.keyPrefix=a,u}t(...r){return this.translator?.translate(...r)}exists(...r){return this.translator?.exists(...r)}setDefaultNamespace(r){this.options.defaultNS=r}hasLoadedNamespace(r,s={}){if(!this.isInitialized)return this.logger.warn("hasLoadedNamespace: i18next was not initialized",this.languages),!1;if(!this.languages||!this.languages.length)return this.logger.warn("hasLoadedNamespace: i18n.languages were undefined or empty",this.languages),!1;const a=s.lng||this.resolvedLanguage||this.languages[0],u=this.options?this.options.fallbackLng:!1,d=this.languages[this.languages.length-1];if(a.toLowerCase()==="cimode")return!0;const h=(m,g)=>{const y=this.services.backendConnector.state[`${m}|${g}`];return y===-1||y===0||y===2};if(s.precheck){const m=s.precheck(this,h);if(m!==void 0)return m}return!!(this.hasResourceBundle(a,r)||!this.services.backendConnector.backend||this.options.resources&&!this.options.partialBundledLanguages||h(a,r)&&(!u||h(d,r)))}loadNamespaces(r,s){const a=Zr();return this.options.ns?(ae(r)&&(r=[r]),r.forEach(u=>{this.options.ns.indexOf(u)<0&&this.options.ns.push(u)}),this.loadResources(u=>{a.resolve(),s&&s(u)}),a):(s&&s(),Promise.resolve())}loadLanguages(r,s){const a=Zr();ae(r)&&(r=[r]);const u=this.options.preload||[],d=r.filter(h=>u.indexOf(h)<0&&this.services.languageUtils.isSupportedCode(h));return d.length?(this.options.preload=u.concat(d),this.loadResources(h=>{a.resolve(),s&&s(h)}),a):(s&&s(),Promise.resolve())}dir(r){if(r||(r=this.resolvedLanguage||(this.languages?.length>0?this.languages[0]:this.language)),!r)return"rtl";try{const u=new Intl.Locale(r);if(u&&u.getTextInfo){const d=u.getTextInfo();if(d&&d.direction)return d.direction}}catch{}const s=["ar","shu","sqr","ssh","xaa","yhd","yud","aao","abh","abv","acm","acq","acw","acx","acy","adf","ads","aeb","aec","afb","ajp","apc","apd","arb","arq","ars","ary","arz","auz","avl","ayh","ayl","ayn","ayp","bbz","pga","he","iw","ps","pbt","pbu","pst","prp","prd","ug","ur","ydd","yds","yih","ji","yi","hbo","men","xmn","fa","jpr","peo","pes","prs","dv","sam","ckb"],a=this.services?.languageUtils||new xd(Cd());return r.toLowerCase().indexOf("-latn")>1?"ltr":s.indexOf(a.getLanguagePartFromCode(r))>-1||r.toLowerCase().indexOf("-arab")>1?"rtl":"ltr"}static createInstance(r={},s){return new es(r,s)}cloneInstance(r={},s=pi){const a=r.forkResourceStore;a&&delete r.forkResourceStore;const u={...this.options,...r,isClone:!0},d=new es(u);if((r.debug!==void 0||r.prefix!==void 0)&&(d.logger=d.logger.clone(r)),["store","services","language"].forEach(m=>{d[m]=this[m]}),d.services={...this.services},d.services.utils={hasLoadedNamespace:d.hasLoadedNamespace.bind(d)},a){const m=Object.keys(this.store.data).reduce((g,y)=>(g[y]={...this.store.data[y]},g[y]=Object.keys(g[y]).reduce((k,x)=>(k[x]={...g[y][x]},k),g[y]),g),{});d.store=new gd(m,u),d.services.resourceStore=d.store}return d.translator=new wi(d.services,u),d.translator.on("*",(m,...g)=>{d.emit(m,...g)}),d.init(u,s),d.translator.options=u,d.translator.backendConnector.services.utils={hasLoadedNamespace:d.hasLoadedNamespace.bind(d)},d}toJSON(){return{options:this.options,store:this.store,language:this.language,languages:this.languages,resolvedLanguage:this.resolvedLanguage}}}const Xe=es.createInstance();Xe.createInstance=es.createInstance;Xe.createInstance;Xe.dir;Xe.init;Xe.loadResources;Xe.reloadResources;Xe.use;Xe.changeLanguage;Xe.getFixedT;Xe.t;Xe.exists;Xe.setDefaultNamespace;Xe.hasLoadedNamespace;Xe.loadNamespaces;Xe.loadLanguages;const{slice:nv,forEach:rv}=[];function sv(o){return rv.call(nv.call(arguments,1),r=>{if(r)for(const s in r)o[s]===void 0&&(o[s]=r[s])}),o}function iv(o){return typeof o!="string"?!1:[/<\s*script.*?>/i,/<\s*\/\s*script\s*>/i,/<\s*img.*?on\w+\s*=/i,/<\s*\w+\s*on\w+\s*=.*?>/i,/javascript\s*:/i,/vbscript\s*:/i,/expression\s*\(/i,/eval\s*\(/i,/alert\s*\(/i,/document\.cookie/i,/document\.write\s*\(/i,/window\.location/i,/innerHTML/i].some(s=>s.test(o))}const bd=/^[\u0009\u0020-\u007e\u0080-\u00ff]+$/,ov=function(o,r){const a=arguments.length>2&&arguments[2]!==void 0?arguments[2]:{path:"/"},u=encodeURIComponent(r);let d=`${o}=${u}`;if(a.maxAge>0){const h=a.maxAge-0;if(Number.isNaN(h))throw new Error("maxAge should be a Number");d+=`; Max-Age=${Math.floor(h)}`}if(a.domain){if(!bd.test(a.domain))throw new TypeError("option domain is invalid");d+=`; Domain=${a.domain}`}if(a.path){if(!bd.test(a.path))throw new TypeError("option path is invalid");d+=`; Path=${a.path}`}if(a.expires){if(typeof a.expires.toUTCString!="function")throw new TypeError("option expires is invalid");d+=`; Expires=${a.expires.toUTCString()}`}if(a.httpOnly&&(d+="; HttpOnly"),a.secure&&(d+="; Secure"),a.sameSite)switch(typeof a.sameSite=="string"?a.sameSite.toLowerCase():a.sameSite){case!0:d+="; SameSite=Strict";break;case"lax":d+="; SameSite=Lax";break;case"strict":d+="; SameSite=Strict";break;case"none":d+="; SameSite=None";break;default:throw new TypeError("option sameSite is invalid")}return a.partitioned&&(d+="; Partitioned"),d},Ed={create(o,r,s,a){let u=arguments.length>4&&arguments[4]!==void 0?arguments[4]:{path:"/",sameSite:"strict"};s&&(u.expires=new Date,u.expires.setTime(u.expires.getTime()+s*60*1e3)),a&&(u.domain=a),document.cookie=ov(o,r,u)},read(o){const r=`${o}=`,s=document.cookie.split(";");for(let a=0;a<s.length;a++){let u=s[a];for(;u.charAt(0)===" ";)u=u.substring(1,u.length);if(u.indexOf(r)===0)return u.substring(r.length,u.length)}return null},remove(o,r){this.create(o,"",-1,r)}};var av={name:"cookie",lookup(o){let{lookupCookie:r}=o;if(r&&typeof document<"u")return Ed.read(r)||void 0},cacheUserLanguage(o,r){let{lookupCookie:s,cookieMinutes:a,cookieDomain:u,cookieOptions:d}=r;s&&typeof document<"u"&&Ed.create(s,o,a,u,d)}},lv={name:"querystring",lookup(o){let{lookupQuerystring:r}=o,s;if(typeof window<"u"){let{search:a}=window.location;!window.location.search&&window.location.hash?.indexOf("?")>-1&&(a=window.location.hash.substring(window.location.hash.indexOf("?")));const d=a.substring(1).split("&");for(let h=0;h<d.length;h++){const m=d[h].indexOf("=");m>0&&d[h].substring(0,m)===r&&(s=d[h].substring(m+1))}}return s}},uv={name:"hash",lookup(o){let{lookupHash:r,lookupFromHashIndex:s}=o,a;if(typeof window<"u"){const{hash:u}=window.location;if(u&&u.length>2){const d=u.substring(1);if(r){const h=d.split("&");for(let m=0;m<h.length;m++){const g=h[m].indexOf("=");g>0&&h[m].substring(0,g)===r&&(a=h[m].substring(g+1))}}if(a)return a;if(!a&&s>-1){const h=u.match(/\/([a-zA-Z-]*)/g);return Array.isArray(h)?h[typeof s=="number"?s:0]?.replace("/",""):void 0}}}return a}};let nr=null;const Od=()=>{if(nr!==null)return nr;try{if(nr=typeof window<"u"&&window.localStorage!==null,!nr)return!1;const o="i18next.translate.boo";window.localStorage.setItem(o,"foo"),window.localStorage.removeItem(o)}catch{nr=!1}return nr};var cv={name:"localStorage",lookup(o){let{lookupLocalStorage:r}=o;if(r&&Od())return window.localStorage.getItem(r)||void 0},cacheUserLanguage(o,r){let{lookupLocalStorage:s}=r;s&&Od()&&window.localStorage.setItem(s,o)}};let rr=null;const Pd=()=>{if(rr!==null)return rr;try{if(rr=typeof window<"u"&&window.sessionStorage!==null,!rr)return!1;const o="i18next.translate.boo";window.sessionStorage.setItem(o,"foo"),window.sessionStorage.removeItem(o)}catch{rr=!1}return rr};var dv={name:"sessionStorage",lookup(o){let{lookupSessionStorage:r}=o;if(r&&Pd())return window.sessionStorage.getItem(r)||void 0},cacheUserLanguage(o,r){let{lookupSessionStorage:s}=r;s&&Pd()&&window.sessionStorage.setItem(s,o)}},fv={name:"navigator",lookup(o){const r=[];if(typeof navigator<"u"){const{languages:s,userLanguage:a,language:u}=navigator;if(s)for(let d=0;d<s.length;d++)r.push(s[d]);a&&r.push(a),u&&r.push(u)}return r.length>0?r:void 0}},hv={name:"htmlTag",lookup(o){let{htmlTag:r}=o,s;const a=r||(typeof document<"u"?document.documentElement:null);return a&&typeof a.getAttribute=="function"&&(s=a.getAttribute("lang")),s}},pv={name:"path",lookup(o){let{lookupFromPathIndex:r}=o;if(typeof window>"u")return;const s=window.location.pathname.match(/\/([a-zA-Z-]*)/g);return Array.isArray(s)?s[typeof r=="number"?r:0]?.replace("/",""):void 0}},mv={name:"subdomain",lookup(o){let{lookupFromSubdomainIndex:r}=o;const s=typeof r=="number"?r+1:1,a=typeof window<"u"&&window.location?.hostname?.match(/^(\w{2,5})\.(([a-z0-9-]{1,63}\.[a-z]{2,6})|localhost)/i);if(a)return a[s]}};let cf=!1;try{document.cookie,cf=!0}catch{}const df=["querystring","cookie","localStorage","sessionStorage","navigator","htmlTag"];cf||df.splice(1,1);const gv=()=>({order:df,lookupQuerystring:"lng",lookupCookie:"i18next",lookupLocalStorage:"i18nextLng",lookupSessionStorage:"i18nextLng",caches:["localStorage"],excludeCacheFor:["cimode"],convertDetectedLanguage:o=>o});class ff{constructor(r){let s=arguments.length>1&&arguments[1]!==void 0?arguments[1]:{};this.type="languageDetector",this.detectors={},this.init(r,s)}init(){let r=arguments.length>0&&arguments[0]!==void 0?arguments[0]:{languageUtils:{}},s=arguments.length>1&&arguments[1]!==void 0?arguments[1]:{},a=arguments.length>2&&arguments[2]!==void 0?arguments[2]:{};this.services=r,this.options=sv(s,this.options||{},gv()),typeof this.options.convertDetectedLanguage=="string"&&this.options.convertDetectedLanguage.indexOf("15897")>-1&&(this.options.convertDetectedLanguage=u=>u.replace("-","_")),this.options.lookupFromUrlIndex&&(this.options.lookupFromPathIndex=this.options.lookupFromUrlIndex),this.i18nOptions=a,this.addDetector(av),this.addDetector(lv),this.addDetector(cv),this.addDetector(dv),this.addDetector(fv),this.addDetector(hv),this.addDetector(pv),this.addDetector(mv),this.addDetector(uv)}addDetector(r){return this.detectors[r.name]=r,this}detect(){let r=arguments.length>0&&arguments[0]!==void 0?arguments[0]:this.options.order,s=[];return r.forEach(a=>{if(this.detectors[a]){let u=this.detectors[a].lookup(this.options);u&&typeof u=="string"&&(u=[u]),u&&(s=s.concat(u))}}),s=s.filter(a=>a!=null&&!iv(a)).map(a=>this.options.convertDetectedLanguage(a)),this.services&&this.services.languageUtils&&this.services.languageUtils.getBestMatchFromCodes?s:s.length>0?s[0]:null}cacheUserLanguage(r){let s=arguments.length>1&&arguments[1]!==void 0?arguments[1]:this.options.caches;s&&(this.options.excludeCacheFor&&this.options.excludeCacheFor.indexOf(r)>-1||s.forEach(a=>{this.detectors[a]&&this.detectors[a].cacheUserLanguage(r,this.options)}))}}ff.type="languageDetector";const yv="Welcome",vv="Compose New Message",xv="Send feedback to the team",wv="Go to My Messages",Sv="View all conversations",kv="Compose Feedback",Nv="Current Behavior",Cv="Describe the current behavior you've observed...",jv="Expected Behavior",bv="Describe how it should behave instead...",Ev="Send Message",Ov="Cancel",Pv="Signed in as",Lv="Create User",zv="Bulk Import",Tv="Show Users",_v="Groups & Permissions",Iv="User Feedback",Dv="My Feedback",Mv="View and manage your feedback submissions",Rv="{{filtered}} of {{total}} feedback items",Fv="Search feedback...",$v="All Feedback",Av="Open",Uv="Solved",Wv="Filter",Bv="No feedback found",Hv="Feedback Details",Vv="Created",Yv="Close",Gv="Current Behavior",Zv="Expected Behavior",Qv="URL",Kv="Chat History",Xv="No messages yet",Jv="Start the conversation below",qv="Mark as solved",ex="Type your message...",tx="Send",nx="Sending...",rx="Press Enter to send, Shift+Enter for new line",sx="You",ix="Current",ox="Just now",ax="{{hours}} hours ago",lx="1 day ago",ux="{{days}} days ago",cx="Admin Dashboard",dx="Collapse Sidebar",fx="Show Organizations",hx="Users",px="Create New User",mx="No users found",gx="Username",yx="First Name",vx="Last Name",xx="Email",wx="Search",Sx="Show All",kx="Is Active",Nx="Is Superuser",Cx="All Groups",jx="All Organizations",bx="No Organization",Ex="Created",Ox="Last Login",Px="Delete User",Lx="Save Changes",zx="Pure Permissions",Tx="Groups",_x="Organization",Ix="Enter last name",Dx="Enter first name",Mx="Enter username",Rx="Password",Fx="Active",$x="None",Ax="Edit Organization",Ux="Active Until",Wx="Expired",Bx="Clear",Hx="Sort by Expiry",Vx="Expiring Soon First",Yx="Expiring Later First",Gx="Status",Zx="Edit User",Qx="email@example.com",Kx="All",Xx="Soon",Jx="Later",qx="Showing Organizations",e0="Create Organization",t0="Organization Name",n0="Organizations",r0="No organizations found.",s0="Admin Dashboard",i0="Please sign in",o0="Email",a0="Enter email",l0="Enter password",u0="Password",c0="Sign In",d0="Edit Organization",f0="Organization Name",h0="Active Until",p0="Organization is active until this date",m0="Delete Organization",g0="Expires in {{days}} days.",y0="Organization is required",v0="Create User",x0="Permissions are access rights that can be assigned to either users or groups",w0="Pure permissions allow you to give a user a specific access right.",S0="A group brings together users who share access rights in the app.",k0={welcome:yv,composeNewMessage:vv,sendFeedBackToTheTeam:xv,GoToMyMessages:wv,ViewAllConversations:Sv,ComposeFeedBack:kv,BehaviourAsItIs:Nv,DescribeBehaviour:Cv,BehaviourAsItShouldBe:jv,DescribeExpectetBehaviour:bv,SendMessage:Ev,Cancel:Ov,signedInAs:Pv,CreateUser:Lv,BulkCreate:zv,ShowUsers:Tv,GroupsPermissions:_v,UserFeedback:Iv,myFeedback:Dv,viewAndManageFeedback:Mv,feedbackItemsCount:Rv,searchFeedbackPlaceholder:Fv,allFeedback:$v,open:Av,solved:Uv,filter:Wv,noFeedbackFound:Bv,feedbackDetails:Hv,created:Vv,close:Yv,currentBehavior:Gv,expectedBehavior:Zv,url:Qv,chatHistory:Kv,noMessagesYet:Xv,startConversationBelow:Jv,markAsSolved:qv,typeYourMessage:ex,send:tx,sending:nx,enterToSendShiftEnterNewLine:rx,you:sx,current:ix,justNow:ox,hoursAgo:ax,oneDayAgo:lx,daysAgo:ux,AdminDashboard:cx,CollapseSidebar:dx,ShowOrganizations:fx,users:hx,createNewUser:px,noUsersFound:mx,Username:gx,FirstName:yx,LastName:vx,Email:xx,Search:wx,ShowAll:Sx,IsActive:kx,IsSuperuser:Nx,AllGroups:Cx,AllOrganizations:jx,NoOrganization:bx,Created:Ex,LastLogin:Ox,DeleteUser:Px,SaveChanges:Lx,PurePermissions:zx,Groups:Tx,Organization:_x,LastNamePlaceholder:Ix,FirstNamePlaceholder:Dx,UsernamePlaceholder:Mx,Password:Rx,Active:Fx,None:$x,EditOrganization:Ax,ActiveUntilLabel:Ux,Expired:Wx,Clear:Bx,SortByExpiry:Hx,ExpiringSoonFirst:Vx,ExpiringLaterFirst:Yx,Status:Gx,manageUser:Zx,EmailPlaceholder:Qx,AllStatus:Kx,Soon:Xx,Later:Jx,ShowingOrganizations:qx,createNewOrganization:e0,OrganizationName:t0,organizations:n0,noOrganizationsFound:r0,adminDashboard:s0,SignInToContinue:i0,emailAddress:o0,enterYourEmail:a0,enterYourPassword:l0,password:u0,signIn:c0,manageOrganization:d0,OrganizationNamePlaceholder:f0,ActiveUntil:h0,ActiveUntilDescription:p0,DeleteOrganization:m0,ExpiresInDays:g0,OrganizationRequiredWarning:y0,createUser:v0,PurePermissionsDescription1:x0,PurePermissionsDescription2:w0,GroupDescription1:S0,"language.german":"German","language.english":"English"},N0="Willkommen",C0="Neue Nachricht verfassen",j0="Feedback an das Team senden",b0="Zu meinen Nachrichten",E0="Alle Unterhaltungen anzeigen",O0="Feedback verfassen",P0="Aktuelles Verhalten",L0="Beschreiben Sie das aktuelle Verhalten, das Sie beobachtet haben...",z0="Erwartetes Verhalten",T0="Beschreiben Sie, wie es sich stattdessen verhalten sollte...",_0="Nachricht senden",I0="Abbrechen",D0="Angemeldet als",M0="Benutzer erstellen",R0="Massenimport",F0="Benutzer anzeigen",$0="Gruppen & Berechtigungen",A0="Benutzerfeedback",U0="Mein Feedback",W0="Ihre Feedback-Einreichungen anzeigen und verwalten",B0="{{filtered}} von {{total}} Feedback-Elementen",H0="Feedback durchsuchen...",V0="Alle Feedback",Y0="Offen",G0="Gelöst",Z0="Filter",Q0="Kein Feedback gefunden",K0="Feedback-Details",X0="Erstellt",J0="Schließen",q0="Aktuelles Verhalten",e1="Erwartetes Verhalten",t1="URL",n1="Chat-Verlauf",r1="Noch keine Nachrichten",s1="Beginnen Sie die Unterhaltung unten",i1="Als gelöst markieren",o1="Geben Sie Ihre Nachricht ein...",a1="Senden",l1="Wird gesendet...",u1="Eingabetaste zum Senden, Shift+Eingabe für neue Zeile",c1="Du",d1="Aktuell",f1="Gerade eben",h1="vor {{hours}} Stunden",p1="vor 1 Tag",m1="vor {{days}} Tagen",g1="Admin-Dashboard",y1="Seitenleiste einklappen",v1="Organisationen anzeigen",x1="Benutzer",w1="Neuen Benutzer erstellen",S1="Keine Benutzer gefunden",k1="Benutzername",N1="Vorname",C1="Nachname",j1="E-Mail",b1="Suchen",E1="Alle anzeigen",O1="Ist aktiv",P1="Ist Superuser",L1="Alle Gruppen",z1="Alle Organisationen",T1="Keine Organisation",_1="Erstellt",I1="Letzte Anmeldung",D1="Benutzer löschen",M1="Änderungen speichern",R1="Reine Berechtigungen",F1="Gruppen",$1="Organisation",A1="Nachname eingeben",U1="Vorname eingeben",W1="Benutzername eingeben",B1="Passwort",H1="Aktiv",V1="Keine",Y1="Organisation bearbeiten",G1="Aktiv bis",Z1="Abgelaufen",Q1="Löschen",K1="Nach Ablauf sortieren",X1="Bald ablaufend zuerst",J1="Später ablaufend zuerst",q1="Status",ew="User bearbeiten",tw="email@example.com",nw="Alle",rw="Bald",sw="Später",iw="Zeige Organisationen",ow="Organisation erstellen",aw="Name der Organisation",lw="Organisationen",uw="Keine Organisationen gefunden.",cw="Admin Dashboard",dw="Bitte einloggen",fw="Email",hw="Email eingeben",pw="Passwort eingeben",mw="Passwort",gw="Einloggen",yw="Organisation bearbeiten",vw="Name der Organisation",xw="Aktiv bis",ww="Organisation ist bis zu diesem Datum aktiv",Sw="Organisation löschen",kw="Läuft in {{days}} Tagen ab.",Nw="Organisation wird benötigt",Cw="Benutzer erstellen",jw="Berechtigungen sind Zugangsrechte, die entweder Benutzern oder Gruppen zugeteilt werden können",bw="Reine Berechtigungen ermöglichen es, einem Benutzer genau ein bestimmtes Zugangsrecht zu geben.",Ew="Eine Gruppe fasst Benutzer zusammen, die Zugangsrechte in der App teilen.",Ow="Die Gruppe kann selbst ein Zugangsrecht sein, oder wiederum Berechtigungen zusammenfassen.",Pw={welcome:N0,composeNewMessage:C0,sendFeedBackToTheTeam:j0,GoToMyMessages:b0,ViewAllConversations:E0,ComposeFeedBack:O0,BehaviourAsItIs:P0,DescribeBehaviour:L0,BehaviourAsItShouldBe:z0,DescribeExpectetBehaviour:T0,SendMessage:_0,Cancel:I0,signedInAs:D0,CreateUser:M0,BulkCreate:R0,ShowUsers:F0,GroupsPermissions:$0,UserFeedback:A0,myFeedback:U0,viewAndManageFeedback:W0,feedbackItemsCount:B0,searchFeedbackPlaceholder:H0,allFeedback:V0,open:Y0,solved:G0,filter:Z0,noFeedbackFound:Q0,feedbackDetails:K0,created:X0,close:J0,currentBehavior:q0,expectedBehavior:e1,url:t1,chatHistory:n1,noMessagesYet:r1,startConversationBelow:s1,markAsSolved:i1,typeYourMessage:o1,send:a1,sending:l1,enterToSendShiftEnterNewLine:u1,you:c1,current:d1,justNow:f1,hoursAgo:h1,oneDayAgo:p1,daysAgo:m1,AdminDashboard:g1,CollapseSidebar:y1,ShowOrganizations:v1,users:x1,createNewUser:w1,noUsersFound:S1,Username:k1,FirstName:N1,LastName:C1,Email:j1,Search:b1,ShowAll:E1,IsActive:O1,IsSuperuser:P1,AllGroups:L1,AllOrganizations:z1,NoOrganization:T1,Created:_1,LastLogin:I1,DeleteUser:D1,SaveChanges:M1,PurePermissions:R1,Groups:F1,Organization:$1,LastNamePlaceholder:A1,FirstNamePlaceholder:U1,UsernamePlaceholder:W1,Password:B1,Active:H1,None:V1,EditOrganization:Y1,ActiveUntilLabel:G1,Expired:Z1,Clear:Q1,SortByExpiry:K1,ExpiringSoonFirst:X1,ExpiringLaterFirst:J1,Status:q1,manageUser:ew,EmailPlaceholder:tw,AllStatus:nw,Soon:rw,Later:sw,ShowingOrganizations:iw,createNewOrganization:ow,OrganizationName:aw,organizations:lw,noOrganizationsFound:uw,adminDashboard:cw,SignInToContinue:dw,emailAddress:fw,enterYourEmail:hw,enterYourPassword:pw,password:mw,signIn:gw,manageOrganization:yw,OrganizationNamePlaceholder:vw,ActiveUntil:xw,ActiveUntilDescription:ww,DeleteOrganization:Sw,ExpiresInDays:kw,OrganizationRequiredWarning:Nw,createUser:Cw,PurePermissionsDescription1:jw,PurePermissionsDescription2:bw,GroupDescription1:Ew,GroupDescription2:Ow,"language.german":"Deutsch","language.english":"Englisch"},Lw={en:{translation:k0},de:{translation:Pw}};Xe.use(ff).use(Ym).init({resources:Lw,fallbackLng:"en",debug:!1,detection:{order:["localStorage","navigator","htmlTag"],caches:["localStorage"]},interpolation:{escapeValue:!1},defaultNS:"translation",ns:["translation"]});xp.createRoot(document.getElementById("root")).render(f.jsx(Wt.StrictMode,{children:f.jsx(Dy,{})}));
+          `,children:[f.jsx("div",{className:"font-bold text-lg text-text-primary",children:o.header}),f.jsx("div",{className:"mt-1 text-text-primary",children:o.text})]})})}function dd({isAdmin:o}){const[r,s]=C.useState([]),[a,u]=C.useState(null),[d,h]=C.useState(""),[m,g]=C.useState("all"),[y,k]=C.useState(!1),[x,L]=C.useState(0),[I,E]=C.useState(""),[z,T]=C.useState(!1),[A,H]=C.useState(!1),{userData:Y,setToast:Q}=C.useContext(et),{t:U}=Ae();C.useEffect(()=>{async function _(){if(Y.token)try{const D=await Pe.getMsg(Y.token,o);s(D)}catch(D){console.error("Error fetching messages:",D)}}_()},[Y.token,x]);async function J(){if(!(!Y.token||!a||!I.trim())){T(!0);try{const _=await Pe.updateFeedBack(Y.token,{message:I.trim(),id:a.id,is_solved:o?A:null},o,Q);E(""),H(!1),L(x+1),u(_)}catch(_){console.log(_)}finally{T(!1)}}}const ee=_=>{_.key==="Enter"&&!_.shiftKey&&(_.preventDefault(),J())},re=_=>{u(_),k(!0),H(_.is_solved||!1)},fe=()=>{k(!1),u(null),E(""),H(!1)},de=r.filter(_=>{const D=_.behaviour_is.toLowerCase().includes(d.toLowerCase())||_.behaviour_should.toLowerCase().includes(d.toLowerCase())||_.full_url.toLowerCase().includes(d.toLowerCase()),S=m==="all"||m==="solved"&&_.is_solved||m==="unsolved"&&!_.is_solved;return D&&S}),Ce=_=>_?f.jsx(Nm,{className:"w-4 h-4 text-success"}):f.jsx("div",{className:"w-2 h-2 bg-accent-primary rounded-full"}),Se=_=>U(_?"solved":"open"),M=_=>{try{const D=new Date(_),P=Math.floor((new Date().getTime()-D.getTime())/(1e3*60*60));if(P<1)return U("justNow");if(P<24)return U("hoursAgo",{hours:P});if(P<48)return U("oneDayAgo");{const ie=Math.floor(P/24);return U("daysAgo",{days:ie})}}catch{return _}},q=_=>{try{return new Date(_).toLocaleTimeString([],{hour:"2-digit",minute:"2-digit"})}catch{return _}},he=_=>_.behaviour_is.substring(0,50)+(_.behaviour_is.length>50?"...":""),se=_=>`${U("current")}: ${_.behaviour_is.substring(0,100)}${_.behaviour_is.length>100?"...":""}`,le=_=>{if(!_)return[];try{return JSON.parse(_)}catch(D){return console.error("Error parsing chat JSON:",D),[]}},B=_=>{const D=le(_);if(D.length===0)return U("noMessagesYet");const S=D[D.length-1];return`${S.from}: ${S.message.substring(0,50)}${S.message.length>50?"...":""}`};return f.jsxs("div",{className:"max-w-7xl mx-auto p-6",children:[f.jsx("div",{className:"bg-surface-primary rounded-lg border border-border-default p-6 mb-6",children:f.jsxs("div",{className:"flex items-center justify-between",children:[f.jsxs("div",{children:[f.jsx("h1",{className:"text-2xl font-semibold text-text-primary mb-2",children:U("myFeedback")}),f.jsx("p",{className:"text-text-secondary",children:U("viewAndManageFeedback")})]}),f.jsx("div",{className:"flex items-center gap-2",children:f.jsx("span",{className:"text-sm text-text-secondary",children:U("feedbackItemsCount",{filtered:de.length,total:r.length})})})]})}),f.jsxs("div",{className:"bg-surface-primary rounded-lg border border-border-default",children:[f.jsx("div",{className:"p-4 border-b border-border-default",children:f.jsxs("div",{className:"flex flex-col sm:flex-row gap-3",children:[f.jsxs("div",{className:"relative flex-1",children:[f.jsx(Lm,{className:"absolute left-3 top-1/2 transform -translate-y-1/2 w-4 h-4 text-text-muted"}),f.jsx("input",{type:"text",value:d,onChange:_=>h(_.target.value),className:"w-full pl-10 pr-4 py-2 rounded-lg border border-border-default bg-surface-secondary text-text-primary focus:outline-none focus:ring-2 focus:ring-brand focus:border-transparent",placeholder:U("searchFeedbackPlaceholder")})]}),f.jsxs("div",{className:"flex gap-2",children:[f.jsxs("select",{value:m,onChange:_=>g(_.target.value),className:"px-3 py-2 rounded-lg border border-border-default bg-surface-secondary text-text-primary focus:outline-none focus:ring-2 focus:ring-brand focus:border-transparent",children:[f.jsx("option",{value:"all",children:U("allFeedback")}),f.jsx("option",{value:"unsolved",children:U("open")}),f.jsx("option",{value:"solved",children:U("solved")})]}),f.jsxs(Fe,{kind:"secondary",className:"flex items-center gap-2",children:[f.jsx(jm,{className:"w-4 h-4"}),U("filter")]})]})]})}),f.jsx("div",{className:"divide-y divide-border-default",children:de.length>0?de.map(_=>f.jsx("div",{onClick:()=>re(_),className:`p-4 cursor-pointer hover:bg-surface-secondary transition-colors border-l-4 ${_.is_solved?"border-l-success":"border-l-warning"}`,children:f.jsxs("div",{className:"flex items-start justify-between gap-3",children:[f.jsxs("div",{className:"flex items-start gap-3 flex-1 min-w-0",children:[f.jsxs("div",{className:"flex items-center gap-2 mt-1",children:[Ce(_.is_solved),f.jsx(sr,{className:"w-4 h-4 text-accent-primary"})]}),f.jsxs("div",{className:"flex-1 min-w-0",children:[f.jsx("h3",{className:"text-sm font-medium truncate text-text-primary",children:he(_)}),f.jsx("p",{className:"text-sm text-text-muted mt-1 line-clamp-2",children:se(_)}),_.chat&&f.jsx("p",{className:"text-xs text-text-muted mt-1 italic",children:B(_.chat)})]})]}),f.jsx("div",{className:"text-xs text-text-muted whitespace-nowrap",children:M(_.created_at)})]})},_.id)):f.jsxs("div",{className:"p-8 text-center",children:[f.jsx(sr,{className:"w-12 h-12 text-text-muted mx-auto mb-3"}),f.jsx("p",{className:"text-text-secondary",children:U("noFeedbackFound")})]})})]}),y&&a&&f.jsx("div",{className:"fixed inset-0 z-50 flex items-center justify-center p-4 bg-black bg-opacity-50",children:f.jsxs("div",{className:"bg-surface-primary rounded-lg border border-border-default w-full max-w-4xl max-h-[90vh] overflow-hidden flex flex-col",children:[f.jsx("div",{className:"p-6 border-b border-border-default",children:f.jsxs("div",{className:"flex items-center justify-between",children:[f.jsxs("div",{className:"flex items-center gap-3",children:[f.jsxs("div",{className:"flex items-center gap-2",children:[Ce(a.is_solved),f.jsx(sr,{className:"w-5 h-5 text-accent-primary"})]}),f.jsxs("div",{children:[f.jsx("h2",{className:"text-xl font-semibold text-text-primary",children:U("feedbackDetails")}),f.jsxs("p",{className:"text-sm text-text-secondary",children:[Se(a.is_solved)," •"," ",U("created")," ",M(a.created_at)]})]})]}),f.jsx("div",{className:"flex items-center gap-2",children:f.jsxs(Fe,{kind:"secondary",className:"flex items-center gap-2",onClick:fe,children:[f.jsx(Fa,{className:"w-4 h-4"}),U("close")]})})]})}),f.jsxs("div",{className:"flex-1 overflow-hidden flex",children:[f.jsx("div",{className:"w-1/2 p-6 border-r border-border-default overflow-y-auto",children:f.jsxs("div",{className:"space-y-6",children:[f.jsxs("div",{children:[f.jsxs("h3",{className:"text-sm font-medium text-text-primary mb-3 flex items-center gap-2",children:[f.jsx("div",{className:"w-1 h-4 bg-accent-primary rounded"}),U("currentBehavior")]}),f.jsx("div",{className:"bg-surface-secondary p-4 rounded-lg",children:f.jsx("p",{className:"text-sm text-text-secondary leading-relaxed",children:a.behaviour_is})})]}),f.jsxs("div",{children:[f.jsxs("h3",{className:"text-sm font-medium text-text-primary mb-3 flex items-center gap-2",children:[f.jsx("div",{className:"w-1 h-4 bg-success rounded"}),U("expectedBehavior")]}),f.jsx("div",{className:"bg-surface-secondary p-4 rounded-lg",children:f.jsx("p",{className:"text-sm text-text-secondary leading-relaxed",children:a.behaviour_should})})]}),f.jsxs("div",{children:[f.jsxs("h3",{className:"text-sm font-medium text-text-primary mb-3 flex items-center gap-2",children:[f.jsx("div",{className:"w-1 h-4 bg-warning rounded"}),U("url")]}),f.jsx("div",{className:"bg-surface-secondary p-4 rounded-lg",children:f.jsx("p",{className:"text-sm text-text-secondary break-all font-mono",children:a.full_url})})]})]})}),f.jsxs("div",{className:"w-1/2 flex flex-col",children:[f.jsx("div",{className:"p-4 border-b border-border-default",children:f.jsxs("h3",{className:"text-sm font-medium text-text-primary flex items-center gap-2",children:[f.jsx(Pm,{className:"w-4 h-4"}),U("chatHistory")]})}),f.jsx("div",{className:"flex-1 overflow-y-auto p-4 space-y-4",children:le(a.chat).length>0?le(a.chat).map((_,D)=>{const S=_.from.split(" ")[0],P=S===Y.email;return f.jsx("div",{className:`flex gap-3 ${P?"justify-end":"justify-start"}`,children:f.jsxs("div",{className:`max-w-[80%] ${P?"order-2":"order-1"}`,children:[f.jsx("div",{className:`p-3 rounded-lg shadow-sm ${P?"bg-brand text-text-primary rounded-br-sm":"bg-surface-secondary text-text-secondary rounded-bl-sm"}`,children:f.jsx("p",{className:"text-sm leading-relaxed whitespace-pre-wrap",children:_.message})}),f.jsxs("div",{className:`flex items-center gap-1 mt-1 text-xs text-text-muted ${P?"justify-end":"justify-start"}`,children:[f.jsxs("div",{className:"flex items-center gap-1",children:[f.jsx(Yd,{className:"w-3 h-3"}),f.jsx("span",{className:"font-medium",children:P?U("you"):S})]}),f.jsxs("div",{className:"flex items-center gap-1 ml-2",children:[f.jsx(Cm,{className:"w-3 h-3"}),f.jsx("span",{children:q(_.timestamp)})]})]})]})},D)}):f.jsxs("div",{className:"flex flex-col items-center justify-center h-full text-center",children:[f.jsx(sr,{className:"w-12 h-12 text-text-muted mb-3"}),f.jsx("p",{className:"text-text-secondary",children:U("noMessagesYet")}),f.jsx("p",{className:"text-text-muted text-sm",children:U("startConversationBelow")})]})}),f.jsxs("div",{className:"p-4 border-t border-border-default",children:[o&&!a.is_solved&&f.jsx("div",{className:"mb-3",children:f.jsxs("label",{className:"flex items-center gap-2 text-sm text-text-primary",children:[f.jsx("input",{type:"checkbox",checked:A,onChange:_=>H(_.target.checked),className:"w-4 h-4 text-brand bg-surface-secondary border-border-default rounded focus:ring-brand focus:ring-2"}),f.jsx("span",{children:U("markAsSolved")})]})}),f.jsxs("div",{className:"flex gap-2",children:[f.jsx("textarea",{value:I,onChange:_=>E(_.target.value),onKeyPress:ee,placeholder:U("typeYourMessage"),className:"flex-1 px-3 py-2 rounded-lg border border-border-default bg-surface-secondary text-text-primary focus:outline-none focus:ring-2 focus:ring-brand focus:border-transparent resize-none",rows:2,disabled:z}),f.jsxs(Fe,{kind:"primary",onClick:J,disabled:!I.trim()||z,className:"flex items-center gap-2 px-4 py-2 self-end",children:[f.jsx(Vd,{className:"w-4 h-4"}),U(z?"sending":"send")]})]}),f.jsx("p",{className:"text-xs text-text-muted mt-2",children:U("enterToSendShiftEnterNewLine")})]})]})]})]})})]})}function af({headText:o,organizationId:r,organizationName:s,setOrganizationName:a,email:u,setEmail:d,activeUntil:h,setActiveUntil:m,handleSubmit:g}){const{t:y}=Ae(),{userData:k,setToast:x}=C.useContext(et);function L(){Pe.deleteOrganization(k.token,r,x)}function I(A){if(!A)return"";const H=new Date(A);return isNaN(H.getTime())?"":H.toISOString().split("T")[0]}const E=new Date().toISOString().split("T")[0],z=h&&new Date(h)>new Date,T=h?Math.ceil((new Date(h).getTime()-new Date().getTime())/(1e3*60*60*24)):0;return f.jsxs("div",{className:"bg-surface-primary rounded-lg border border-border-default p-6",children:[f.jsx("h1",{className:"text-2xl font-semibold text-text-primary mb-6",children:o}),f.jsxs("div",{className:"space-y-6",children:[f.jsxs("div",{className:"grid grid-cols-1 md:grid-cols-2 lg:grid-cols-3 gap-4",children:[f.jsxs("div",{children:[f.jsxs("label",{className:"block text-sm font-medium text-text-secondary mb-2",children:[y("OrganizationName")," ",f.jsx("span",{className:"text-red-500",children:"*"})]}),f.jsx("input",{type:"text",value:s,onChange:A=>a(A.target.value),className:"w-full rounded-lg border border-border-default px-4 py-2 text-text-primary bg-surface-secondary focus:outline-none focus:ring-2 focus:ring-blue-600 focus:border-transparent",placeholder:y("OrganizationNamePlaceholder"),required:!0})]}),f.jsxs("div",{children:[f.jsxs("label",{className:"block text-sm font-medium text-text-secondary mb-2",children:[y("Email")," ",f.jsx("span",{className:"text-red-500",children:"*"})]}),f.jsx("input",{type:"email",value:u,onChange:A=>d(A.target.value),className:"w-full rounded-lg border border-border-default px-4 py-2 text-text-primary bg-surface-secondary focus:outline-none focus:ring-2 focus:ring-blue-600 focus:border-transparent",placeholder:y("OrganizationEmailPlaceholder"),required:!0})]}),f.jsxs("div",{children:[f.jsxs("label",{className:"block text-sm font-medium text-text-secondary mb-2",children:[y("ActiveUntil")," ",f.jsx("span",{className:"text-red-500",children:"*"})]}),f.jsx("input",{type:"date",value:I(h),onChange:A=>m(A.target.value),min:E,className:"w-full rounded-lg border border-border-default px-4 py-2 text-text-primary bg-surface-secondary focus:outline-none focus:ring-2 focus:ring-blue-600 focus:border-transparent",required:!0}),f.jsx("p",{className:"mt-1 text-xs text-text-muted",children:y("ActiveUntilDescription")})]})]}),r&&h&&f.jsxs("div",{className:"p-4 bg-surface-secondary rounded-lg border border-border-default",children:[f.jsx("h3",{className:"text-sm font-medium text-text-secondary mb-3",children:y("Status")}),f.jsxs("div",{className:"flex flex-col sm:flex-row sm:items-center gap-3",children:[f.jsxs("div",{className:`flex items-center gap-2 px-3 py-1.5 rounded-full w-fit ${z?"bg-green-100 text-green-700":"bg-red-100 text-red-700"}`,children:[f.jsx("div",{className:`w-2 h-2 rounded-full ${z?"bg-green-500":"bg-red-500"}`}),f.jsx("span",{className:"text-sm font-medium",children:y(z?"Active":"Expired")})]}),z&&T>0&&f.jsx("div",{className:`px-3 py-1.5 rounded-full text-sm font-medium w-fit ${T<=30?"bg-orange-100 text-orange-700":"bg-blue-100 text-blue-700"}`,children:T<=30?y("ExpiresInDaysWarning",{days:T}):y("ExpiresInDays",{days:T})})]})]}),k.is_superuser&&f.jsxs("div",{className:"flex gap-4 pt-4",children:[f.jsx(Fe,{onClick:g,kind:"primary",children:y(o==="Create Organization"?"CreateOrganization":"SaveChanges")}),o!=="Create Organization"&&f.jsx(Fe,{onClick:L,kind:"danger",children:y("DeleteOrganization")})]}),!k.is_superuser&&f.jsx("div",{className:"p-4 bg-yellow-50 border border-yellow-200 rounded-lg",children:f.jsx("p",{className:"text-sm text-yellow-800",children:y("OnlySuperusersCanModifyOrganizations")})})]})]})}function Py(){const{userData:o,setToast:r}=C.useContext(et),{t:s}=Ae(),{id:a}=Ad(),u=a??"",[d,h]=C.useState(""),[m,g]=C.useState(""),[y,k]=C.useState("");C.useEffect(()=>{async function L(){if(u==="")return;const I=await Pe.oneOrganization(o.token,u);I&&(h(I.organization_name),g(I.email),k(I.active_until))}L()},[o,u]);function x(){const L={organization_name:d,email:m,active_until:y};Pe.editOneOrganization(o.token,u,L,r)}return f.jsx(af,{headText:s("manageOrganization"),organizationId:u,organizationName:d,setOrganizationName:h,email:m,setEmail:g,activeUntil:y,setActiveUntil:k,handleSubmit:x})}function Ly(){const{userData:o,setToast:r}=C.useContext(et),{t:s}=Ae(),[a,u]=C.useState(""),[d,h]=C.useState(""),[m,g]=C.useState("2999-01-01");function y(){const k={organization_name:a,email:d,active_until:m};Pe.createOrganization(o.token,k,r)}return f.jsx(af,{headText:s("createOrganization"),organizationId:"",organizationName:a,setOrganizationName:u,email:d,setEmail:h,activeUntil:m,setActiveUntil:g,handleSubmit:y})}function zy({setShowData:o,allOrganizations:r}){const{t:s}=Ae(),[a,u]=C.useState(""),[d,h]=C.useState(""),[m,g]=C.useState("all"),[y,k]=C.useState("");function x(){try{let z=r;if(a&&(z=z.filter(T=>T.organization_name.toLowerCase().includes(a.toLowerCase()))),d&&(z=z.filter(T=>T.email.toLowerCase().includes(d.toLowerCase()))),m!=="all"){const T=new Date;z=z.filter(A=>{const H=new Date(A.active_until)>T;return m==="active"?H:!H})}y&&(z=z.sort((T,A)=>{const H=new Date(T.active_until).getTime(),Y=new Date(A.active_until).getTime();return y==="asc"?H-Y:Y-H})),o(z)}catch(z){console.error("Error filtering organizations:",z)}}function L(){o(r),u(""),h(""),g("all"),k("")}C.useEffect(()=>{x()},[m,y]),C.useEffect(()=>{r.length>0&&x()},[r]);const E=(()=>{const z=new Date;z.setDate(z.getDate()+30);const T=new Date;return r.filter(A=>{const H=new Date(A.active_until);return H>T&&H<=z}).length})();return f.jsxs("div",{className:"p-6 space-y-4 bg-surface-secondary border-b border-border-default",children:[E>0&&f.jsxs("div",{className:"bg-orange-50 border-l-4 border-orange-500 rounded-lg p-4 flex items-start gap-3",children:[f.jsx("svg",{className:"w-5 h-5 text-orange-600 mt-0.5 flex-shrink-0",fill:"currentColor",viewBox:"0 0 20 20",children:f.jsx("path",{fillRule:"evenodd",d:"M8.257 3.099c.765-1.36 2.722-1.36 3.486 0l5.58 9.92c.75 1.334-.213 2.98-1.742 2.98H4.42c-1.53 0-2.493-1.646-1.743-2.98l5.58-9.92zM11 13a1 1 0 11-2 0 1 1 0 012 0zm-1-8a1 1 0 00-1 1v3a1 1 0 002 0V6a1 1 0 00-1-1z",clipRule:"evenodd"})}),f.jsx("div",{className:"flex-1",children:f.jsxs("p",{className:"text-sm font-medium text-orange-800",children:[E," ",E===1?s("OrganizationExpiringWithin30Days"):s("OrganizationsExpiringWithin30Days",{count:E})]})})]}),f.jsxs("div",{className:"grid grid-cols-1 sm:grid-cols-2 lg:grid-cols-4 gap-3",children:[f.jsx("input",{type:"text",placeholder:s("OrganizationName"),value:a,onChange:z=>u(z.target.value),onKeyPress:z=>z.key==="Enter"&&x(),className:"px-4 py-2 rounded-lg bg-surface-primary text-text-primary border-2 border-border-default placeholder-text-muted focus:outline-none focus:ring-2 focus:ring-blue-600 focus:border-transparent"}),f.jsx("input",{type:"email",placeholder:s("Email"),value:d,onChange:z=>h(z.target.value),onKeyPress:z=>z.key==="Enter"&&x(),className:"px-4 py-2 rounded-lg bg-surface-primary text-text-primary border-2 border-border-default placeholder-text-muted focus:outline-none focus:ring-2 focus:ring-blue-600 focus:border-transparent"}),f.jsxs("select",{value:m,onChange:z=>g(z.target.value),className:"px-4 py-2 rounded-lg bg-surface-primary text-text-primary border-2 border-border-default focus:outline-none focus:ring-2 focus:ring-blue-600 focus:border-transparent",children:[f.jsx("option",{value:"all",children:s("AllStatus")}),f.jsx("option",{value:"active",children:s("Active")}),f.jsx("option",{value:"expired",children:s("Expired")})]}),f.jsxs("div",{className:"flex gap-2",children:[f.jsx(Fe,{kind:"primary",onClick:x,className:"flex-1 bg-blue-600 hover:bg-blue-700 text-white",children:s("Search")}),f.jsx(Fe,{kind:"secondary",onClick:L,className:"flex-1 bg-surface-secondary hover:bg-surface-tertiary text-text-primary border-2 border-border-default",children:s("Clear")})]})]}),f.jsx("div",{className:"flex flex-col sm:flex-row sm:items-center sm:justify-between gap-3",children:f.jsxs("div",{className:"flex items-center gap-2",children:[f.jsxs("label",{className:"text-sm font-medium text-text-primary whitespace-nowrap",children:[s("SortByExpiry"),":"]}),f.jsxs("div",{className:"flex gap-2",children:[f.jsx("button",{onClick:()=>k(y==="asc"?"":"asc"),className:`px-3 py-1.5 rounded-lg text-sm font-medium transition-all duration-200 ${y==="asc"?"bg-blue-600 text-white shadow-sm":"bg-surface-primary hover:bg-surface-tertiary text-text-primary border border-border-default"}`,title:s("ExpiringSoonFirst"),children:f.jsxs("span",{className:"flex items-center gap-1",children:[f.jsx("svg",{className:"w-4 h-4",fill:"none",stroke:"currentColor",viewBox:"0 0 24 24",children:f.jsx("path",{strokeLinecap:"round",strokeLinejoin:"round",strokeWidth:2,d:"M5 10l7-7m0 0l7 7m-7-7v18"})}),s("Soon")]})}),f.jsx("button",{onClick:()=>k(y==="desc"?"":"desc"),className:`px-3 py-1.5 rounded-lg text-sm font-medium transition-all duration-200 ${y==="desc"?"bg-blue-600 text-white shadow-sm":"bg-surface-primary hover:bg-surface-tertiary text-text-primary border border-border-default"}`,title:s("ExpiringLaterFirst"),children:f.jsxs("span",{className:"flex items-center gap-1",children:[f.jsx("svg",{className:"w-4 h-4",fill:"none",stroke:"currentColor",viewBox:"0 0 24 24",children:f.jsx("path",{strokeLinecap:"round",strokeLinejoin:"round",strokeWidth:2,d:"M19 14l-7 7m0 0l-7-7m7 7V3"})}),s("Later")]})})]})]})})]})}function Ty({organization:o}){const{t:r}=Ae(),s=_t(),a=new Date(o.active_until)>new Date,u=Math.ceil((new Date(o.active_until).getTime()-new Date().getTime())/(1e3*60*60*24)),d=h=>new Date(h).toLocaleDateString("en-US",{year:"numeric",month:"short",day:"numeric"});return f.jsx("div",{className:"bg-surface-secondary border border-border-default rounded-lg p-4 mb-3 hover:shadow-md transition-all duration-200 cursor-pointer",onClick:()=>s(`/manageorganization/${o.id}`),children:f.jsxs("div",{className:"flex flex-col lg:flex-row lg:items-start lg:justify-between gap-4",children:[f.jsxs("div",{className:"flex-1 min-w-0",children:[f.jsxs("div",{className:"flex items-start gap-3 mb-3",children:[f.jsx(hg,{className:"text-blue-600 w-5 h-5 mt-0.5 flex-shrink-0"}),f.jsxs("div",{className:"flex-1 min-w-0",children:[f.jsx("h3",{className:"text-lg font-semibold text-text-primary truncate mb-1",title:o.organization_name,children:o.organization_name}),f.jsxs("div",{className:"flex items-center gap-2 flex-wrap",children:[f.jsxs("div",{className:`flex items-center gap-1.5 px-2 py-1 rounded-full text-xs font-medium ${a?"bg-green-100 text-green-700":"bg-red-100 text-red-700"}`,children:[f.jsx("div",{className:`w-1.5 h-1.5 rounded-full ${a?"bg-green-500":"bg-red-500"}`}),r(a?"Active":"Expired")]}),a&&u<=30&&f.jsx("span",{className:"px-2 py-1 text-xs font-medium text-orange-700 bg-orange-100 rounded-full",children:r("ExpiresInDaysWarning",{days:u})})]})]})]}),f.jsxs("div",{className:"grid grid-cols-1 md:grid-cols-2 gap-3 pl-8",children:[f.jsxs("div",{className:"flex items-center gap-2 text-text-secondary min-w-0",children:[f.jsx(gg,{className:"w-4 h-4 flex-shrink-0"}),f.jsx("span",{className:"text-sm truncate",title:o.email,children:o.email})]}),f.jsxs("div",{className:"flex items-center gap-2 text-text-secondary",children:[f.jsx(pg,{className:"w-4 h-4 flex-shrink-0"}),f.jsxs("div",{className:"text-sm",children:[f.jsxs("span",{className:"text-text-muted",children:[r("ActiveUntilLabel"),":"," "]}),f.jsx("span",{className:"font-medium text-text-primary whitespace-nowrap",children:d(o.active_until)})]})]})]})]}),f.jsx("button",{onClick:h=>{h.stopPropagation(),s(`/manageorganization/${o.id}`)},className:"self-start p-2 text-text-secondary hover:text-blue-600 hover:bg-blue-50 rounded-lg transition-colors duration-200 flex-shrink-0","aria-label":r("EditOrganization"),children:f.jsx(mg,{className:"w-5 h-5"})})]})})}function _y({showData:o}){const[r,s]=C.useState([]),a=C.useRef(null),u=20;return C.useEffect(()=>{s(o.slice(0,u))},[o]),C.useEffect(()=>{const d=new IntersectionObserver(h=>{if(h[0].isIntersecting&&r.length<o.length){const g=o.slice(r.length,r.length+u);s(y=>[...y,...g])}},{root:null,rootMargin:"20px",threshold:.1});return a.current&&d.observe(a.current),()=>{a.current&&d.unobserve(a.current)}},[r,o]),f.jsxs("div",{className:"w-full space-y-0",children:[r.map((d,h)=>f.jsx(Ty,{organization:d},d.id||`${d.organization_name}-${h}`)),r.length<o.length&&f.jsx("div",{ref:a,className:"w-full p-4 flex justify-center",children:f.jsx("div",{className:"animate-spin h-8 w-8 border-4 border-blue-500 rounded-full border-t-transparent"})})]})}function Iy(){const{userData:o}=C.useContext(et),{t:r}=Ae(),s=_t(),[a,u]=C.useState([]),[d,h]=C.useState([]);return C.useEffect(()=>{async function m(){if(!(!o||!o.token))try{const g=await Pe.allOrganizations(o.token);if(!g)return;u(g),h(g)}catch(g){g instanceof Error?console.error("Error fetching organizations:",g):console.log(g)}}m()},[o]),f.jsx("div",{className:"space-y-6",children:f.jsxs("div",{className:"bg-surface-primary rounded-lg border border-border-default shadow-sm",children:[f.jsxs("div",{className:"flex flex-col sm:flex-row sm:items-center sm:justify-between gap-4 p-6 border-b border-border-default",children:[f.jsx("h1",{className:"text-2xl font-semibold text-text-primary",children:r("organizations")}),f.jsxs("button",{onClick:()=>s("/createorganization"),className:"inline-flex items-center justify-center gap-2 px-4 py-2 bg-blue-600 hover:bg-blue-700 text-white font-medium rounded-lg transition-colors duration-200 shadow-sm hover:shadow-md whitespace-nowrap",children:[f.jsx("svg",{className:"w-5 h-5",fill:"none",stroke:"currentColor",viewBox:"0 0 24 24",children:f.jsx("path",{strokeLinecap:"round",strokeLinejoin:"round",strokeWidth:2,d:"M12 4v16m8-8H4"})}),r("createNewOrganization")]})]}),f.jsx(zy,{setShowData:h,allOrganizations:a}),f.jsx("div",{className:"p-6 pt-0",children:d.length>0?f.jsx(_y,{showData:d}):f.jsx("div",{className:"text-center py-12 text-text-secondary",children:r("noOrganizationsFound")})})]})})}const et=C.createContext({userData:{email:null,token:null,is_superuser:!1,is_organizationadmin:!1},toast:{show:!1,success:!0,text:"",header:""},setUserData:()=>{},setToast:()=>{}});function Dy(){const[o,r]=C.useState(My()),[s,a]=C.useState({show:!1,success:!0,header:"",text:""}),[u,d]=C.useState(!0),[h,m]=C.useState(!1);C.useEffect(()=>{Pe.onSessionRefresh=(y,g)=>r(k=>({...k,token:y,refresh_token:g}))},[]);return f.jsx(f.Fragment,{children:f.jsx(_m,{children:f.jsx(mm,{children:f.jsx(et.Provider,{value:{userData:o,setUserData:r,toast:s,setToast:a},children:f.jsxs("div",{className:"bg-surface-secondary min-h-screen",children:[f.jsx(Oy,{}),o.email?f.jsxs("div",{className:"flex h-screen overflow-hidden",children:[f.jsx(ng,{isExpanded:u,setIsExpanded:d,isMobileOpen:h,setIsMobileOpen:m}),f.jsxs("div",{className:"flex-1 flex flex-col",children:[f.jsx(eg,{isSidebarExpanded:u,setIsMobileOpen:m}),f.jsx("main",{className:"flex overflow-y-auto pt-16 justify-center",children:f.jsx("div",{className:u?"flex lg:pl-[288px]":"flex md:pl-20 lg:pl-14",children:f.jsxs(dm,{children:[f.jsx(zt,{path:"/",element:f.jsx(sg,{})}),f.jsx(zt,{path:"/createuser",element:f.jsx(yg,{})}),f.jsx(zt,{path:"/showusers",element:f.jsx(by,{})}),f.jsx(zt,{path:"/manageuser/:id",element:f.jsx(Ey,{})}),f.jsx(zt,{path:"/createorganization",element:f.jsx(Ly,{})}),f.jsx(zt,{path:"/showorganizations",element:f.jsx(Iy,{})}),f.jsx(zt,{path:"/manageorganization/:id",element:f.jsx(Py,{})}),f.jsx(zt,{path:"/mymessages",element:f.jsx(dd,{isAdmin:!1})}),f.jsx(zt,{path:"/adminmessages",element:f.jsx(dd,{isAdmin:!0})})]})})})]})]}):f.jsx(rg,{})]})})})})})}function My(){const o=sessionStorage.getItem("userData");return o?JSON.parse(o):{email:null,token:null,is_superuser:!1,is_organizationadmin:!1}}const ae=o=>typeof o=="string",Zr=()=>{let o,r;const s=new Promise((a,u)=>{o=a,r=u});return s.resolve=o,s.reject=r,s},fd=o=>o==null?"":""+o,Ry=(o,r,s)=>{o.forEach(a=>{r[a]&&(s[a]=r[a])})},Fy=/###/g,hd=o=>o&&o.indexOf("###")>-1?o.replace(Fy,"."):o,pd=o=>!o||ae(o),Qr=(o,r,s)=>{const a=ae(r)?r.split("."):r;let u=0;for(;u<a.length-1;){if(pd(o))return{};const d=hd(a[u]);!o[d]&&s&&(o[d]=new s),Object.prototype.hasOwnProperty.call(o,d)?o=o[d]:o={},++u}return pd(o)?{}:{obj:o,k:hd(a[u])}},md=(o,r,s)=>{const{obj:a,k:u}=Qr(o,r,Object);if(a!==void 0||r.length===1){a[u]=s;return}let d=r[r.length-1],h=r.slice(0,r.length-1),m=Qr(o,h,Object);for(;m.obj===void 0&&h.length;)d=`${h[h.length-1]}.${d}`,h=h.slice(0,h.length-1),m=Qr(o,h,Object),m?.obj&&typeof m.obj[`${m.k}.${d}`]<"u"&&(m.obj=void 0);m.obj[`${m.k}.${d}`]=s},$y=(o,r,s,a)=>{const{obj:u,k:d}=Qr(o,r,Object);u[d]=u[d]||[],u[d].push(s)},vi=(o,r)=>{const{obj:s,k:a}=Qr(o,r);if(s&&Object.prototype.hasOwnProperty.call(s,a))return s[a]},Ay=(o,r,s)=>{const a=vi(o,s);return a!==void 0?a:vi(r,s)},lf=(o,r,s)=>{for(const a in r)a!=="__proto__"&&a!=="constructor"&&(a in o?ae(o[a])||o[a]instanceof String||ae(r[a])||r[a]instanceof String?s&&(o[a]=r[a]):lf(o[a],r[a],s):o[a]=r[a]);return o},tr=o=>o.replace(/[\-\[\]\/\{\}\(\)\*\+\?\.\\\^\$\|]/g,"\\$&");var Uy={"&":"&amp;","<":"&lt;",">":"&gt;",'"':"&quot;","'":"&#39;","/":"&#x2F;"};const Wy=o=>ae(o)?o.replace(/[&<>"'\/]/g,r=>Uy[r]):o;class By{constructor(r){this.capacity=r,this.regExpMap=new Map,this.regExpQueue=[]}getRegExp(r){const s=this.regExpMap.get(r);if(s!==void 0)return s;const a=new RegExp(r);return this.regExpQueue.length===this.capacity&&this.regExpMap.delete(this.regExpQueue.shift()),this.regExpMap.set(r,a),this.regExpQueue.push(r),a}}const Hy=[" ",",","?","!",";"],Vy=new By(20),Yy=(o,r,s)=>{r=r||"",s=s||"";const a=Hy.filter(h=>r.indexOf(h)<0&&s.indexOf(h)<0);if(a.length===0)return!0;const u=Vy.getRegExp(`(${a.map(h=>h==="?"?"\\?":h).join("|")})`);let d=!u.test(o);if(!d){const h=o.indexOf(s);h>0&&!u.test(o.substring(0,h))&&(d=!0)}return d},Ia=(o,r,s=".")=>{if(!o)return;if(o[r])return Object.prototype.hasOwnProperty.call(o,r)?o[r]:void 0;const a=r.split(s);let u=o;for(let d=0;d<a.length;){if(!u||typeof u!="object")return;let h,m="";for(let g=d;g<a.length;++g)if(g!==d&&(m+=s),m+=a[g],h=u[m],h!==void 0){if(["string","number","boolean"].indexOf(typeof h)>-1&&g<a.length-1)continue;d+=g-d+1;break}u=h}return u},qr=o=>o?.replace("_","-"),Gy={type:"logger",log(o){this.output("log",o)},warn(o){this.output("warn",o)},error(o){this.output("error",o)},output(o,r){console?.[o]?.apply?.(console,r)}};class xi{constructor(r,s={}){this.init(r,s)}init(r,s={}){this.prefix=s.prefix||"i18next:",this.logger=r||Gy,this.options=s,this.debug=s.debug}log(...r){return this.forward(r,"log","",!0)}warn(...r){return this.forward(r,"warn","",!0)}error(...r){return this.forward(r,"error","")}deprecate(...r){return this.forward(r,"warn","WARNING DEPRECATED: ",!0)}forward(r,s,a,u){return u&&!this.debug?null:(ae(r[0])&&(r[0]=`${a}${this.prefix} ${r[0]}`),this.logger[s](r))}create(r){return new xi(this.logger,{prefix:`${this.prefix}:${r}:`,...this.options})}clone(r){return r=r||this.options,r.prefix=r.prefix||this.prefix,new xi(this.logger,r)}}var Tt=new xi;class ji{constructor(){this.observers={}}on(r,s){return r.split(" ").forEach(a=>{this.observers[a]||(this.observers[a]=new Map);const u=this.observers[a].get(s)||0;this.observers[a].set(s,u+1)}),this}off(r,s){if(this.observers[r]){if(!s){delete this.observers[r];return}this.observers[r].delete(s)}}emit(r,...s){this.observers[r]&&Array.from(this.observers[r].entries()).forEach(([u,d])=>{for(let h=0;h<d;h++)u(...s)}),this.observers["*"]&&Array.from(this.observers["*"].entries()).forEach(([u,d])=>{for(let h=0;h<d;h++)u.apply(u,[r,...s])})}}class gd extends ji{constructor(r,s={ns:["translation"],defaultNS:"translation"}){super(),this.data=r||{},this.options=s,this.options.keySeparator===void 0&&(this.options.keySeparator="."),this.options.ignoreJSONStructure===void 0&&(this.options.ignoreJSONStructure=!0)}addNamespaces(r){this.options.ns.indexOf(r)<0&&this.options.ns.push(r)}removeNamespaces(r){const s=this.options.ns.indexOf(r);s>-1&&this.options.ns.splice(s,1)}getResource(r,s,a,u={}){const d=u.keySeparator!==void 0?u.keySeparator:this.options.keySeparator,h=u.ignoreJSONStructure!==void 0?u.ignoreJSONStructure:this.options.ignoreJSONStructure;let m;r.indexOf(".")>-1?m=r.split("."):(m=[r,s],a&&(Array.isArray(a)?m.push(...a):ae(a)&&d?m.push(...a.split(d)):m.push(a)));const g=vi(this.data,m);return!g&&!s&&!a&&r.indexOf(".")>-1&&(r=m[0],s=m[1],a=m.slice(2).join(".")),g||!h||!ae(a)?g:Ia(this.data?.[r]?.[s],a,d)}addResource(r,s,a,u,d={silent:!1}){const h=d.keySeparator!==void 0?d.keySeparator:this.options.keySeparator;let m=[r,s];a&&(m=m.concat(h?a.split(h):a)),r.indexOf(".")>-1&&(m=r.split("."),u=s,s=m[1]),this.addNamespaces(s),md(this.data,m,u),d.silent||this.emit("added",r,s,a,u)}addResources(r,s,a,u={silent:!1}){for(const d in a)(ae(a[d])||Array.isArray(a[d]))&&this.addResource(r,s,d,a[d],{silent:!0});u.silent||this.emit("added",r,s,a)}addResourceBundle(r,s,a,u,d,h={silent:!1,skipCopy:!1}){let m=[r,s];r.indexOf(".")>-1&&(m=r.split("."),u=a,a=s,s=m[1]),this.addNamespaces(s);let g=vi(this.data,m)||{};h.skipCopy||(a=JSON.parse(JSON.stringify(a))),u?lf(g,a,d):g={...g,...a},md(this.data,m,g),h.silent||this.emit("added",r,s,a)}removeResourceBundle(r,s){this.hasResourceBundle(r,s)&&delete this.data[r][s],this.removeNamespaces(s),this.emit("removed",r,s)}hasResourceBundle(r,s){return this.getResource(r,s)!==void 0}getResourceBundle(r,s){return s||(s=this.options.defaultNS),this.getResource(r,s)}getDataByLanguage(r){return this.data[r]}hasLanguageSomeTranslations(r){const s=this.getDataByLanguage(r);return!!(s&&Object.keys(s)||[]).find(u=>s[u]&&Object.keys(s[u]).length>0)}toJSON(){return this.data}}var uf={processors:{},addPostProcessor(o){this.processors[o.name]=o},handle(o,r,s,a,u){return o.forEach(d=>{r=this.processors[d]?.process(r,s,a,u)??r}),r}};const yd={},vd=o=>!ae(o)&&typeof o!="boolean"&&typeof o!="number";class wi extends ji{constructor(r,s={}){super(),Ry(["resourceStore","languageUtils","pluralResolver","interpolator","backendConnector","i18nFormat","utils"],r,this),this.options=s,this.options.keySeparator===void 0&&(this.options.keySeparator="."),this.logger=Tt.create("translator")}changeLanguage(r){r&&(this.language=r)}exists(r,s={interpolation:{}}){const a={...s};return r==null?!1:this.resolve(r,a)?.res!==void 0}extractFromKey(r,s){let a=s.nsSeparator!==void 0?s.nsSeparator:this.options.nsSeparator;a===void 0&&(a=":");const u=s.keySeparator!==void 0?s.keySeparator:this.options.keySeparator;let d=s.ns||this.options.defaultNS||[];const h=a&&r.indexOf(a)>-1,m=!this.options.userDefinedKeySeparator&&!s.keySeparator&&!this.options.userDefinedNsSeparator&&!s.nsSeparator&&!Yy(r,a,u);if(h&&!m){const g=r.match(this.interpolator.nestingRegexp);if(g&&g.length>0)return{key:r,namespaces:ae(d)?[d]:d};const y=r.split(a);(a!==u||a===u&&this.options.ns.indexOf(y[0])>-1)&&(d=y.shift()),r=y.join(u)}return{key:r,namespaces:ae(d)?[d]:d}}translate(r,s,a){let u=typeof s=="object"?{...s}:s;if(typeof u!="object"&&this.options.overloadTranslationOptionHandler&&(u=this.options.overloadTranslationOptionHandler(arguments)),typeof options=="object"&&(u={...u}),u||(u={}),r==null)return"";Array.isArray(r)||(r=[String(r)]);const d=u.returnDetails!==void 0?u.returnDetails:this.options.returnDetails,h=u.keySeparator!==void 0?u.keySeparator:this.options.keySeparator,{key:m,namespaces:g}=this.extractFromKey(r[r.length-1],u),y=g[g.length-1];let k=u.nsSeparator!==void 0?u.nsSeparator:this.options.nsSeparator;k===void 0&&(k=":");const x=u.lng||this.language,L=u.appendNamespaceToCIMode||this.options.appendNamespaceToCIMode;if(x?.toLowerCase()==="cimode")return L?d?{res:`${y}${k}${m}`,usedKey:m,exactUsedKey:m,usedLng:x,usedNS:y,usedParams:this.getUsedParamsDetails(u)}:`${y}${k}${m}`:d?{res:m,usedKey:m,exactUsedKey:m,usedLng:x,usedNS:y,usedParams:this.getUsedParamsDetails(u)}:m;const I=this.resolve(r,u);let E=I?.res;const z=I?.usedKey||m,T=I?.exactUsedKey||m,A=["[object Number]","[object Function]","[object RegExp]"],H=u.joinArrays!==void 0?u.joinArrays:this.options.joinArrays,Y=!this.i18nFormat||this.i18nFormat.handleAsObject,Q=u.count!==void 0&&!ae(u.count),U=wi.hasDefaultValue(u),J=Q?this.pluralResolver.getSuffix(x,u.count,u):"",ee=u.ordinal&&Q?this.pluralResolver.getSuffix(x,u.count,{ordinal:!1}):"",re=Q&&!u.ordinal&&u.count===0,fe=re&&u[`defaultValue${this.options.pluralSeparator}zero`]||u[`defaultValue${J}`]||u[`defaultValue${ee}`]||u.defaultValue;let de=E;Y&&!E&&U&&(de=fe);const Ce=vd(de),Se=Object.prototype.toString.apply(de);if(Y&&de&&Ce&&A.indexOf(Se)<0&&!(ae(H)&&Array.isArray(de))){if(!u.returnObjects&&!this.options.returnObjects){this.options.returnedObjectHandler||this.logger.warn("accessing an object - but returnObjects options is not enabled!");const M=this.options.returnedObjectHandler?this.options.returnedObjectHandler(z,de,{...u,ns:g}):`key '${m} (${this.language})' returned an object instead of string.`;return d?(I.res=M,I.usedParams=this.getUsedParamsDetails(u),I):M}if(h){const M=Array.isArray(de),q=M?[]:{},he=M?T:z;for(const se in de)if(Object.prototype.hasOwnProperty.call(de,se)){const le=`${he}${h}${se}`;U&&!E?q[se]=this.translate(le,{...u,defaultValue:vd(fe)?fe[se]:void 0,joinArrays:!1,ns:g}):q[se]=this.translate(le,{...u,joinArrays:!1,ns:g}),q[se]===le&&(q[se]=de[se])}E=q}}else if(Y&&ae(H)&&Array.isArray(E))E=E.join(H),E&&(E=this.extendTranslation(E,r,u,a));else{let M=!1,q=!1;!this.isValidLookup(E)&&U&&(M=!0,E=fe),this.isValidLookup(E)||(q=!0,E=m);const se=(u.missingKeyNoValueFallbackToKey||this.options.missingKeyNoValueFallbackToKey)&&q?void 0:E,le=U&&fe!==E&&this.options.updateMissing;if(q||M||le){if(this.logger.log(le?"updateKey":"missingKey",x,y,m,le?fe:E),h){const S=this.resolve(m,{...u,keySeparator:!1});S&&S.res&&this.logger.warn("Seems the loaded translations were in flat JSON format instead of nested. Either set keySeparator: false on init or make sure your translations are published in nested format.")}let B=[];const _=this.languageUtils.getFallbackCodes(this.options.fallbackLng,u.lng||this.language);if(this.options.saveMissingTo==="fallback"&&_&&_[0])for(let S=0;S<_.length;S++)B.push(_[S]);else this.options.saveMissingTo==="all"?B=this.languageUtils.toResolveHierarchy(u.lng||this.language):B.push(u.lng||this.language);const D=(S,P,ie)=>{const ue=U&&ie!==E?ie:se;this.options.missingKeyHandler?this.options.missingKeyHandler(S,y,P,ue,le,u):this.backendConnector?.saveMissing&&this.backendConnector.saveMissing(S,y,P,ue,le,u),this.emit("missingKey",S,y,P,E)};this.options.saveMissing&&(this.options.saveMissingPlurals&&Q?B.forEach(S=>{const P=this.pluralResolver.getSuffixes(S,u);re&&u[`defaultValue${this.options.pluralSeparator}zero`]&&P.indexOf(`${this.options.pluralSeparator}zero`)<0&&P.push(`${this.options.pluralSeparator}zero`),P.forEach(ie=>{D([S],m+ie,u[`defaultValue${ie}`]||fe)})}):D(B,m,fe))}E=this.extendTranslation(E,r,u,I,a),q&&E===m&&this.options.appendNamespaceToMissingKey&&(E=`${y}${k}${m}`),(q||M)&&this.options.parseMissingKeyHandler&&(E=this.options.parseMissingKeyHandler(this.options.appendNamespaceToMissingKey?`${y}${k}${m}`:m,M?E:void 0,u))}return d?(I.res=E,I.usedParams=this.getUsedParamsDetails(u),I):E}extendTranslation(r,s,a,u,d){if(this.i18nFormat?.parse)r=this.i18nFormat.parse(r,{...this.options.interpolation.defaultVariables,...a},a.lng||this.language||u.usedLng,u.usedNS,u.usedKey,{resolved:u});else if(!a.skipInterpolation){a.interpolation&&this.interpolator.init({...a,interpolation:{...this.options.interpolation,...a.interpolation}});const g=ae(r)&&(a?.interpolation?.skipOnVariables!==void 0?a.interpolation.skipOnVariables:this.options.interpolation.skipOnVariables);let y;if(g){const x=r.match(this.interpolator.nestingRegexp);y=x&&x.length}let k=a.replace&&!ae(a.replace)?a.replace:a;if(this.options.interpolation.defaultVariables&&(k={...this.options.interpolation.defaultVariables,...k}),r=this.interpolator.interpolate(r,k,a.lng||this.language||u.usedLng,a),g){const x=r.match(this.interpolator.nestingRegexp),L=x&&x.length;y<L&&(a.nest=!1)}!a.lng&&u&&u.res&&(a.lng=this.language||u.usedLng),a.nest!==!1&&(r=this.interpolator.nest(r,(...x)=>d?.[0]===x[0]&&!a.context?(this.logger.warn(`It seems you are nesting recursively key: ${x[0]} in key: ${s[0]}`),null):this.translate(...x,s),a)),a.interpolation&&this.interpolator.reset()}const h=a.postProcess||this.options.postProcess,m=ae(h)?[h]:h;return r!=null&&m?.length&&a.applyPostProcessor!==!1&&(r=uf.handle(m,r,s,this.options&&this.options.postProcessPassResolved?{i18nResolved:{...u,usedParams:this.getUsedParamsDetails(a)},...a}:a,this)),r}resolve(r,s={}){let a,u,d,h,m;return ae(r)&&(r=[r]),r.forEach(g=>{if(this.isValidLookup(a))return;const y=this.extractFromKey(g,s),k=y.key;u=k;let x=y.namespaces;this.options.fallbackNS&&(x=x.concat(this.options.fallbackNS));const L=s.count!==void 0&&!ae(s.count),I=L&&!s.ordinal&&s.count===0,E=s.context!==void 0&&(ae(s.context)||typeof s.context=="number")&&s.context!=="",z=s.lngs?s.lngs:this.languageUtils.toResolveHierarchy(s.lng||this.language,s.fallbackLng);x.forEach(T=>{this.isValidLookup(a)||(m=T,!yd[`${z[0]}-${T}`]&&this.utils?.hasLoadedNamespace&&!this.utils?.hasLoadedNamespace(m)&&(yd[`${z[0]}-${T}`]=!0,this.logger.warn(`key "${u}" for languages "${z.join(", ")}" won't get resolved as namespace "${m}" was not yet loaded`,"This means something IS WRONG in your setup. You access the t function before i18next.init / i18next.loadNamespace / i18next.changeLanguage was done. Wait for the callback or Promise to resolve before accessing it!!!")),z.forEach(A=>{if(this.isValidLookup(a))return;h=A;const H=[k];if(this.i18nFormat?.addLookupKeys)this.i18nFormat.addLookupKeys(H,k,A,T,s);else{let Q;L&&(Q=this.pluralResolver.getSuffix(A,s.count,s));const U=`${this.options.pluralSeparator}zero`,J=`${this.options.pluralSeparator}ordinal${this.options.pluralSeparator}`;if(L&&(H.push(k+Q),s.ordinal&&Q.indexOf(J)===0&&H.push(k+Q.replace(J,this.options.pluralSeparator)),I&&H.push(k+U)),E){const ee=`${k}${this.options.contextSeparator}${s.context}`;H.push(ee),L&&(H.push(ee+Q),s.ordinal&&Q.indexOf(J)===0&&H.push(ee+Q.replace(J,this.options.pluralSeparator)),I&&H.push(ee+U))}}let Y;for(;Y=H.pop();)this.isValidLookup(a)||(d=Y,a=this.getResource(A,T,Y,s))}))})}),{res:a,usedKey:u,exactUsedKey:d,usedLng:h,usedNS:m}}isValidLookup(r){return r!==void 0&&!(!this.options.returnNull&&r===null)&&!(!this.options.returnEmptyString&&r==="")}getResource(r,s,a,u={}){return this.i18nFormat?.getResource?this.i18nFormat.getResource(r,s,a,u):this.resourceStore.getResource(r,s,a,u)}getUsedParamsDetails(r={}){const s=["defaultValue","ordinal","context","replace","lng","lngs","fallbackLng","ns","keySeparator","nsSeparator","returnObjects","returnDetails","joinArrays","postProcess","interpolation"],a=r.replace&&!ae(r.replace);let u=a?r.replace:r;if(a&&typeof r.count<"u"&&(u.count=r.count),this.options.interpolation.defaultVariables&&(u={...this.options.interpolation.defaultVariables,...u}),!a){u={...u};for(const d of s)delete u[d]}return u}static hasDefaultValue(r){const s="defaultValue";for(const a in r)if(Object.prototype.hasOwnProperty.call(r,a)&&s===a.substring(0,s.length)&&r[a]!==void 0)return!0;return!1}}class xd{constructor(r){this.options=r,this.supportedLngs=this.options.supportedLngs||!1,this.logger=Tt.create("languageUtils")}getScriptPartFromCode(r){if(r=qr(r),!r||r.indexOf("-")<0)return null;const s=r.split("-");return s.length===2||(s.pop(),s[s.length-1].toLowerCase()==="x")?null:this.formatLanguageCode(s.join("-"))}getLanguagePartFromCode(r){if(r=qr(r),!r||r.indexOf("-")<0)return r;const s=r.split("-");return this.formatLanguageCode(s[0])}formatLanguageCode(r){if(ae(r)&&r.indexOf("-")>-1){let s;try{s=Intl.getCanonicalLocales(r)[0]}catch{}return s&&this.options.lowerCaseLng&&(s=s.toLowerCase()),s||(this.options.lowerCaseLng?r.toLowerCase():r)}return this.options.cleanCode||this.options.lowerCaseLng?r.toLowerCase():r}isSupportedCode(r){return(this.options.load==="languageOnly"||this.options.nonExplicitSupportedLngs)&&(r=this.getLanguagePartFromCode(r)),!this.supportedLngs||!this.supportedLngs.length||this.supportedLngs.indexOf(r)>-1}getBestMatchFromCodes(r){if(!r)return null;let s;return r.forEach(a=>{if(s)return;const u=this.formatLanguageCode(a);(!this.options.supportedLngs||this.isSupportedCode(u))&&(s=u)}),!s&&this.options.supportedLngs&&r.forEach(a=>{if(s)return;const u=this.getScriptPartFromCode(a);if(this.isSupportedCode(u))return s=u;const d=this.getLanguagePartFromCode(a);if(this.isSupportedCode(d))return s=d;s=this.options.supportedLngs.find(h=>{if(h===d)return h;if(!(h.indexOf("-")<0&&d.indexOf("-")<0)&&(h.indexOf("-")>0&&d.indexOf("-")<0&&h.substring(0,h.indexOf("-"))===d||h.indexOf(d)===0&&d.length>1))return h})}),s||(s=this.getFallbackCodes(this.options.fallbackLng)[0]),s}getFallbackCodes(r,s){if(!r)return[];if(typeof r=="function"&&(r=r(s)),ae(r)&&(r=[r]),Array.isArray(r))return r;if(!s)return r.default||[];let a=r[s];return a||(a=r[this.getScriptPartFromCode(s)]),a||(a=r[this.formatLanguageCode(s)]),a||(a=r[this.getLanguagePartFromCode(s)]),a||(a=r.default),a||[]}toResolveHierarchy(r,s){const a=this.getFallbackCodes((s===!1?[]:s)||this.options.fallbackLng||[],r),u=[],d=h=>{h&&(this.isSupportedCode(h)?u.push(h):this.logger.warn(`rejecting language code not found in supportedLngs: ${h}`))};return ae(r)&&(r.indexOf("-")>-1||r.indexOf("_")>-1)?(this.options.load!=="languageOnly"&&d(this.formatLanguageCode(r)),this.options.load!=="languageOnly"&&this.options.load!=="currentOnly"&&d(this.getScriptPartFromCode(r)),this.options.load!=="currentOnly"&&d(this.getLanguagePartFromCode(r))):ae(r)&&d(this.formatLanguageCode(r)),a.forEach(h=>{u.indexOf(h)<0&&d(this.formatLanguageCode(h))}),u}}const wd={zero:0,one:1,two:2,few:3,many:4,other:5},Sd={select:o=>o===1?"one":"other",resolvedOptions:()=>({pluralCategories:["one","other"]})};class Zy{constructor(r,s={}){this.languageUtils=r,this.options=s,this.logger=Tt.create("pluralResolver"),this.pluralRulesCache={}}addRule(r,s){this.rules[r]=s}clearCache(){this.pluralRulesCache={}}getRule(r,s={}){const a=qr(r==="dev"?"en":r),u=s.ordinal?"ordinal":"cardinal",d=JSON.stringify({cleanedCode:a,type:u});if(d in this.pluralRulesCache)return this.pluralRulesCache[d];let h;try{h=new Intl.PluralRules(a,{type:u})}catch{if(!Intl)return this.logger.error("No Intl support, please use an Intl polyfill!"),Sd;if(!r.match(/-|_/))return Sd;const g=this.languageUtils.getLanguagePartFromCode(r);h=this.getRule(g,s)}return this.pluralRulesCache[d]=h,h}needsPlural(r,s={}){let a=this.getRule(r,s);return a||(a=this.getRule("dev",s)),a?.resolvedOptions().pluralCategories.length>1}getPluralFormsOfKey(r,s,a={}){return this.getSuffixes(r,a).map(u=>`${s}${u}`)}getSuffixes(r,s={}){let a=this.getRule(r,s);return a||(a=this.getRule("dev",s)),a?a.resolvedOptions().pluralCategories.sort((u,d)=>wd[u]-wd[d]).map(u=>`${this.options.prepend}${s.ordinal?`ordinal${this.options.prepend}`:""}${u}`):[]}getSuffix(r,s,a={}){const u=this.getRule(r,a);return u?`${this.options.prepend}${a.ordinal?`ordinal${this.options.prepend}`:""}${u.select(s)}`:(this.logger.warn(`no plural rule found for: ${r}`),this.getSuffix("dev",s,a))}}const kd=(o,r,s,a=".",u=!0)=>{let d=Ay(o,r,s);return!d&&u&&ae(s)&&(d=Ia(o,s,a),d===void 0&&(d=Ia(r,s,a))),d},Ea=o=>o.replace(/\$/g,"$$$$");class Qy{constructor(r={}){this.logger=Tt.create("interpolator"),this.options=r,this.format=r?.interpolation?.format||(s=>s),this.init(r)}init(r={}){r.interpolation||(r.interpolation={escapeValue:!0});const{escape:s,escapeValue:a,useRawValueToEscape:u,prefix:d,prefixEscaped:h,suffix:m,suffixEscaped:g,formatSeparator:y,unescapeSuffix:k,unescapePrefix:x,nestingPrefix:L,nestingPrefixEscaped:I,nestingSuffix:E,nestingSuffixEscaped:z,nestingOptionsSeparator:T,maxReplaces:A,alwaysFormat:H}=r.interpolation;this.escape=s!==void 0?s:Wy,this.escapeValue=a!==void 0?a:!0,this.useRawValueToEscape=u!==void 0?u:!1,this.prefix=d?tr(d):h||"{{",this.suffix=m?tr(m):g||"}}",this.formatSeparator=y||",",this.unescapePrefix=k?"":x||"-",this.unescapeSuffix=this.unescapePrefix?"":k||"",this.nestingPrefix=L?tr(L):I||tr("$t("),this.nestingSuffix=E?tr(E):z||tr(")"),this.nestingOptionsSeparator=T||",",this.maxReplaces=A||1e3,this.alwaysFormat=H!==void 0?H:!1,this.resetRegExp()}reset(){this.options&&this.init(this.options)}resetRegExp(){const r=(s,a)=>s?.source===a?(s.lastIndex=0,s):new RegExp(a,"g");this.regexp=r(this.regexp,`${this.prefix}(.+?)${this.suffix}`),this.regexpUnescape=r(this.regexpUnescape,`${this.prefix}${this.unescapePrefix}(.+?)${this.unescapeSuffix}${this.suffix}`),this.nestingRegexp=r(this.nestingRegexp,`${this.nestingPrefix}(.+?)${this.nestingSuffix}`)}interpolate(r,s,a,u){let d,h,m;const g=this.options&&this.options.interpolation&&this.options.interpolation.defaultVariables||{},y=I=>{if(I.indexOf(this.formatSeparator)<0){const A=kd(s,g,I,this.options.keySeparator,this.options.ignoreJSONStructure);return this.alwaysFormat?this.format(A,void 0,a,{...u,...s,interpolationkey:I}):A}const E=I.split(this.formatSeparator),z=E.shift().trim(),T=E.join(this.formatSeparator).trim();return this.format(kd(s,g,z,this.options.keySeparator,this.options.ignoreJSONStructure),T,a,{...u,...s,interpolationkey:z})};this.resetRegExp();const k=u?.missingInterpolationHandler||this.options.missingInterpolationHandler,x=u?.interpolation?.skipOnVariables!==void 0?u.interpolation.skipOnVariables:this.options.interpolation.skipOnVariables;return[{regex:this.regexpUnescape,safeValue:I=>Ea(I)},{regex:this.regexp,safeValue:I=>this.escapeValue?Ea(this.escape(I)):Ea(I)}].forEach(I=>{for(m=0;d=I.regex.exec(r);){const E=d[1].trim();if(h=y(E),h===void 0)if(typeof k=="function"){const T=k(r,d,u);h=ae(T)?T:""}else if(u&&Object.prototype.hasOwnProperty.call(u,E))h="";else if(x){h=d[0];continue}else this.logger.warn(`missed to pass in variable ${E} for interpolating ${r}`),h="";else!ae(h)&&!this.useRawValueToEscape&&(h=fd(h));const z=I.safeValue(h);if(r=r.replace(d[0],z),x?(I.regex.lastIndex+=h.length,I.regex.lastIndex-=d[0].length):I.regex.lastIndex=0,m++,m>=this.maxReplaces)break}}),r}nest(r,s,a={}){let u,d,h;const m=(g,y)=>{const k=this.nestingOptionsSeparator;if(g.indexOf(k)<0)return g;const x=g.split(new RegExp(`${k}[ ]*{`));let L=`{${x[1]}`;g=x[0],L=this.interpolate(L,h);const I=L.match(/'/g),E=L.match(/"/g);((I?.length??0)%2===0&&!E||E.length%2!==0)&&(L=L.replace(/'/g,'"'));try{h=JSON.parse(L),y&&(h={...y,...h})}catch(z){return this.logger.warn(`failed parsing options string in nesting for key ${g}`,z),`${g}${k}${L}`}return h.defaultValue&&h.defaultValue.indexOf(this.prefix)>-1&&delete h.defaultValue,g};for(;u=this.nestingRegexp.exec(r);){let g=[];h={...a},h=h.replace&&!ae(h.replace)?h.replace:h,h.applyPostProcessor=!1,delete h.defaultValue;const y=/{.*}/.test(u[1])?u[1].lastIndexOf("}")+1:u[1].indexOf(this.formatSeparator);if(y!==-1&&(g=u[1].slice(y).split(this.formatSeparator).map(k=>k.trim()).filter(Boolean),u[1]=u[1].slice(0,y)),d=s(m.call(this,u[1].trim(),h),h),d&&u[0]===r&&!ae(d))return d;ae(d)||(d=fd(d)),d||(this.logger.warn(`missed to resolve ${u[1]} for nesting ${r}`),d=""),g.length&&(d=g.reduce((k,x)=>this.format(k,x,a.lng,{...a,interpolationkey:u[1].trim()}),d.trim())),r=r.replace(u[0],d),this.regexp.lastIndex=0}return r}}const Ky=o=>{let r=o.toLowerCase().trim();const s={};if(o.indexOf("(")>-1){const a=o.split("(");r=a[0].toLowerCase().trim();const u=a[1].substring(0,a[1].length-1);r==="currency"&&u.indexOf(":")<0?s.currency||(s.currency=u.trim()):r==="relativetime"&&u.indexOf(":")<0?s.range||(s.range=u.trim()):u.split(";").forEach(h=>{if(h){const[m,...g]=h.split(":"),y=g.join(":").trim().replace(/^'+|'+$/g,""),k=m.trim();s[k]||(s[k]=y),y==="false"&&(s[k]=!1),y==="true"&&(s[k]=!0),isNaN(y)||(s[k]=parseInt(y,10))}})}return{formatName:r,formatOptions:s}},Nd=o=>{const r={};return(s,a,u)=>{let d=u;u&&u.interpolationkey&&u.formatParams&&u.formatParams[u.interpolationkey]&&u[u.interpolationkey]&&(d={...d,[u.interpolationkey]:void 0});const h=a+JSON.stringify(d);let m=r[h];return m||(m=o(qr(a),u),r[h]=m),m(s)}},Xy=o=>(r,s,a)=>o(qr(s),a)(r);class Jy{constructor(r={}){this.logger=Tt.create("formatter"),this.options=r,this.init(r)}init(r,s={interpolation:{}}){this.formatSeparator=s.interpolation.formatSeparator||",";const a=s.cacheInBuiltFormats?Nd:Xy;this.formats={number:a((u,d)=>{const h=new Intl.NumberFormat(u,{...d});return m=>h.format(m)}),currency:a((u,d)=>{const h=new Intl.NumberFormat(u,{...d,style:"currency"});return m=>h.format(m)}),datetime:a((u,d)=>{const h=new Intl.DateTimeFormat(u,{...d});return m=>h.format(m)}),relativetime:a((u,d)=>{const h=new Intl.RelativeTimeFormat(u,{...d});return m=>h.format(m,d.range||"day")}),list:a((u,d)=>{const h=new Intl.ListFormat(u,{...d});return m=>h.format(m)})}}add(r,s){this.formats[r.toLowerCase().trim()]=s}addCached(r,s){this.formats[r.toLowerCase().trim()]=Nd(s)}format(r,s,a,u={}){const d=s.split(this.formatSeparator);if(d.length>1&&d[0].indexOf("(")>1&&d[0].indexOf(")")<0&&d.find(m=>m.indexOf(")")>-1)){const m=d.findIndex(g=>g.indexOf(")")>-1);d[0]=[d[0],...d.splice(1,m)].join(this.formatSeparator)}return d.reduce((m,g)=>{const{formatName:y,formatOptions:k}=Ky(g);if(this.formats[y]){let x=m;try{const L=u?.formatParams?.[u.interpolationkey]||{},I=L.locale||L.lng||u.locale||u.lng||a;x=this.formats[y](m,I,{...k,...u,...L})}catch(L){this.logger.warn(L)}return x}else this.logger.warn(`there was no format function for ${y}`);return m},r)}}const qy=(o,r)=>{o.pending[r]!==void 0&&(delete o.pending[r],o.pendingCount--)};class ev extends ji{constructor(r,s,a,u={}){super(),this.backend=r,this.store=s,this.services=a,this.languageUtils=a.languageUtils,this.options=u,this.logger=Tt.create("backendConnector"),this.waitingReads=[],this.maxParallelReads=u.maxParallelReads||10,this.readingCalls=0,this.maxRetries=u.maxRetries>=0?u.maxRetries:5,this.retryTimeout=u.retryTimeout>=1?u.retryTimeout:350,this.state={},this.queue=[],this.backend?.init?.(a,u.backend,u)}queueLoad(r,s,a,u){const d={},h={},m={},g={};return r.forEach(y=>{let k=!0;s.forEach(x=>{const L=`${y}|${x}`;!a.reload&&this.store.hasResourceBundle(y,x)?this.state[L]=2:this.state[L]<0||(this.state[L]===1?h[L]===void 0&&(h[L]=!0):(this.state[L]=1,k=!1,h[L]===void 0&&(h[L]=!0),d[L]===void 0&&(d[L]=!0),g[x]===void 0&&(g[x]=!0)))}),k||(m[y]=!0)}),(Object.keys(d).length||Object.keys(h).length)&&this.queue.push({pending:h,pendingCount:Object.keys(h).length,loaded:{},errors:[],callback:u}),{toLoad:Object.keys(d),pending:Object.keys(h),toLoadLanguages:Object.keys(m),toLoadNamespaces:Object.keys(g)}}loaded(r,s,a){const u=r.split("|"),d=u[0],h=u[1];s&&this.emit("failedLoading",d,h,s),!s&&a&&this.store.addResourceBundle(d,h,a,void 0,void 0,{skipCopy:!0}),this.state[r]=s?-1:2,s&&a&&(this.state[r]=0);const m={};this.queue.forEach(g=>{$y(g.loaded,[d],h),qy(g,r),s&&g.errors.push(s),g.pendingCount===0&&!g.done&&(Object.keys(g.loaded).forEach(y=>{m[y]||(m[y]={});const k=g.loaded[y];k.length&&k.forEach(x=>{m[y][x]===void 0&&(m[y][x]=!0)})}),g.done=!0,g.errors.length?g.callback(g.errors):g.callback())}),this.emit("loaded",m),this.queue=this.queue.filter(g=>!g.done)}read(r,s,a,u=0,d=this.retryTimeout,h){if(!r.length)return h(null,{});if(this.readingCalls>=this.maxParallelReads){this.waitingReads.push({lng:r,ns:s,fcName:a,tried:u,wait:d,callback:h});return}this.readingCalls++;const m=(y,k)=>{if(this.readingCalls--,this.waitingReads.length>0){const x=this.waitingReads.shift();this.read(x.lng,x.ns,x.fcName,x.tried,x.wait,x.callback)}if(y&&k&&u<this.maxRetries){setTimeout(()=>{this.read.call(this,r,s,a,u+1,d*2,h)},d);return}h(y,k)},g=this.backend[a].bind(this.backend);if(g.length===2){try{const y=g(r,s);y&&typeof y.then=="function"?y.then(k=>m(null,k)).catch(m):m(null,y)}catch(y){m(y)}return}return g(r,s,m)}prepareLoading(r,s,a={},u){if(!this.backend)return this.logger.warn("No backend was added via i18next.use. Will not load resources."),u&&u();ae(r)&&(r=this.languageUtils.toResolveHierarchy(r)),ae(s)&&(s=[s]);const d=this.queueLoad(r,s,a,u);if(!d.toLoad.length)return d.pending.length||u(),null;d.toLoad.forEach(h=>{this.loadOne(h)})}load(r,s,a){this.prepareLoading(r,s,{},a)}reload(r,s,a){this.prepareLoading(r,s,{reload:!0},a)}loadOne(r,s=""){const a=r.split("|"),u=a[0],d=a[1];this.read(u,d,"read",void 0,void 0,(h,m)=>{h&&this.logger.warn(`${s}loading namespace ${d} for language ${u} failed`,h),!h&&m&&this.logger.log(`${s}loaded namespace ${d} for language ${u}`,m),this.loaded(r,h,m)})}saveMissing(r,s,a,u,d,h={},m=()=>{}){if(this.services?.utils?.hasLoadedNamespace&&!this.services?.utils?.hasLoadedNamespace(s)){this.logger.warn(`did not save key "${a}" as the namespace "${s}" was not yet loaded`,"This means something IS WRONG in your setup. You access the t function before i18next.init / i18next.loadNamespace / i18next.changeLanguage was done. Wait for the callback or Promise to resolve before accessing it!!!");return}if(!(a==null||a==="")){if(this.backend?.create){const g={...h,isUpdate:d},y=this.backend.create.bind(this.backend);if(y.length<6)try{let k;y.length===5?k=y(r,s,a,u,g):k=y(r,s,a,u),k&&typeof k.then=="function"?k.then(x=>m(null,x)).catch(m):m(null,k)}catch(k){m(k)}else y(r,s,a,u,m,g)}!r||!r[0]||this.store.addResource(r[0],s,a,u)}}}const Cd=()=>({debug:!1,initAsync:!0,ns:["translation"],defaultNS:["translation"],fallbackLng:["dev"],fallbackNS:!1,supportedLngs:!1,nonExplicitSupportedLngs:!1,load:"all",preload:!1,simplifyPluralSuffix:!0,keySeparator:".",nsSeparator:":",pluralSeparator:"_",contextSeparator:"_",partialBundledLanguages:!1,saveMissing:!1,updateMissing:!1,saveMissingTo:"fallback",saveMissingPlurals:!0,missingKeyHandler:!1,missingInterpolationHandler:!1,postProcess:!1,postProcessPassResolved:!1,returnNull:!1,returnEmptyString:!0,returnObjects:!1,joinArrays:!1,returnedObjectHandler:!1,parseMissingKeyHandler:!1,appendNamespaceToMissingKey:!1,appendNamespaceToCIMode:!1,overloadTranslationOptionHandler:o=>{let r={};if(typeof o[1]=="object"&&(r=o[1]),ae(o[1])&&(r.defaultValue=o[1]),ae(o[2])&&(r.tDescription=o[2]),typeof o[2]=="object"||typeof o[3]=="object"){const s=o[3]||o[2];Object.keys(s).forEach(a=>{r[a]=s[a]})}return r},interpolation:{escapeValue:!0,format:o=>o,prefix:"{{",suffix:"}}",formatSeparator:",",unescapePrefix:"-",nestingPrefix:"$t(",nestingSuffix:")",nestingOptionsSeparator:",",maxReplaces:1e3,skipOnVariables:!0},cacheInBuiltFormats:!0}),jd=o=>(ae(o.ns)&&(o.ns=[o.ns]),ae(o.fallbackLng)&&(o.fallbackLng=[o.fallbackLng]),ae(o.fallbackNS)&&(o.fallbackNS=[o.fallbackNS]),o.supportedLngs?.indexOf?.("cimode")<0&&(o.supportedLngs=o.supportedLngs.concat(["cimode"])),typeof o.initImmediate=="boolean"&&(o.initAsync=o.initImmediate),o),pi=()=>{},tv=o=>{Object.getOwnPropertyNames(Object.getPrototypeOf(o)).forEach(s=>{typeof o[s]=="function"&&(o[s]=o[s].bind(o))})};class es extends ji{constructor(r={},s){if(super(),this.options=jd(r),this.services={},this.logger=Tt,this.modules={external:[]},tv(this),s&&!this.isInitialized&&!r.isClone){if(!this.options.initAsync)return this.init(r,s),this;setTimeout(()=>{this.init(r,s)},0)}}init(r={},s){this.isInitializing=!0,typeof r=="function"&&(s=r,r={}),r.defaultNS==null&&r.ns&&(ae(r.ns)?r.defaultNS=r.ns:r.ns.indexOf("translation")<0&&(r.defaultNS=r.ns[0]));const a=Cd();this.options={...a,...this.options,...jd(r)},this.options.interpolation={...a.interpolation,...this.options.interpolation},r.keySeparator!==void 0&&(this.options.userDefinedKeySeparator=r.keySeparator),r.nsSeparator!==void 0&&(this.options.userDefinedNsSeparator=r.nsSeparator);const u=y=>y?typeof y=="function"?new y:y:null;if(!this.options.isClone){this.modules.logger?Tt.init(u(this.modules.logger),this.options):Tt.init(null,this.options);let y;this.modules.formatter?y=this.modules.formatter:y=Jy;const k=new xd(this.options);this.store=new gd(this.options.resources,this.options);const x=this.services;x.logger=Tt,x.resourceStore=this.store,x.languageUtils=k,x.pluralResolver=new Zy(k,{prepend:this.options.pluralSeparator,simplifyPluralSuffix:this.options.simplifyPluralSuffix}),this.options.interpolation.format&&this.options.interpolation.format!==a.interpolation.format&&this.logger.warn("init: you are still using the legacy format function, please use the new approach: https://www.i18next.com/translation-function/formatting"),y&&(!this.options.interpolation.format||this.options.interpolation.format===a.interpolation.format)&&(x.formatter=u(y),x.formatter.init&&x.formatter.init(x,this.options),this.options.interpolation.format=x.formatter.format.bind(x.formatter)),x.interpolator=new Qy(this.options),x.utils={hasLoadedNamespace:this.hasLoadedNamespace.bind(this)},x.backendConnector=new ev(u(this.modules.backend),x.resourceStore,x,this.options),x.backendConnector.on("*",(I,...E)=>{this.emit(I,...E)}),this.modules.languageDetector&&(x.languageDetector=u(this.modules.languageDetector),x.languageDetector.init&&x.languageDetector.init(x,this.options.detection,this.options)),this.modules.i18nFormat&&(x.i18nFormat=u(this.modules.i18nFormat),x.i18nFormat.init&&x.i18nFormat.init(this)),this.translator=new wi(this.services,this.options),this.translator.on("*",(I,...E)=>{this.emit(I,...E)}),this.modules.external.forEach(I=>{I.init&&I.init(this)})}if(this.format=this.options.interpolation.format,s||(s=pi),this.options.fallbackLng&&!this.services.languageDetector&&!this.options.lng){const y=this.services.languageUtils.getFallbackCodes(this.options.fallbackLng);y.length>0&&y[0]!=="dev"&&(this.options.lng=y[0])}!this.services.languageDetector&&!this.options.lng&&this.logger.warn("init: no languageDetector is used and no lng is defined"),["getResource","hasResourceBundle","getResourceBundle","getDataByLanguage"].forEach(y=>{this[y]=(...k)=>this.store[y](...k)}),["addResource","addResources","addResourceBundle","removeResourceBundle"].forEach(y=>{this[y]=(...k)=>(this.store[y](...k),this)});const m=Zr(),g=()=>{const y=(k,x)=>{this.isInitializing=!1,this.isInitialized&&!this.initializedStoreOnce&&this.logger.warn("init: i18next is already initialized. You should call init just once!"),this.isInitialized=!0,this.options.isClone||this.logger.log("initialized",this.options),this.emit("initialized",this.options),m.resolve(x),s(k,x)};if(this.languages&&!this.isInitialized)return y(null,this.t.bind(this));this.changeLanguage(this.options.lng,y)};return this.options.resources||!this.options.initAsync?g():setTimeout(g,0),m}loadResources(r,s=pi){let a=s;const u=ae(r)?r:this.language;if(typeof r=="function"&&(a=r),!this.options.resources||this.options.partialBundledLanguages){if(u?.toLowerCase()==="cimode"&&(!this.options.preload||this.options.preload.length===0))return a();const d=[],h=m=>{if(!m||m==="cimode")return;this.services.languageUtils.toResolveHierarchy(m).forEach(y=>{y!=="cimode"&&d.indexOf(y)<0&&d.push(y)})};u?h(u):this.services.languageUtils.getFallbackCodes(this.options.fallbackLng).forEach(g=>h(g)),this.options.preload?.forEach?.(m=>h(m)),this.services.backendConnector.load(d,this.options.ns,m=>{!m&&!this.resolvedLanguage&&this.language&&this.setResolvedLanguage(this.language),a(m)})}else a(null)}reloadResources(r,s,a){const u=Zr();return typeof r=="function"&&(a=r,r=void 0),typeof s=="function"&&(a=s,s=void 0),r||(r=this.languages),s||(s=this.options.ns),a||(a=pi),this.services.backendConnector.reload(r,s,d=>{u.resolve(),a(d)}),u}use(r){if(!r)throw new Error("You are passing an undefined module! Please check the object you are passing to i18next.use()");if(!r.type)throw new Error("You are passing a wrong module! Please check the object you are passing to i18next.use()");return r.type==="backend"&&(this.modules.backend=r),(r.type==="logger"||r.log&&r.warn&&r.error)&&(this.modules.logger=r),r.type==="languageDetector"&&(this.modules.languageDetector=r),r.type==="i18nFormat"&&(this.modules.i18nFormat=r),r.type==="postProcessor"&&uf.addPostProcessor(r),r.type==="formatter"&&(this.modules.formatter=r),r.type==="3rdParty"&&this.modules.external.push(r),this}setResolvedLanguage(r){if(!(!r||!this.languages)&&!(["cimode","dev"].indexOf(r)>-1)){for(let s=0;s<this.languages.length;s++){const a=this.languages[s];if(!(["cimode","dev"].indexOf(a)>-1)&&this.store.hasLanguageSomeTranslations(a)){this.resolvedLanguage=a;break}}!this.resolvedLanguage&&this.languages.indexOf(r)<0&&this.store.hasLanguageSomeTranslations(r)&&(this.resolvedLanguage=r,this.languages.unshift(r))}}changeLanguage(r,s){this.isLanguageChangingTo=r;const a=Zr();this.emit("languageChanging",r);const u=m=>{this.language=m,this.languages=this.services.languageUtils.toResolveHierarchy(m),this.resolvedLanguage=void 0,this.setResolvedLanguage(m)},d=(m,g)=>{g?this.isLanguageChangingTo===r&&(u(g),this.translator.changeLanguage(g),this.isLanguageChangingTo=void 0,this.emit("languageChanged",g),this.logger.log("languageChanged",g)):this.isLanguageChangingTo=void 0,a.resolve((...y)=>this.t(...y)),s&&s(m,(...y)=>this.t(...y))},h=m=>{!r&&!m&&this.services.languageDetector&&(m=[]);const g=ae(m)?m:m&&m[0],y=this.store.hasLanguageSomeTranslations(g)?g:this.services.languageUtils.getBestMatchFromCodes(ae(m)?[m]:m);y&&(this.language||u(y),this.translator.language||this.translator.changeLanguage(y),this.services.languageDetector?.cacheUserLanguage?.(y)),this.loadResources(y,k=>{d(k,y)})};return!r&&this.services.languageDetector&&!this.services.languageDetector.async?h(this.services.languageDetector.detect()):!r&&this.services.languageDetector&&this.services.languageDetector.async?this.services.languageDetector.detect.length===0?this.services.languageDetector.detect().then(h):this.services.languageDetector.detect(h):h(r),a}getFixedT(r,s,a){const u=(d,h,...m)=>{let g;typeof h!="object"?g=this.options.overloadTranslationOptionHandler([d,h].concat(m)):g={...h},g.lng=g.lng||u.lng,g.lngs=g.lngs||u.lngs,g.ns=g.ns||u.ns,g.keyPrefix!==""&&(g.keyPrefix=g.keyPrefix||a||u.keyPrefix);const y=this.options.keySeparator||".";let k;return g.keyPrefix&&Array.isArray(d)?k=d.map(x=>`${g.keyPrefix}${y}${x}`):k=g.keyPrefix?`${g.keyPrefix}${y}${d}`:d,this.t(k,g)};return ae(r)?u.lng=r:u.lngs=r,u.ns=s,u.keyPrefix=a,u}t(...r){return this.translator?.translate(...r)}exists(...r){return this.translator?.exists(...r)}setDefaultNamespace(r){this.options.defaultNS=r}hasLoadedNamespace(r,s={}){if(!this.isInitialized)return this.logger.warn("hasLoadedNamespace: i18next was not initialized",this.languages),!1;if(!this.languages||!this.languages.length)return this.logger.warn("hasLoadedNamespace: i18n.languages were undefined or empty",this.languages),!1;const a=s.lng||this.resolvedLanguage||this.languages[0],u=this.options?this.options.fallbackLng:!1,d=this.languages[this.languages.length-1];if(a.toLowerCase()==="cimode")return!0;const h=(m,g)=>{const y=this.services.backendConnector.state[`${m}|${g}`];return y===-1||y===0||y===2};if(s.precheck){const m=s.precheck(this,h);if(m!==void 0)return m}return!!(this.hasResourceBundle(a,r)||!this.services.backendConnector.backend||this.options.resources&&!this.options.partialBundledLanguages||h(a,r)&&(!u||h(d,r)))}loadNamespaces(r,s){const a=Zr();return this.options.ns?(ae(r)&&(r=[r]),r.forEach(u=>{this.options.ns.indexOf(u)<0&&this.options.ns.push(u)}),this.loadResources(u=>{a.resolve(),s&&s(u)}),a):(s&&s(),Promise.resolve())}loadLanguages(r,s){const a=Zr();ae(r)&&(r=[r]);const u=this.options.preload||[],d=r.filter(h=>u.indexOf(h)<0&&this.services.languageUtils.isSupportedCode(h));return d.length?(this.options.preload=u.concat(d),this.loadResources(h=>{a.resolve(),s&&s(h)}),a):(s&&s(),Promise.resolve())}dir(r){if(r||(r=this.resolvedLanguage||(this.languages?.length>0?this.languages[0]:this.language)),!r)return"rtl";try{const u=new Intl.Locale(r);if(u&&u.getTextInfo){const d=u.getTextInfo();if(d&&d.direction)return d.direction}}catch{}const s=["ar","shu","sqr","ssh","xaa","yhd","yud","aao","abh","abv","acm","acq","acw","acx","acy","adf","ads","aeb","aec","afb","ajp","apc","apd","arb","arq","ars","ary","arz","auz","avl","ayh","ayl","ayn","ayp","bbz","pga","he","iw","ps","pbt","pbu","pst","prp","prd","ug","ur","ydd","yds","yih","ji","yi","hbo","men","xmn","fa","jpr","peo","pes","prs","dv","sam","ckb"],a=this.services?.languageUtils||new xd(Cd());return r.toLowerCase().indexOf("-latn")>1?"ltr":s.indexOf(a.getLanguagePartFromCode(r))>-1||r.toLowerCase().indexOf("-arab")>1?"rtl":"ltr"}static createInstance(r={},s){return new es(r,s)}cloneInstance(r={},s=pi){const a=r.forkResourceStore;a&&delete r.forkResourceStore;const u={...this.options,...r,isClone:!0},d=new es(u);if((r.debug!==void 0||r.prefix!==void 0)&&(d.logger=d.logger.clone(r)),["store","services","language"].forEach(m=>{d[m]=this[m]}),d.services={...this.services},d.services.utils={hasLoadedNamespace:d.hasLoadedNamespace.bind(d)},a){const m=Object.keys(this.store.data).reduce((g,y)=>(g[y]={...this.store.data[y]},g[y]=Object.keys(g[y]).reduce((k,x)=>(k[x]={...g[y][x]},k),g[y]),g),{});d.store=new gd(m,u),d.services.resourceStore=d.store}return d.translator=new wi(d.services,u),d.translator.on("*",(m,...g)=>{d.emit(m,...g)}),d.init(u,s),d.translator.options=u,d.translator.backendConnector.services.utils={hasLoadedNamespace:d.hasLoadedNamespace.bind(d)},d}toJSON(){return{options:this.options,store:this.store,language:this.language,languages:this.languages,resolvedLanguage:this.resolvedLanguage}}}const Xe=es.createInstance();Xe.createInstance=es.createInstance;Xe.createInstance;Xe.dir;Xe.init;Xe.loadResources;Xe.reloadResources;Xe.use;Xe.changeLanguage;Xe.getFixedT;Xe.t;Xe.exists;Xe.setDefaultNamespace;Xe.hasLoadedNamespace;Xe.loadNamespaces;Xe.loadLanguages;const{slice:nv,forEach:rv}=[];function sv(o){return rv.call(nv.call(arguments,1),r=>{if(r)for(const s in r)o[s]===void 0&&(o[s]=r[s])}),o}function iv(o){return typeof o!="string"?!1:[/<\s*script.*?>/i,/<\s*\/\s*script\s*>/i,/<\s*img.*?on\w+\s*=/i,/<\s*\w+\s*on\w+\s*=.*?>/i,/javascript\s*:/i,/vbscript\s*:/i,/expression\s*\(/i,/eval\s*\(/i,/alert\s*\(/i,/document\.cookie/i,/document\.write\s*\(/i,/window\.location/i,/innerHTML/i].some(s=>s.test(o))}const bd=/^[\u0009\u0020-\u007e\u0080-\u00ff]+$/,ov=function(o,r){const a=arguments.length>2&&arguments[2]!==void 0?arguments[2]:{path:"/"},u=encodeURIComponent(r);let d=`${o}=${u}`;if(a.maxAge>0){const h=a.maxAge-0;if(Number.isNaN(h))throw new Error("maxAge should be a Number");d+=`; Max-Age=${Math.floor(h)}`}if(a.domain){if(!bd.test(a.domain))throw new TypeError("option domain is invalid");d+=`; Domain=${a.domain}`}if(a.path){if(!bd.test(a.path))throw new TypeError("option path is invalid");d+=`; Path=${a.path}`}if(a.expires){if(typeof a.expires.toUTCString!="function")throw new TypeError("option expires is invalid");d+=`; Expires=${a.expires.toUTCString()}`}if(a.httpOnly&&(d+="; HttpOnly"),a.secure&&(d+="; Secure"),a.sameSite)switch(typeof a.sameSite=="string"?a.sameSite.toLowerCase():a.sameSite){case!0:d+="; SameSite=Strict";break;case"lax":d+="; SameSite=Lax";break;case"strict":d+="; SameSite=Strict";break;case"none":d+="; SameSite=None";break;default:throw new TypeError("option sameSite is invalid")}return a.partitioned&&(d+="; Partitioned"),d},Ed={create(o,r,s,a){let u=arguments.length>4&&arguments[4]!==void 0?arguments[4]:{path:"/",sameSite:"strict"};s&&(u.expires=new Date,u.expires.setTime(u.expires.getTime()+s*60*1e3)),a&&(u.domain=a),document.cookie=ov(o,r,u)},read(o){const r=`${o}=`,s=document.cookie.split(";");for(let a=0;a<s.length;a++){let u=s[a];for(;u.charAt(0)===" ";)u=u.substring(1,u.length);if(u.indexOf(r)===0)return u.substring(r.length,u.length)}return null},remove(o,r){this.create(o,"",-1,r)}};var av={name:"cookie",lookup(o){let{lookupCookie:r}=o;if(r&&typeof document<"u")return Ed.read(r)||void 0},cacheUserLanguage(o,r){let{lookupCookie:s,cookieMinutes:a,cookieDomain:u,cookieOptions:d}=r;s&&typeof document<"u"&&Ed.create(s,o,a,u,d)}},lv={name:"querystring",lookup(o){let{lookupQuerystring:r}=o,s;if(typeof window<"u"){let{search:a}=window.location;!window.location.search&&window.location.hash?.indexOf("?")>-1&&(a=window.location.hash.substring(window.location.hash.indexOf("?")));const d=a.substring(1).split("&");for(let h=0;h<d.length;h++){const m=d[h].indexOf("=");m>0&&d[h].substring(0,m)===r&&(s=d[h].substring(m+1))}}return s}},uv={name:"hash",lookup(o){let{lookupHash:r,lookupFromHashIndex:s}=o,a;if(typeof window<"u"){const{hash:u}=window.location;if(u&&u.length>2){const d=u.substring(1);if(r){const h=d.split("&");for(let m=0;m<h.length;m++){const g=h[m].indexOf("=");g>0&&h[m].substring(0,g)===r&&(a=h[m].substring(g+1))}}if(a)return a;if(!a&&s>-1){const h=u.match(/\/([a-zA-Z-]*)/g);return Array.isArray(h)?h[typeof s=="number"?s:0]?.replace("/",""):void 0}}}return a}};let nr=null;const Od=()=>{if(nr!==null)return nr;try{if(nr=typeof window<"u"&&window.localStorage!==null,!nr)return!1;const o="i18next.translate.boo";window.localStorage.setItem(o,"foo"),window.localStorage.removeItem(o)}catch{nr=!1}return nr};var cv={name:"localStorage",lookup(o){let{lookupLocalStorage:r}=o;if(r&&Od())return window.localStorage.getItem(r)||void 0},cacheUserLanguage(o,r){let{lookupLocalStorage:s}=r;s&&Od()&&window.localStorage.setItem(s,o)}};let rr=null;const Pd=()=>{if(rr!==null)return rr;try{if(rr=typeof window<"u"&&window.sessionStorage!==null,!rr)return!1;const o="i18next.translate.boo";window.sessionStorage.setItem(o,"foo"),window.sessionStorage.removeItem(o)}catch{rr=!1}return rr};var dv={name:"sessionStorage",lookup(o){let{lookupSessionStorage:r}=o;if(r&&Pd())return window.sessionStorage.getItem(r)||void 0},cacheUserLanguage(o,r){let{lookupSessionStorage:s}=r;s&&Pd()&&window.sessionStorage.setItem(s,o)}},fv={name:"navigator",lookup(o){const r=[];if(typeof navigator<"u"){const{languages:s,userLanguage:a,language:u}=navigator;if(s)for(let d=0;d<s.length;d++)r.push(s[d]);a&&r.push(a),u&&r.push(u)}return r.length>0?r:void 0}},hv={name:"htmlTag",lookup(o){let{htmlTag:r}=o,s;const a=r||(typeof document<"u"?document.documentElement:null);return a&&typeof a.getAttribute=="function"&&(s=a.getAttribute("lang")),s}},pv={name:"path",lookup(o){let{lookupFromPathIndex:r}=o;if(typeof window>"u")return;const s=window.location.pathname.match(/\/([a-zA-Z-]*)/g);return Array.isArray(s)?s[typeof r=="number"?r:0]?.replace("/",""):void 0}},mv={name:"subdomain",lookup(o){let{lookupFromSubdomainIndex:r}=o;const s=typeof r=="number"?r+1:1,a=typeof window<"u"&&window.location?.hostname?.match(/^(\w{2,5})\.(([a-z0-9-]{1,63}\.[a-z]{2,6})|localhost)/i);if(a)return a[s]}};let cf=!1;try{document.cookie,cf=!0}catch{}const df=["querystring","cookie","localStorage","sessionStorage","navigator","htmlTag"];cf||df.splice(1,1);const gv=()=>({order:df,lookupQuerystring:"lng",lookupCookie:"i18next",lookupLocalStorage:"i18nextLng",lookupSessionStorage:"i18nextLng",caches:["localStorage"],excludeCacheFor:["cimode"],convertDetectedLanguage:o=>o});class ff{constructor(r){let s=arguments.length>1&&arguments[1]!==void 0?arguments[1]:{};this.type="languageDetector",this.detectors={},this.init(r,s)}init(){let r=arguments.length>0&&arguments[0]!==void 0?arguments[0]:{languageUtils:{}},s=arguments.length>1&&arguments[1]!==void 0?arguments[1]:{},a=arguments.length>2&&arguments[2]!==void 0?arguments[2]:{};this.services=r,this.options=sv(s,this.options||{},gv()),typeof this.options.convertDetectedLanguage=="string"&&this.options.convertDetectedLanguage.indexOf("15897")>-1&&(this.options.convertDetectedLanguage=u=>u.replace("-","_")),this.options.lookupFromUrlIndex&&(this.options.lookupFromPathIndex=this.options.lookupFromUrlIndex),this.i18nOptions=a,this.addDetector(av),this.addDetector(lv),this.addDetector(cv),this.addDetector(dv),this.addDetector(fv),this.addDetector(hv),this.addDetector(pv),this.addDetector(mv),this.addDetector(uv)}addDetector(r){return this.detectors[r.name]=r,this}detect(){let r=arguments.length>0&&arguments[0]!==void 0?arguments[0]:this.options.order,s=[];return r.forEach(a=>{if(this.detectors[a]){let u=this.detectors[a].lookup(this.options);u&&typeof u=="string"&&(u=[u]),u&&(s=s.concat(u))}}),s=s.filter(a=>a!=null&&!iv(a)).map(a=>this.options.convertDetectedLanguage(a)),this.services&&this.services.languageUtils&&this.services.languageUtils.getBestMatchFromCodes?s:s.length>0?s[0]:null}cacheUserLanguage(r){let s=arguments.length>1&&arguments[1]!==void 0?arguments[1]:this.options.caches;s&&(this.options.excludeCacheFor&&this.options.excludeCacheFor.indexOf(r)>-1||s.forEach(a=>{this.detectors[a]&&this.detectors[a].cacheUserLanguage(r,this.options)}))}}ff.type="languageDetector";const yv="Welcome",vv="Compose New Message",xv="Send feedback to the team",wv="Go to My Messages",Sv="View all conversations",kv="Compose Feedback",Nv="Current Behavior",Cv="Describe the current behavior you've observed...",jv="Expected Behavior",bv="Describe how it should behave instead...",Ev="Send Message",Ov="Cancel",Pv="Signed in as",Lv="Create User",zv="Bulk Import",Tv="Show Users",_v="Groups & Permissions",Iv="User Feedback",Dv="My Feedback",Mv="View and manage your feedback submissions",Rv="{{filtered}} of {{total}} feedback items",Fv="Search feedback...",$v="All Feedback",Av="Open",Uv="Solved",Wv="Filter",Bv="No feedback found",Hv="Feedback Details",Vv="Created",Yv="Close",Gv="Current Behavior",Zv="Expected Behavior",Qv="URL",Kv="Chat History",Xv="No messages yet",Jv="Start the conversation below",qv="Mark as solved",ex="Type your message...",tx="Send",nx="Sending...",rx="Press Enter to send, Shift+Enter for new line",sx="You",ix="Current",ox="Just now",ax="{{hours}} hours ago",lx="1 day ago",ux="{{days}} days ago",cx="Admin Dashboard",dx="Collapse Sidebar",fx="Show Organizations",hx="Users",px="Create New User",mx="No users found",gx="Username",yx="First Name",vx="Last Name",xx="Email",wx="Search",Sx="Show All",kx="Is Active",Nx="Is Superuser",Cx="All Groups",jx="All Organizations",bx="No Organization",Ex="Created",Ox="Last Login",Px="Delete User",Lx="Save Changes",zx="Pure Permissions",Tx="Groups",_x="Organization",Ix="Enter last name",Dx="Enter first name",Mx="Enter username",Rx="Password",Fx="Active",$x="None",Ax="Edit Organization",Ux="Active Until",Wx="Expired",Bx="Clear",Hx="Sort by Expiry",Vx="Expiring Soon First",Yx="Expiring Later First",Gx="Status",Zx="Edit User",Qx="email@example.com",Kx="All",Xx="Soon",Jx="Later",qx="Showing Organizations",e0="Create Organization",t0="Organization Name",n0="Organizations",r0="No organizations found.",s0="Admin Dashboard",i0="Please sign in",o0="Email",a0="Enter email",l0="Enter password",u0="Password",c0="Sign In",d0="Edit Organization",f0="Organization Name",h0="Active Until",p0="Organization is active until this date",m0="Delete Organization",g0="Expires in {{days}} days.",y0="Organization is required",v0="Create User",x0="Permissions are access rights that can be assigned to either users or groups",w0="Pure permissions allow you to give a user a specific access right.",S0="A group brings together users who share access rights in the app.",k0={welcome:yv,composeNewMessage:vv,sendFeedBackToTheTeam:xv,GoToMyMessages:wv,ViewAllConversations:Sv,ComposeFeedBack:kv,BehaviourAsItIs:Nv,DescribeBehaviour:Cv,BehaviourAsItShouldBe:jv,DescribeExpectetBehaviour:bv,SendMessage:Ev,Cancel:Ov,signedInAs:Pv,CreateUser:Lv,BulkCreate:zv,ShowUsers:Tv,GroupsPermissions:_v,UserFeedback:Iv,myFeedback:Dv,viewAndManageFeedback:Mv,feedbackItemsCount:Rv,searchFeedbackPlaceholder:Fv,allFeedback:$v,open:Av,solved:Uv,filter:Wv,noFeedbackFound:Bv,feedbackDetails:Hv,created:Vv,close:Yv,currentBehavior:Gv,expectedBehavior:Zv,url:Qv,chatHistory:Kv,noMessagesYet:Xv,startConversationBelow:Jv,markAsSolved:qv,typeYourMessage:ex,send:tx,sending:nx,enterToSendShiftEnterNewLine:rx,you:sx,current:ix,justNow:ox,hoursAgo:ax,oneDayAgo:lx,daysAgo:ux,AdminDashboard:cx,CollapseSidebar:dx,ShowOrganizations:fx,users:hx,createNewUser:px,noUsersFound:mx,Username:gx,FirstName:yx,LastName:vx,Email:xx,Search:wx,ShowAll:Sx,IsActive:kx,IsSuperuser:Nx,AllGroups:Cx,AllOrganizations:jx,NoOrganization:bx,Created:Ex,LastLogin:Ox,DeleteUser:Px,SaveChanges:Lx,PurePermissions:zx,Groups:Tx,Organization:_x,LastNamePlaceholder:Ix,FirstNamePlaceholder:Dx,UsernamePlaceholder:Mx,Password:Rx,Active:Fx,None:$x,EditOrganization:Ax,ActiveUntilLabel:Ux,Expired:Wx,Clear:Bx,SortByExpiry:Hx,ExpiringSoonFirst:Vx,ExpiringLaterFirst:Yx,Status:Gx,manageUser:Zx,EmailPlaceholder:Qx,AllStatus:Kx,Soon:Xx,Later:Jx,ShowingOrganizations:qx,createNewOrganization:e0,OrganizationName:t0,organizations:n0,noOrganizationsFound:r0,adminDashboard:s0,SignInToContinue:i0,emailAddress:o0,enterYourEmail:a0,enterYourPassword:l0,password:u0,signIn:c0,forgotPassword:"Forgot your password?",tfaCode:"2FA code",enterSixDigitCode:"Enter the 6-digit code or a recovery code",twofaEnrollTitle:"Set up two-factor authentication",twofaEnrollHint:"Scan the QR code with your authenticator app and enter the code it shows.",twofaSecret:"Or enter this key manually:",recoveryCodesTitle:"Your recovery codes",recoveryCodesHint:"Store these codes in a safe place. Each code signs you in once if you lose your authenticator app. They are only shown now.",continue:"Continue",manageOrganization:d0,OrganizationNamePlaceholder:f0,ActiveUntil:h0,ActiveUntilDescription:p0,DeleteOrganization:m0,ExpiresInDays:g0,OrganizationRequiredWarning:y0,createUser:v0,PurePermissionsDescription1:x0,PurePermissionsDescription2:w0,GroupDescription1:S0,"language.german":"German","language.english":"English"},N0="Willkommen",C0="Neue Nachricht verfassen",j0="Feedback an das Team senden",b0="Zu meinen Nachrichten",E0="Alle Unterhaltungen anzeigen",O0="Feedback verfassen",P0="Aktuelles Verhalten",L0="Beschreiben Sie das aktuelle Verhalten, das Sie beobachtet haben...",z0="Erwartetes Verhalten",T0="Beschreiben Sie, wie es sich stattdessen verhalten sollte...",_0="Nachricht senden",I0="Abbrechen",D0="Angemeldet als",M0="Benutzer erstellen",R0="Massenimport",F0="Benutzer anzeigen",$0="Gruppen & Berechtigungen",A0="Benutzerfeedback",U0="Mein Feedback",W0="Ihre Feedback-Einreichungen anzeigen und verwalten",B0="{{filtered}} von {{total}} Feedback-Elementen",H0="Feedback durchsuchen...",V0="Alle Feedback",Y0="Offen",G0="Gelöst",Z0="Filter",Q0="Kein Feedback gefunden",K0="Feedback-Details",X0="Erstellt",J0="Schließen",q0="Aktuelles Verhalten",e1="Erwartetes Verhalten",t1="URL",n1="Chat-Verlauf",r1="Noch keine Nachrichten",s1="Beginnen Sie die Unterhaltung unten",i1="Als gelöst markieren",o1="Geben Sie Ihre Nachricht ein...",a1="Senden",l1="Wird gesendet...",u1="Eingabetaste zum Senden, Shift+Eingabe für neue Zeile",c1="Du",d1="Aktuell",f1="Gerade eben",h1="vor {{hours}} Stunden",p1="vor 1 Tag",m1="vor {{days}} Tagen",g1="Admin-Dashboard",y1="Seitenleiste einklappen",v1="Organisationen anzeigen",x1="Benutzer",w1="Neuen Benutzer erstellen",S1="Keine Benutzer gefunden",k1="Benutzername",N1="Vorname",C1="Nachname",j1="E-Mail",b1="Suchen",E1="Alle anzeigen",O1="Ist aktiv",P1="Ist Superuser",L1="Alle Gruppen",z1="Alle Organisationen",T1="Keine Organisation",_1="Erstellt",I1="Letzte Anmeldung",D1="Benutzer löschen",M1="Änderungen speichern",R1="Reine Berechtigungen",F1="Gruppen",$1="Organisation",A1="Nachname eingeben",U1="Vorname eingeben",W1="Benutzername eingeben",B1="Passwort",H1="Aktiv",V1="Keine",Y1="Organisation bearbeiten",G1="Aktiv bis",Z1="Abgelaufen",Q1="Löschen",K1="Nach Ablauf sortieren",X1="Bald ablaufend zuerst",J1="Später ablaufend zuerst",q1="Status",ew="User bearbeiten",tw="email@example.com",nw="Alle",rw="Bald",sw="Später",iw="Zeige Organisationen",ow="Organisation erstellen",aw="Name der Organisation",lw="Organisationen",uw="Keine Organisationen gefunden.",cw="Admin Dashboard",dw="Bitte einloggen",fw="Email",hw="Email eingeben",pw="Passwort eingeben",mw="Passwort",gw="Einloggen",yw="Organisation bearbeiten",vw="Name der Organisation",xw="Aktiv bis",ww="Organisation ist bis zu diesem Datum aktiv",Sw="Organisation löschen",kw="Läuft in {{days}} Tagen ab.",Nw="Organisation wird benötigt",Cw="Benutzer erstellen",jw="Berechtigungen sind Zugangsrechte, die entweder Benutzern oder Gruppen zugeteilt werden können",bw="Reine Berechtigungen ermöglichen es, einem Benutzer genau ein bestimmtes Zugangsrecht zu geben.",Ew="Eine Gruppe fasst Benutzer zusammen, die Zugangsrechte in der App teilen.",Ow="Die Gruppe kann selbst ein Zugangsrecht sein, oder wiederum Berechtigungen zusammenfassen.",Pw={welcome:N0,composeNewMessage:C0,sendFeedBackToTheTeam:j0,GoToMyMessages:b0,ViewAllConversations:E0,ComposeFeedBack:O0,BehaviourAsItIs:P0,DescribeBehaviour:L0,BehaviourAsItShouldBe:z0,DescribeExpectetBehaviour:T0,SendMessage:_0,Cancel:I0,signedInAs:D0,CreateUser:M0,BulkCreate:R0,ShowUsers:F0,GroupsPermissions:$0,UserFeedback:A0,myFeedback:U0,viewAndManageFeedback:W0,feedbackItemsCount:B0,searchFeedbackPlaceholder:H0,allFeedback:V0,open:Y0,solved:G0,filter:Z0,noFeedbackFound:Q0,feedbackDetails:K0,created:X0,close:J0,currentBehavior:q0,expectedBehavior:e1,url:t1,chatHistory:n1,noMessagesYet:r1,startConversationBelow:s1,markAsSolved:i1,typeYourMessage:o1,send:a1,sending:l1,enterToSendShiftEnterNewLine:u1,you:c1,current:d1,justNow:f1,hoursAgo:h1,oneDayAgo:p1,daysAgo:m1,AdminDashboard:g1,CollapseSidebar:y1,ShowOrganizations:v1,users:x1,createNewUser:w1,noUsersFound:S1,Username:k1,FirstName:N1,LastName:C1,Email:j1,Search:b1,ShowAll:E1,IsActive:O1,IsSuperuser:P1,AllGroups:L1,AllOrganizations:z1,NoOrganization:T1,Created:_1,LastLogin:I1,DeleteUser:D1,SaveChanges:M1,PurePermissions:R1,Groups:F1,Organization:$1,LastNamePlaceholder:A1,FirstNamePlaceholder:U1,UsernamePlaceholder:W1,Password:B1,Active:H1,None:V1,EditOrganization:Y1,ActiveUntilLabel:G1,Expired:Z1,Clear:Q1,SortByExpiry:K1,ExpiringSoonFirst:X1,ExpiringLaterFirst:J1,Status:q1,manageUser:ew,EmailPlaceholder:tw,AllStatus:nw,Soon:rw,Later:sw,ShowingOrganizations:iw,createNewOrganization:ow,OrganizationName:aw,organizations:lw,noOrganizationsFound:uw,adminDashboard:cw,SignInToContinue:dw,emailAddress:fw,enterYourEmail:hw,enterYourPassword:pw,password:mw,signIn:gw,forgotPassword:"Passwort vergessen?",tfaCode:"2FA-Code",enterSixDigitCode:"6-stelligen Code oder Wiederherstellungscode eingeben",twofaEnrollTitle:"Zwei-Faktor-Authentifizierung einrichten",twofaEnrollHint:"Scannen Sie den QR-Code mit Ihrer Authenticator-App und geben Sie den angezeigten Code ein.",twofaSecret:"Oder geben Sie diesen Schlüssel manuell ein:",recoveryCodesTitle:"Ihre Wiederherstellungscodes",recoveryCodesHint:"Bewahren Sie diese Codes sicher auf. Jeder Code meldet Sie einmal an, falls Sie Ihre Authenticator-App verlieren. Sie werden nur jetzt angezeigt.",continue:"Weiter",manageOrganization:yw,OrganizationNamePlaceholder:vw,ActiveUntil:xw,ActiveUntilDescription:ww,DeleteOrganization:Sw,ExpiresInDays:kw,OrganizationRequiredWarning:Nw,createUser:Cw,PurePermissionsDescription1:jw,PurePermissionsDescription2:bw,GroupDescription1:Ew,GroupDescription2:Ow,"language.german":"Deutsch","language.english":"Englisch"},Lw={en:{translation:k0},de:{translation:Pw}};Xe.use(ff).use(Ym).init({resources:Lw,fallbackLng:"en",debug:!1,detection:{order:["localStorage","navigator","htmlTag"],caches:["localStorage"]},interpolation:{escapeValue:!1},defaultNS:"translation",ns:["translation"]});xp.createRoot(document.getElementById("root")).render(f.jsx(Wt.StrictMode,{children:f.jsx(Dy,{})}));